@@ -7,6 +7,7 @@ import (
 	"path/filepath"
 
 	"github.com/flanksource/gavel/linters"
+	"github.com/flanksource/gavel/models"
 	"github.com/flanksource/gavel/testrunner/parsers"
 	testui "github.com/flanksource/gavel/testrunner/ui"
 )
@@ -26,6 +27,31 @@ type ViolationKey struct {
 	Rule   string
 }
 
+// KeyForTest returns the key t is matched by across runs.
+func KeyForTest(t parsers.Test) TestKey {
+	return TestKey{
+		Framework:   string(t.Framework),
+		PackagePath: t.PackagePath,
+		FullName:    t.FullName(),
+	}
+}
+
+// KeyForViolation returns the key v, reported by r, is matched by across
+// runs. Absolute paths are made relative to the linter's working directory.
+func KeyForViolation(r *linters.LinterResult, v models.Violation) ViolationKey {
+	file := v.File
+	if r.WorkDir != "" && filepath.IsAbs(file) {
+		if rel, err := filepath.Rel(r.WorkDir, file); err == nil {
+			file = rel
+		}
+	}
+	rule := ""
+	if v.Rule != nil {
+		rule = v.Rule.Method
+	}
+	return ViolationKey{Linter: r.Linter, File: file, Rule: rule}
+}
+
 func LoadSnapshot(path string) (*testui.Snapshot, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
@@ -44,11 +70,7 @@ func ExtractFailedTestKeys(tests []parsers.Test) map[TestKey]bool {
 		if !t.Failed {
 			return
 		}
-		keys[TestKey{
-			Framework:   string(t.Framework),
-			PackagePath: t.PackagePath,
-			FullName:    t.FullName(),
-		}] = true
+		keys[KeyForTest(t)] = true
 	})
 	return keys
 }
@@ -60,21 +82,7 @@ func ExtractViolationKeys(results []*linters.LinterResult) map[ViolationKey]bool
 			continue
 		}
 		for _, v := range r.Violations {
-			file := v.File
-			if r.WorkDir != "" && filepath.IsAbs(file) {
-				if rel, err := filepath.Rel(r.WorkDir, file); err == nil {
-					file = rel
-				}
-			}
-			rule := ""
-			if v.Rule != nil {
-				rule = v.Rule.Method
-			}
-			keys[ViolationKey{
-				Linter: r.Linter,
-				File:   file,
-				Rule:   rule,
-			}] = true
+			keys[KeyForViolation(r, v)] = true
 		}
 	}
 	return keys
@@ -0,0 +1,298 @@
+package baseline
+
+import (
+	"sort"
+	"time"
+
+	"github.com/flanksource/gavel/linters"
+	"github.com/flanksource/gavel/testrunner/parsers"
+	testui "github.com/flanksource/gavel/testrunner/ui"
+	"github.com/flanksource/gavel/utils"
+)
+
+const (
+	DefaultSlowerPct   = 50
+	DefaultMinSlowdown = 100 * time.Millisecond
+)
+
+// CompareOptions controls when a test that passed in both runs counts as
+// slower: it must take SlowerPct percent longer in head and at least
+// MinSlowdown more in absolute terms, so millisecond tests do not flap.
+type CompareOptions struct {
+	SlowerPct   int
+	MinSlowdown time.Duration
+}
+
+func DefaultCompareOptions() CompareOptions {
+	return CompareOptions{SlowerPct: DefaultSlowerPct, MinSlowdown: DefaultMinSlowdown}
+}
+
+// Compare classifies how head differs from base. Tests are matched by
+// TestKey and lint violations by ViolationKey, the same keys --baseline
+// uses. Only leaf tests are compared; package and suite nodes just roll up
+// their children.
+//
+// A test added in head that fails is reported as newly failing, not added.
+// Quarantined failures are not failures, so they never make a regression;
+// a test that failed in base and is quarantined in head is reported as
+// fixed, with a message saying it was quarantined rather than passing.
+// Lint findings are only compared for linters that ran in both runs, so a
+// run without --lint does not read as every violation being fixed.
+func Compare(base, head *testui.Snapshot, opts CompareOptions) *testui.RunDiff {
+	if base == nil {
+		base = &testui.Snapshot{}
+	}
+	if head == nil {
+		head = &testui.Snapshot{}
+	}
+	diff := &testui.RunDiff{Base: diffRun(base), Head: diffRun(head)}
+	diff.Tests, diff.Unchanged = compareTests(base.Tests, head.Tests, opts)
+	diff.Lint = compareLint(base.Lint, head.Lint)
+	return diff
+}
+
+func diffRun(s *testui.Snapshot) testui.DiffRun {
+	var run testui.DiffRun
+	if s.Git != nil {
+		run.SHA = s.Git.SHA
+	}
+	if s.Metadata != nil {
+		run.Started = s.Metadata.Started
+	}
+	return run
+}
+
+func compareTests(baseTests, headTests []parsers.Test, opts CompareOptions) ([]testui.TestDiff, int) {
+	base, baseOrder := leafTests(baseTests)
+	head, headOrder := leafTests(headTests)
+
+	var diffs []testui.TestDiff
+	unchanged := 0
+	for _, key := range headOrder {
+		h := head[key]
+		b, inBase := base[key]
+		d := testui.TestDiff{
+			Framework:    key.Framework,
+			PackagePath:  key.PackagePath,
+			Name:         key.FullName,
+			HeadStatus:   testStatus(h),
+			HeadDuration: h.Duration,
+		}
+		if inBase {
+			d.BaseStatus = testStatus(b)
+			d.BaseDuration = b.Duration
+		}
+		switch {
+		case isFailure(h) && inBase && isFailure(b):
+			d.Kind = testui.DiffStillFailing
+			d.Message = failureMessage(h)
+		case isFailure(h):
+			d.Kind = testui.DiffNewlyFailing
+			d.Message = failureMessage(h)
+		case !inBase:
+			d.Kind = testui.DiffAdded
+		case isFailure(b) && h.Passed:
+			d.Kind = testui.DiffFixed
+		case isFailure(b) && h.Quarantined && (h.Failed || h.TimedOut):
+			d.Kind = testui.DiffFixed
+			d.Message = "quarantined"
+		case isSlower(b, h, opts):
+			d.Kind = testui.DiffSlower
+		default:
+			unchanged++
+			continue
+		}
+		diffs = append(diffs, d)
+	}
+	for _, key := range baseOrder {
+		if _, ok := head[key]; ok {
+			continue
+		}
+		b := base[key]
+		diffs = append(diffs, testui.TestDiff{
+			Kind:         testui.DiffRemoved,
+			Framework:    key.Framework,
+			PackagePath:  key.PackagePath,
+			Name:         key.FullName,
+			BaseStatus:   testStatus(b),
+			BaseDuration: b.Duration,
+		})
+	}
+	sort.SliceStable(diffs, func(i, j int) bool {
+		a, b := diffs[i], diffs[j]
+		if a.Kind != b.Kind {
+			return a.Kind.Order() < b.Kind.Order()
+		}
+		if a.PackagePath != b.PackagePath {
+			return a.PackagePath < b.PackagePath
+		}
+		return a.Name < b.Name
+	})
+	return diffs, unchanged
+}
+
+// leafTests indexes the leaves of tests that have a result by TestKey, and
+// returns the keys in the order they were first seen.
+func leafTests(tests []parsers.Test) (map[TestKey]parsers.Test, []TestKey) {
+	index := make(map[TestKey]parsers.Test)
+	var order []TestKey
+	var walk func(tests []parsers.Test)
+	walk = func(tests []parsers.Test) {
+		for _, t := range tests {
+			if len(t.Children) > 0 {
+				walk(t.Children)
+				continue
+			}
+			if !t.Failed && !t.Passed && !t.Skipped && !t.TimedOut {
+				continue
+			}
+			key := KeyForTest(t)
+			if _, ok := index[key]; !ok {
+				order = append(order, key)
+			}
+			index[key] = t
+		}
+	}
+	walk(tests)
+	return index, order
+}
+
+// isFailure reports whether t failed in a way that counts. Quarantined tests
+// never fail a run, so they are not failures in a diff either.
+func isFailure(t parsers.Test) bool {
+	return (t.Failed || t.TimedOut) && !t.Quarantined
+}
+
+func isSlower(base, head parsers.Test, opts CompareOptions) bool {
+	if !base.Passed || !head.Passed || base.Cached || head.Cached || base.Duration <= 0 {
+		return false
+	}
+	delta := head.Duration - base.Duration
+	if delta <= 0 || delta < opts.MinSlowdown {
+		return false
+	}
+	return int64(delta)*100 >= int64(base.Duration)*int64(opts.SlowerPct)
+}
+
+func testStatus(t parsers.Test) string {
+	switch {
+	case t.Quarantined && (t.Failed || t.TimedOut):
+		return "quarantined"
+	case t.TimedOut:
+		return "timedout"
+	case t.Failed:
+		return "failed"
+	case t.Skipped:
+		return "skipped"
+	case t.Passed:
+		return "passed"
+	}
+	return ""
+}
+
+func failureMessage(t parsers.Test) string {
+	msg := utils.FirstLine(parsers.StripANSI(t.Message))
+	if msg == "" && t.TimedOut {
+		return "timed out"
+	}
+	return msg
+}
+
+type lintEntry struct {
+	count   int
+	message string
+}
+
+func compareLint(baseResults, headResults []*linters.LinterResult) []testui.LintDiff {
+	ran := ranLinters(baseResults)
+	for linter := range ran {
+		if !ranLinters(headResults)[linter] {
+			delete(ran, linter)
+		}
+	}
+	base, baseOrder := lintEntries(baseResults, ran)
+	head, headOrder := lintEntries(headResults, ran)
+
+	var diffs []testui.LintDiff
+	for _, key := range headOrder {
+		h := head[key]
+		d := testui.LintDiff{
+			Kind:      testui.DiffNewlyFailing,
+			Linter:    key.Linter,
+			File:      key.File,
+			Rule:      key.Rule,
+			HeadCount: h.count,
+			Message:   h.message,
+		}
+		if b, ok := base[key]; ok {
+			d.Kind = testui.DiffStillFailing
+			d.BaseCount = b.count
+		}
+		diffs = append(diffs, d)
+	}
+	for _, key := range baseOrder {
+		if _, ok := head[key]; ok {
+			continue
+		}
+		b := base[key]
+		diffs = append(diffs, testui.LintDiff{
+			Kind:      testui.DiffFixed,
+			Linter:    key.Linter,
+			File:      key.File,
+			Rule:      key.Rule,
+			BaseCount: b.count,
+			Message:   b.message,
+		})
+	}
+	sort.SliceStable(diffs, func(i, j int) bool {
+		a, b := diffs[i], diffs[j]
+		if a.Kind != b.Kind {
+			return a.Kind.Order() < b.Kind.Order()
+		}
+		if a.Linter != b.Linter {
+			return a.Linter < b.Linter
+		}
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		return a.Rule < b.Rule
+	})
+	return diffs
+}
+
+// ranLinters returns the linters that completed, so their violations (or
+// lack of them) are a real result.
+func ranLinters(results []*linters.LinterResult) map[string]bool {
+	ran := make(map[string]bool)
+	for _, r := range results {
+		if r == nil || r.Skipped || r.TimedOut || r.Error != "" {
+			continue
+		}
+		ran[r.Linter] = true
+	}
+	return ran
+}
+
+func lintEntries(results []*linters.LinterResult, include map[string]bool) (map[ViolationKey]*lintEntry, []ViolationKey) {
+	entries := make(map[ViolationKey]*lintEntry)
+	var order []ViolationKey
+	for _, r := range results {
+		if r == nil || !include[r.Linter] {
+			continue
+		}
+		for _, v := range r.Violations {
+			key := KeyForViolation(r, v)
+			e, ok := entries[key]
+			if !ok {
+				e = &lintEntry{}
+				entries[key] = e
+				order = append(order, key)
+			}
+			e.count++
+			if e.message == "" && v.Message != nil {
+				e.message = utils.FirstLine(parsers.StripANSI(*v.Message))
+			}
+		}
+	}
+	return entries, order
+}
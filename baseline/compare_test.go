@@ -0,0 +1,176 @@
+package baseline
+
+import (
+	"time"
+
+	"github.com/flanksource/gavel/linters"
+	"github.com/flanksource/gavel/models"
+	"github.com/flanksource/gavel/testrunner/parsers"
+	testui "github.com/flanksource/gavel/testrunner/ui"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func goTest(name string, mut func(t *parsers.Test)) parsers.Test {
+	t := parsers.Test{Name: name, PackagePath: "./pkg", Framework: parsers.GoTest, Passed: true, Duration: time.Second}
+	if mut != nil {
+		mut(&t)
+	}
+	return t
+}
+
+func failed(t *parsers.Test) {
+	t.Passed = false
+	t.Failed = true
+	t.Message = "\x1b[31mwant 1, got 2\x1b[0m\nmore"
+}
+
+func kinds(d *testui.RunDiff) map[string]testui.DiffKind {
+	out := map[string]testui.DiffKind{}
+	for _, t := range d.Tests {
+		out[t.Name] = t.Kind
+	}
+	return out
+}
+
+var _ = Describe("Compare", func() {
+	It("classifies tests by how their outcome changed", func() {
+		base := &testui.Snapshot{
+			Git: &testui.SnapshotGit{SHA: "abc123"},
+			Tests: []parsers.Test{{
+				Name: "./pkg",
+				Children: parsers.Tests{
+					goTest("TestStillFailing", failed),
+					goTest("TestFixed", failed),
+					goTest("TestBreaks", nil),
+					goTest("TestRemoved", nil),
+					goTest("TestSame", nil),
+					goTest("TestSlower", nil),
+				},
+			}},
+		}
+		head := &testui.Snapshot{
+			Git: &testui.SnapshotGit{SHA: "def456"},
+			Tests: []parsers.Test{{
+				Name: "./pkg",
+				Children: parsers.Tests{
+					goTest("TestStillFailing", failed),
+					goTest("TestFixed", nil),
+					goTest("TestBreaks", func(t *parsers.Test) { t.Passed = false; t.Failed = true; t.TimedOut = true }),
+					goTest("TestSame", nil),
+					goTest("TestSlower", func(t *parsers.Test) { t.Duration = 2 * time.Second }),
+					goTest("TestAdded", nil),
+					goTest("TestAddedFailing", failed),
+				},
+			}},
+		}
+
+		diff := Compare(base, head, DefaultCompareOptions())
+		Expect(diff.Base.SHA).To(Equal("abc123"))
+		Expect(diff.Head.SHA).To(Equal("def456"))
+		Expect(kinds(diff)).To(Equal(map[string]testui.DiffKind{
+			"TestStillFailing": testui.DiffStillFailing,
+			"TestFixed":        testui.DiffFixed,
+			"TestBreaks":       testui.DiffNewlyFailing,
+			"TestAddedFailing": testui.DiffNewlyFailing,
+			"TestRemoved":      testui.DiffRemoved,
+			"TestSlower":       testui.DiffSlower,
+			"TestAdded":        testui.DiffAdded,
+		}))
+		Expect(diff.Unchanged).To(Equal(1))
+		Expect(diff.TestCount(testui.DiffNewlyFailing)).To(Equal(2))
+		Expect(diff.HasRegression()).To(BeTrue())
+
+		Expect(diff.Tests[0].Name).To(Equal("TestAddedFailing"), "sorted by kind then name")
+		Expect(diff.Tests[0].BaseStatus).To(BeEmpty())
+		Expect(diff.Tests[0].Message).To(Equal("want 1, got 2"))
+		Expect(diff.Tests[1].HeadStatus).To(Equal("timedout"))
+		Expect(diff.Tests[1].Message).To(Equal("timed out"))
+	})
+
+	It("does not count quarantined failures as failing", func() {
+		quarantined := func(t *parsers.Test) { failed(t); t.Quarantined = true }
+		base := &testui.Snapshot{Tests: []parsers.Test{
+			goTest("TestQuarantined", nil),
+			goTest("TestStillQuarantined", quarantined),
+			goTest("TestNowQuarantined", failed),
+		}}
+		head := &testui.Snapshot{Tests: []parsers.Test{
+			goTest("TestQuarantined", quarantined),
+			goTest("TestStillQuarantined", quarantined),
+			goTest("TestNowQuarantined", quarantined),
+			goTest("TestAddedQuarantined", quarantined),
+		}}
+		diff := Compare(base, head, DefaultCompareOptions())
+		Expect(kinds(diff)).To(Equal(map[string]testui.DiffKind{
+			"TestNowQuarantined":   testui.DiffFixed,
+			"TestAddedQuarantined": testui.DiffAdded,
+		}))
+		Expect(diff.Tests[1].HeadStatus).To(Equal("quarantined"))
+		Expect(diff.Unchanged).To(Equal(2))
+		Expect(diff.HasRegression()).To(BeFalse())
+	})
+
+	It("reports a failing test that head quarantined as fixed by quarantine", func() {
+		base := &testui.Snapshot{Tests: []parsers.Test{goTest("TestFlaky", failed)}}
+		head := &testui.Snapshot{Tests: []parsers.Test{
+			goTest("TestFlaky", func(t *parsers.Test) { failed(t); t.Quarantined = true }),
+		}}
+		diff := Compare(base, head, DefaultCompareOptions())
+		Expect(diff.Tests).To(HaveLen(1))
+		Expect(diff.Tests[0].Kind).To(Equal(testui.DiffFixed))
+		Expect(diff.Tests[0].BaseStatus).To(Equal("failed"))
+		Expect(diff.Tests[0].HeadStatus).To(Equal("quarantined"))
+		Expect(diff.Tests[0].Message).To(Equal("quarantined"))
+		Expect(diff.Unchanged).To(BeZero())
+		Expect(diff.HasRegression()).To(BeFalse())
+	})
+
+	It("ignores small or cached slowdowns", func() {
+		base := &testui.Snapshot{Tests: []parsers.Test{
+			goTest("TestTiny", func(t *parsers.Test) { t.Duration = 10 * time.Millisecond }),
+			goTest("TestCached", nil),
+			goTest("TestBarely", nil),
+		}}
+		head := &testui.Snapshot{Tests: []parsers.Test{
+			goTest("TestTiny", func(t *parsers.Test) { t.Duration = 50 * time.Millisecond }),
+			goTest("TestCached", func(t *parsers.Test) { t.Duration = 3 * time.Second; t.Cached = true }),
+			goTest("TestBarely", func(t *parsers.Test) { t.Duration = 1400 * time.Millisecond }),
+		}}
+		diff := Compare(base, head, DefaultCompareOptions())
+		Expect(diff.Tests).To(BeEmpty())
+		Expect(diff.Unchanged).To(Equal(3))
+
+		diff = Compare(base, head, CompareOptions{SlowerPct: 20, MinSlowdown: 0})
+		Expect(kinds(diff)).To(Equal(map[string]testui.DiffKind{
+			"TestTiny":   testui.DiffSlower,
+			"TestBarely": testui.DiffSlower,
+		}))
+	})
+
+	It("classifies lint violations by ViolationKey", func() {
+		base := &testui.Snapshot{Lint: []*linters.LinterResult{
+			{Linter: "golangci-lint", WorkDir: "/repo", Violations: []models.Violation{
+				{File: "/repo/a.go", Line: 1, Rule: &models.Rule{Method: "unused"}},
+				{File: "b.go", Line: 2, Rule: &models.Rule{Method: "errcheck"}, Message: models.StringPtr("error not checked")},
+			}},
+			{Linter: "eslint", Violations: []models.Violation{{File: "x.ts", Rule: &models.Rule{Method: "no-console"}}}},
+		}}
+		head := &testui.Snapshot{Lint: []*linters.LinterResult{
+			{Linter: "golangci-lint", WorkDir: "/repo", Violations: []models.Violation{
+				{File: "a.go", Line: 8, Rule: &models.Rule{Method: "unused"}},
+				{File: "a.go", Line: 9, Rule: &models.Rule{Method: "unused"}},
+				{File: "c.go", Line: 3, Rule: &models.Rule{Method: "govet"}, Message: models.StringPtr("printf: bad verb")},
+			}},
+			{Linter: "eslint", Skipped: true},
+		}}
+
+		diff := Compare(base, head, DefaultCompareOptions())
+		Expect(diff.Lint).To(Equal([]testui.LintDiff{
+			{Kind: testui.DiffNewlyFailing, Linter: "golangci-lint", File: "c.go", Rule: "govet", HeadCount: 1, Message: "printf: bad verb"},
+			{Kind: testui.DiffFixed, Linter: "golangci-lint", File: "b.go", Rule: "errcheck", BaseCount: 1, Message: "error not checked"},
+			{Kind: testui.DiffStillFailing, Linter: "golangci-lint", File: "a.go", Rule: "unused", BaseCount: 1, HeadCount: 2},
+		}), "eslint did not run in head, so its violation is not reported as fixed")
+		Expect(diff.LintCount(testui.DiffNewlyFailing)).To(Equal(1))
+	})
+})
@@ -1,8 +1,6 @@
 package baseline
 
 import (
-	"path/filepath"
-
 	"github.com/flanksource/gavel/linters"
 	"github.com/flanksource/gavel/testrunner/parsers"
 )
@@ -19,12 +17,7 @@ func FilterNewTestFailures(tests []parsers.Test, baselineKeys map[TestKey]bool)
 
 func filterTestNode(t *parsers.Test, baselineKeys map[TestKey]bool) {
 	if t.Failed {
-		key := TestKey{
-			Framework:   string(t.Framework),
-			PackagePath: t.PackagePath,
-			FullName:    t.FullName(),
-		}
-		if baselineKeys[key] {
+		if baselineKeys[KeyForTest(*t)] {
 			t.Failed = false
 			t.Passed = true
 		}
@@ -45,18 +38,7 @@ func FilterNewViolations(results []*linters.LinterResult, baselineKeys map[Viola
 		}
 		kept := r.Violations[:0]
 		for _, v := range r.Violations {
-			file := v.File
-			if r.WorkDir != "" && filepath.IsAbs(file) {
-				if rel, err := filepath.Rel(r.WorkDir, file); err == nil {
-					file = rel
-				}
-			}
-			rule := ""
-			if v.Rule != nil {
-				rule = v.Rule.Method
-			}
-			key := ViolationKey{Linter: r.Linter, File: file, Rule: rule}
-			if !baselineKeys[key] {
+			if !baselineKeys[KeyForViolation(r, v)] {
 				kept = append(kept, v)
 			}
 		}
@@ -0,0 +1,337 @@
+package baseline
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/flanksource/gavel/internal/changegraph"
+	"github.com/flanksource/gavel/linters"
+)
+
+// FileName is the committed baseline `gavel lint` reads from the git root.
+const FileName = ".gavel-baseline.json"
+
+// fileVersion is bumped whenever the fingerprint algorithm changes, so an
+// old baseline is not silently matched with different hashes.
+const fileVersion = 1
+
+// contextRadius is how many lines either side of a violation feed its
+// fingerprint.
+const contextRadius = 2
+
+// File is the on-disk form of a fingerprinted lint baseline.
+type File struct {
+	Version int         `json:"version"`
+	Entries []FileEntry `json:"entries"`
+}
+
+// FileEntry records one accepted violation. Fingerprint hashes the normalized
+// source line together with its neighbours; LineHash hashes the line alone
+// and is the fallback when only the surrounding code changed. Line and
+// Message are informational and never used for matching.
+type FileEntry struct {
+	Linter      string `json:"linter"`
+	Rule        string `json:"rule,omitempty"`
+	File        string `json:"file"`
+	Line        int    `json:"line,omitempty"`
+	Message     string `json:"message,omitempty"`
+	Fingerprint string `json:"fingerprint"`
+	LineHash    string `json:"line_hash"`
+}
+
+// LoadFile reads a baseline written by Save. A missing file is reported as
+// an error wrapping fs.ErrNotExist.
+func LoadFile(path string) (*File, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading baseline %s: %w", path, err)
+	}
+	var f File
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("parsing baseline %s: %w", path, err)
+	}
+	if f.Version != fileVersion {
+		return nil, fmt.Errorf("baseline %s has version %d, expected %d; regenerate it with --update-baseline", path, f.Version, fileVersion)
+	}
+	return &f, nil
+}
+
+// Save writes f to path with its entries in a stable order, so regenerating
+// an unchanged baseline produces no diff.
+func (f *File) Save(path string) error {
+	f.Version = fileVersion
+	sort.SliceStable(f.Entries, func(i, j int) bool {
+		a, b := f.Entries[i], f.Entries[j]
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		if a.Linter != b.Linter {
+			return a.Linter < b.Linter
+		}
+		return a.Rule < b.Rule
+	})
+	if f.Entries == nil {
+		f.Entries = []FileEntry{}
+	}
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// NewFile fingerprints every violation in results. File paths are stored
+// relative to root, which should be the directory the baseline lives in.
+func NewFile(results []*linters.LinterResult, root string) *File {
+	f := &File{Version: fileVersion}
+	src := sourceLines{}
+	for _, r := range results {
+		if r == nil || r.Skipped {
+			continue
+		}
+		for _, v := range r.Violations {
+			e := entryFor(r, v.File, v.Line, root, src)
+			if v.Message != nil {
+				e.Message = *v.Message
+			}
+			if v.Rule != nil {
+				e.Rule = v.Rule.Method
+			}
+			f.Entries = append(f.Entries, e)
+		}
+	}
+	return f
+}
+
+// MergeScope describes what an --update-baseline run looked at, so Merge
+// knows which earlier entries it could have reproduced.
+type MergeScope struct {
+	// Paths are the files and directories, relative to the baseline root,
+	// the run was limited to. Empty means the whole tree.
+	Paths []string
+	// Incremental marks a run that only reports new issues (--changed,
+	// --since), which never proves an earlier entry fixed.
+	Incremental bool
+}
+
+// covers reports whether the run linted file. Go-style "/..." suffixes are
+// treated as the directory they recurse into.
+func (s MergeScope) covers(file string) bool {
+	if s.Incremental {
+		return false
+	}
+	if len(s.Paths) == 0 {
+		return true
+	}
+	for _, p := range s.Paths {
+		p = strings.TrimSuffix(filepath.ToSlash(filepath.Clean(p)), "/...")
+		if p == "." || p == "..." || file == p || strings.HasPrefix(file, p+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// Merge carries over the entries of previous the run could not have
+// reproduced, so a partial update does not forget them: entries of linters
+// that did not run, and entries whose file is outside scope. Only the
+// linter+file pairs the run covered are replaced. A carried-over entry the
+// run recorded again is not duplicated.
+func (f *File) Merge(previous *File, results []*linters.LinterResult, scope MergeScope) {
+	if previous == nil {
+		return
+	}
+	ran := map[string]bool{}
+	for _, r := range results {
+		if r != nil && !r.Skipped {
+			ran[r.Linter] = true
+		}
+	}
+	type key struct{ linter, rule, file, fingerprint string }
+	recorded := map[key]int{}
+	for _, e := range f.Entries {
+		recorded[key{e.Linter, e.Rule, e.File, e.Fingerprint}]++
+	}
+	for _, e := range previous.Entries {
+		if ran[e.Linter] && scope.covers(e.File) {
+			continue
+		}
+		k := key{e.Linter, e.Rule, e.File, e.Fingerprint}
+		if recorded[k] > 0 {
+			recorded[k]--
+			continue
+		}
+		f.Entries = append(f.Entries, e)
+	}
+}
+
+// Apply removes the violations in results that match an entry of f and
+// returns how many were removed, plus the entries no violation matched
+// anymore (fixed, and safe to prune). renames maps a file's path in the
+// baseline to its current path. Each entry suppresses at most one violation:
+// first by exact fingerprint, then by the hash of the line alone. Mutates
+// results in place.
+func Apply(results []*linters.LinterResult, root string, f *File, renames map[string]string) (int, []FileEntry) {
+	if f == nil {
+		return 0, nil
+	}
+
+	type scope struct{ linter, rule, file string }
+	pending := map[scope][]int{}
+	for i, e := range f.Entries {
+		file := e.File
+		if renamed, ok := renames[file]; ok {
+			file = renamed
+		}
+		key := scope{e.Linter, e.Rule, file}
+		pending[key] = append(pending[key], i)
+	}
+	used := make([]bool, len(f.Entries))
+
+	type candidate struct {
+		result, violation int
+		key               scope
+		entry             FileEntry
+	}
+	var candidates []candidate
+	src := sourceLines{}
+	for ri, r := range results {
+		if r == nil || r.Skipped {
+			continue
+		}
+		for vi, v := range r.Violations {
+			e := entryFor(r, v.File, v.Line, root, src)
+			rule := ""
+			if v.Rule != nil {
+				rule = v.Rule.Method
+			}
+			candidates = append(candidates, candidate{ri, vi, scope{r.Linter, rule, e.File}, e})
+		}
+	}
+
+	suppressed := map[[2]int]bool{}
+	match := func(same func(a, b FileEntry) bool) {
+		for _, c := range candidates {
+			at := [2]int{c.result, c.violation}
+			if suppressed[at] {
+				continue
+			}
+			for _, i := range pending[c.key] {
+				if !used[i] && same(f.Entries[i], c.entry) {
+					used[i] = true
+					suppressed[at] = true
+					break
+				}
+			}
+		}
+	}
+	match(func(a, b FileEntry) bool { return a.Fingerprint == b.Fingerprint })
+	match(func(a, b FileEntry) bool { return a.LineHash == b.LineHash })
+
+	for ri, r := range results {
+		if r == nil || r.Skipped || len(r.Violations) == 0 {
+			continue
+		}
+		kept := r.Violations[:0]
+		for vi, v := range r.Violations {
+			if !suppressed[[2]int{ri, vi}] {
+				kept = append(kept, v)
+			}
+		}
+		r.Violations = kept
+	}
+
+	var fixed []FileEntry
+	for i, e := range f.Entries {
+		if !used[i] {
+			fixed = append(fixed, e)
+		}
+	}
+	return len(suppressed), fixed
+}
+
+// Renames returns the files git sees as renamed since the baseline in root
+// was last committed (or since HEAD while it is uncommitted), keyed by the
+// path recorded in the baseline.
+func Renames(root string) (map[string]string, error) {
+	cmd := exec.Command("git", "log", "-1", "--format=%H", "--", FileName)
+	cmd.Dir = root
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git log %s: %w", FileName, err)
+	}
+	ref := strings.TrimSpace(string(out))
+	if ref == "" {
+		ref = "HEAD"
+	}
+	return changegraph.ComputeRenames(root, ref)
+}
+
+// entryFor fingerprints the violation at file:line of r. The returned
+// entry's File is relative to root with forward slashes.
+func entryFor(r *linters.LinterResult, file string, line int, root string, src sourceLines) FileEntry {
+	abs := file
+	if !filepath.IsAbs(abs) {
+		dir := r.WorkDir
+		if dir == "" {
+			dir = root
+		}
+		abs = filepath.Join(dir, file)
+	}
+	rel := file
+	if p, err := filepath.Rel(root, abs); err == nil && !strings.HasPrefix(p, "..") {
+		rel = p
+	}
+
+	var lines []string
+	if line > 0 {
+		lines = src.get(abs)
+	}
+	return FileEntry{
+		Linter:      r.Linter,
+		File:        filepath.ToSlash(rel),
+		Line:        line,
+		Fingerprint: hashLines(lines, line-contextRadius, line+contextRadius),
+		LineHash:    hashLines(lines, line, line),
+	}
+}
+
+// hashLines hashes the 1-based lines from..to that exist, with whitespace
+// collapsed so reindenting does not change the result. With no lines (a
+// file-level finding) it hashes to a constant.
+func hashLines(lines []string, from, to int) string {
+	h := sha256.New()
+	for n := from; n <= to; n++ {
+		if n < 1 || n > len(lines) {
+			continue
+		}
+		fmt.Fprintf(h, "%s\n", strings.Join(strings.Fields(lines[n-1]), " "))
+	}
+	return hex.EncodeToString(h.Sum(nil))[:16]
+}
+
+// sourceLines caches file contents split into lines for one pass over the
+// results. Unreadable files yield no lines.
+type sourceLines map[string][]string
+
+func (s sourceLines) get(path string) []string {
+	if lines, ok := s[path]; ok {
+		return lines
+	}
+	var lines []string
+	if data, err := os.ReadFile(path); err == nil {
+		lines = strings.Split(string(data), "\n")
+	}
+	s[path] = lines
+	return lines
+}
@@ -0,0 +1,152 @@
+package baseline
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/flanksource/gavel/linters"
+	"github.com/flanksource/gavel/models"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Fingerprinted baseline", func() {
+	var root string
+
+	write := func(name string, lines ...string) {
+		path := filepath.Join(root, name)
+		Expect(os.MkdirAll(filepath.Dir(path), 0o755)).To(Succeed())
+		Expect(os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0o644)).To(Succeed())
+	}
+	result := func(violations ...models.Violation) []*linters.LinterResult {
+		return []*linters.LinterResult{{Linter: "golangci-lint", WorkDir: root, Violations: violations}}
+	}
+	violation := func(file string, line int, rule string) models.Violation {
+		return models.Violation{File: filepath.Join(root, file), Line: line, Rule: &models.Rule{Method: rule}}
+	}
+
+	BeforeEach(func() {
+		root = GinkgoT().TempDir()
+		write("pkg/a.go", "package pkg", "", "func A() {", "\tx := 1", "}")
+	})
+
+	It("round-trips through Save and LoadFile with root-relative paths", func() {
+		f := NewFile(result(violation("pkg/a.go", 4, "ineffassign")), root)
+		path := filepath.Join(root, FileName)
+		Expect(f.Save(path)).To(Succeed())
+
+		loaded, err := LoadFile(path)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(loaded.Entries).To(HaveLen(1))
+		Expect(loaded.Entries[0].File).To(Equal("pkg/a.go"))
+		Expect(loaded.Entries[0].Rule).To(Equal("ineffassign"))
+		Expect(loaded.Entries[0].Fingerprint).NotTo(BeEmpty())
+	})
+
+	It("suppresses a baselined violation after its line shifts", func() {
+		f := NewFile(result(violation("pkg/a.go", 4, "ineffassign")), root)
+		write("pkg/a.go", "package pkg", "", "// A does a thing.", "", "func A() {", "    x := 1", "}")
+
+		results := result(violation("pkg/a.go", 6, "ineffassign"), violation("pkg/a.go", 5, "unused"))
+		suppressed, fixed := Apply(results, root, f, nil)
+		Expect(suppressed).To(Equal(1))
+		Expect(fixed).To(BeEmpty())
+		Expect(results[0].Violations).To(HaveLen(1))
+		Expect(results[0].Violations[0].Rule.Method).To(Equal("unused"))
+	})
+
+	It("falls back to the line hash when the surrounding code changed", func() {
+		f := NewFile(result(violation("pkg/a.go", 4, "ineffassign")), root)
+		write("pkg/a.go", "package pkg", "", "func B() {", "\tx := 1", "\treturn", "}")
+
+		suppressed, _ := Apply(result(violation("pkg/a.go", 4, "ineffassign")), root, f, nil)
+		Expect(suppressed).To(Equal(1))
+	})
+
+	It("reports entries without a matching violation as fixed", func() {
+		f := NewFile(result(violation("pkg/a.go", 4, "ineffassign"), violation("pkg/a.go", 3, "unused")), root)
+		write("pkg/a.go", "package pkg", "", "func A() {", "\t_ = 1", "}")
+
+		results := result(violation("pkg/a.go", 3, "unused"))
+		suppressed, fixed := Apply(results, root, f, nil)
+		Expect(suppressed).To(Equal(1))
+		Expect(fixed).To(HaveLen(1))
+		Expect(fixed[0].Rule).To(Equal("ineffassign"))
+		Expect(results[0].Violations).To(BeEmpty())
+	})
+
+	It("matches each entry at most once", func() {
+		write("pkg/a.go", "package pkg", "", "var x = 1", "var x = 1")
+		f := NewFile(result(violation("pkg/a.go", 3, "dup")), root)
+
+		results := result(violation("pkg/a.go", 3, "dup"), violation("pkg/a.go", 4, "dup"))
+		suppressed, _ := Apply(results, root, f, nil)
+		Expect(suppressed).To(Equal(1))
+		Expect(results[0].Violations).To(HaveLen(1))
+	})
+
+	It("follows files git saw renamed since the baseline was committed", func() {
+		git := func(args ...string) {
+			cmd := exec.Command("git", args...)
+			cmd.Dir = root
+			out, err := cmd.CombinedOutput()
+			Expect(err).NotTo(HaveOccurred(), "git %v failed: %s", args, out)
+		}
+		git("init", "--quiet", "-b", "main")
+		git("config", "user.email", "test@example.com")
+		git("config", "user.name", "Test")
+		git("config", "commit.gpgsign", "false")
+
+		f := NewFile(result(violation("pkg/a.go", 4, "ineffassign")), root)
+		Expect(f.Save(filepath.Join(root, FileName))).To(Succeed())
+		git("add", ".")
+		git("commit", "--quiet", "-m", "baseline")
+		git("mv", "pkg/a.go", "pkg/b.go")
+
+		renames, err := Renames(root)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(renames).To(Equal(map[string]string{"pkg/a.go": "pkg/b.go"}))
+
+		suppressed, fixed := Apply(result(violation("pkg/b.go", 4, "ineffassign")), root, f, renames)
+		Expect(suppressed).To(Equal(1))
+		Expect(fixed).To(BeEmpty())
+	})
+
+	It("keeps the entries of linters that did not run when merging", func() {
+		previous := &File{Entries: []FileEntry{{Linter: "eslint", File: "web/app.ts"}, {Linter: "golangci-lint", File: "pkg/old.go"}}}
+		f := NewFile(result(violation("pkg/a.go", 4, "ineffassign")), root)
+		f.Merge(previous, result(), MergeScope{})
+
+		var files []string
+		for _, e := range f.Entries {
+			files = append(files, e.File)
+		}
+		Expect(files).To(ConsistOf("pkg/a.go", "web/app.ts"))
+	})
+
+	It("keeps the entries outside the scope of a scoped update", func() {
+		write("other/b.go", "package other", "", "func B() {", "\ty := 2", "}")
+		previous := NewFile(result(violation("pkg/a.go", 4, "ineffassign"), violation("other/b.go", 4, "ineffassign")), root)
+		previous.Entries = append(previous.Entries, FileEntry{Linter: "golangci-lint", Rule: "unused", File: "pkg/fixed.go", Fingerprint: "gone"})
+
+		// gavel lint --update-baseline ./pkg/... still finds pkg/a.go.
+		f := NewFile(result(violation("pkg/a.go", 4, "ineffassign")), root)
+		f.Merge(previous, result(), MergeScope{Paths: []string{"./pkg/..."}})
+		var files []string
+		for _, e := range f.Entries {
+			files = append(files, e.File)
+		}
+		Expect(files).To(ConsistOf("pkg/a.go", "other/b.go"), "pkg/fixed.go was in scope and is pruned")
+
+		// --changed only reports new issues: nothing is pruned or duplicated.
+		f = NewFile(result(violation("pkg/a.go", 4, "ineffassign")), root)
+		f.Merge(previous, result(), MergeScope{Incremental: true})
+		files = nil
+		for _, e := range f.Entries {
+			files = append(files, e.File)
+		}
+		Expect(files).To(ConsistOf("pkg/a.go", "other/b.go", "pkg/fixed.go"))
+	})
+})
@@ -10,9 +10,11 @@ import (
 
 	"github.com/flanksource/clicky"
 	"github.com/flanksource/clicky/formatters"
+	"github.com/flanksource/commons/logger"
 	"github.com/flanksource/gavel/testrunner"
 	"github.com/flanksource/gavel/testrunner/bench"
 	"github.com/flanksource/gavel/testrunner/parsers"
+	testui "github.com/flanksource/gavel/testrunner/ui"
 	"github.com/spf13/cobra"
 )
 
@@ -96,8 +98,9 @@ func runBenchRun(cmd *cobra.Command, args []string) error {
 		TestTimeout:   benchRunTestTimeout,
 	}
 
+	var srv *testui.Server
 	if benchRunUI {
-		srv, _ := startTestUI(benchRunUIAddr)
+		srv, _ = startTestUI(benchRunUIAddr)
 		if srv != nil {
 			srv.SetVersion(version)
 			srv.SetRunArgs(snapshotArgs(opts))
@@ -121,6 +124,7 @@ func runBenchRun(cmd *cobra.Command, args []string) error {
 	if len(runs) == 0 {
 		return fmt.Errorf("no benchmark results collected; did the packages contain Benchmark* funcs?")
 	}
+	recordBenchHistory(workDir, runs)
 
 	data, err := json.MarshalIndent(runs, "", "  ")
 	if err != nil {
@@ -133,6 +137,13 @@ func runBenchRun(cmd *cobra.Command, args []string) error {
 	}
 
 	if benchRunUI {
+		if srv != nil {
+			if history, err := loadBenchHistory(workDir, 0, 0); err != nil {
+				logger.Warnf("bench history: %v", err)
+			} else {
+				srv.SetBenchHistory(history)
+			}
+		}
 		waitForInterrupt()
 	}
 	return nil
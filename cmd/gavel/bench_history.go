@@ -0,0 +1,174 @@
+package main
+
+import (
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/flanksource/clicky"
+	"github.com/flanksource/clicky/formatters"
+	"github.com/flanksource/commons/logger"
+	"github.com/flanksource/gavel/snapshots"
+	"github.com/flanksource/gavel/testrunner/bench"
+	"github.com/flanksource/gavel/testrunner/parsers"
+	"github.com/spf13/cobra"
+)
+
+var (
+	benchHistoryThreshold float64
+	benchHistoryLimit     int
+	benchHistoryPattern   string
+	benchHistoryUI        bool
+	benchHistoryUIAddr    string
+	benchHistoryOpts      clicky.FormatOptions
+)
+
+var benchHistoryCmd = &cobra.Command{
+	Use:   "history",
+	Short: "Show per-benchmark trends across commits and the commits that shifted them",
+	Long: `Show per-benchmark ns/op, B/op and allocs/op trends from .gavel/bench-history.jsonl.
+
+Every gavel bench run and gavel test --bench appends its results to the
+history, keyed by the HEAD commit (plus a checksum of uncommitted changes).
+Runs repeated at the same commit are pooled. Commits are ordered by git
+ancestry (git rev-list --topo-order), falling back to their commit time for
+commits that are no longer reachable, before --limit is applied. Change points are found with a
+Welch t-test between the commits before and after each candidate split; a
+shift must be significant (p < 0.05) and larger than --threshold. The first
+commit after a regression is reported as the one that introduced it.`,
+	SilenceUsage: true,
+	RunE:         runBenchHistory,
+}
+
+func runBenchHistory(cmd *cobra.Command, args []string) error {
+	workDir, err := getWorkingDir()
+	if err != nil {
+		return err
+	}
+	history, err := loadBenchHistory(workDir, benchHistoryThreshold, benchHistoryLimit)
+	if err != nil {
+		return err
+	}
+	if benchHistoryPattern != "" {
+		re, err := regexp.Compile(benchHistoryPattern)
+		if err != nil {
+			return fmt.Errorf("--pattern: %w", err)
+		}
+		trends := history.Trends[:0]
+		for _, t := range history.Trends {
+			if re.MatchString(t.Name) {
+				trends = append(trends, t)
+			}
+		}
+		history.Trends = trends
+	}
+
+	clicky.MustPrint(history, benchHistoryOpts)
+
+	if benchHistoryUI {
+		srv, _ := startTestUI(benchHistoryUIAddr)
+		if srv != nil {
+			srv.SetBenchHistory(history)
+			srv.MarkDone()
+			waitForInterrupt()
+		}
+	}
+	return nil
+}
+
+func benchHistoryPath(workDir string) string {
+	return filepath.Join(workDir, snapshots.Dir, bench.HistoryFile)
+}
+
+func loadBenchHistory(workDir string, threshold float64, limit int) (*bench.BenchHistory, error) {
+	records, err := bench.LoadHistory(benchHistoryPath(workDir))
+	if err != nil {
+		return nil, err
+	}
+	history := bench.BuildHistory(records, threshold, limit, gitAncestry(workDir))
+	return &history, nil
+}
+
+// gitAncestry lists every reachable commit oldest first, in topological
+// order. It returns nil outside a git repository, leaving BuildHistory to
+// order commits by their recorded commit time.
+func gitAncestry(workDir string) []string {
+	cmd := exec.Command("git", "rev-list", "--topo-order", "--reverse", "--all")
+	cmd.Dir = workDir
+	out, err := cmd.Output()
+	if err != nil {
+		logger.V(1).Infof("bench history: git rev-list failed, ordering by commit time: %v", err)
+		return nil
+	}
+	return strings.Fields(string(out))
+}
+
+// recordBenchHistory appends runs to the history store under the current
+// HEAD. Failures only warn: the history must never fail the run itself.
+func recordBenchHistory(workDir string, runs []bench.BenchRun) {
+	if len(runs) == 0 {
+		return
+	}
+	sha, uncommitted, err := snapshots.SnapshotID(workDir)
+	if err != nil || sha == "" {
+		logger.V(1).Infof("bench history: no git HEAD in %s, not recording", workDir)
+		return
+	}
+	rec := bench.HistoryRecord{
+		Commit:      sha,
+		Uncommitted: uncommitted,
+		Subject:     gitCommitSubject(workDir),
+		CommitTime:  gitCommitTime(workDir),
+		Time:        time.Now().UTC(),
+		Runs:        runs,
+	}
+	path := benchHistoryPath(workDir)
+	if err := bench.AppendHistory(path, rec); err != nil {
+		logger.Warnf("bench history: %v", err)
+		return
+	}
+	logger.V(1).Infof("appended %d benchmarks to %s", len(runs), path)
+}
+
+// recordTestBenchHistory records the benchmarks of a gavel test --bench run.
+func recordTestBenchHistory(workDir string, tests []parsers.Test) {
+	recordBenchHistory(workDir, testsToBenchRuns(tests))
+}
+
+func gitCommitSubject(workDir string) string {
+	cmd := exec.Command("git", "log", "-1", "--format=%s")
+	cmd.Dir = workDir
+	out, err := cmd.Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+func gitCommitTime(workDir string) time.Time {
+	cmd := exec.Command("git", "log", "-1", "--format=%cI")
+	cmd.Dir = workDir
+	out, err := cmd.Output()
+	if err != nil {
+		return time.Time{}
+	}
+	t, err := time.Parse(time.RFC3339, strings.TrimSpace(string(out)))
+	if err != nil {
+		return time.Time{}
+	}
+	return t.UTC()
+}
+
+func init() {
+	benchHistoryCmd.Flags().Float64Var(&benchHistoryThreshold, "threshold", bench.DefaultThreshold, "Minimum shift in percent for a change point")
+	benchHistoryCmd.Flags().IntVar(&benchHistoryLimit, "limit", 0, "Only consider the last N recorded commits (0 = all)")
+	benchHistoryCmd.Flags().StringVar(&benchHistoryPattern, "pattern", "", "Only show benchmarks whose name matches this regex")
+	benchHistoryCmd.Flags().BoolVar(&benchHistoryUI, "ui", false, "Launch browser UI with the trends preloaded")
+	benchHistoryCmd.Flags().StringVar(&benchHistoryUIAddr, "addr", "localhost", "Interface to bind --ui HTTP server. Use 0.0.0.0 to expose on the LAN.")
+	formatters.BindPFlags(benchHistoryCmd.Flags(), &benchHistoryOpts)
+
+	benchCmd.AddCommand(benchHistoryCmd)
+}
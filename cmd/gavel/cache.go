@@ -0,0 +1,304 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	nethttp "net/http"
+	"os"
+	"os/signal"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/flanksource/clicky"
+	"github.com/flanksource/clicky/api"
+	"github.com/flanksource/commons/logger"
+	"github.com/flanksource/gavel/internal/runcache"
+	"github.com/spf13/cobra"
+)
+
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Manage the run cache used by gavel test --cache",
+	Long: `Manage the per-package run cache that lets gavel test --cache skip
+packages whose content fingerprint already has a passing run.
+
+Entries live in ~/.cache/gavel/runs. A shared cache can be served with
+"gavel cache serve" and consumed by pointing test.cache.url in .gavel.yaml
+(or GAVEL_CACHE_URL) at it.
+
+Subcommands:
+  serve    Serve a cache directory over HTTP
+  stats    Summarize the entries in a cache directory
+  inspect  List entries, optionally filtered by fingerprint or import path
+  prune    Remove old, invalid, or excess entries`,
+}
+
+func init() {
+	rootCmd.AddCommand(cacheCmd)
+}
+
+type CacheServeOptions struct {
+	Dir            string `flag:"dir" help:"Cache directory to serve (default: ~/.cache/gavel/runs)"`
+	Host           string `flag:"host" help:"Listen address (use 0.0.0.0 to serve other machines)" default:"127.0.0.1"`
+	Port           int    `flag:"port" help:"Listen port" default:"8411"`
+	Token          string `flag:"token" help:"Bearer token required for writes (default: $GAVEL_CACHE_TOKEN)"`
+	ReadToken      string `flag:"read-token" help:"Bearer token required for reads (default: $GAVEL_CACHE_READ_TOKEN; empty = anonymous reads)"`
+	ReadOnly       bool   `flag:"read-only" help:"Reject all writes"`
+	InsecureWrites bool   `flag:"insecure-writes" help:"Accept unauthenticated writes when no --token is set"`
+}
+
+func (CacheServeOptions) Help() string {
+	return `Serve a run-cache directory over HTTP so CI and developer machines share
+passing package runs.
+
+  GET /<fingerprint>   fetch an entry (404 on miss)
+  PUT /<fingerprint>   store an entry
+  GET /                cache statistics
+
+Writes need --token; without one the server refuses to start unless
+--read-only or --insecure-writes is given, since anyone able to write a
+passing entry can make gavel test --cache skip a package. Reads need
+--read-token (or --token) when one is set. The server listens on 127.0.0.1
+unless --host says otherwise. A typical setup gives CI the write token and
+GAVEL_CACHE_MODE=readwrite, while laptops read anonymously or with the read
+token:
+
+  gavel cache serve --host 0.0.0.0 --dir /var/cache/gavel --token "$CI_CACHE_TOKEN"
+
+  # .gavel.yaml
+  test:
+    cache:
+      url: https://gavel-cache.internal:8411`
+}
+
+func runCacheServe(opts CacheServeOptions) (any, error) {
+	store, err := runcache.Open(opts.Dir)
+	if err != nil {
+		return nil, err
+	}
+	token := opts.Token
+	if token == "" {
+		token = os.Getenv(runcache.EnvToken)
+	}
+	readToken := opts.ReadToken
+	if readToken == "" {
+		readToken = os.Getenv("GAVEL_CACHE_READ_TOKEN")
+	}
+	if token == "" && !opts.ReadOnly {
+		if !opts.InsecureWrites {
+			return nil, fmt.Errorf("no --token (or $%s) set: pass one, or --read-only, or --insecure-writes to accept anonymous writes", runcache.EnvToken)
+		}
+		logger.Warnf("run-cache: --insecure-writes without a token, anyone who can reach the server can write entries")
+	}
+
+	addr := net.JoinHostPort(opts.Host, strconv.Itoa(opts.Port))
+	srv := &nethttp.Server{
+		Addr: addr,
+		Handler: &runcache.Server{
+			Store:          store,
+			Token:          token,
+			ReadToken:      readToken,
+			ReadOnly:       opts.ReadOnly,
+			InsecureWrites: opts.InsecureWrites,
+		},
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		<-sig
+		logger.Infof("Shutting down run-cache server...")
+		_ = srv.Close()
+	}()
+
+	logger.Infof("Serving run cache %s on http://%s", store.Dir, addr)
+	if err := srv.ListenAndServe(); err != nil && !errors.Is(err, nethttp.ErrServerClosed) {
+		return nil, err
+	}
+	return nil, nil
+}
+
+type CacheStatsOptions struct {
+	Dir string `flag:"dir" help:"Cache directory (default: ~/.cache/gavel/runs)"`
+}
+
+func (CacheStatsOptions) Help() string {
+	return `Summarize a run-cache directory: entry count, size, entries per framework,
+age range, and the test time the cached runs represent.`
+}
+
+type cacheStatsReport struct {
+	runcache.Stats
+}
+
+func (r cacheStatsReport) Pretty() api.Text {
+	t := clicky.Text("Run cache ", "bold").Append(r.Dir, "text-blue-500").NewLine()
+	t = t.Append(fmt.Sprintf("%d entries", r.Entries), "bold").
+		Append(fmt.Sprintf(" · %s", formatCacheBytes(r.Bytes)), "text-muted")
+	if r.Invalid > 0 {
+		t = t.Append(fmt.Sprintf(" · %d invalid", r.Invalid), "text-orange-500")
+	}
+	if r.SavedNanos > 0 {
+		t = t.Append(fmt.Sprintf(" · %s of test time", formatDuration(time.Duration(r.SavedNanos))), "text-muted")
+	}
+	if !r.Oldest.IsZero() {
+		t = t.NewLine().Append(fmt.Sprintf("Recorded %s to %s", r.Oldest.Format(time.DateTime), r.Newest.Format(time.DateTime)), "text-muted")
+	}
+	frameworks := make([]string, 0, len(r.ByFramework))
+	for fw := range r.ByFramework {
+		frameworks = append(frameworks, fw)
+	}
+	sort.Strings(frameworks)
+	for _, fw := range frameworks {
+		t = t.NewLine().Append(fmt.Sprintf("  %-12s %d", fw, r.ByFramework[fw]))
+	}
+	return t
+}
+
+func runCacheStats(opts CacheStatsOptions) (any, error) {
+	store, err := runcache.Open(opts.Dir)
+	if err != nil {
+		return nil, err
+	}
+	st, err := store.Stats()
+	if err != nil {
+		return nil, err
+	}
+	return cacheStatsReport{st}, nil
+}
+
+type CacheInspectOptions struct {
+	Dir     string   `flag:"dir" help:"Cache directory (default: ~/.cache/gavel/runs)"`
+	Filters []string `json:"filters,omitempty" args:"true"`
+}
+
+func (CacheInspectOptions) Help() string {
+	return `List run-cache entries. Each argument keeps the entries whose fingerprint
+starts with it or whose import path contains it.
+
+  gavel cache inspect
+  gavel cache inspect github.com/acme/app/pkg/store
+  gavel cache inspect "go test:3fa9"`
+}
+
+type cacheEntryList []runcache.StoredEntry
+
+func (l cacheEntryList) Pretty() api.Text {
+	if len(l) == 0 {
+		return clicky.Text("No cache entries", "text-muted")
+	}
+	t := clicky.Text(fmt.Sprintf("%d cache entries", len(l)), "bold")
+	for _, e := range l {
+		t = t.NewLine()
+		if !e.Valid {
+			t = t.Append("invalid ", "text-orange-500").Append(e.Fingerprint, "text-muted")
+			continue
+		}
+		t = t.Append(e.Entry.ImportPath, "text-blue-500").
+			Append(" ["+e.Entry.Framework+"]", "text-muted").
+			Append(fmt.Sprintf(" %d passed", e.Entry.PassCount), "text-green-500")
+		if e.Entry.SkipCount > 0 {
+			t = t.Append(fmt.Sprintf(" %d skipped", e.Entry.SkipCount), "text-yellow-500")
+		}
+		t = t.Append(fmt.Sprintf(" %s · %s", formatDuration(e.Entry.Duration()), time.Unix(0, e.Entry.RecordedAt).Format(time.DateTime)), "text-muted").
+			NewLine().Append("  "+e.Fingerprint, "text-muted")
+	}
+	return t
+}
+
+func runCacheInspect(opts CacheInspectOptions) (any, error) {
+	store, err := runcache.Open(opts.Dir)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := store.Entries()
+	if err != nil {
+		return nil, err
+	}
+	if len(opts.Filters) == 0 {
+		return cacheEntryList(entries), nil
+	}
+	var out cacheEntryList
+	for _, e := range entries {
+		for _, f := range opts.Filters {
+			if strings.HasPrefix(e.Fingerprint, f) || (e.Entry.ImportPath != "" && strings.Contains(e.Entry.ImportPath, f)) {
+				out = append(out, e)
+				break
+			}
+		}
+	}
+	return out, nil
+}
+
+type CachePruneOptions struct {
+	Dir        string        `flag:"dir" help:"Cache directory (default: ~/.cache/gavel/runs)"`
+	OlderThan  time.Duration `flag:"older-than" help:"Remove entries recorded longer ago than this (e.g. 720h)"`
+	MaxEntries int           `flag:"max-entries" help:"Keep only the newest N entries"`
+	DryRun     bool          `flag:"dry-run" help:"Show what would be removed without deleting"`
+}
+
+func (CachePruneOptions) Help() string {
+	return `Remove run-cache entries. Corrupt entries and entries from an older cache
+format are always removed; --older-than and --max-entries remove valid
+entries by age.
+
+  gavel cache prune --older-than 720h
+  gavel cache prune --max-entries 5000 --dry-run`
+}
+
+type cachePruneReport struct {
+	DryRun  bool                   `json:"dry_run,omitempty"`
+	Removed []runcache.StoredEntry `json:"removed"`
+}
+
+func (r cachePruneReport) Pretty() api.Text {
+	verb := "Removed"
+	if r.DryRun {
+		verb = "Would remove"
+	}
+	var bytes int64
+	for _, e := range r.Removed {
+		bytes += e.Size
+	}
+	return clicky.Text(fmt.Sprintf("%s %d entries", verb, len(r.Removed)), "bold").
+		Append(fmt.Sprintf(" (%s)", formatCacheBytes(bytes)), "text-muted")
+}
+
+func runCachePrune(opts CachePruneOptions) (any, error) {
+	store, err := runcache.Open(opts.Dir)
+	if err != nil {
+		return nil, err
+	}
+	removed, err := store.Prune(runcache.PruneOptions{
+		OlderThan:  opts.OlderThan,
+		MaxEntries: opts.MaxEntries,
+		DryRun:     opts.DryRun,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return cachePruneReport{DryRun: opts.DryRun, Removed: removed}, nil
+}
+
+func formatCacheBytes(n int64) string {
+	switch {
+	case n >= 1<<20:
+		return fmt.Sprintf("%.1f MB", float64(n)/(1<<20))
+	case n >= 1<<10:
+		return fmt.Sprintf("%.1f KB", float64(n)/(1<<10))
+	default:
+		return fmt.Sprintf("%d B", n)
+	}
+}
+
+func init() {
+	clicky.AddNamedCommand("serve", cacheCmd, CacheServeOptions{}, runCacheServe)
+	clicky.AddNamedCommand("stats", cacheCmd, CacheStatsOptions{}, runCacheStats)
+	clicky.AddNamedCommand("inspect", cacheCmd, CacheInspectOptions{}, runCacheInspect)
+	clicky.AddNamedCommand("prune", cacheCmd, CachePruneOptions{}, runCachePrune)
+}
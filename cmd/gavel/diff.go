@@ -0,0 +1,69 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/flanksource/clicky"
+	"github.com/flanksource/commons/duration"
+	"github.com/flanksource/gavel/baseline"
+	testui "github.com/flanksource/gavel/testrunner/ui"
+)
+
+type DiffOptions struct {
+	Files       []string          `json:"files,omitempty" args:"true"`
+	SlowerPct   int               `flag:"slower" help:"Percent a passing test must slow down by to be reported as slower" default:"50"`
+	MinSlowdown duration.Duration `flag:"min-slowdown" help:"Ignore slowdowns smaller than this" default:"100ms"`
+}
+
+func (DiffOptions) Help() string {
+	return `Compare two gavel test results and report what changed between them.
+
+Tests are matched by framework, package and full name, the key --baseline uses,
+and classified as newly failing, fixed, still failing, added, removed or
+significantly slower. Quarantined failures do not count as failing. Lint
+violations are matched by linter, file and rule and classified as new, fixed
+or remaining.
+
+Each input is a file written by gavel test --format json=<file>; plain test
+arrays are accepted too. The first file is the base, the second the head.
+
+  gavel diff main.json branch.json
+  gavel diff main.json branch.json --format markdown > comment.md
+  gavel ui serve --compare main.json branch.json`
+}
+
+func runDiff(opts DiffOptions) (any, error) {
+	if len(opts.Files) != 2 {
+		return nil, fmt.Errorf("exactly two result files are required (base and head), got %d", len(opts.Files))
+	}
+	_, diff, err := loadRunDiff(opts.Files[0], opts.Files[1], baseline.CompareOptions{
+		SlowerPct:   opts.SlowerPct,
+		MinSlowdown: time.Duration(opts.MinSlowdown),
+	})
+	return diff, err
+}
+
+// loadRunDiff compares the results in headPath against basePath, labelling
+// each side with its file name. The head snapshot is returned alongside so
+// callers can display it.
+func loadRunDiff(basePath, headPath string, opts baseline.CompareOptions) (testui.Snapshot, *testui.RunDiff, error) {
+	base, err := loadShardSnapshot(basePath)
+	if err != nil {
+		return testui.Snapshot{}, nil, err
+	}
+	head, err := loadShardSnapshot(headPath)
+	if err != nil {
+		return testui.Snapshot{}, nil, err
+	}
+	diff := baseline.Compare(&base, &head, opts)
+	diff.Base.Label = filepath.Base(basePath)
+	diff.Head.Label = filepath.Base(headPath)
+	return head, diff, nil
+}
+
+func init() {
+	cmd := clicky.AddNamedCommand("diff", rootCmd, DiffOptions{}, runDiff)
+	cmd.Short = "Compare two test runs: newly failing, fixed, slower, lint changes"
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/flanksource/clicky/formatters"
+)
+
+// gavelFormats are the --format names gavel registers itself. clicky only
+// accepts its built-in names in a --format spec, so these are split out here.
+var gavelFormats = map[string]bool{
+	"sarif": true,
+	"junit": true,
+	"tap":   true,
+}
+
+// resolveFormatSinks parses a --format spec that names a gavel format and
+// stores the result in o.Sinks. clicky skips its own parsing (and the name
+// check that would reject the spec) once Sinks is set, and dispatches each
+// sink to the registered formatter. Specs without gavel formats are left to
+// clicky.
+func resolveFormatSinks(o *formatters.FormatOptions) error {
+	spec := strings.TrimSpace(o.Format)
+	if spec == "" || len(o.Sinks) > 0 {
+		return nil
+	}
+	var sinks []formatters.FormatSink
+	var custom bool
+	stdout := 0
+	for _, raw := range strings.Split(spec, ",") {
+		part := strings.TrimSpace(raw)
+		if part == "" {
+			continue
+		}
+		name, file, hasFile := strings.Cut(part, "=")
+		name, file = strings.TrimSpace(name), strings.TrimSpace(file)
+		if !hasFile {
+			stdout++
+		}
+		if !gavelFormats[name] {
+			// Let clicky validate and canonicalise its own formats.
+			parsed := formatters.FormatOptions{Format: part}
+			if err := parsed.ParseFormatSpec(); err != nil {
+				return err
+			}
+			sinks = append(sinks, parsed.Sinks...)
+			continue
+		}
+		if hasFile && file == "" {
+			return fmt.Errorf("invalid --format entry %q: empty file path after '='", part)
+		}
+		custom = true
+		sinks = append(sinks, formatters.FormatSink{Format: name, File: file})
+	}
+	if !custom {
+		return nil
+	}
+	if stdout > 1 {
+		return fmt.Errorf("invalid --format %q: more than one stdout format specified", spec)
+	}
+	o.Sinks = sinks
+	return nil
+}
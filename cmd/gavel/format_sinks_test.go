@@ -0,0 +1,124 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/flanksource/clicky/formatters"
+	"github.com/flanksource/clicky/task"
+	"github.com/flanksource/gavel/fixtures"
+	"github.com/flanksource/gavel/linters"
+	"github.com/flanksource/gavel/models"
+	"github.com/flanksource/gavel/testreport"
+	"github.com/flanksource/gavel/testrunner/parsers"
+	testui "github.com/flanksource/gavel/testrunner/ui"
+)
+
+func TestResolveFormatSinks(t *testing.T) {
+	tests := []struct {
+		spec    string
+		want    []formatters.FormatSink
+		wantErr bool
+	}{
+		{spec: "json"},
+		{spec: "pretty,json=out.json"},
+		{spec: "sarif", want: []formatters.FormatSink{{Format: "sarif"}}},
+		{
+			spec: "pretty, sarif=gavel.sarif ,md=summary.md",
+			want: []formatters.FormatSink{
+				{Format: "pretty"},
+				{Format: "sarif", File: "gavel.sarif"},
+				{Format: "markdown", File: "summary.md"},
+			},
+		},
+		{
+			spec: "pretty,junit=report.xml,tap=report.tap",
+			want: []formatters.FormatSink{
+				{Format: "pretty"},
+				{Format: "junit", File: "report.xml"},
+				{Format: "tap", File: "report.tap"},
+			},
+		},
+		{spec: "sarif,json", wantErr: true},
+		{spec: "sarif=", wantErr: true},
+		{spec: "sarif=x.sarif,bogus", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			opts := formatters.FormatOptions{Format: tt.spec}
+			err := resolveFormatSinks(&opts)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got sinks %v", opts.Sinks)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(opts.Sinks) != len(tt.want) {
+				t.Fatalf("sinks = %v, want %v", opts.Sinks, tt.want)
+			}
+			for i := range tt.want {
+				if opts.Sinks[i] != tt.want[i] {
+					t.Fatalf("sinks = %v, want %v", opts.Sinks, tt.want)
+				}
+			}
+			if opts.Format != tt.spec {
+				t.Fatalf("Format rewritten to %q; isPrettyFormat relies on the raw spec", opts.Format)
+			}
+		})
+	}
+}
+
+func TestToSARIFFromTestSnapshot(t *testing.T) {
+	snap := testui.Snapshot{Lint: []*linters.LinterResult{{
+		Linter:     "golangci-lint",
+		WorkDir:    "/repo",
+		Violations: []models.Violation{{File: "a.go", Line: 2, Rule: &models.Rule{Method: "errcheck"}}},
+	}}}
+	log, err := toSARIF([]interface{}{snap}, "/repo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(log.Runs) != 1 || len(log.Runs[0].Results) != 1 || log.Runs[0].Results[0].RuleID != "errcheck" {
+		t.Fatalf("unexpected log: %+v", log)
+	}
+	if _, err := toSARIF(42, "/repo"); err == nil {
+		t.Fatal("expected an error for unsupported data")
+	}
+}
+
+func TestToReportCases(t *testing.T) {
+	snap := testui.Snapshot{
+		Tests: []parsers.Test{{Name: "TestA", Package: "example.com/a", Failed: true, Message: "boom"}},
+		Lint: []*linters.LinterResult{{
+			Linter:     "golangci-lint",
+			WorkDir:    "/repo",
+			Violations: []models.Violation{{File: "a.go", Line: 2, Rule: &models.Rule{Method: "errcheck"}}},
+		}},
+	}
+	cases, err := toReportCases([]interface{}{snap}, "/repo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cases) != 2 || cases[0].Suite != "example.com/a" || cases[1].Suite != testreport.LintSuite {
+		t.Fatalf("unexpected cases: %+v", cases)
+	}
+
+	tree := fixtures.FixtureNode{Name: "cli.md", Type: fixtures.FileNode, Children: []*fixtures.FixtureNode{{
+		Name:    "prints help",
+		Type:    fixtures.TestNode,
+		Results: &fixtures.FixtureResult{Name: "prints help", Status: task.StatusPASS},
+	}}}
+	cases, err = toReportCases(tree, "/repo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cases) != 1 || cases[0].Suite != "cli.md" || cases[0].Status != testreport.StatusPassed {
+		t.Fatalf("unexpected fixture cases: %+v", cases)
+	}
+
+	if _, err := toReportCases(42, "/repo"); err == nil {
+		t.Fatal("expected an error for unsupported data")
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/flanksource/clicky/api"
 	"github.com/flanksource/clicky/formatters"
 	"github.com/flanksource/gavel/linters"
+	"github.com/flanksource/gavel/testrunner/coverage"
 	"github.com/flanksource/gavel/testrunner/parsers"
 	testui "github.com/flanksource/gavel/testrunner/ui"
 )
@@ -42,19 +43,25 @@ type htmlPackageSummary struct {
 	Pending   int
 	Duration  time.Duration
 	Failures  []parsers.Test
+	// Flaky and Quarantined are reported as cards only; quarantined
+	// failures are excluded from Failed.
+	Flaky       int
+	Quarantined int
 }
 
 func renderGavelHTMLReport(s testui.Snapshot) string {
 	pkgs := collectHTMLPackageSummaries(s.Tests)
 	lintFailures := collectHTMLLintFailures(s.Lint)
 
-	var totalPassed, totalFailed, totalSkipped, totalPending int
+	var totalPassed, totalFailed, totalSkipped, totalPending, totalFlaky, totalQuarantined int
 	var totalDuration time.Duration
 	for _, pkg := range pkgs {
 		totalPassed += pkg.Passed
 		totalFailed += pkg.Failed
 		totalSkipped += pkg.Skipped
 		totalPending += pkg.Pending
+		totalFlaky += pkg.Flaky
+		totalQuarantined += pkg.Quarantined
 		totalDuration += pkg.Duration
 	}
 
@@ -63,7 +70,7 @@ func renderGavelHTMLReport(s testui.Snapshot) string {
 		body = append(body, el("div", attrs("class", "muted"), txt(s.Git.Repo+" @ "+shortSHA(s.Git.SHA))))
 	}
 
-	body = append(body, el("div", attrs("class", "cards"),
+	cards := []api.Textable{
 		metricCard("Passed", totalPassed, "ok"),
 		metricCard("Failed", totalFailed, "fail"),
 		metricCard("Skipped", totalSkipped, "skip"),
@@ -72,7 +79,20 @@ func renderGavelHTMLReport(s testui.Snapshot) string {
 			el("div", attrs("class", "muted"), txt("Duration")),
 			el("div", attrs("class", "metric"), txt(formatHTMLDuration(totalDuration))),
 		),
-	))
+	}
+	if totalFlaky > 0 {
+		cards = append(cards, metricCard("Flaky", totalFlaky, "skip"))
+	}
+	if totalQuarantined > 0 {
+		cards = append(cards, metricCard("Quarantined", totalQuarantined, "pending"))
+	}
+	if s.Coverage != nil {
+		cards = append(cards, coverageCard("Coverage", s.Coverage.Summary, false))
+		if d := s.Coverage.Diff; d != nil {
+			cards = append(cards, coverageCard("Diff coverage", d.Summary, d.Failed()))
+		}
+	}
+	body = append(body, el("div", attrs("class", "cards"), cards...))
 
 	body = append(body,
 		el("h2", nil, txt("Packages")),
@@ -109,6 +129,10 @@ func renderGavelHTMLReport(s testui.Snapshot) string {
 		))
 	}
 
+	if s.Coverage != nil {
+		body = append(body, coverageSection(s.Coverage)...)
+	}
+
 	return renderHTML(
 		raw("<!doctype html>"),
 		el("html", nil,
@@ -152,8 +176,13 @@ func collectHTMLPackageSummaries(tests []parsers.Test) []htmlPackageSummary {
 		}
 		if t.Passed {
 			p.Passed++
+			if t.Flaky {
+				p.Flaky++
+			}
 		}
-		if t.Failed || t.TimedOut {
+		if t.Quarantined && (t.Failed || t.TimedOut) {
+			p.Quarantined++
+		} else if t.Failed || t.TimedOut {
 			p.Failed++
 			p.Failures = append(p.Failures, t)
 		}
@@ -272,6 +301,76 @@ func metricCard(label string, value int, cls string) api.Textable {
 	)
 }
 
+func coverageCard(label string, summary coverage.Summary, failed bool) api.Textable {
+	cls := "ok"
+	if failed {
+		cls = "fail"
+	}
+	return el("div", attrs("class", "card"),
+		el("div", attrs("class", "muted"), txt(label)),
+		el("div", attrs("class", "metric "+cls), txt(fmt.Sprintf("%.1f%%", summary.Percent))),
+	)
+}
+
+// coverageSection renders per-package coverage and, when present, the files
+// whose changed lines are not fully covered.
+func coverageSection(report *coverage.Report) []api.Textable {
+	out := []api.Textable{el("h2", nil, txt("Coverage"))}
+	if d := report.Diff; d != nil && len(d.Files) > 0 {
+		title := fmt.Sprintf("Changed lines: %.1f%% (%d/%d)", d.Percent, d.Covered, d.Total)
+		if d.Threshold > 0 {
+			title += fmt.Sprintf(", threshold %d%%", d.Threshold)
+		}
+		rows := make([]api.Textable, 0, len(d.Files))
+		for _, f := range d.Files {
+			rows = append(rows, el("tr", nil,
+				el("td", attrs("class", "package"), txt(f.File)),
+				el("td", attrs("class", "num"), txt(fmt.Sprintf("%.1f%%", f.Percent))),
+				el("td", nil, txt(formatLineList(f.Uncovered))),
+			))
+		}
+		out = append(out,
+			el("div", attrs("class", "muted"), txt(title)),
+			el("table", nil,
+				el("thead", nil,
+					el("tr", nil,
+						el("th", nil, txt("File")),
+						el("th", attrs("class", "num"), txt("Covered")),
+						el("th", nil, txt("Uncovered lines")),
+					),
+				),
+				el("tbody", nil, rows...),
+			),
+		)
+	}
+	rows := make([]api.Textable, 0, len(report.Packages))
+	for _, pkg := range report.Packages {
+		rows = append(rows, el("tr", nil,
+			el("td", attrs("class", "package"), txt(pkg.Package)),
+			el("td", attrs("class", "num"), txt(fmt.Sprintf("%d/%d", pkg.Covered, pkg.Total))),
+			el("td", attrs("class", "num"), txt(fmt.Sprintf("%.1f%%", pkg.Percent))),
+		))
+	}
+	return append(out, el("table", nil,
+		el("thead", nil,
+			el("tr", nil,
+				el("th", nil, txt("Package")),
+				el("th", attrs("class", "num"), txt("Lines")),
+				el("th", attrs("class", "num"), txt("Covered")),
+			),
+		),
+		el("tbody", nil, rows...),
+	))
+}
+
+func formatLineList(lines []int) string {
+	parts := make([]string, len(lines))
+	for i, l := range lines {
+		parts[i] = fmt.Sprintf("%d", l)
+	}
+	return strings.Join(parts, ", ")
+}
+
 func packageStatus(pkg htmlPackageSummary) (string, string) {
 	if pkg.Failed > 0 {
 		return "✗", "fail"
@@ -5,6 +5,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/flanksource/gavel/testrunner/coverage"
 	"github.com/flanksource/gavel/testrunner/parsers"
 	testui "github.com/flanksource/gavel/testrunner/ui"
 )
@@ -46,3 +47,31 @@ func TestRenderGavelHTMLReportShowsPackageTableAndFailureTrace(t *testing.T) {
 		}
 	}
 }
+
+func TestRenderGavelHTMLReportShowsCoverage(t *testing.T) {
+	report := coverage.NewReport(coverage.Profile{
+		"pkg/foo/foo.go": {1: 1, 2: 0, 3: 1, 4: 1},
+	})
+	report.Diff = &coverage.DiffCoverage{
+		Summary:   coverage.Summary{Covered: 1, Total: 2, Percent: 50},
+		Threshold: 80,
+		Files: []coverage.DiffFileCoverage{{
+			File:      "pkg/foo/foo.go",
+			Summary:   coverage.Summary{Covered: 1, Total: 2, Percent: 50},
+			Uncovered: []int{2},
+		}},
+	}
+
+	html := renderGavelHTMLReport(testui.Snapshot{Coverage: report})
+	for _, want := range []string{
+		"<h2>Coverage</h2>",
+		"Diff coverage",
+		"75.0%",
+		"threshold 80%",
+		"pkg/foo/foo.go",
+	} {
+		if !strings.Contains(html, want) {
+			t.Fatalf("HTML missing %q:\n%s", want, html)
+		}
+	}
+}
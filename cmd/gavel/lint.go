@@ -21,12 +21,14 @@ import (
 	"github.com/flanksource/commons/logger"
 	"github.com/flanksource/gavel/baseline"
 	"github.com/flanksource/gavel/linters"
+	"github.com/flanksource/gavel/linters/arch"
 	"github.com/flanksource/gavel/linters/betterleaks"
 	"github.com/flanksource/gavel/linters/eslint"
 	"github.com/flanksource/gavel/linters/golangci"
 	"github.com/flanksource/gavel/linters/jscpd"
 	"github.com/flanksource/gavel/linters/markdownlint"
 	"github.com/flanksource/gavel/linters/pyright"
+	"github.com/flanksource/gavel/linters/quality"
 	"github.com/flanksource/gavel/linters/ruff"
 	"github.com/flanksource/gavel/linters/tsc"
 	"github.com/flanksource/gavel/linters/vale"
@@ -40,27 +42,32 @@ import (
 )
 
 type LintOptions struct {
-	Linters      []string        `flag:"linters" help:"Only run the named linters (comma-separated or repeated). Empty = run every detected linter. Unknown names hard-fail."`
-	Ignore       []string        `flag:"ignore" help:"Glob patterns to exclude from linting"`
-	Triage       bool            `flag:"triage" help:"Interactive mode to select violation types to ignore"`
-	Fix          bool            `flag:"fix" help:"Enable auto-fixing"`
-	NoCache      bool            `flag:"no-lint-cache" help:"Disable linter result caching/debounce"`
-	Timeout      string          `flag:"timeout" help:"Timeout per linter (e.g. 5m, 30s)" default:"5m"`
-	SyncTodos    string          `flag:"sync-todos" help:"Sync violations to TODO files in directory (default: .todos/lint)"`
-	GroupBy      string          `flag:"group-by" help:"Group synced TODOs by: file, package, message" default:"file"`
-	WorkDir      string          `flag:"work-dir" help:"Working directory"`
-	Changed      bool            `flag:"changed" help:"Only report new issues vs origin/main (or $GAVEL_CHANGED_BASE)"`
-	Since        string          `flag:"since" help:"Only report new issues since <ref> (merge-base with HEAD)"`
-	UI           bool            `flag:"ui" help:"Launch browser UI to view violations"`
-	Addr         string          `flag:"addr" help:"Interface to bind --ui HTTP server. Use 0.0.0.0 to expose on the LAN." default:"localhost"`
-	DryRun       bool            `flag:"dry-run" help:"Print the linter commands that would run without executing them"`
-	Baseline     string          `flag:"baseline" help:"Path to previous results JSON; only report NEW violations not in baseline"`
-	Failed       string          `flag:"failed" help:"Path to previous results JSON; re-run only linters/files that had violations"`
-	Summary      bool            `flag:"summary" help:"Collapse output: group by linter -> rule, show count and the first --summary-limit locations"`
-	SummaryLimit int             `flag:"summary-limit" help:"Max example locations shown per rule in --summary mode" default:"5"`
-	Files        []string        `args:"true"`
-	OutputTee    io.Writer       `json:"-"`
-	Context      context.Context `json:"-"`
+	Linters        []string        `flag:"linters" help:"Only run the named linters (comma-separated or repeated). Empty = run every detected linter. Unknown names hard-fail."`
+	Ignore         []string        `flag:"ignore" help:"Glob patterns to exclude from linting"`
+	Triage         bool            `flag:"triage" help:"Interactive mode to select violation types to ignore"`
+	Fix            bool            `flag:"fix" help:"Enable auto-fixing"`
+	Preview        bool            `flag:"preview" help:"With --fix, run the fixers on a temporary worktree and review their diff hunk by hunk instead of editing files"`
+	NoCache        bool            `flag:"no-lint-cache" help:"Disable linter result caching/debounce"`
+	Timeout        string          `flag:"timeout" help:"Timeout per linter (e.g. 5m, 30s)" default:"5m"`
+	SyncTodos      string          `flag:"sync-todos" help:"Sync violations to TODO files in directory (default: .todos/lint)"`
+	GroupBy        string          `flag:"group-by" help:"Group synced TODOs by: file, package, message" default:"file"`
+	WorkDir        string          `flag:"work-dir" help:"Working directory"`
+	Changed        bool            `flag:"changed" help:"Only report new issues vs origin/main (or $GAVEL_CHANGED_BASE)"`
+	Since          string          `flag:"since" help:"Only report new issues since <ref> (merge-base with HEAD)"`
+	ChangedLines   bool            `flag:"changed-lines" help:"Only report violations on lines added or modified since the --changed/--since base (the uncommitted changes when neither is set)"`
+	LinesContext   int             `flag:"changed-lines-context" help:"With --changed-lines, also keep violations within N lines of a change"`
+	UI             bool            `flag:"ui" help:"Launch browser UI to view violations"`
+	Addr           string          `flag:"addr" help:"Interface to bind --ui HTTP server. Use 0.0.0.0 to expose on the LAN." default:"localhost"`
+	DryRun         bool            `flag:"dry-run" help:"Print the linter commands that would run without executing them"`
+	Baseline       string          `flag:"baseline" help:"Path to previous results JSON; only report NEW violations not in baseline"`
+	UpdateBaseline bool            `flag:"update-baseline" help:"Record the current violations in .gavel-baseline.json at the git root; later runs suppress them automatically"`
+	Failed         string          `flag:"failed" help:"Path to previous results JSON; re-run only linters/files that had violations"`
+	Summary        bool            `flag:"summary" help:"Collapse output: group by linter -> rule, show count and the first --summary-limit locations"`
+	SummaryLimit   int             `flag:"summary-limit" help:"Max example locations shown per rule in --summary mode" default:"5"`
+	Watch          bool            `flag:"watch" help:"After the run, re-lint each saved file until interrupted"`
+	Files          []string        `args:"true"`
+	OutputTee      io.Writer       `json:"-"`
+	Context        context.Context `json:"-"`
 
 	AIFix         bool `flag:"ai-fix" help:"Invoke the AI configured by 'captain configure' to fix violations and re-lint until clean (or bounded by --ai-fix-max-iterations / --budget)"`
 	AIFixMaxIters int  `flag:"ai-fix-max-iterations" help:"Max AI→re-lint cycles" default:"3"`
@@ -115,8 +122,12 @@ Examples:
   gavel lint --linters=golangci-lint
   gavel lint --linters=golangci-lint,ruff
   gavel lint --fix
+  gavel lint --fix --preview        # review fixes as a diff, apply hunk by hunk
   gavel lint --triage
   gavel lint -y                      # auto-AI-fix violations (implies --ai-fix)
+  gavel lint --watch                 # re-lint files as they are saved
+  gavel lint --since origin/main --changed-lines   # only violations on lines this branch touched
+  gavel lint --update-baseline       # accept current violations into .gavel-baseline.json
   gavel lint ./pkg/...`
 }
 
@@ -154,6 +165,13 @@ func runLint(opts LintOptions) (any, error) {
 	defer cancelRun()
 	opts.Context = runCtx
 
+	if opts.Watch && (opts.Triage || resolveAIFix(opts)) {
+		return nil, fmt.Errorf("--watch cannot be combined with --triage or --ai-fix")
+	}
+	if opts.Preview && !opts.Fix {
+		return nil, fmt.Errorf("--preview requires --fix")
+	}
+
 	if opts.DryRun {
 		groups := groupFilesByGitRoot(opts)
 		for _, g := range groups {
@@ -214,6 +232,12 @@ func runLint(opts LintOptions) (any, error) {
 	if uiServer != nil {
 		uiServer.SetGitRoot(opts.WorkDir)
 	}
+	if opts.Preview {
+		if err := reviewFixes(opts, groups); err != nil {
+			return nil, err
+		}
+		opts.Fix = false
+	}
 	logger.Infof("Running linters %s", opts.Pretty().ANSI())
 
 	var allResults []*linters.LinterResult
@@ -225,6 +249,15 @@ func runLint(opts LintOptions) (any, error) {
 		if err != nil {
 			return nil, err
 		}
+		scope := baseline.MergeScope{Paths: g.files, Incremental: lintBaseRef(opts) != ""}
+		if err := applyBaselineFile(results, g.gitRoot, opts.UpdateBaseline, scope); err != nil {
+			return nil, err
+		}
+		if opts.ChangedLines {
+			if err := filterToChangedLines(results, g.gitRoot, lintBaseRef(opts), opts.LinesContext); err != nil {
+				return nil, err
+			}
+		}
 		allResults = append(allResults, results...)
 	}
 
@@ -257,6 +290,9 @@ func runLint(opts LintOptions) (any, error) {
 		if violations > 0 {
 			exitCode = 1
 		}
+		if opts.Watch {
+			return nil, watchLint(opts, allResults)
+		}
 		sig := make(chan os.Signal, 1)
 		signal.Notify(sig, syscall.SIGINT, syscall.SIGTERM)
 		<-sig
@@ -313,6 +349,14 @@ func runLint(opts LintOptions) (any, error) {
 		logger.V(1).Infof("wrote snapshot to %s", path)
 	}
 
+	if opts.Watch {
+		if opts.Summary {
+			clicky.MustPrint(newLintSummaryView(allResults, opts.SummaryLimit), clicky.FormatOptions{})
+		} else {
+			clicky.MustPrint(allResults, clicky.FormatOptions{})
+		}
+		return nil, watchLint(opts, allResults)
+	}
 	if opts.Summary {
 		return newLintSummaryView(allResults, opts.SummaryLimit), nil
 	}
@@ -366,7 +410,8 @@ func shouldRunLinter(workDir string, cfg verify.GavelConfig, linterName string,
 	return true, ""
 }
 
-// buildLinterRegistry registers every available linter rooted at workDir.
+// buildLinterRegistry registers every available linter rooted at workDir,
+// including the lint.custom declarations from .gavel.yaml.
 // Shared between the execute path and the dry-run path so both stay in sync.
 func buildLinterRegistry(workDir string) *linters.Registry {
 	registry := linters.NewRegistry()
@@ -379,6 +424,9 @@ func buildLinterRegistry(workDir string) *linters.Registry {
 	registry.Register(vale.NewVale(workDir))
 	registry.Register(jscpd.NewJSCPD(workDir))
 	registry.Register(betterleaks.NewBetterleaks(workDir))
+	registry.Register(arch.NewArch(workDir))
+	registry.Register(quality.NewQuality(workDir))
+	registerCustomLinters(registry, workDir)
 	return registry
 }
 
@@ -454,16 +502,22 @@ func executeLinters(opts LintOptions) ([]*linters.LinterResult, error) {
 		opts.WorkDir, _ = os.Getwd()
 	}
 
-	timeout, err := time.ParseDuration(opts.Timeout)
-	if err != nil {
-		timeout = models.DefaultLinterTimeout
-	}
-
 	registry := buildLinterRegistry(opts.WorkDir)
 	requestedLinters, explicit, err := resolveRequestedLinters(registry, opts.Linters)
 	if err != nil {
 		return nil, err
 	}
+	return executeSelectedLinters(opts, registry, requestedLinters, explicit)
+}
+
+// executeSelectedLinters runs the named linters of registry in opts.WorkDir.
+// explicit reports whether the names were requested by the user, which skips
+// config-based detection the same way --linters does.
+func executeSelectedLinters(opts LintOptions, registry *linters.Registry, requestedLinters []string, explicit bool) ([]*linters.LinterResult, error) {
+	timeout, err := time.ParseDuration(opts.Timeout)
+	if err != nil {
+		timeout = models.DefaultLinterTimeout
+	}
 
 	// Resolve the merge-base once per git-root so every per-module golangci
 	// invocation shares the same --new-from-rev target.
@@ -533,7 +587,7 @@ func executeLinters(opts LintOptions) ([]*linters.LinterResult, error) {
 				Ignores:    opts.Ignore,
 				Fix:        opts.Fix,
 				NoCache:    opts.NoCache,
-				Timeout:    timeout,
+				Timeout:    linterTimeout(linter, timeout),
 				ForceJSON:  true,
 				OutputTee:  opts.OutputTee,
 			}
@@ -747,7 +801,7 @@ func displayLintDryRun(opts LintOptions) error {
 				Ignores:    opts.Ignore,
 				Fix:        opts.Fix,
 				NoCache:    opts.NoCache,
-				Timeout:    timeout,
+				Timeout:    linterTimeout(linter, timeout),
 				ForceJSON:  true,
 			}
 			if linter.Name() == "golangci-lint" {
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+
+	"github.com/flanksource/commons/logger"
+	"github.com/flanksource/gavel/baseline"
+	"github.com/flanksource/gavel/linters"
+)
+
+// applyBaselineFile suppresses the violations recorded in root's
+// .gavel-baseline.json and reports the entries that no longer match
+// anything. With update, the file is first rewritten from results (keeping
+// the entries outside scope), so the run reports nothing.
+func applyBaselineFile(results []*linters.LinterResult, root string, update bool, scope baseline.MergeScope) error {
+	path := filepath.Join(root, baseline.FileName)
+	existing, err := baseline.LoadFile(path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		if !update {
+			return err
+		}
+		logger.Warnf("Replacing unreadable baseline: %v", err)
+	}
+
+	if update {
+		updated := baseline.NewFile(results, root)
+		updated.Merge(existing, results, scope)
+		if err := updated.Save(path); err != nil {
+			return fmt.Errorf("--update-baseline: %w", err)
+		}
+		logger.Infof("Wrote %d baseline entries to %s", len(updated.Entries), path)
+		baseline.Apply(results, root, updated, nil)
+		return nil
+	}
+	if existing == nil {
+		return nil
+	}
+
+	renames, err := baseline.Renames(root)
+	if err != nil {
+		logger.Debugf("baseline rename detection unavailable: %v", err)
+	}
+	suppressed, fixed := baseline.Apply(results, root, existing, renames)
+	if suppressed > 0 {
+		logger.Infof("Suppressed %d violations recorded in %s", suppressed, baseline.FileName)
+	}
+	if len(fixed) > 0 {
+		logger.Infof("%d %s entries are fixed; run gavel lint --update-baseline to prune them", len(fixed), baseline.FileName)
+		for _, e := range fixed {
+			logger.Debugf("fixed baseline entry: %s %s %s:%d", e.Linter, e.Rule, e.File, e.Line)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/flanksource/commons/logger"
+	"github.com/flanksource/gavel/internal/changegraph"
+	"github.com/flanksource/gavel/linters"
+)
+
+// filterToChangedLines implements --changed-lines: it drops the violations
+// in results that are not on, or within radius lines of, a line added or
+// modified in root since merge-base(HEAD, base). An empty base compares
+// against HEAD, i.e. only the uncommitted changes count. Unlike --baseline,
+// which matches on file and rule, this is line-granular, so touching one line
+// of a legacy file does not resurface its existing violations.
+func filterToChangedLines(results []*linters.LinterResult, root, base string, radius int) error {
+	changed, err := changegraph.ComputeLineSet(root, changegraph.DiffOptions{
+		Since:            base,
+		IncludeStaged:    true,
+		IncludeUnstaged:  true,
+		IncludeUntracked: true,
+	})
+	if err != nil {
+		return fmt.Errorf("--changed-lines: %w", err)
+	}
+	if dropped := linters.FilterViolationsToChangedLines(results, root, changed, radius); dropped > 0 {
+		logger.Infof("Filtered %d violations outside changed lines in %s", dropped, root)
+	}
+	return nil
+}
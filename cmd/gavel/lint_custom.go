@@ -0,0 +1,57 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/flanksource/commons/logger"
+	"github.com/flanksource/gavel/linters"
+	"github.com/flanksource/gavel/linters/custom"
+	"github.com/flanksource/gavel/verify"
+)
+
+// registerCustomLinters adds the lint.custom declarations visible from
+// workDir. Invalid declarations are logged and skipped so the built-in
+// linters still run.
+func registerCustomLinters(registry *linters.Registry, workDir string) {
+	cfg, err := verify.LoadGavelConfig(workDir)
+	if err != nil {
+		logger.Warnf("Failed to load .gavel.yaml for custom linters: %v", err)
+		return
+	}
+	for _, err := range custom.Register(registry, workDir, cfg.Lint.Custom) {
+		logger.Warnf("Skipping %v", err)
+	}
+}
+
+// resolveCustomLinterExecutable resolves a custom linter's command: paths
+// (anything with a separator) relative to the git root, bare names on PATH.
+func resolveCustomLinterExecutable(linter *custom.Linter, gitRoot string) (string, string) {
+	command := linter.Command()
+	if !strings.ContainsAny(command, `/\`) {
+		if path, err := exec.LookPath(command); err == nil {
+			return path, ""
+		}
+		return "", "not found on PATH"
+	}
+	if !filepath.IsAbs(command) {
+		command = filepath.Join(gitRoot, command)
+	}
+	if info, err := os.Stat(command); err != nil || info.IsDir() {
+		return "", fmt.Sprintf("%s not found", command)
+	}
+	return command, ""
+}
+
+// linterTimeout returns the timeout a custom linter declares, falling back
+// to the --timeout value.
+func linterTimeout(linter linters.Linter, fallback time.Duration) time.Duration {
+	if c, ok := linter.(*custom.Linter); ok && c.Timeout() > 0 {
+		return c.Timeout()
+	}
+	return fallback
+}
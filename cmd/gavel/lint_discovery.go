@@ -8,10 +8,15 @@ import (
 	"github.com/bmatcuk/doublestar/v4"
 	"github.com/flanksource/gavel/linters"
 	"github.com/flanksource/gavel/linters/betterleaks"
+	"github.com/flanksource/gavel/linters/custom"
 	"github.com/flanksource/gavel/verify"
 )
 
 var linterDirectConfigPatterns = map[string][]string{
+	"arch": {
+		"arch.yaml",
+		"arch-unit.yaml",
+	},
 	"betterleaks": {
 		".betterleaks.toml",
 		"betterleaks.toml",
@@ -41,6 +46,10 @@ var linterDirectConfigPatterns = map[string][]string{
 		"pyrightconfig.json",
 		"pyproject.toml",
 	},
+	"quality": {
+		"arch.yaml",
+		"arch-unit.yaml",
+	},
 	"ruff": {
 		"ruff.toml",
 		"pyproject.toml",
@@ -70,7 +79,13 @@ func isLinterExplicitlyEnabled(cfg verify.GavelConfig, name string) bool {
 }
 
 func shouldSelectLinter(workDir string, cfg verify.GavelConfig, linter linters.Linter, cliExplicit bool) (bool, string) {
-	if cliExplicit {
+	// Declaring a custom linter is already an opt-in, so it runs whenever its
+	// includes match anywhere under the work dir, not just at the top level.
+	_, declared := linter.(*custom.Linter)
+	if declared && !cliExplicit && !cfg.Lint.IsLinterEnabled(linter.Name(), true) {
+		return false, "disabled via .gavel.yaml"
+	}
+	if cliExplicit || declared {
 		if !hasMatchingFiles(workDir, linter.DefaultIncludes()) {
 			return false, "no matching files"
 		}
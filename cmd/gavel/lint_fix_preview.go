@@ -0,0 +1,140 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/flanksource/commons/logger"
+	"github.com/flanksource/gavel/cmd/gavel/choose"
+	"github.com/flanksource/gavel/internal/prompting"
+	"github.com/flanksource/gavel/linters/fixpreview"
+	"github.com/flanksource/gavel/utils"
+)
+
+// reviewFixes implements --fix --preview: the fixers run in a throwaway
+// worktree, and their edits are offered hunk by hunk, in the lint UI when it
+// is open and otherwise in the terminal. Only accepted hunks reach the real
+// tree; without a terminal the diff is printed and nothing is applied.
+func reviewFixes(opts LintOptions, groups []lintGroup) error {
+	preview, err := buildFixPreview(opts, groups)
+	if err != nil {
+		return err
+	}
+	if preview.HunkCount() == 0 {
+		logger.Infof("--preview: the fixers made no changes")
+		return nil
+	}
+	if uiServer != nil {
+		uiServer.SetFixPreview(preview)
+		logger.Infof("--preview: %d fix hunks are ready for review in the lint UI", preview.HunkCount())
+		return nil
+	}
+
+	fmt.Fprint(os.Stdout, preview.Unified())
+	if !stdoutIsTerminal() {
+		logger.Infof("--preview: run in a terminal or with --ui to apply the fixes selectively")
+		return nil
+	}
+	ids, err := chooseFixHunks(preview)
+	if err != nil || len(ids) == 0 {
+		return err
+	}
+	result, err := preview.Apply(ids)
+	if err != nil {
+		return fmt.Errorf("--preview: %w", err)
+	}
+	logger.Infof("Applied %d of %d fix hunks", len(result.Applied), preview.HunkCount())
+	for id, reason := range result.Conflicts {
+		logger.Warnf("Skipped %s: %s", id, reason)
+	}
+	return nil
+}
+
+// buildFixPreview runs every fix-capable linter of each group in a sandbox
+// of its git root, one linter at a time so each hunk is attributed to the
+// linter that produced it.
+func buildFixPreview(opts LintOptions, groups []lintGroup) (*fixpreview.Preview, error) {
+	preview := &fixpreview.Preview{}
+	for _, g := range groups {
+		root := utils.FindGitRoot(g.gitRoot)
+		if root == "" {
+			return nil, fmt.Errorf("--preview: %s is not inside a git repository", g.gitRoot)
+		}
+		sandbox, err := fixpreview.NewSandbox(root)
+		if err != nil {
+			return nil, fmt.Errorf("--preview: %w", err)
+		}
+		diffs, err := previewGroupFixes(opts, g, sandbox)
+		sandbox.Close()
+		if err != nil {
+			return nil, fmt.Errorf("--preview: %w", err)
+		}
+		preview.Add(diffs...)
+	}
+	return preview, nil
+}
+
+func previewGroupFixes(opts LintOptions, g lintGroup, sandbox *fixpreview.Sandbox) ([]fixpreview.FileDiff, error) {
+	workDir := sandbox.Path(g.gitRoot)
+	registry := buildLinterRegistry(workDir)
+	names, explicit, err := resolveRequestedLinters(registry, opts.Linters)
+	if err != nil {
+		return nil, err
+	}
+
+	var diffs []fixpreview.FileDiff
+	for _, name := range names {
+		linter, ok := registry.Get(name)
+		if !ok || !linter.SupportsFix() {
+			continue
+		}
+		sandboxOpts := opts
+		sandboxOpts.WorkDir = workDir
+		sandboxOpts.Files = g.files
+		sandboxOpts.Fix = true
+		sandboxOpts.NoCache = true
+		if _, err := executeSelectedLinters(sandboxOpts, registry, []string{name}, explicit); err != nil {
+			return nil, err
+		}
+		found, err := sandbox.Collect(name)
+		if err != nil {
+			return nil, err
+		}
+		diffs = append(diffs, found...)
+	}
+	return diffs, nil
+}
+
+// chooseFixHunks lets the user pick the hunks to apply, showing each hunk's
+// diff in the detail pane.
+func chooseFixHunks(preview *fixpreview.Preview) ([]string, error) {
+	prompting.Prepare()
+
+	var items, ids []string
+	var hunks []fixpreview.Hunk
+	for _, f := range preview.Files {
+		for _, h := range f.Hunks {
+			items = append(items, fmt.Sprintf("%s  %s %s", f.Linter, f.File, h.Header()))
+			ids = append(ids, h.ID)
+			hunks = append(hunks, h)
+		}
+	}
+	selected, err := choose.Run(items,
+		choose.WithHeader("Select fixes to apply:"),
+		choose.WithLimit(0),
+		choose.WithDetailFunc(func(i int) string {
+			if i < 0 || i >= len(hunks) {
+				return ""
+			}
+			return hunks[i].Text()
+		}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	accepted := make([]string, 0, len(selected))
+	for _, i := range selected {
+		accepted = append(accepted, ids[i])
+	}
+	return accepted, nil
+}
@@ -28,6 +28,8 @@ var lintSubcommands = []struct {
 	{"jscpd", "jscpd", "Run only jscpd (duplicate-code detector)"},
 	{"betterleaks", "betterleaks", "Run only betterleaks (secret scanner)"},
 	{"secrets", "betterleaks", "Alias for betterleaks"},
+	{"arch", "arch", "Run only arch (arch.yaml import and layering rules)"},
+	{"quality", "quality", "Run only quality (arch.yaml file length, naming and comment rules)"},
 }
 
 func registerLintLinterSubcommands(parent *cobra.Command) {
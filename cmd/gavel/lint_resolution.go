@@ -12,6 +12,7 @@ import (
 	"github.com/flanksource/commons/logger"
 	deps "github.com/flanksource/deps"
 	"github.com/flanksource/gavel/linters"
+	"github.com/flanksource/gavel/linters/custom"
 	"github.com/flanksource/gavel/utils"
 )
 
@@ -99,6 +100,13 @@ func golangciInstalledPath(gitRoot string) string {
 var installGolangciLint = deps.InstallWithContext
 
 func resolveLinterExecutable(ctx context.Context, linter linters.Linter, gitRoot string, hasDirectConfig bool, dryRun bool) (string, string, error) {
+	if ip, ok := linter.(linters.InProcess); ok && ip.InProcess() {
+		return linter.Name(), "", nil
+	}
+	if c, ok := linter.(*custom.Linter); ok {
+		path, reason := resolveCustomLinterExecutable(c, gitRoot)
+		return path, reason, nil
+	}
 	if path, err := exec.LookPath(linter.Name()); err == nil {
 		return path, "", nil
 	}
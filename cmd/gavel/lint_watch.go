@@ -0,0 +1,123 @@
+package main
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+
+	"github.com/flanksource/clicky"
+	"github.com/flanksource/commons/logger"
+	"github.com/flanksource/gavel/internal/changegraph"
+	"github.com/flanksource/gavel/internal/watch"
+	"github.com/flanksource/gavel/linters"
+)
+
+// watchLint implements gavel lint --watch: after the initial run it re-lints
+// the files saved in each debounced batch and replaces their violations in
+// current. The UI shows the whole merged result set; the terminal shows each
+// iteration's results.
+func watchLint(opts LintOptions, current []*linters.LinterResult) error {
+	parent := opts.Context
+	if parent == nil {
+		parent = context.Background()
+	}
+	ctx, stop := signal.NotifyContext(parent, syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	w, err := watch.New(watch.Options{Root: opts.WorkDir})
+	if err != nil {
+		return err
+	}
+	defer w.Close()
+	logger.Infof("Watching %s for changes (Ctrl+C to stop)", opts.WorkDir)
+
+	return w.Run(ctx, func(changed changegraph.FileSet) {
+		var files []string
+		for _, f := range changed.Sorted() {
+			// Deleted files only need their old violations dropped.
+			if info, err := os.Stat(filepath.Join(opts.WorkDir, f)); err == nil && !info.IsDir() {
+				files = append(files, f)
+			}
+		}
+		logger.Infof("Changed: %s", clicky.CompactList(changed.Sorted()).String())
+
+		var results []*linters.LinterResult
+		if len(files) > 0 {
+			clicky.ClearGlobalTasks()
+			iterCtx, cancel := newStopContext(ctx, 0)
+			defer cancel()
+			iterOpts := opts
+			iterOpts.Files = files
+			iterOpts.Context = iterCtx
+			if uiServer != nil {
+				uiServer.SetStopFunc(cancel)
+				uiServer.BeginRun("rerun")
+			}
+			results, err = executeLinters(iterOpts)
+			if err != nil {
+				logger.Warnf("watch: %v", err)
+				return
+			}
+		}
+		current = mergeWatchedLintResults(current, results, changed, opts.WorkDir)
+
+		if uiServer != nil {
+			uiServer.SetLintResults(current)
+			uiServer.MarkDone()
+			return
+		}
+		clicky.WaitForGlobalCompletion()
+		if len(results) > 0 {
+			clicky.MustPrint(results, clicky.FormatOptions{})
+		}
+	})
+}
+
+// mergeWatchedLintResults drops the violations current holds for the changed
+// files and adds the fresh ones from results, matching linters by name and
+// work dir. Linters that only appear in results are appended.
+func mergeWatchedLintResults(current, results []*linters.LinterResult, changed changegraph.FileSet, workDir string) []*linters.LinterResult {
+	inChanged := func(file string) bool {
+		if filepath.IsAbs(file) {
+			rel, err := filepath.Rel(workDir, file)
+			if err != nil {
+				return false
+			}
+			file = rel
+		}
+		return changed.Has(filepath.ToSlash(filepath.Clean(file)))
+	}
+
+	byKey := map[string]*linters.LinterResult{}
+	for _, r := range current {
+		if r == nil {
+			continue
+		}
+		kept := r.Violations[:0:0]
+		for _, v := range r.Violations {
+			if !inChanged(v.File) {
+				kept = append(kept, v)
+			}
+		}
+		r.Violations = kept
+		byKey[r.Linter+"\x00"+r.WorkDir] = r
+	}
+	for _, r := range results {
+		if r == nil {
+			continue
+		}
+		prev, ok := byKey[r.Linter+"\x00"+r.WorkDir]
+		if !ok {
+			current = append(current, r)
+			byKey[r.Linter+"\x00"+r.WorkDir] = r
+			continue
+		}
+		prev.Violations = append(prev.Violations, r.Violations...)
+		prev.Error = r.Error
+		prev.TimedOut = r.TimedOut
+		prev.Success = r.Success
+	}
+	return current
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/flanksource/gavel/internal/changegraph"
+	"github.com/flanksource/gavel/linters"
+	"github.com/flanksource/gavel/models"
+)
+
+func TestMergeWatchedLintResultsReplacesChangedFiles(t *testing.T) {
+	workDir := t.TempDir()
+	current := []*linters.LinterResult{{
+		Linter:  "golangci-lint",
+		WorkDir: workDir,
+		Violations: []models.Violation{
+			{File: "pkg/a.go", Line: 1},
+			{File: filepath.Join(workDir, "pkg/b.go"), Line: 2},
+			{File: "pkg/c.go", Line: 3},
+		},
+	}}
+	fresh := []*linters.LinterResult{
+		{Linter: "golangci-lint", WorkDir: workDir, Success: true, Violations: []models.Violation{{File: "pkg/a.go", Line: 9}}},
+		{Linter: "markdownlint", WorkDir: workDir, Success: true, Violations: []models.Violation{{File: "README.md", Line: 1}}},
+	}
+	changed := changegraph.NewFileSet()
+	changed.Add("pkg/a.go")
+	changed.Add("pkg/b.go") // deleted: its violation goes, nothing replaces it
+	changed.Add("README.md")
+
+	merged := mergeWatchedLintResults(current, fresh, changed, workDir)
+
+	if len(merged) != 2 {
+		t.Fatalf("want golangci-lint + markdownlint, got %d results", len(merged))
+	}
+	var lines []int
+	for _, v := range merged[0].Violations {
+		lines = append(lines, v.Line)
+	}
+	if len(lines) != 2 || lines[0] != 3 || lines[1] != 9 {
+		t.Errorf("golangci-lint violations = %v, want the untouched c.go one plus the fresh a.go one", lines)
+	}
+	if !merged[0].Success || merged[1].Linter != "markdownlint" {
+		t.Errorf("merged = %+v", merged)
+	}
+}
@@ -25,8 +25,9 @@ var (
 var rootCmd = &cobra.Command{
 	Use:   "gavel",
 	Short: "Gavel CLI",
-	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
 		clicky.Flags.UseFlags()
+		return resolveFormatSinks(&clicky.Flags.FormatOptions)
 	},
 }
 
@@ -105,6 +106,7 @@ func gavelMCPExcludedCommands() []string {
 		// long-running servers that would block the MCP request indefinitely
 		"^ssh$", "^ssh ",
 		"^ui$", "^ui ",
+		"^cache serve$",
 		// top-level lint/test are enough; framework/linter aliases duplicate the same options
 		"^lint ",
 		"^test ",
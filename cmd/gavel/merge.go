@@ -0,0 +1,92 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/flanksource/clicky"
+	"github.com/flanksource/commons/logger"
+	"github.com/flanksource/gavel/testrunner/parsers"
+	testui "github.com/flanksource/gavel/testrunner/ui"
+)
+
+type MergeOptions struct {
+	Files []string `json:"files,omitempty" args:"true"`
+}
+
+func (MergeOptions) Help() string {
+	return `Combine the JSON results of the shards of a gavel test --shard i/N run into
+a single snapshot, so gavel summary and the HTML report describe the whole run.
+
+Each input is a file written by gavel test --format json=<file>; plain test
+arrays are accepted too. Tests are merged by framework and package, lint
+results are deduplicated, coverage is combined per file, and the exit code is
+the worst of the shards.
+
+  gavel merge shard-*.json --format json=gavel-results.json,html=gavel-results.html
+  gavel summary --input gavel-results.json`
+}
+
+func runMerge(opts MergeOptions) (any, error) {
+	if len(opts.Files) == 0 {
+		return nil, fmt.Errorf("at least one shard result file is required")
+	}
+	shards := make([]testui.Snapshot, 0, len(opts.Files))
+	var shas []string
+	for _, path := range opts.Files {
+		snap, err := loadShardSnapshot(path)
+		if err != nil {
+			return nil, err
+		}
+		if snap.Git != nil && snap.Git.SHA != "" && !slices.Contains(shas, snap.Git.SHA) {
+			shas = append(shas, snap.Git.SHA)
+		}
+		shards = append(shards, snap)
+	}
+	if len(shas) > 1 {
+		logger.Warnf("merging shards from different commits: %s", strings.Join(shas, ", "))
+	}
+	return testui.MergeSnapshots(shards), nil
+}
+
+// loadShardSnapshot reads one shard result. Crash stubs written by the
+// composite action carry an error instead of tests; they are kept as a failed
+// exit code so the merged run does not look green.
+func loadShardSnapshot(path string) (testui.Snapshot, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return testui.Snapshot{}, fmt.Errorf("read %s: %w", path, err)
+	}
+	if strings.HasPrefix(strings.TrimSpace(string(raw)), "[") {
+		var tests []parsers.Test
+		if err := json.Unmarshal(raw, &tests); err != nil {
+			return testui.Snapshot{}, fmt.Errorf("parse %s: %w", path, err)
+		}
+		return testui.Snapshot{Tests: tests}, nil
+	}
+	var snap testui.Snapshot
+	if err := json.Unmarshal(raw, &snap); err != nil {
+		return testui.Snapshot{}, fmt.Errorf("parse %s: %w", path, err)
+	}
+	var stub gavelResultJSON
+	if err := json.Unmarshal(raw, &stub); err == nil && stub.Error != "" {
+		logger.Warnf("%s: shard did not complete: %s", path, stub.Error)
+		code := 1
+		if stub.ExitCode != nil && *stub.ExitCode != 0 {
+			code = *stub.ExitCode
+		}
+		if snap.Metadata == nil {
+			snap.Metadata = &testui.SnapshotMetadata{}
+		}
+		snap.Metadata.ExitCode = &code
+	}
+	return snap, nil
+}
+
+func init() {
+	cmd := clicky.AddNamedCommand("merge", rootCmd, MergeOptions{}, runMerge)
+	cmd.Short = "Merge gavel test --shard results into one snapshot"
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/flanksource/clicky/formatters"
+	"github.com/flanksource/gavel/linters"
+	"github.com/flanksource/gavel/sarif"
+	testui "github.com/flanksource/gavel/testrunner/ui"
+	"github.com/flanksource/gavel/verify"
+)
+
+func init() {
+	formatters.RegisterFormatter("sarif", func(data interface{}, options formatters.FormatOptions) (string, error) {
+		root, _ := getWorkingDir()
+		log, err := toSARIF(data, root)
+		if err != nil {
+			return "", err
+		}
+		return log.JSON()
+	})
+}
+
+// toSARIF converts the results gavel lint, gavel test and gavel verify
+// return. Lint ignores and baselines have already been applied to them, so
+// the report holds exactly the findings gavel fails on.
+func toSARIF(data any, root string) (*sarif.Log, error) {
+	if items, ok := data.([]interface{}); ok && len(items) == 1 {
+		data = items[0]
+	}
+	switch v := data.(type) {
+	case []*linters.LinterResult:
+		return sarif.FromLint(v, root), nil
+	case *lintSummaryView:
+		return sarif.FromLint(v.Results, root), nil
+	case testui.Snapshot:
+		return sarif.FromLint(v.Lint, root), nil
+	case *testui.Snapshot:
+		if v == nil {
+			return sarif.NewLog(), nil
+		}
+		return sarif.FromLint(v.Lint, root), nil
+	case *verify.VerifyResult:
+		return sarif.FromVerify(v, root), nil
+	case *verify.FixLoopResult:
+		var last *verify.VerifyResult
+		if v != nil && len(v.Turns) > 0 {
+			last = v.Turns[len(v.Turns)-1].Result
+		}
+		return sarif.FromVerify(last, root), nil
+	case nil:
+		return sarif.NewLog(), nil
+	default:
+		return nil, fmt.Errorf("sarif output is not supported for %T", data)
+	}
+}
@@ -11,12 +11,22 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/flanksource/gavel/linters"
+	"github.com/flanksource/gavel/testrunner/coverage"
 	"github.com/flanksource/gavel/testrunner/parsers"
+	"github.com/flanksource/gavel/utils"
 )
 
 type summaryOptions struct {
 	InputPath  string
 	OutputPath string
+
+	// ChangedLines drops lint violations that are not on a line added or
+	// modified since merge-base(HEAD, Since), so PR comments only cover the
+	// author's own lines. Since defaults to the --changed base.
+	ChangedLines bool
+	Since        string
+	LinesContext int
+	WorkDir      string
 }
 
 type compactSummaryBudget struct {
@@ -36,8 +46,9 @@ var defaultCompactBudget = compactSummaryBudget{
 // summary command can read any gavel test result file without depending on
 // the internal testrunner types.
 type gavelResultJSON struct {
-	Tests []parsers.Test          `json:"tests"`
-	Lint  []*linters.LinterResult `json:"lint"`
+	Tests    []parsers.Test          `json:"tests"`
+	Lint     []*linters.LinterResult `json:"lint"`
+	Coverage *coverage.Report        `json:"coverage,omitempty"`
 	// Error / ExitCode / LogTail are populated by the composite action
 	// when gavel crashes before writing results. Stub files carry these
 	// fields so `gavel summary` can emit a useful crash marker instead
@@ -81,6 +92,19 @@ func runSummary(opts summaryOptions) error {
 	if err := json.Unmarshal(raw, &data); err != nil {
 		return fmt.Errorf("parse %s: %w", opts.InputPath, err)
 	}
+	if opts.ChangedLines && len(data.Lint) > 0 {
+		workDir := opts.WorkDir
+		if workDir == "" {
+			workDir, _ = os.Getwd()
+		}
+		if root := utils.FindGitRoot(workDir); root != "" {
+			workDir = root
+		}
+		base := lintBaseRef(LintOptions{Changed: true, Since: opts.Since})
+		if err := filterToChangedLines(data.Lint, workDir, base, opts.LinesContext); err != nil {
+			return err
+		}
+	}
 	md := buildCompactSummary(data, defaultCompactBudget)
 	if opts.OutputPath == "" {
 		_, err := os.Stdout.WriteString(md)
@@ -93,11 +117,13 @@ func runSummary(opts summaryOptions) error {
 }
 
 type sourceCounts struct {
-	name     string
-	passed   int
-	failed   int
-	skipped  int
-	duration time.Duration
+	name        string
+	passed      int
+	failed      int
+	skipped     int
+	flaky       int
+	quarantined int
+	duration    time.Duration
 }
 
 func buildCompactSummary(data gavelResultJSON, budget compactSummaryBudget) string {
@@ -135,6 +161,7 @@ func buildCompactSummary(data gavelResultJSON, budget compactSummaryBudget) stri
 	var b strings.Builder
 	writeCountsTable(&b, sources)
 	writeTotals(&b, sources)
+	writeCoverage(&b, data.Coverage, budget)
 	writeFailingTests(&b, failures, budget)
 	writeFailingLinters(&b, failingLinters, budget)
 	return b.String()
@@ -186,6 +213,8 @@ func walkTests(t parsers.Test, sources map[string]*sourceCounts, failures *[]par
 	sc := ensureSource(sources, source)
 	sc.duration += t.Duration
 	switch {
+	case t.Failed && t.Quarantined:
+		sc.quarantined++
 	case t.Failed:
 		sc.failed++
 		*failures = append(*failures, t)
@@ -193,6 +222,9 @@ func walkTests(t parsers.Test, sources map[string]*sourceCounts, failures *[]par
 		sc.skipped++
 	case t.Passed:
 		sc.passed++
+		if t.Flaky {
+			sc.flaky++
+		}
 	}
 }
 
@@ -260,10 +292,71 @@ func writeTotals(b *strings.Builder, sources map[string]*sourceCounts) {
 		totals.passed += sc.passed
 		totals.failed += sc.failed
 		totals.skipped += sc.skipped
+		totals.flaky += sc.flaky
+		totals.quarantined += sc.quarantined
 		totals.duration += sc.duration
 	}
-	fmt.Fprintf(b, "**Totals:** %d passed · %d failed · %d skipped · %s\n\n",
-		totals.passed, totals.failed, totals.skipped, formatDuration(totals.duration))
+	fmt.Fprintf(b, "**Totals:** %d passed · %d failed · %d skipped", totals.passed, totals.failed, totals.skipped)
+	// Flaky tests are already counted as passed and quarantined failures do
+	// not fail the run; both are called out so they are not forgotten.
+	if totals.flaky > 0 {
+		fmt.Fprintf(b, " · %d flaky", totals.flaky)
+	}
+	if totals.quarantined > 0 {
+		fmt.Fprintf(b, " · %d quarantined", totals.quarantined)
+	}
+	fmt.Fprintf(b, " · %s\n\n", formatDuration(totals.duration))
+}
+
+// writeCoverage reports total and diff coverage. When a --min-diff-coverage
+// threshold was set the diff line states whether it passed, and the files
+// with uncovered changed lines are listed so reviewers know where to look.
+func writeCoverage(b *strings.Builder, report *coverage.Report, budget compactSummaryBudget) {
+	if report == nil {
+		return
+	}
+	b.WriteString("### Coverage\n\n")
+	if d := report.Diff; d != nil {
+		fmt.Fprintf(b, "**Diff coverage:** %.1f%% (%d/%d changed lines)", d.Percent, d.Covered, d.Total)
+		switch {
+		case d.Error != "":
+			fmt.Fprintf(b, " — ❌ unavailable: %s", d.Error)
+		case d.Failed():
+			fmt.Fprintf(b, " — ❌ below the %d%% threshold", d.Threshold)
+		case d.Threshold > 0:
+			fmt.Fprintf(b, " — ✅ meets the %d%% threshold", d.Threshold)
+		}
+		b.WriteString("  \n")
+	}
+	fmt.Fprintf(b, "**Total coverage:** %.1f%% (%d/%d lines)\n\n",
+		report.Summary.Percent, report.Summary.Covered, report.Summary.Total)
+
+	if report.Diff == nil {
+		return
+	}
+	var uncovered []coverage.DiffFileCoverage
+	for _, f := range report.Diff.Files {
+		if len(f.Uncovered) > 0 {
+			uncovered = append(uncovered, f)
+		}
+	}
+	if len(uncovered) == 0 {
+		return
+	}
+	shown := uncovered
+	if len(shown) > budget.maxFailures {
+		shown = shown[:budget.maxFailures]
+	}
+	b.WriteString("| File | Diff coverage | Uncovered lines |\n")
+	b.WriteString("|---|---:|---|\n")
+	for _, f := range shown {
+		fmt.Fprintf(b, "| `%s` | %.1f%% | %s |\n",
+			escapePipe(f.File), f.Percent, truncateLine(formatLineList(f.Uncovered), budget.maxCharsPerLine))
+	}
+	if dropped := len(uncovered) - len(shown); dropped > 0 {
+		fmt.Fprintf(b, "\n_... and %d more files with uncovered changes._\n", dropped)
+	}
+	b.WriteString("\n")
 }
 
 func writeFailingTests(b *strings.Builder, failures []parsers.Test, budget compactSummaryBudget) {
@@ -431,12 +524,19 @@ summary suitable for a GitHub PR comment or job step summary. The output
 contains a counts table grouped by source (test package or linter), totals,
 and up to 5 failing tests with the first 5 lines (≤200 chars each) of their
 stderr/stdout/message. Passing tests and clean linters do not appear in the
-detail sections. The full report remains in the JSON/HTML artifacts.`,
+detail sections. The full report remains in the JSON/HTML artifacts.
+
+With --changed-lines, lint violations outside the lines changed since
+--since are dropped first, so the comment only covers the author's lines.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return runSummary(opts)
 		},
 	}
 	cmd.Flags().StringVar(&opts.InputPath, "input", "gavel-results.json", "Path to gavel JSON result file")
 	cmd.Flags().StringVar(&opts.OutputPath, "output", "", "Path to write compact markdown (default: stdout)")
+	cmd.Flags().BoolVar(&opts.ChangedLines, "changed-lines", false, "Only count lint violations on lines added or modified since --since")
+	cmd.Flags().StringVar(&opts.Since, "since", "", "Base ref for --changed-lines (merge-base with HEAD; default origin/main or $GAVEL_CHANGED_BASE)")
+	cmd.Flags().IntVar(&opts.LinesContext, "changed-lines-context", 0, "With --changed-lines, also keep violations within N lines of a change")
+	cmd.Flags().StringVar(&opts.WorkDir, "work-dir", "", "Repository the results were produced in (default: current directory)")
 	rootCmd.AddCommand(cmd)
 }
@@ -3,6 +3,7 @@ package main
 import (
 	"encoding/json"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"strings"
 	"testing"
@@ -10,6 +11,7 @@ import (
 
 	"github.com/flanksource/gavel/linters"
 	"github.com/flanksource/gavel/models"
+	"github.com/flanksource/gavel/testrunner/coverage"
 	"github.com/flanksource/gavel/testrunner/parsers"
 )
 
@@ -329,6 +331,72 @@ func TestRunSummaryReadsJSONFile(t *testing.T) {
 	}
 }
 
+func TestRunSummaryChangedLines(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	repo := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-c", "user.name=t", "-c", "user.email=t@example.com"}, args...)...)
+		cmd.Dir = repo
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	git("init", "--quiet")
+	if err := os.WriteFile(filepath.Join(repo, "a.go"), []byte("a\nb\nc\nd\ne\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	git("add", "a.go")
+	git("commit", "--quiet", "-m", "init")
+	if err := os.WriteFile(filepath.Join(repo, "a.go"), []byte("a\nb\nC\nd\ne\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	message := func(s string) *string { return &s }
+	data := gavelResultJSON{Lint: []*linters.LinterResult{{
+		Linter:  "golangci-lint",
+		WorkDir: repo,
+		Violations: []models.Violation{
+			{File: filepath.Join(repo, "a.go"), Line: 1, Message: message("legacy issue")},
+			{File: filepath.Join(repo, "a.go"), Line: 3, Message: message("new issue")},
+		},
+	}}}
+	raw, err := json.Marshal(data)
+	if err != nil {
+		t.Fatalf("marshal fixture: %v", err)
+	}
+	inputPath := filepath.Join(t.TempDir(), "gavel-results.json")
+	if err := os.WriteFile(inputPath, raw, 0o644); err != nil {
+		t.Fatalf("write fixture: %v", err)
+	}
+
+	summarize := func(opts summaryOptions) string {
+		t.Helper()
+		opts.InputPath = inputPath
+		opts.OutputPath = filepath.Join(t.TempDir(), "summary.md")
+		if err := runSummary(opts); err != nil {
+			t.Fatalf("runSummary: %v", err)
+		}
+		out, err := os.ReadFile(opts.OutputPath)
+		if err != nil {
+			t.Fatalf("read output: %v", err)
+		}
+		return string(out)
+	}
+
+	body := summarize(summaryOptions{ChangedLines: true, Since: "HEAD", WorkDir: repo})
+	if !strings.Contains(body, "new issue") || strings.Contains(body, "legacy issue") {
+		t.Errorf("expected only the violation on the changed line, got:\n%s", body)
+	}
+
+	body = summarize(summaryOptions{ChangedLines: true, Since: "HEAD", WorkDir: repo, LinesContext: 2})
+	if !strings.Contains(body, "legacy issue") {
+		t.Errorf("expected the violation within the context radius, got:\n%s", body)
+	}
+}
+
 func longMultilineStderr() string {
 	var sb strings.Builder
 	// 8 lines of 250 chars each — must be truncated to 5 lines of ≤200 chars.
@@ -338,3 +406,60 @@ func longMultilineStderr() string {
 	}
 	return sb.String()
 }
+
+func TestBuildCompactSummaryReportsDiffCoverage(t *testing.T) {
+	input := gavelResultJSON{
+		Tests: []parsers.Test{{Package: "pkg/x", Name: "TestOne", Passed: true}},
+		Coverage: &coverage.Report{
+			Summary: coverage.Summary{Covered: 80, Total: 100, Percent: 80},
+			Diff: &coverage.DiffCoverage{
+				Summary:   coverage.Summary{Covered: 3, Total: 5, Percent: 60},
+				Threshold: 75,
+				Files: []coverage.DiffFileCoverage{
+					{File: "pkg/x/x.go", Summary: coverage.Summary{Covered: 1, Total: 3, Percent: 33.3}, Uncovered: []int{4, 7}},
+					{File: "pkg/x/y.go", Summary: coverage.Summary{Covered: 2, Total: 2, Percent: 100}},
+				},
+			},
+		},
+	}
+
+	out := buildCompactSummary(input, defaultCompactBudget)
+	for _, want := range []string{
+		"### Coverage",
+		"**Diff coverage:** 60.0% (3/5 changed lines) — ❌ below the 75% threshold",
+		"**Total coverage:** 80.0% (80/100 lines)",
+		"| `pkg/x/x.go` | 33.3% | 4, 7 |",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("summary missing %q, got:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "pkg/x/y.go") {
+		t.Errorf("fully covered files must not be listed, got:\n%s", out)
+	}
+
+	input.Coverage.Diff.Threshold = 50
+	out = buildCompactSummary(input, defaultCompactBudget)
+	if !strings.Contains(out, "✅ meets the 50% threshold") {
+		t.Errorf("expected passing threshold, got:\n%s", out)
+	}
+}
+
+func TestBuildCompactSummaryCountsFlakyAndQuarantined(t *testing.T) {
+	input := gavelResultJSON{
+		Tests: []parsers.Test{
+			{Package: "pkg", Name: "TestStable", Passed: true},
+			{Package: "pkg", Name: "TestRetried", Passed: true, Flaky: true},
+			{Package: "pkg", Name: "TestKnownBad", Failed: true, Quarantined: true, Message: "boom"},
+		},
+	}
+
+	out := buildCompactSummary(input, defaultCompactBudget)
+
+	if !strings.Contains(out, "**Totals:** 2 passed · 0 failed · 0 skipped · 1 flaky · 1 quarantined") {
+		t.Errorf("totals should count flaky as passed and quarantined apart from failed:\n%s", out)
+	}
+	if strings.Contains(out, "TestKnownBad") {
+		t.Errorf("a quarantined failure must not be listed as a failing test:\n%s", out)
+	}
+}
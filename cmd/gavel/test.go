@@ -24,8 +24,10 @@ import (
 	"github.com/flanksource/gavel/linters"
 	"github.com/flanksource/gavel/snapshots"
 	"github.com/flanksource/gavel/testrunner"
+	"github.com/flanksource/gavel/testrunner/coverage"
 	"github.com/flanksource/gavel/testrunner/parsers"
 	testui "github.com/flanksource/gavel/testrunner/ui"
+	"github.com/flanksource/gavel/utils"
 	"github.com/flanksource/gavel/verify"
 	"github.com/spf13/cobra"
 )
@@ -50,6 +52,12 @@ func runTests(opts testrunner.RunOptions) (any, error) {
 	opts.TestTimeout = testDurationFlags.TestTimeout
 
 	diagnostics := newRunDiagnosticsReporter(runDiagnosticsOptions{Output: logger.GetOutput()})
+	if opts.Watch && !opts.DryRun {
+		if err := watchSupported(opts); err != nil {
+			return nil, err
+		}
+	}
+	watchParent := opts.Context
 	runCtx, cancelRun := newMonitoredStopContext(opts.Context, monitoredStopOptions{
 		Timeout:  opts.Timeout,
 		Interval: 10 * time.Minute,
@@ -144,7 +152,8 @@ func runTests(opts testrunner.RunOptions) (any, error) {
 	// the end. The stream adapter (below) forwards testrunner updates to
 	// the UI while keeping hook pseudo-tests pinned at the top.
 	var (
-		attachUIUpdates func() chan []parsers.Test
+		attachUIUpdates      func() chan []parsers.Test
+		attachUIRerunUpdates func() chan []parsers.Test
 	)
 	if opts.UI {
 		uiServer, uiListener = startTestUI(opts.Addr)
@@ -171,7 +180,7 @@ func runTests(opts testrunner.RunOptions) (any, error) {
 			}()
 			return testrunnerUpdates
 		}
-		attachUIRerunUpdates := func() chan []parsers.Test {
+		attachUIRerunUpdates = func() chan []parsers.Test {
 			testrunnerUpdates := make(chan []parsers.Test, 16)
 			uiUpdates := make(chan []parsers.Test, 16)
 			uiServer.StreamRerunFrom(uiUpdates)
@@ -257,6 +266,13 @@ func runTests(opts testrunner.RunOptions) (any, error) {
 				}
 				lintResults = append(lintResults, results...)
 			}
+			if lintErr == nil {
+				root := utils.FindGitRoot(workDir)
+				if root == "" {
+					root = workDir
+				}
+				lintErr = applyBaselineFile(lintResults, root, false, baseline.MergeScope{})
+			}
 			if uiServer != nil {
 				uiServer.SetLintResults(lintResults)
 			}
@@ -268,6 +284,10 @@ func runTests(opts testrunner.RunOptions) (any, error) {
 	// so we can't derive real pass/skip counts from it.
 	var fullSummary parsers.TestSummary
 	opts.SummaryOut = &fullSummary
+	var coverageProfile coverage.Profile
+	if opts.CoverageEnabled() {
+		opts.CoverageOut = &coverageProfile
+	}
 
 	result, err := testrunner.Run(opts)
 
@@ -286,6 +306,25 @@ func runTests(opts testrunner.RunOptions) (any, error) {
 		logger.Warnf("Linting failed: %v", lintErr)
 	}
 
+	var coverageReport *coverage.Report
+	if opts.CoverageEnabled() {
+		var covErr error
+		coverageReport, covErr = testrunner.BuildCoverageReport(opts, coverageProfile)
+		switch {
+		case coverageReport.Diff.Failed() && coverageReport.Diff.Error != "":
+			logger.Errorf("Diff coverage unavailable, failing --min-diff-coverage=%d%%: %v", coverageReport.Diff.Threshold, covErr)
+			exitCode = 1
+		case coverageReport.Diff.Failed():
+			logger.Errorf("Diff coverage %.1f%% is below --min-diff-coverage=%d%%", coverageReport.Diff.Percent, coverageReport.Diff.Threshold)
+			exitCode = 1
+		case covErr != nil:
+			logger.Warnf("Diff coverage unavailable: %v", covErr)
+		}
+		if uiServer != nil {
+			uiServer.SetCoverage(coverageReport)
+		}
+	}
+
 	// Apply baseline filtering to lint results when --baseline is set.
 	if opts.Baseline != "" && len(lintResults) > 0 {
 		baselineSnap, baselineErr := baseline.LoadSnapshot(opts.Baseline)
@@ -295,6 +334,18 @@ func runTests(opts testrunner.RunOptions) (any, error) {
 		baseline.FilterNewViolations(lintResults, baseline.ExtractViolationKeys(baselineSnap.Lint))
 	}
 
+	// Narrow lint results to the lines this change touched when --changed-lines is set.
+	if opts.ChangedLines && len(lintResults) > 0 {
+		workDir := opts.WorkDir
+		if workDir == "" {
+			workDir, _ = os.Getwd()
+		}
+		base := lintBaseRef(LintOptions{Changed: opts.Changed, Since: opts.Since})
+		if err := filterToChangedLines(lintResults, workDir, base, opts.LinesContext); err != nil {
+			return nil, err
+		}
+	}
+
 	// Count lint violations
 	var lintViolations int
 	for _, lr := range lintResults {
@@ -322,9 +373,11 @@ func runTests(opts testrunner.RunOptions) (any, error) {
 		} else if isPrettyFormat() {
 			printTestRunSummary(fullSummary, lintResults)
 		}
+		printCoverageSummary(coverageReport)
 		return result, err
 	}
 	if tests, ok := result.([]parsers.Test); ok {
+		recordTestBenchHistory(opts.WorkDir, tests)
 		if opts.Baseline != "" {
 			baselineSnap, baselineErr := baseline.LoadSnapshot(opts.Baseline)
 			if baselineErr != nil {
@@ -338,7 +391,7 @@ func runTests(opts testrunner.RunOptions) (any, error) {
 		}
 		if uiServer != nil {
 			if testDurationFlags.Detach {
-				snapshot := buildTestSnapshot(opts, tests, lintResults, runStarted, time.Now().UTC(), captureFinalDiagnostics(opts.Diagnostics, os.Getpid()))
+				snapshot := buildTestSnapshot(opts, tests, lintResults, coverageReport, runStarted, time.Now().UTC(), captureFinalDiagnostics(opts.Diagnostics, os.Getpid()))
 				if path, err := snapshots.Save(opts.WorkDir, &snapshot); err != nil {
 					logger.Warnf("persist snapshot: %v", err)
 				} else {
@@ -359,6 +412,7 @@ func runTests(opts testrunner.RunOptions) (any, error) {
 				}
 				clicky.StopCapturingOutput()
 				printTestRunResults(tests, opts, fullSummary, lintResults)
+				printCoverageSummary(coverageReport)
 				return nil, nil
 			}
 			// Release the stdout/stderr capture before we block on SIGINT:
@@ -374,19 +428,24 @@ func runTests(opts testrunner.RunOptions) (any, error) {
 			// gated by --show-stdout / --show-stderr so CI logs don't get
 			// flooded unless the user opts in.
 			printTestRunResults(tests, opts, fullSummary, lintResults)
-			snapshot := buildTestSnapshot(opts, tests, lintResults, runStarted, time.Now().UTC(), captureFinalDiagnostics(opts.Diagnostics, os.Getpid()))
+			printCoverageSummary(coverageReport)
+			snapshot := buildTestSnapshot(opts, tests, lintResults, coverageReport, runStarted, time.Now().UTC(), captureFinalDiagnostics(opts.Diagnostics, os.Getpid()))
 			if path, err := snapshots.SavePerRun(opts.WorkDir, &snapshot, runStarted); err != nil {
 				logger.Warnf("persist per-run snapshot: %v", err)
 			} else {
 				logger.V(1).Infof("wrote per-run snapshot to %s", path)
 			}
 			runSucceeded = true
+			if opts.Watch {
+				cancelRun()
+				return nil, watchTests(watchParent, opts, attachUIRerunUpdates)
+			}
 			sig := make(chan os.Signal, 1)
 			signal.Notify(sig, syscall.SIGINT, syscall.SIGTERM)
 			<-sig
 			return nil, nil
 		}
-		snapshot := buildTestSnapshot(opts, tests, lintResults, runStarted, time.Now().UTC(), captureFinalDiagnostics(opts.Diagnostics, os.Getpid()))
+		snapshot := buildTestSnapshot(opts, tests, lintResults, coverageReport, runStarted, time.Now().UTC(), captureFinalDiagnostics(opts.Diagnostics, os.Getpid()))
 		if path, err := snapshots.Save(opts.WorkDir, &snapshot); err != nil {
 			logger.Warnf("persist snapshot: %v", err)
 		} else {
@@ -409,6 +468,11 @@ func runTests(opts testrunner.RunOptions) (any, error) {
 		clicky.StopCapturingOutput()
 		clicky.WaitForGlobalCompletion()
 		printTestRunResults(tests, opts, fullSummary, lintResults)
+		printCoverageSummary(coverageReport)
+		if opts.Watch {
+			cancelRun()
+			return nil, watchTests(watchParent, opts, nil)
+		}
 		// For pretty (terminal) output, return nil so clicky doesn't also
 		// render Snapshot.Pretty() — a one-line duplicate of the summary
 		// already printed above. For a serialized format (--format json=...,
@@ -544,16 +608,19 @@ func finishHookTest(idx int, dur time.Duration, output string, runErr error) {
 	}
 }
 
-// printTestRunDetails writes a three-section breakdown of everything the
-// user needs to see at the end of a run: failures, timeouts, and skips.
-// Each section is elided when empty so passing runs stay quiet. Called from
-// every non-UI exit path so the user sees what went wrong without having
-// to scroll back through the streaming task pane.
+// printTestRunDetails writes a sectioned breakdown of everything the user
+// needs to see at the end of a run: failures, timeouts, flaky and
+// quarantined tests, and skips. Each section is elided when empty so
+// passing runs stay quiet. Called from every non-UI exit path so the user
+// sees what went wrong without having to scroll back through the streaming
+// task pane.
 func printTestRunDetails(tests []parsers.Test, showStdout, showStderr testrunner.OutputMode, showPassed bool) {
 	failed := collectLeaves(tests, func(t parsers.Test) bool {
-		return t.Failed && !t.TimedOut
+		return t.Failed && !t.TimedOut && !t.Quarantined
 	})
-	timedOut := collectLeaves(tests, func(t parsers.Test) bool { return t.TimedOut })
+	timedOut := collectLeaves(tests, func(t parsers.Test) bool { return t.TimedOut && !t.Quarantined })
+	quarantined := collectLeaves(tests, func(t parsers.Test) bool { return t.Failed && t.Quarantined })
+	flaky := collectLeaves(tests, func(t parsers.Test) bool { return t.Flaky })
 	skipped := collectLeaves(tests, func(t parsers.Test) bool { return t.Skipped })
 
 	printSection := func(title, style string, items []parsers.Test, applyOutputMask bool) {
@@ -584,6 +651,8 @@ func printTestRunDetails(tests []parsers.Test, showStdout, showStderr testrunner
 
 	printSection("Test failures", "text-red-600", failed, true)
 	printSection("Test timeouts", "text-amber-600", timedOut, true)
+	printSection("Flaky tests (passed on retry)", "text-orange-500", flaky, false)
+	printSection("Quarantined failures (not failing the build)", "text-gray-500", quarantined, true)
 	printSection("Skipped tests", "text-yellow-500", skipped, false)
 }
 
@@ -696,6 +765,15 @@ func printTestRunResults(tests []parsers.Test, opts testrunner.RunOptions, summa
 	printTestRunSummary(summary, lintResults)
 }
 
+// printCoverageSummary prints the one-line coverage roll-up after the test
+// summary. No-op when coverage was not collected or output is serialized.
+func printCoverageSummary(report *coverage.Report) {
+	if report == nil || !isPrettyFormat() {
+		return
+	}
+	fmt.Println(clicky.MustFormat(report.Pretty()))
+}
+
 // publishHookSnapshotToUI flushes the current hookTests slice to the UI
 // directly via SetResults. This is used before testrunner.Run starts
 // streaming (when no real tests have arrived yet) and as a last-ditch
@@ -783,6 +861,10 @@ func buildRerunArgs(req testui.RerunRequest) []string {
 			focus = strings.Join(req.Suite, " ") + " " + req.TestName
 		}
 		return []string{"--focus", focus}
+	case parsers.Pytest:
+		return []string{"-k", parsers.PytestBaseName(req.TestName)}
+	case parsers.Cargo:
+		return []string{"--exact", parsers.CargoTestPath(parsers.Test{Name: req.TestName, Suite: req.Suite})}
 	}
 	return nil
 }
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/flanksource/clicky/formatters"
+	"github.com/flanksource/gavel/fixtures"
+	"github.com/flanksource/gavel/linters"
+	"github.com/flanksource/gavel/testreport"
+	"github.com/flanksource/gavel/testrunner"
+	testui "github.com/flanksource/gavel/testrunner/ui"
+)
+
+func init() {
+	formatters.RegisterFormatter("junit", func(data interface{}, options formatters.FormatOptions) (string, error) {
+		root, _ := getWorkingDir()
+		cases, err := toReportCases(data, root)
+		if err != nil {
+			return "", err
+		}
+		return testreport.JUnit(cases).XML()
+	})
+	formatters.RegisterFormatter("tap", func(data interface{}, options formatters.FormatOptions) (string, error) {
+		root, _ := getWorkingDir()
+		cases, err := toReportCases(data, root)
+		if err != nil {
+			return "", err
+		}
+		return testreport.TAP(cases)
+	})
+}
+
+// toReportCases converts the results gavel test, gavel fixtures and gavel
+// lint return into the cases the junit and tap formats render.
+func toReportCases(data any, root string) ([]testreport.Case, error) {
+	if items, ok := data.([]interface{}); ok && len(items) == 1 {
+		data = items[0]
+	}
+	switch v := data.(type) {
+	case testui.Snapshot:
+		return testreport.Collect(v.Tests, v.Lint, root), nil
+	case *testui.Snapshot:
+		if v == nil {
+			return nil, nil
+		}
+		return testreport.Collect(v.Tests, v.Lint, root), nil
+	case fixtures.FixtureNode:
+		return testreport.Collect(testrunner.FixtureNodeToTests(&v), nil, root), nil
+	case *fixtures.FixtureNode:
+		if v == nil {
+			return nil, nil
+		}
+		return testreport.Collect(testrunner.FixtureNodeToTests(v), nil, root), nil
+	case []*linters.LinterResult:
+		return testreport.Collect(nil, v, root), nil
+	case *lintSummaryView:
+		return testreport.Collect(nil, v.Results, root), nil
+	case nil:
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("junit and tap output is not supported for %T", data)
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/flanksource/commons/logger"
 	"github.com/flanksource/gavel/linters"
 	"github.com/flanksource/gavel/testrunner"
+	"github.com/flanksource/gavel/testrunner/coverage"
 	"github.com/flanksource/gavel/testrunner/parsers"
 	testui "github.com/flanksource/gavel/testrunner/ui"
 	"github.com/flanksource/gavel/utils"
@@ -18,6 +19,7 @@ func buildTestSnapshot(
 	opts testrunner.RunOptions,
 	tests []parsers.Test,
 	lint []*linters.LinterResult,
+	cov *coverage.Report,
 	started time.Time,
 	ended time.Time,
 	diagnostics *testui.DiagnosticsSnapshot,
@@ -46,6 +48,7 @@ func buildTestSnapshot(
 		},
 		Tests:       tests,
 		Lint:        lint,
+		Coverage:    cov,
 		Diagnostics: diagnostics,
 	}
 }
@@ -99,9 +102,14 @@ func snapshotArgs(opts testrunner.RunOptions) map[string]any {
 		"cache":          opts.Cache,
 		"changed":        opts.Changed,
 		"since":          opts.Since,
+		"changed_lines":  opts.ChangedLines,
+		"lines_context":  opts.LinesContext,
 		"bench":          opts.Bench,
+		"coverage":       opts.CoverageEnabled(),
 		"fixtures":       opts.Fixtures,
 		"fixture_files":  append([]string(nil), opts.FixtureFiles...),
+		"shard":          opts.Shard,
+		"watch":          opts.Watch,
 	}
 }
 
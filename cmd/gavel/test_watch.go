@@ -0,0 +1,73 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os/signal"
+	"syscall"
+
+	"github.com/flanksource/clicky"
+	"github.com/flanksource/commons/logger"
+	"github.com/flanksource/gavel/testrunner"
+	"github.com/flanksource/gavel/testrunner/parsers"
+)
+
+// watchTests implements the --watch loop that follows the initial run. Each
+// batch of saves reruns the affected packages: with --ui they stream in as a
+// rerun (adding attempts to the tests they touch), otherwise each iteration
+// prints its own results. --timeout bounds every iteration, not the session,
+// which ends on Ctrl+C.
+func watchTests(parent context.Context, opts testrunner.RunOptions, uiUpdates func() chan []parsers.Test) error {
+	if parent == nil {
+		parent = context.Background()
+	}
+	ctx, stop := signal.NotifyContext(parent, syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	return testrunner.Watch(ctx, opts, func(files []string, iteration testrunner.RunOptions) error {
+		logger.Infof("Changed: %s", clicky.CompactList(files).String())
+		iterCtx, cancel := newStopContext(iteration.Context, opts.Timeout)
+		defer cancel()
+		iteration.Context = iterCtx
+		iteration.RunKind = "rerun"
+		iteration.Lint = false
+		clicky.ClearGlobalTasks()
+
+		if uiServer != nil && uiUpdates != nil {
+			uiServer.SetStopFunc(cancel)
+			uiServer.BeginRun("rerun")
+			iteration.Updates = uiUpdates()
+			_, err := testrunner.Run(iteration)
+			return err
+		}
+
+		var summary parsers.TestSummary
+		iteration.SummaryOut = &summary
+		result, err := testrunner.Run(iteration)
+		clicky.WaitForGlobalCompletion()
+		if tests, ok := result.([]parsers.Test); ok {
+			printTestRunResults(tests, iteration, summary, nil)
+		}
+		if summary.Failed > 0 || err != nil {
+			exitCode = 1
+		} else {
+			exitCode = 0
+		}
+		return err
+	})
+}
+
+// watchSupported rejects flag combinations --watch cannot honour: a
+// detached UI has no terminal to watch from, a shard is one slice of a CI
+// run, and serialized formats expect a single document.
+func watchSupported(opts testrunner.RunOptions) error {
+	switch {
+	case testDurationFlags.Detach:
+		return fmt.Errorf("--watch cannot be combined with --detach")
+	case opts.Shard != "":
+		return fmt.Errorf("--watch cannot be combined with --shard")
+	case !isPrettyFormat():
+		return fmt.Errorf("--watch cannot be combined with --format %s", clicky.Flags.ResolveFormat())
+	}
+	return nil
+}
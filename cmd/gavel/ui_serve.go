@@ -12,6 +12,7 @@ import (
 
 	"github.com/flanksource/clicky"
 	"github.com/flanksource/commons/logger"
+	"github.com/flanksource/gavel/baseline"
 	testui "github.com/flanksource/gavel/testrunner/ui"
 )
 
@@ -37,6 +38,7 @@ type UIServeOptions struct {
 	AutoStop     time.Duration `json:"-"`
 	IdleTimeout  time.Duration `json:"-"`
 	URLFile      string        `flag:"url-file" help:"Write the bound URL to this path atomically so shell scripts can read it back."`
+	Compare      bool          `flag:"compare" help:"Compare two results files (base then head) in a Compare tab."`
 }
 
 // uiServeDurations holds the duration flags attached imperatively to the
@@ -58,7 +60,13 @@ Standalone replay of a JSON snapshot:
 The server prints its URL on the first line of stdout so a wrapping script can
 ` + "`head -n1`" + ` it, and exits when either --auto-stop (hard wall clock from
 start) or --idle-timeout (reset on every HTTP request) fires — whichever comes
-first. A zero duration disables the corresponding timer.`
+first. A zero duration disables the corresponding timer.
+
+Compare two runs, showing the head run with a Compare tab that lists newly
+failing, fixed, still failing, added, removed and slower tests and the lint
+changes (see gavel diff):
+
+  gavel ui serve --compare main.json branch.json`
 }
 
 func init() {
@@ -88,7 +96,12 @@ func runUIServe(opts UIServeOptions) (any, error) {
 	}
 
 	srv := testui.NewServer()
-	if len(opts.ResultsFiles) > 0 {
+	if opts.Compare {
+		if err := loadComparison(srv, opts.ResultsFiles); err != nil {
+			listener.Close() //nolint:errcheck
+			return nil, err
+		}
+	} else if len(opts.ResultsFiles) > 0 {
 		if err := loadResults(srv, opts.ResultsFiles...); err != nil {
 			listener.Close() //nolint:errcheck
 			return nil, fmt.Errorf("load results %v: %w", opts.ResultsFiles, err)
@@ -200,15 +213,39 @@ func loadResults(srv *testui.Server, paths ...string) error {
 	return nil
 }
 
+// loadComparison replays the head run (the second file) with its diff
+// against the base run attached.
+func loadComparison(srv *testui.Server, paths []string) error {
+	if len(paths) != 2 {
+		return fmt.Errorf("--compare requires exactly two results files (base and head), got %d", len(paths))
+	}
+	head, diff, err := loadRunDiff(paths[0], paths[1], baseline.DefaultCompareOptions())
+	if err != nil {
+		return err
+	}
+	head.Diff = diff
+	srv.LoadSnapshot(head)
+	return nil
+}
+
 func mergeSnapshots(dst, src testui.Snapshot) testui.Snapshot {
 	dst.Tests = append(dst.Tests, src.Tests...)
 	dst.Lint = append(dst.Lint, src.Lint...)
 	if src.Bench != nil {
 		dst.Bench = src.Bench
 	}
+	if src.BenchHistory != nil {
+		dst.BenchHistory = src.BenchHistory
+	}
+	if src.Coverage != nil {
+		dst.Coverage = src.Coverage
+	}
 	if src.Diagnostics != nil {
 		dst.Diagnostics = src.Diagnostics
 	}
+	if src.Diff != nil {
+		dst.Diff = src.Diff
+	}
 	dst.Status.Running = dst.Status.Running || src.Status.Running
 	dst.Status.LintRun = dst.Status.LintRun || src.Status.LintRun
 	dst.Status.DiagnosticsAvailable = dst.Status.DiagnosticsAvailable || src.Status.DiagnosticsAvailable || src.Diagnostics != nil
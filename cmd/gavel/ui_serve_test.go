@@ -127,6 +127,42 @@ func TestLoadResults_MergesMultipleSnapshots(t *testing.T) {
 	assert.Equal(t, 42, got.Diagnostics.Root.PID)
 }
 
+func TestLoadComparison_AttachesDiffToHeadRun(t *testing.T) {
+	clicky.ClearGlobalTasks()
+	t.Cleanup(clicky.ClearGlobalTasks)
+
+	dir := t.TempDir()
+	base := writeSnapshot(t, dir)
+	head := filepath.Join(dir, "head.json")
+	data, err := json.Marshal(testui.Snapshot{
+		Git: &testui.SnapshotGit{SHA: "222"},
+		Tests: []parsers.Test{
+			{Name: "TestA", Package: "pkg/a", Failed: true, Framework: parsers.GoTest, Message: "broke"},
+			{Name: "TestB", Package: "pkg/b", Passed: true, Framework: parsers.GoTest},
+		},
+	})
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(head, data, 0o600))
+
+	srv := testui.NewServer()
+	require.Error(t, loadComparison(srv, []string{base}))
+	require.NoError(t, loadComparison(srv, []string{base, head}))
+
+	req, _ := http.NewRequest("GET", "/api/tests", nil)
+	rw := &recordingResponse{header: http.Header{}}
+	srv.Handler().ServeHTTP(rw, req)
+
+	var got testui.Snapshot
+	require.NoError(t, json.Unmarshal(rw.body, &got))
+	require.Len(t, got.Tests, 2)
+	require.NotNil(t, got.Diff)
+	assert.Equal(t, "snapshot.json", got.Diff.Base.Label)
+	assert.Equal(t, "head.json", got.Diff.Head.Label)
+	assert.Equal(t, "222", got.Diff.Head.SHA)
+	assert.Equal(t, 1, got.Diff.TestCount(testui.DiffNewlyFailing))
+	assert.Equal(t, 1, got.Diff.TestCount(testui.DiffFixed))
+}
+
 // TestLoadResults_IgnoresGlobalTaskPollution pins the contract that a replayed
 // snapshot serves exactly the loaded tests, even when the process-global clicky
 // task registry holds stray running tasks left by a concurrent/earlier run.
@@ -71,7 +71,7 @@ func SyncTodos(result *verify.VerifyResult, opts SyncOptions) (*SyncResult, erro
 	for id, cr := range result.Checks {
 		filename := id + ".md"
 		filePath := filepath.Join(opts.TodosDir, filename)
-		category := checkCategory(id)
+		category := checkCategory(result, id)
 
 		if !cr.Pass {
 			paths := evidencePaths(cr.Evidence)
@@ -211,11 +211,9 @@ func evidencePaths(evidence []verify.Evidence) []string {
 	return paths
 }
 
-func checkCategory(id string) string {
-	for _, c := range verify.AllChecks {
-		if c.ID == id {
-			return c.Category
-		}
+func checkCategory(result *verify.VerifyResult, id string) string {
+	if c, ok := result.CheckByID(id); ok {
+		return c.Category
 	}
 	return ""
 }
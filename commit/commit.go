@@ -16,6 +16,7 @@ import (
 	"github.com/flanksource/gavel/git"
 	"github.com/flanksource/gavel/internal/changegraph"
 	"github.com/flanksource/gavel/models"
+	"github.com/flanksource/gavel/utils"
 	"github.com/flanksource/gavel/verify"
 )
 
@@ -307,7 +308,7 @@ func runSingleCommit(ctx context.Context, opts Options) (*Result, error) {
 		return result, fmt.Errorf("create commit: %w", err)
 	}
 	result.Hash = hash
-	logger.Infof("Committed %s: %s", shortHash(hash), firstLine(result.Message))
+	logger.Infof("Committed %s: %s", shortHash(hash), utils.FirstLine(result.Message))
 	restoreLocalReplaces(opts.WorkDir, source.PendingRestores)
 	return result, nil
 }
@@ -487,7 +488,7 @@ func runCommitAll(ctx context.Context, opts Options) (*Result, error) {
 			return result, fmt.Errorf("create commit for %s: %w", group.labelOrDefault(), commitErr)
 		}
 		result.Commits[i].Hash = hash
-		logger.Infof("Committed %s: %s", shortHash(hash), firstLine(result.Commits[i].Message))
+		logger.Infof("Committed %s: %s", shortHash(hash), utils.FirstLine(result.Commits[i].Message))
 	}
 
 	restoreLocalReplaces(opts.WorkDir, source.PendingRestores)
@@ -678,10 +679,3 @@ func shortHash(h string) string {
 	}
 	return h
 }
-
-func firstLine(s string) string {
-	if i := strings.IndexByte(s, '\n'); i >= 0 {
-		return s[:i]
-	}
-	return s
-}
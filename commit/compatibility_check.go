@@ -8,6 +8,7 @@ import (
 
 	"github.com/flanksource/clicky/api"
 	"github.com/flanksource/commons/logger"
+	"github.com/flanksource/gavel/utils"
 )
 
 var ErrCompatibilityCancelled = errors.New("commit cancelled by compatibility checks")
@@ -111,7 +112,7 @@ func formatCompatibilityError(commit CommitResult) error {
 func formatCompatibilityFindings(commit CommitResult) string {
 	var lines []string
 	if strings.TrimSpace(commit.Message) != "" {
-		lines = append(lines, fmt.Sprintf("commit: %s", firstLine(commit.Message)))
+		lines = append(lines, fmt.Sprintf("commit: %s", utils.FirstLine(commit.Message)))
 	}
 	if len(commit.FunctionalityRemoved) > 0 {
 		lines = append(lines, "functionality removed:")
@@ -164,6 +164,7 @@ func (e *CELEvaluator) GetAvailableVariables() []string {
 		"rawStdout - Raw command stdout",
 		"rawStderr - Raw command stderr",
 		"exitCode - Command exit code",
+		"screen - Terminal screen after settling ANSI cursor movement (PTY fixtures)",
 		"isHelpError - Whether help text was detected",
 		"json - Parsed JSON data (when available)",
 		"temp - Temporary file data",
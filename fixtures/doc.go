@@ -18,6 +18,10 @@
 //
 // Unrecognized table column headers become template variables usable in exec, args, and build.
 // Custom keys in YAML frontmatter provide global defaults, overridable per-row.
+// The http columns (method, url, headers, body, retries, status, timeout) and
+// the sql ones (dsn, setup, teardown) are only interpreted in files of that
+// type, and ignore only next to a snapshot column; elsewhere they are
+// ordinary template variables.
 //
 // Prefer markdown tables over command blocks unless commands are multi-line or need
 // per-test setup/teardown. Tables are more compact and easier to scan.
@@ -36,6 +40,56 @@
 //
 // Priority order (highest to lowest): TemplateVars (file expansion) > Properties (table columns) > Metadata (frontmatter)
 //
+// # HTTP Fixtures
+//
+// Setting type: http in the front-matter makes every table row an HTTP
+// request instead of a command. method, url, headers and body are templated
+// like exec fields and can be set in the front-matter or per row:
+//
+//	---
+//	type: http
+//	url: "http://localhost:{{.port}}{{.path}}"
+//	headers:
+//	  Authorization: Bearer $TOKEN
+//	retries: 2
+//	---
+//
+//	| Name | path | method | status | CEL Validation |
+//	|------|------|--------|--------|----------------|
+//	| list users | /users | | 200 | headers["content-type"].startsWith("application/json") |
+//	| missing | /nope | | 404 | body.contains("not found") |
+//
+// CEL expressions see status, headers (lower-cased names), body and json.
+// Output/stdout expectations, including @file goldens, apply to the body.
+//
+// # SQL Fixtures
+//
+// Setting type: sql and a dsn in the front-matter runs every ```sql block (or
+// query table cell) against the database. ```sql setup and ```sql teardown
+// blocks run around each query on a fresh connection:
+//
+//	---
+//	type: sql
+//	dsn: ":memory:"
+//	---
+//
+//	```sql setup
+//	CREATE TABLE users (name TEXT);
+//	INSERT INTO users VALUES ('alice');
+//	```
+//
+//	| Name | query | count | CEL Validation |
+//	|------|-------|-------|----------------|
+//	| all users | SELECT name FROM users | 1 | rows[0].name == "alice" |
+//
+// postgres:// DSNs connect through commons-db; anything else is opened with
+// the pure-Go sqlite driver. CEL expressions see rows, count and columns, and
+// output/stdout expectations compare the rows rendered as indented JSON.
+//
+// Setup and teardown run for every query, not once per file, so :memory:
+// gives each query its own database. Against a file or server DSN the
+// teardown must undo the setup, and parallel fixtures need a shared lock.
+//
 // # Command Block Format
 //
 // Define tests with command blocks and frontmatter.
@@ -113,6 +167,56 @@
 // The fields terminal, os, arch, and skip can also be set per-test via
 // table columns or command block YAML frontmatter (test-level overrides file-level).
 //
+// # Interactive Scripts
+//
+// script: (in front-matter or command block YAML) runs the command in an
+// 80x24 PTY and plays send/expect steps against it:
+//
+//	script:
+//	  - expect: "Name\\? $"
+//	  - send: "bob\r"
+//	  - key: down down enter
+//	  - resize: 120x40
+//	  - expect: Saved
+//	    timeout: 30s
+//
+// expect waits (default 10s) for a regex to match the screen as settled by
+// the ANSI interpreter, so redrawn TUI frames are matched as displayed. key
+// takes names such as enter, tab, esc, up, down, backspace and ctrl+c. After
+// the last step the program must exit within the test timeout; its final
+// screen is available in CEL as screen.
+//
+// # Services
+//
+// services: in the front-matter declares background processes started before
+// the file's first fixture and stopped (SIGTERM, then SIGKILL) after its last:
+//
+//	---
+//	services:
+//	  api:
+//	    command: ./server --port {{.port}}
+//	    ready:
+//	      http: /healthz
+//	    timeout: 30s
+//	---
+//
+// Each service gets a free port ({{.port}} and $PORT) unless port: is set.
+// The readiness probe waits for the port to open (port: true, the default),
+// an HTTP 200 (http:) and/or a log line regex (log:). Fixtures see API_PORT,
+// API_ADDR and API_URL as template variables and env, as do services
+// started after it (services start in name order), and failed fixtures
+// carry the captured service output in FixtureResult.ServiceLogs.
+//
+// # Parallel Execution
+//
+// Fixtures run one at a time unless the file sets parallel: N or the runner
+// is created with RunnerOptions.Parallel (gavel fixtures --parallel). Results
+// are written back to the fixture tree, so output and RunTesting's t.Run
+// order match the markdown regardless of completion order. Fixtures that
+// share a port or database declare it with serial: true (run alone) or
+// lock: <name> (never overlap with another fixture holding the same lock);
+// both can be set in front-matter, table columns or command block YAML.
+//
 // # Working Directory (CWD) Resolution
 //
 // The working directory for test execution is resolved with the following priority:
@@ -155,6 +259,20 @@
 //   - not: Negation of any validation type
 //   - json: JSON path validation
 //
+// # Snapshots
+//
+// snapshot: (a table column, command block YAML or expects field) compares
+// JSON or YAML output structurally against a literal or @file golden, so key
+// order and formatting do not matter. String values in the golden may use
+// placeholders for volatile data: <uuid>, <timestamp>, <date>, <number>,
+// <sha> and <any>, mixed with literal text ("build-<number>"), or a whole
+// value <regex:PATTERN>. ignore: lists JSONPath expressions ($.meta.took,
+// $..id) removed from both sides before comparing; front-matter ignore:
+// applies to every test in the file. Failures list one line per differing
+// path, and --update-golden rewrites the file with the actual output while
+// keeping placeholders that still match. Files ending in .yaml or .yml are
+// read and written as YAML.
+//
 // # File Expansion
 //
 // Fixtures support glob patterns to run tests across multiple files:
@@ -16,6 +16,8 @@ import (
 // It can be populated from inline code fence attributes or YAML expects blocks.
 type Expectations struct {
 	ExitCode *int `yaml:"exitCode,omitempty" json:"exitCode,omitempty"`
+	// Status is the expected HTTP status code for http fixtures
+	Status *int `yaml:"status,omitempty" json:"status,omitempty"`
 	// Matches stdout contains expected string
 	Stdout string `yaml:"stdout,omitempty" json:"stdout,omitempty"`
 	// Matches stderr contains expected string
@@ -30,6 +32,11 @@ type Expectations struct {
 	Timeout    *time.Duration         `yaml:"timeout,omitempty" json:"timeout,omitempty"`
 	CEL        string                 `yaml:"cel,omitempty" json:"cel,omitempty"`
 	Properties map[string]interface{} `yaml:"properties,omitempty" json:"properties,omitempty"`
+	// Snapshot compares JSON/YAML output structurally against a literal or
+	// @file golden that may contain placeholders such as <uuid>
+	Snapshot string `yaml:"snapshot,omitempty" json:"snapshot,omitempty"`
+	// Ignore lists JSONPath expressions left out of the snapshot comparison
+	Ignore []string `yaml:"ignore,omitempty" json:"ignore,omitempty"`
 }
 
 // EvaluateOptions carries optional context for Expectations.Evaluate.
@@ -80,6 +87,11 @@ func (e Expectations) Evaluate(fixture FixtureResult, p exec.ExecResult, opts Ev
 	} else if updated {
 		fixture.Metadata["golden_updated_stderr"] = true
 	}
+	if updated, failed := evaluateSnapshot(&fixture, e.Snapshot, e.snapshotIgnore(fixture), p.Stdout, "stdout", opts); failed {
+		return fixture
+	} else if updated {
+		fixture.Metadata["golden_updated_snapshot"] = true
+	}
 	if e.CEL != "" {
 		// Use RunExpression for CEL expressions, not RunTemplate
 		t := fixture.Test.AsMap()
@@ -88,6 +100,7 @@ func (e Expectations) Evaluate(fixture FixtureResult, p exec.ExecResult, opts Ev
 		t["stderr"] = p.Stderr
 		t["exitCode"] = p.ExitCode
 		combined := p.Stdout + p.Stderr
+		t["screen"] = finalText(combined)
 		dups := duplicateLines(combined)
 		dupList := make([]map[string]any, 0, len(dups))
 		for _, d := range dups {
@@ -106,45 +119,73 @@ func (e Expectations) Evaluate(fixture FixtureResult, p exec.ExecResult, opts Ev
 			"has_duplicates":  len(dups) > 0,
 		}
 		// Try to parse JSON output if it looks like JSON
-		if strings.HasPrefix(strings.TrimSpace(p.Stdout), "{") || strings.HasPrefix(strings.TrimSpace(p.Stdout), "[") {
-			var jsonData interface{}
-			if err := json.Unmarshal([]byte(p.Stdout), &jsonData); err == nil {
-				t["json"] = jsonData
-				fixture.Metadata["json"] = jsonData
-			}
-		}
-
-		// Add temp file data to CEL context
-		for name, tempFile := range fixture.Test.TempFiles {
-			t[name] = tempFile.GetCELData()
-		}
-		output, err := gomplate.RunExpression(t, gomplate.Template{
-			Expression: e.CEL,
-			CelEnvs:    ANSICelFunctions(),
-		})
-		if err != nil {
-			return fixture.Errorf(err, "failed to evaluate CEL expression with gomplate")
+		if jsonData, ok := parseJSONOutput(p.Stdout); ok {
+			t["json"] = jsonData
+			fixture.Metadata["json"] = jsonData
 		}
 
-		switch v := output.(type) {
-		case bool:
-			if !v {
-				fixture.CELExpression = e.CEL
-				fixture.CELVars = t
-				return fixture.Failf("CEL expression evaluated to false")
-			}
-		case string:
-			if strings.ToLower(strings.TrimSpace(v)) != "true" {
-				return fixture.Failf("%s != true", v)
-			}
-		default:
-			return fixture.Failf("CEL expression did not return a boolean: got %T(%v)", output, output)
+		if failed := evaluateCEL(&fixture, e.CEL, t); failed {
+			return fixture
 		}
 	}
 	fixture.Status = task.StatusPASS
 	return fixture
 }
 
+// snapshotIgnore combines the file-level ignore paths with the test's own.
+func (e Expectations) snapshotIgnore(fixture FixtureResult) []string {
+	return append(append([]string{}, fixture.Test.FrontMatter.Ignore...), e.Ignore...)
+}
+
+// parseJSONOutput decodes s when it looks like a JSON object or array.
+func parseJSONOutput(s string) (any, bool) {
+	trimmed := strings.TrimSpace(s)
+	if !strings.HasPrefix(trimmed, "{") && !strings.HasPrefix(trimmed, "[") {
+		return nil, false
+	}
+	var data any
+	if err := json.Unmarshal([]byte(trimmed), &data); err != nil {
+		return nil, false
+	}
+	return data, true
+}
+
+// evaluateCEL runs expression against vars, adding the fixture's temp file
+// data. Returns failed=true when the fixture has been marked failed or
+// errored.
+func evaluateCEL(fixture *FixtureResult, expression string, vars map[string]any) (failed bool) {
+	for name, tempFile := range fixture.Test.TempFiles {
+		vars[name] = tempFile.GetCELData()
+	}
+	output, err := gomplate.RunExpression(vars, gomplate.Template{
+		Expression: expression,
+		CelEnvs:    ANSICelFunctions(),
+	})
+	if err != nil {
+		*fixture = fixture.Errorf(err, "failed to evaluate CEL expression with gomplate")
+		return true
+	}
+
+	switch v := output.(type) {
+	case bool:
+		if !v {
+			fixture.CELExpression = expression
+			fixture.CELVars = vars
+			*fixture = fixture.Failf("CEL expression evaluated to false")
+			return true
+		}
+	case string:
+		if strings.ToLower(strings.TrimSpace(v)) != "true" {
+			*fixture = fixture.Failf("%s != true", v)
+			return true
+		}
+	default:
+		*fixture = fixture.Failf("CEL expression did not return a boolean: got %T(%v)", output, output)
+		return true
+	}
+	return false
+}
+
 // ParseInlineExpectations converts inline code fence attributes to Expectations.
 // Supports: exitCode=N, timeout=N (seconds)
 func ParseInlineExpectations(attrs map[string]string) *Expectations {
@@ -177,6 +218,8 @@ func MergeExpectations(inlineAttrs map[string]string, yamlExpects *Expectations)
 		result.Stdout = yamlExpects.Stdout
 		result.Stderr = yamlExpects.Stderr
 		result.Timeout = yamlExpects.Timeout
+		result.Snapshot = yamlExpects.Snapshot
+		result.Ignore = yamlExpects.Ignore
 	}
 
 	// Parse inline attributes
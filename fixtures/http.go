@@ -0,0 +1,196 @@
+package fixtures
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/flanksource/clicky"
+	"github.com/flanksource/clicky/api"
+	"github.com/flanksource/clicky/task"
+	"github.com/flanksource/gomplate/v3"
+)
+
+// HTTPFixtureBase describes the request made by fixtures of type http. Like
+// ExecFixtureBase it can be set in the file front-matter and overridden per
+// test; every field is templated before the request is sent.
+type HTTPFixtureBase struct {
+	// Method is the HTTP method, defaults to GET
+	Method string `yaml:"method,omitempty" json:"method,omitempty"`
+	// URL of the request, e.g. http://localhost:{{.port}}/api/users
+	URL string `yaml:"url,omitempty" json:"url,omitempty"`
+	// Headers sent with the request
+	Headers map[string]string `yaml:"headers,omitempty" json:"headers,omitempty"`
+	// Body is the request body
+	Body string `yaml:"body,omitempty" json:"body,omitempty"`
+	// Retries is the number of extra attempts made after a transport error or
+	// a 5xx response that the test does not expect
+	Retries int `yaml:"retries,omitempty" json:"retries,omitempty"`
+}
+
+func (h HTTPFixtureBase) IsEmpty() bool {
+	return h.URL == "" && h.Method == "" && h.Body == "" && len(h.Headers) == 0
+}
+
+// MergeInto overlays the non-empty fields of other onto h.
+func (h HTTPFixtureBase) MergeInto(other HTTPFixtureBase) HTTPFixtureBase {
+	merged := h
+	if other.Method != "" {
+		merged.Method = other.Method
+	}
+	if other.URL != "" {
+		merged.URL = other.URL
+	}
+	if other.Body != "" {
+		merged.Body = other.Body
+	}
+	if other.Retries != 0 {
+		merged.Retries = other.Retries
+	}
+	headers := make(map[string]string, len(h.Headers)+len(other.Headers))
+	for k, v := range h.Headers {
+		headers[k] = v
+	}
+	for k, v := range other.Headers {
+		headers[k] = v
+	}
+	merged.Headers = headers
+	if merged.Method == "" {
+		merged.Method = http.MethodGet
+	}
+	return merged
+}
+
+// Template expands $VAR references and gomplate templates in the method,
+// URL, header values and body.
+func (h HTTPFixtureBase) Template(data map[string]any) (HTTPFixtureBase, error) {
+	var err error
+	if h.Method, err = TemplateString(h.Method, data); err != nil {
+		return HTTPFixtureBase{}, fmt.Errorf("method: %w", err)
+	}
+	h.Method = strings.ToUpper(h.Method)
+	if h.URL, err = TemplateString(h.URL, data); err != nil {
+		return HTTPFixtureBase{}, fmt.Errorf("url: %w", err)
+	}
+	if h.Body, err = TemplateString(h.Body, data); err != nil {
+		return HTTPFixtureBase{}, fmt.Errorf("body: %w", err)
+	}
+	headers := make(map[string]string, len(h.Headers))
+	for k, v := range h.Headers {
+		if headers[k], err = TemplateString(v, data); err != nil {
+			return HTTPFixtureBase{}, fmt.Errorf("header %s: %w", k, err)
+		}
+	}
+	h.Headers = headers
+	return h, nil
+}
+
+// TemplateString expands $VAR references and then gomplate templates in value.
+func TemplateString(value string, data map[string]any) (string, error) {
+	if value == "" {
+		return "", nil
+	}
+	return gomplate.RunTemplate(data, gomplate.Template{Template: ExpandVars(value, data)})
+}
+
+func (h HTTPFixtureBase) Pretty() api.Text {
+	return clicky.Text(h.Method, "font-bold").Space().Append(h.URL)
+}
+
+// ParseHeaders parses "Name: value" pairs separated by ";" as written in a
+// fixture table cell.
+func ParseHeaders(value string) map[string]string {
+	headers := map[string]string{}
+	for _, pair := range strings.Split(value, ";") {
+		name, val, ok := strings.Cut(pair, ":")
+		if !ok || strings.TrimSpace(name) == "" {
+			continue
+		}
+		headers[strings.TrimSpace(name)] = strings.TrimSpace(val)
+	}
+	return headers
+}
+
+// HTTPResponse is the outcome of an http fixture request.
+type HTTPResponse struct {
+	Method   string            `json:"method"`
+	URL      string            `json:"url"`
+	Status   int               `json:"status"`
+	Headers  map[string]string `json:"headers,omitempty"` // lower-cased names, repeated values joined with ", "
+	Body     string            `json:"body,omitempty"`
+	Attempts int               `json:"attempts,omitempty"`
+	Duration time.Duration     `json:"duration,omitempty"`
+}
+
+// ResponseHeaders flattens h into lower-cased names so CEL lookups such as
+// headers["content-type"] do not depend on the server's casing.
+func ResponseHeaders(h http.Header) map[string]string {
+	out := make(map[string]string, len(h))
+	keys := make([]string, 0, len(h))
+	for k := range h {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		out[strings.ToLower(k)] = strings.Join(h[k], ", ")
+	}
+	return out
+}
+
+// EvaluateHTTP checks an http fixture response. The status must equal
+// Expectations.Status when set, and otherwise be 2xx unless a CEL expression
+// takes over the check. Stdout (and Output) expectations, including @file
+// goldens, are compared against the response body. CEL expressions see
+// status, headers, body and json alongside the fixture's template variables.
+func (e Expectations) EvaluateHTTP(fixture FixtureResult, resp HTTPResponse, opts EvaluateOptions) FixtureResult {
+	fixture.Command = resp.Method + " " + resp.URL
+	fixture.Stdout = resp.Body
+	if fixture.Metadata == nil {
+		fixture.Metadata = map[string]interface{}{}
+	}
+	fixture.Metadata["status"] = resp.Status
+	if resp.Attempts > 1 {
+		fixture.Metadata["attempts"] = resp.Attempts
+	}
+
+	switch {
+	case e.Status != nil && resp.Status != *e.Status:
+		return fixture.Failf("expected status %d, got %d\n  body: %s", *e.Status, resp.Status, truncateForError(resp.Body))
+	case e.Status == nil && e.CEL == "" && (resp.Status < 200 || resp.Status > 299):
+		return fixture.Failf("expected a 2xx status, got %d\n  body: %s", resp.Status, truncateForError(resp.Body))
+	}
+
+	expectedBody := e.Stdout
+	if expectedBody == "" {
+		expectedBody = e.Output
+	}
+	if updated, failed := evaluateStream(&fixture, expectedBody, resp.Body, "body", opts); failed {
+		return fixture
+	} else if updated {
+		fixture.Metadata["golden_updated_body"] = true
+	}
+	if updated, failed := evaluateSnapshot(&fixture, e.Snapshot, e.snapshotIgnore(fixture), resp.Body, "body", opts); failed {
+		return fixture
+	} else if updated {
+		fixture.Metadata["golden_updated_snapshot"] = true
+	}
+
+	if e.CEL != "" {
+		t := fixture.Test.AsMap()
+		t["status"] = resp.Status
+		t["headers"] = resp.Headers
+		t["body"] = resp.Body
+		t["output"] = resp.Body
+		if jsonData, ok := parseJSONOutput(resp.Body); ok {
+			t["json"] = jsonData
+			fixture.Metadata["json"] = jsonData
+		}
+		if failed := evaluateCEL(&fixture, e.CEL, t); failed {
+			return fixture
+		}
+	}
+	fixture.Status = task.StatusPASS
+	return fixture
+}
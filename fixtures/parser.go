@@ -5,8 +5,10 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/bmatcuk/doublestar/v4"
 	"github.com/goccy/go-yaml"
@@ -70,8 +72,36 @@ func ParseMarkdownFixtures(fixtureFilePath string) ([]FixtureNode, error) {
 	return nodes, nil
 }
 
-// parseTableRow converts a table row into a FixtureTest
-func parseTableRow(headers, values []string) *FixtureNode {
+// typedColumns are the table columns only fixtures of the listed types
+// interpret. Other fixtures keep them as properties, so an exec fixture can
+// still template {{.url}} or {{.body}}.
+var typedColumns = map[string][]string{
+	"method":          {"http"},
+	"url":             {"http"},
+	"headers":         {"http"},
+	"body":            {"http"},
+	"request body":    {"http"},
+	"retries":         {"http"},
+	"status":          {"http"},
+	"expected status": {"http"},
+	"timeout":         {"http", "sql"},
+	"dsn":             {"sql"},
+}
+
+// isTypedColumn reports whether header is a column fixtureType interprets
+// itself. ignore is only meaningful next to a snapshot column.
+func isTypedColumn(header, fixtureType string, hasSnapshot bool) bool {
+	if header == "ignore" {
+		return hasSnapshot
+	}
+	types, ok := typedColumns[header]
+	return !ok || slices.Contains(types, fixtureType)
+}
+
+// parseTableRow converts a table row into a FixtureTest. fixtureType is the
+// file's front-matter type, which decides whether type specific columns such
+// as url or dsn are interpreted or kept as properties.
+func parseTableRow(headers, values []string, fixtureType string) *FixtureNode {
 	if len(headers) != len(values) {
 		return nil
 	}
@@ -80,17 +110,28 @@ func parseTableRow(headers, values []string) *FixtureNode {
 		Expected: Expectations{},
 	}
 
+	hasSnapshot := slices.ContainsFunc(headers, func(h string) bool {
+		return strings.EqualFold(strings.TrimSpace(h), "snapshot")
+	})
+
 	for i, header := range headers {
 		value := values[i]
 		header = strings.ToLower(strings.TrimSpace(header))
 
-		switch header {
+		column := header
+		if !isTypedColumn(header, fixtureType, hasSnapshot) {
+			column = ""
+		}
+
+		switch column {
 		case "test name", "name":
 			fixture.Name = value
 		case "cwd", "working directory", "dir":
 			fixture.CWD = value
 		case "query":
 			fixture.Query = value
+		case "dsn":
+			fixture.SQL.DSN = value
 		case "cli", "command", "exec":
 			fixture.Exec = value
 		case "terminal", "term":
@@ -101,6 +142,10 @@ func parseTableRow(headers, values []string) *FixtureNode {
 			fixture.TestArch = value
 		case "skip":
 			fixture.TestSkip = value
+		case "lock":
+			fixture.TestLock = value
+		case "serial":
+			fixture.TestSerial, _ = strconv.ParseBool(value)
 		case "cli args", "args", "arguments":
 			fixture.Args = strings.Split(value, " ")
 		case "exit code", "exitcode", "expected exit code":
@@ -135,6 +180,35 @@ func parseTableRow(headers, values []string) *FixtureNode {
 			fixture.Expected.Output = value
 		case "cel validation", "cel", "validation", "expr":
 			fixture.Expected.CEL = value
+		case "snapshot":
+			fixture.Expected.Snapshot = value
+		case "ignore":
+			fixture.Expected.Ignore = ParseIgnorePaths(value)
+		case "method":
+			fixture.HTTP.Method = value
+		case "url":
+			fixture.HTTP.URL = value
+		case "headers":
+			fixture.HTTP.Headers = ParseHeaders(value)
+		case "body", "request body":
+			fixture.HTTP.Body = value
+		case "retries":
+			if n, err := strconv.Atoi(value); err == nil {
+				fixture.HTTP.Retries = n
+			}
+		case "status", "expected status":
+			if value != "" && value != "-" {
+				if code, err := strconv.Atoi(value); err == nil {
+					fixture.Expected.Status = &code
+				}
+			}
+		case "timeout":
+			if d, err := time.ParseDuration(value); err == nil {
+				fixture.Expected.Timeout = &d
+			} else if secs, err := strconv.Atoi(value); err == nil {
+				d := time.Duration(secs) * time.Second
+				fixture.Expected.Timeout = &d
+			}
 		default:
 			if value != "" {
 				if fixture.Expected.Properties == nil {
@@ -45,6 +45,7 @@ func parseMarkdownWithGoldmarkTree(content string, frontMatter *FrontMatter, sou
 	source := []byte(content)
 	reader := text.NewReader(source)
 	doc := md.Parser().Parse(reader)
+	frontMatter = collectSQLSetupBlocks(doc, source, frontMatter)
 
 	// State for parsing
 	var currentCommand *commandBlockBuilder
@@ -162,6 +163,11 @@ func parseMarkdownWithGoldmarkTree(content string, frontMatter *FrontMatter, sou
 			lang := strings.ToLower(extractLanguage(infoString))
 			codeContent := extractCodeBlockContent(&node.BaseBlock, source)
 
+			if sqlBlockRole(infoString) != "" {
+				// Already applied to the front-matter by collectSQLSetupBlocks
+				return ast.WalkContinue, nil
+			}
+
 			if inCommandBlock && currentCommand != nil {
 				// Handle code blocks within command blocks (existing behavior)
 				// Get allowed code blocks from frontmatter
@@ -177,7 +183,7 @@ func parseMarkdownWithGoldmarkTree(content string, frontMatter *FrontMatter, sou
 				} else {
 					logger.V(4).Infof("Skipping code block '%s' not in allowed %v", lang, allowedBlocks)
 				}
-			} else if !inCommandBlock && isExecutableLanguage(lang) {
+			} else if !inCommandBlock && (isExecutableLanguage(lang) || (lang == "sql" && isSQLFile(frontMatter))) {
 				// Handle standalone code blocks (new behavior)
 				// Complete any pending standalone code block first
 				if standaloneCodeBlock != nil && !standaloneCodeBlock.isComplete {
@@ -300,6 +306,55 @@ func parseMarkdownWithGoldmarkTree(content string, frontMatter *FrontMatter, sou
 	return rootNode, nil
 }
 
+// collectSQLSetupBlocks appends the contents of ```sql setup and
+// ```sql teardown blocks to the front-matter, so they apply to every test in
+// the file regardless of where they appear. Files of any other type leave
+// them alone.
+func collectSQLSetupBlocks(doc ast.Node, source []byte, frontMatter *FrontMatter) *FrontMatter {
+	if !isSQLFile(frontMatter) {
+		return frontMatter
+	}
+	var setup, teardown []string
+	_ = ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		block, ok := n.(*ast.FencedCodeBlock)
+		if !entering || !ok || block.Info == nil {
+			return ast.WalkContinue, nil
+		}
+		switch sqlBlockRole(string(block.Info.Segment.Value(source))) {
+		case "setup":
+			setup = append(setup, extractCodeBlockContent(&block.BaseBlock, source))
+		case "teardown":
+			teardown = append(teardown, extractCodeBlockContent(&block.BaseBlock, source))
+		}
+		return ast.WalkContinue, nil
+	})
+	if len(setup) == 0 && len(teardown) == 0 {
+		return frontMatter
+	}
+	fm := FrontMatter{}
+	if frontMatter != nil {
+		fm = *frontMatter
+	}
+	frontMatter = &fm
+	if len(setup) > 0 {
+		frontMatter.SQL.Setup = joinSQL(append([]string{frontMatter.SQL.Setup}, setup...))
+	}
+	if len(teardown) > 0 {
+		frontMatter.SQL.Teardown = joinSQL(append([]string{frontMatter.SQL.Teardown}, teardown...))
+	}
+	return frontMatter
+}
+
+func joinSQL(scripts []string) string {
+	var parts []string
+	for _, s := range scripts {
+		if s = strings.TrimSpace(s); s != "" {
+			parts = append(parts, s)
+		}
+	}
+	return strings.Join(parts, "\n")
+}
+
 // parseMarkdownWithGoldmark provides backwards compatibility by converting tree to flat list
 func parseMarkdownWithGoldmark(content string, frontMatter *FrontMatter, sourceDir string) ([]FixtureNode, error) {
 	tree, err := parseMarkdownWithGoldmarkTree(content, frontMatter, sourceDir)
@@ -441,6 +496,11 @@ func buildFixtureFromCommand(cmd *commandBlockBuilder, frontMatter *FrontMatter,
 			Properties: make(map[string]interface{}),
 		},
 	}
+	if cmd.language == "sql" {
+		// sql blocks are queries for the sql fixture type, not commands
+		fixture.ExecFixtureBase = ExecFixtureBase{}
+		fixture.Query = cmd.content
+	}
 
 	// Apply frontmatter from command block if present
 	if cmd.frontmatter != "" {
@@ -450,9 +510,14 @@ func buildFixtureFromCommand(cmd *commandBlockBuilder, frontMatter *FrontMatter,
 			Env      map[string]any `yaml:"env"`
 			Timeout  string         `yaml:"timeout"`
 			Terminal string         `yaml:"terminal"`
+			Script   []ScriptStep   `yaml:"script"`
 			OS       string         `yaml:"os"`
 			Arch     string         `yaml:"arch"`
 			Skip     string         `yaml:"skip"`
+			Serial   bool           `yaml:"serial"`
+			Lock     string         `yaml:"lock"`
+			Snapshot string         `yaml:"snapshot"`
+			Ignore   []string       `yaml:"ignore"`
 		}
 
 		if err := yaml.Unmarshal([]byte(cmd.frontmatter), &cmdFrontMatter); err == nil {
@@ -468,6 +533,9 @@ func buildFixtureFromCommand(cmd *commandBlockBuilder, frontMatter *FrontMatter,
 			if cmdFrontMatter.Terminal != "" {
 				fixture.Terminal = cmdFrontMatter.Terminal
 			}
+			if len(cmdFrontMatter.Script) > 0 {
+				fixture.Script = cmdFrontMatter.Script
+			}
 			if cmdFrontMatter.OS != "" {
 				fixture.TestOS = cmdFrontMatter.OS
 			}
@@ -477,6 +545,14 @@ func buildFixtureFromCommand(cmd *commandBlockBuilder, frontMatter *FrontMatter,
 			if cmdFrontMatter.Skip != "" {
 				fixture.TestSkip = cmdFrontMatter.Skip
 			}
+			fixture.TestSerial = cmdFrontMatter.Serial
+			if cmdFrontMatter.Lock != "" {
+				fixture.TestLock = cmdFrontMatter.Lock
+			}
+			if cmdFrontMatter.Snapshot != "" {
+				fixture.Expected.Snapshot = cmdFrontMatter.Snapshot
+			}
+			fixture.Expected.Ignore = cmdFrontMatter.Ignore
 		}
 	}
 
@@ -529,6 +605,10 @@ func parseTableFromAST(tableAST *extast.Table, source []byte, frontMatter *Front
 	}
 	var headers []string
 	rowIndex := 0
+	fixtureType := ""
+	if frontMatter != nil {
+		fixtureType = frontMatter.Type
+	}
 
 	// Walk through table rows
 	for child := tableAST.FirstChild(); child != nil; child = child.NextSibling() {
@@ -553,7 +633,7 @@ func parseTableFromAST(tableAST *extast.Table, source []byte, frontMatter *Front
 
 			// Create fixture from row
 			if len(headers) > 0 && len(values) == len(headers) {
-				if fixtureNode := parseTableRow(headers, values); fixtureNode != nil {
+				if fixtureNode := parseTableRow(headers, values, fixtureType); fixtureNode != nil {
 					// Apply frontmatter and source directory
 					if fixtureNode.Test != nil {
 						applyFrontMatterToFixture(fixtureNode.Test, frontMatter)
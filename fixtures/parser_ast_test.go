@@ -343,6 +343,71 @@ Validations:
 			m2 := fixtures[1].Test.AsMap()
 			Expect(m2["baseUrl"]).To(Equal("https://api.example.com"))
 		})
+
+		It("should only interpret http, sql and ignore columns for the fixture type that uses them", func() {
+			content := `
+| Name | url | body | status | timeout | dsn | ignore |
+|------|-----|------|--------|---------|-----|--------|
+| row | https://example.com | {"a":1} | 201 | 5s | :memory: | $.id |
+`
+			fixtures, err := parseMarkdownWithGoldmark(content, nil, "/tmp/test")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fixtures).To(HaveLen(1))
+			f := fixtures[0].Test
+			Expect(f.HTTP.IsEmpty()).To(BeTrue())
+			Expect(f.SQL.DSN).To(BeEmpty())
+			Expect(f.Expected.Status).To(BeNil())
+			Expect(f.Expected.Timeout).To(BeNil())
+			Expect(f.Expected.Ignore).To(BeEmpty())
+			Expect(f.AsMap()).To(HaveKeyWithValue("url", "https://example.com"))
+			Expect(f.AsMap()).To(HaveKeyWithValue("status", "201"))
+			Expect(f.AsMap()).To(HaveKeyWithValue("dsn", ":memory:"))
+			Expect(f.AsMap()).To(HaveKeyWithValue("ignore", "$.id"))
+
+			fixtures, err = parseMarkdownWithGoldmark(content, &FrontMatter{Type: "http"}, "/tmp/test")
+			Expect(err).NotTo(HaveOccurred())
+			f = fixtures[0].Test
+			Expect(f.HTTP.URL).To(Equal("https://example.com"))
+			Expect(f.HTTP.Body).To(Equal(`{"a":1}`))
+			Expect(*f.Expected.Status).To(Equal(201))
+			Expect(f.Expected.Timeout).NotTo(BeNil())
+			Expect(f.SQL.DSN).To(BeEmpty())
+			Expect(f.Expected.Properties).To(HaveKeyWithValue("dsn", ":memory:"))
+
+			snapshot := `
+| Name | snapshot | ignore |
+|------|----------|--------|
+| row | {"id": 1} | $.id |
+`
+			fixtures, err = parseMarkdownWithGoldmark(snapshot, nil, "/tmp/test")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fixtures[0].Test.Expected.Ignore).To(Equal([]string{"$.id"}))
+		})
+
+		It("should keep http and sql front-matter keys as metadata for other fixture types", func() {
+			fm := &FrontMatter{
+				HTTP: HTTPFixtureBase{URL: "https://example.com"},
+				SQL:  SQLFixtureBase{Setup: "CREATE TABLE t (id int)"},
+				Metadata: map[string]any{
+					"url":   "https://example.com",
+					"setup": "CREATE TABLE t (id int)",
+				},
+			}
+			fm.CleanMetadata()
+			Expect(fm.HTTP.IsEmpty()).To(BeTrue())
+			Expect(fm.SQL.Setup).To(BeEmpty())
+			Expect(fm.Metadata).To(HaveKeyWithValue("url", "https://example.com"))
+			Expect(fm.Metadata).To(HaveKeyWithValue("setup", "CREATE TABLE t (id int)"))
+
+			fm = &FrontMatter{
+				Type:     "http",
+				HTTP:     HTTPFixtureBase{URL: "https://example.com"},
+				Metadata: map[string]any{"url": "https://example.com"},
+			}
+			fm.CleanMetadata()
+			Expect(fm.HTTP.URL).To(Equal("https://example.com"))
+			Expect(fm.Metadata).NotTo(HaveKey("url"))
+		})
 	})
 
 	Context("when falling back to legacy parser", func() {
@@ -40,11 +40,33 @@ func shouldExecuteCodeBlock(language string, codeBlocks []string) bool {
 }
 
 // getCodeBlocksOrDefault returns the codeBlocks list or defaults to ["bash"]
+// (["sql"] for files of type sql)
 func getCodeBlocksOrDefault(frontMatter *FrontMatter) []string {
-	if frontMatter == nil || len(frontMatter.CodeBlocks) == 0 {
-		return []string{"bash"}
+	if frontMatter != nil && len(frontMatter.CodeBlocks) > 0 {
+		return frontMatter.CodeBlocks
 	}
-	return frontMatter.CodeBlocks
+	if isSQLFile(frontMatter) {
+		return []string{"sql"}
+	}
+	return []string{"bash"}
+}
+
+func isSQLFile(frontMatter *FrontMatter) bool {
+	return frontMatter != nil && frontMatter.Type == "sql"
+}
+
+// sqlBlockRole returns "setup" or "teardown" for ```sql setup and
+// ```sql teardown fences, and "" for any other block.
+func sqlBlockRole(infoString string) string {
+	parts := strings.Fields(strings.ToLower(infoString))
+	if len(parts) < 2 || parts[0] != "sql" {
+		return ""
+	}
+	switch parts[1] {
+	case "setup", "teardown":
+		return parts[1]
+	}
+	return ""
 }
 
 // isExecutableLanguage returns true if the language is an executable language
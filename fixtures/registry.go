@@ -159,6 +159,13 @@ func (r *Registry) GetForFixture(fixture FixtureTest) (FixtureType, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
+	if fixture.Type != "" {
+		if ft, ok := r.types[fixture.Type]; ok {
+			return ft, nil
+		}
+		return nil, fmt.Errorf("%s fixture type not registered; import _ \"github.com/flanksource/gavel/fixtures/types\"", fixture.Type)
+	}
+
 	if fixture.Query != "" {
 		if ft, ok := r.types["query"]; ok {
 			return ft, nil
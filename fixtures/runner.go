@@ -9,6 +9,7 @@ import (
 	"os/exec"
 	"runtime"
 	"strconv"
+	"sync"
 	"syscall"
 	"time"
 
@@ -28,14 +29,19 @@ type RunnerOptions struct {
 	NoColor        bool     // Disable colored output
 	WorkDir        string   // Working directory
 	MaxWorkers     int      // Maximum number of parallel workers
+	Parallel       int      // Fixtures per file run concurrently unless front-matter sets parallel (default 1)
 	Logger         logger.Logger
 	ExecutablePath string              // Path to the current executable (for fixtures to use)
 	OnResult       func(FixtureResult) // Called after each fixture completes
 	OnParsed       func(*FixtureNode)  // Called after fixture files are parsed, before execution
 	UpdateGolden   bool                // When true, mismatched @file expectations are rewritten with actual output instead of failing
 	Display        *DisplayOptions     // Optional result visibility controls for CLI rendering
+	Timeout        time.Duration       // Per-fixture timeout, not counting time queued for a slot (default DefaultFixtureTimeout)
 }
 
+// DefaultFixtureTimeout bounds a single fixture when RunnerOptions.Timeout is unset.
+const DefaultFixtureTimeout = 2 * time.Minute
+
 // Runner manages fixture test execution using typed tasks
 type Runner struct {
 	options    RunnerOptions
@@ -44,6 +50,8 @@ type Runner struct {
 	tree       *FixtureNode // Hierarchical tree structure
 	daemonCmd  *exec.Cmd
 	daemonPort int
+	resultMu   sync.Mutex // Serializes OnResult when fixtures run in parallel
+	services   map[string]*fileServices
 }
 
 // NewRunner creates a new fixture runner
@@ -218,6 +226,10 @@ func (r *Runner) executeFixtures() (*FixtureGroup, error) {
 	}
 
 	ctx := flanksourceContext.NewContext(context.Background())
+	if r.options.WorkDir == "" {
+		// Resolved once up front: executeFixture runs concurrently
+		r.options.WorkDir, _ = os.Getwd()
+	}
 
 	// Run build command synchronously before any fixtures
 	buildCmd := r.getBuildCommand()
@@ -238,22 +250,48 @@ func (r *Runner) executeFixtures() (*FixtureGroup, error) {
 		defer r.stopDaemon()
 	}
 
-	// Create typed task group for fixture execution
-	fixtureGroup := task.StartGroup[FixtureResult]("Fixture Tests")
-
-	taskToNodeMap := make(map[task.TypedTask[FixtureResult]]*FixtureNode)
+	var nodes []*FixtureNode
 	r.tree.Walk(func(node *FixtureNode) {
 		if node.Test != nil {
-			typedTask := fixtureGroup.Add(node.Test.String(), func(ctx flanksourceContext.Context, t *task.Task) (FixtureResult, error) {
-				result, err := r.executeFixture(ctx, *node.Test)
-				if r.options.OnResult != nil {
-					r.options.OnResult(result)
-				}
-				return result, err
-			}, clicky.WithTaskTimeout(2*time.Minute))
-			taskToNodeMap[typedTask] = node
+			nodes = append(nodes, node)
 		}
 	})
+	scheduler := newFixtureScheduler(r.options.Parallel, r.options.MaxWorkers, nodes)
+	r.services = newFileServices(nodes)
+	defer r.stopServices()
+
+	// Create typed task group for fixture execution. Results are written back
+	// to the tree nodes, so output order does not depend on completion order.
+	// Every fixture gets a task slot: the scheduler bounds how many run, and a
+	// fixture waiting on a file or lock slot must not starve other files.
+	fixtureGroup := task.StartGroup[FixtureResult]("Fixture Tests", task.WithConcurrency(max(len(nodes), 1)))
+	timeout := r.options.Timeout
+	if timeout <= 0 {
+		timeout = DefaultFixtureTimeout
+	}
+
+	taskToNodeMap := make(map[task.TypedTask[FixtureResult]]*FixtureNode)
+	for _, node := range nodes {
+		node := node
+		typedTask := fixtureGroup.Add(node.Test.String(), func(ctx flanksourceContext.Context, t *task.Task) (FixtureResult, error) {
+			release, err := scheduler.acquire(ctx, node)
+			if err != nil {
+				return FixtureResult{
+					Name:   node.Test.Name,
+					Status: task.StatusERR,
+					Test:   *node.Test,
+					Error:  fmt.Sprintf("waiting to run: %v", err),
+				}, nil
+			}
+			runCtx, cancel := ctx.WithTimeout(timeout)
+			result, err := r.runFixtureNode(runCtx, node)
+			cancel()
+			release()
+			r.reportResult(result)
+			return result, err
+		})
+		taskToNodeMap[typedTask] = node
+	}
 
 	// Wait for all fixture tasks to complete and collect results
 	groupResult := fixtureGroup.WaitFor()
@@ -297,6 +335,67 @@ func (r *Runner) executeFixtures() (*FixtureGroup, error) {
 	return results, nil
 }
 
+// runFixtureNode runs the fixture of node after starting its file's
+// services, passing their addresses as template variables and env, and
+// attaches the service logs when the fixture fails.
+func (r *Runner) runFixtureNode(ctx flanksourceContext.Context, node *FixtureNode) (FixtureResult, error) {
+	fixture := *node.Test
+	services := r.services[fixtureFileKey(node)]
+	if services == nil {
+		return r.executeFixture(ctx, fixture)
+	}
+	defer services.Finish()
+
+	if err := services.Start(ctx, r.options.WorkDir); err != nil {
+		return FixtureResult{
+			Name:        fixture.Name,
+			Status:      task.StatusERR,
+			Test:        fixture,
+			Error:       err.Error(),
+			ServiceLogs: services.Logs(),
+		}, nil
+	}
+
+	vars := make(map[string]any, len(fixture.TemplateVars))
+	for k, v := range fixture.TemplateVars {
+		vars[k] = v
+	}
+	env := make(map[string]any, len(fixture.Env))
+	for k, v := range fixture.Env {
+		env[k] = v
+	}
+	explicit := fixture.ExecBase().Env
+	for k, v := range services.Vars() {
+		vars[k] = v
+		if _, ok := explicit[k]; !ok {
+			env[k] = v
+		}
+	}
+	fixture.TemplateVars = vars
+	fixture.Env = env
+
+	result, err := r.executeFixture(ctx, fixture)
+	if isFailureResult(&result) {
+		result.ServiceLogs = services.Logs()
+	}
+	return result, err
+}
+
+func (r *Runner) stopServices() {
+	for _, services := range r.services {
+		services.Stop()
+	}
+}
+
+func (r *Runner) reportResult(result FixtureResult) {
+	if r.options.OnResult == nil {
+		return
+	}
+	r.resultMu.Lock()
+	defer r.resultMu.Unlock()
+	r.options.OnResult(result)
+}
+
 // getBuildCommand extracts build command from first fixture that has one
 func (r *Runner) getBuildCommand() string {
 	for _, fixture := range r.fixtures {
@@ -369,10 +468,14 @@ func (r *Runner) executeFixture(ctx flanksourceContext.Context, fixture FixtureT
 	}
 
 	if r.daemonPort > 0 {
-		if fixture.TemplateVars == nil {
-			fixture.TemplateVars = make(map[string]any)
+		// Copy before writing: the map is shared with the tree node and other
+		// fixtures may be running concurrently
+		vars := make(map[string]any, len(fixture.TemplateVars)+1)
+		for k, v := range fixture.TemplateVars {
+			vars[k] = v
 		}
-		fixture.TemplateVars["port"] = strconv.Itoa(r.daemonPort)
+		vars["port"] = strconv.Itoa(r.daemonPort)
+		fixture.TemplateVars = vars
 	}
 
 	if r.options.WorkDir == "" {
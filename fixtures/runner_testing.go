@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"sort"
 	"strings"
 	"testing"
 
@@ -99,6 +100,7 @@ func (r *Runner) RegisterGinkgoSpecs() {
 			}
 		})
 		ginkgo.AfterAll(func() {
+			r.stopServices()
 			r.stopDaemon()
 		})
 
@@ -114,10 +116,9 @@ func registerGinkgoNode(r *Runner, node *FixtureNode) {
 	}
 
 	if node.Test != nil {
-		fixture := *node.Test
 		ginkgo.It(testNodeName(node), func() {
 			ginkgo.GinkgoHelper()
-			result, err := r.runSingleFixture(fixture)
+			result, err := r.runSingleFixture(node)
 			node.Results = &result
 			if err != nil {
 				ginkgo.Fail(formatNodeFailure(node, &result, err), 1)
@@ -137,9 +138,9 @@ func registerGinkgoNode(r *Runner, node *FixtureNode) {
 	})
 }
 
-func (r *Runner) runSingleFixture(fixture FixtureTest) (FixtureResult, error) {
+func (r *Runner) runSingleFixture(node *FixtureNode) (FixtureResult, error) {
 	ctx := flanksourceContext.NewContext(context.Background())
-	return r.executeFixture(ctx, fixture)
+	return r.runFixtureNode(ctx, node)
 }
 
 func (r *Runner) setupGinkgoRun() error {
@@ -162,6 +163,14 @@ func (r *Runner) setupGinkgoRun() error {
 		}
 	}
 
+	var nodes []*FixtureNode
+	r.tree.Walk(func(node *FixtureNode) {
+		if node.Test != nil {
+			nodes = append(nodes, node)
+		}
+	})
+	r.services = newFileServices(nodes)
+
 	return nil
 }
 
@@ -289,6 +298,14 @@ func formatNodeFailure(node *FixtureNode, result *FixtureResult, err error) stri
 		if result.Stdout != "" {
 			fmt.Fprintf(&b, "\nstdout:\n%s", truncateFixtureOutput(result.Stdout))
 		}
+		services := make([]string, 0, len(result.ServiceLogs))
+		for name := range result.ServiceLogs {
+			services = append(services, name)
+		}
+		sort.Strings(services)
+		for _, name := range services {
+			fmt.Fprintf(&b, "\nservice %s:\n%s", name, truncateFixtureOutput(result.ServiceLogs[name]))
+		}
 		if result.CELExpression != "" {
 			fmt.Fprintf(&b, "\ncel: %s", result.CELExpression)
 		}
@@ -0,0 +1,126 @@
+package fixtures
+
+import (
+	"context"
+	"sync"
+)
+
+// fixtureScheduler gates fixture execution inside the task group. Each file
+// runs at most its parallel limit of fixtures at once, serial fixtures run
+// with nothing else in flight, and fixtures that name the same lock never
+// overlap. Slots are always taken in the order file, serial, lock, worker so
+// two fixtures can never wait on each other, and a fixture blocked on a file
+// or lock slot does not hold one of the workers other files could use.
+type fixtureScheduler struct {
+	limit   int
+	workers chan struct{}
+	serial  sync.RWMutex
+
+	mu    sync.Mutex
+	files map[string]chan struct{}
+	locks map[string]chan struct{}
+}
+
+// newFixtureScheduler sizes a slot pool per file from its front-matter
+// parallel value, falling back to defaultParallel (and to 1 when that is
+// unset). maxWorkers, when positive, caps the overall concurrency.
+func newFixtureScheduler(defaultParallel, maxWorkers int, nodes []*FixtureNode) *fixtureScheduler {
+	if defaultParallel <= 0 {
+		defaultParallel = 1
+	}
+	s := &fixtureScheduler{
+		limit: 1,
+		files: map[string]chan struct{}{},
+		locks: map[string]chan struct{}{},
+	}
+	for _, node := range nodes {
+		key := fixtureFileKey(node)
+		if _, ok := s.files[key]; ok {
+			continue
+		}
+		n := defaultParallel
+		if node.Test.FrontMatter.Parallel > 0 {
+			n = node.Test.FrontMatter.Parallel
+		}
+		s.files[key] = make(chan struct{}, n)
+		if n > s.limit {
+			s.limit = n
+		}
+	}
+	if maxWorkers > 0 && s.limit > maxWorkers {
+		s.limit = maxWorkers
+	}
+	s.workers = make(chan struct{}, s.limit)
+	return s
+}
+
+// acquire blocks until node may run and returns the function that frees its
+// slots. It only fails when ctx is done while waiting for a slot. The caller
+// starts the fixture's timeout after acquire returns, so time spent queued
+// behind other fixtures never counts against it.
+func (s *fixtureScheduler) acquire(ctx context.Context, node *FixtureNode) (func(), error) {
+	var releases []func()
+	release := func() {
+		for i := len(releases) - 1; i >= 0; i-- {
+			releases[i]()
+		}
+	}
+
+	if slot := s.files[fixtureFileKey(node)]; slot != nil {
+		if err := take(ctx, slot); err != nil {
+			return nil, err
+		}
+		releases = append(releases, func() { <-slot })
+	}
+
+	if node.Test.IsSerial() {
+		s.serial.Lock()
+		releases = append(releases, s.serial.Unlock)
+	} else {
+		s.serial.RLock()
+		releases = append(releases, s.serial.RUnlock)
+	}
+
+	if name := node.Test.LockName(); name != "" {
+		lock := s.lock(name)
+		if err := take(ctx, lock); err != nil {
+			release()
+			return nil, err
+		}
+		releases = append(releases, func() { <-lock })
+	}
+
+	if err := take(ctx, s.workers); err != nil {
+		release()
+		return nil, err
+	}
+	releases = append(releases, func() { <-s.workers })
+	return release, nil
+}
+
+func (s *fixtureScheduler) lock(name string) chan struct{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	lock, ok := s.locks[name]
+	if !ok {
+		lock = make(chan struct{}, 1)
+		s.locks[name] = lock
+	}
+	return lock
+}
+
+func take(ctx context.Context, slot chan struct{}) error {
+	select {
+	case slot <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func fixtureFileKey(node *FixtureNode) string {
+	if node.Origin != nil && node.Origin.File != "" {
+		return node.Origin.File
+	}
+	return node.Test.SourceDir
+}
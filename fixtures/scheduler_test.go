@@ -0,0 +1,159 @@
+package fixtures_test
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/flanksource/clicky/task"
+	"github.com/flanksource/gavel/fixtures"
+)
+
+func TestRunnerParallelKeepsTreeOrder(t *testing.T) {
+	path := writeFixtureFile(t, `
+---
+exec: bash
+args: ["-c", "sleep 1; echo {{.word}}"]
+parallel: 4
+---
+
+| Name | word | CEL Validation |
+|------|------|----------------|
+| one | alpha | stdout.contains("alpha") |
+| two | beta | stdout.contains("beta") |
+| three | gamma | stdout.contains("gamma") |
+| four | delta | stdout.contains("delta") |
+`)
+
+	runner, err := fixtures.NewRunner(fixtures.RunnerOptions{
+		Paths:   []string{path},
+		WorkDir: filepath.Dir(path),
+	})
+	if err != nil {
+		t.Fatalf("new runner: %v", err)
+	}
+
+	start := time.Now()
+	tree := runner.RunTesting(t)
+	if elapsed := time.Since(start); elapsed > 3*time.Second {
+		t.Fatalf("expected rows to run concurrently, took %s", elapsed)
+	}
+
+	table := findFixtureNode(tree, fixtures.TableNode, "Table 1")
+	if table == nil || len(table.Children) != 4 {
+		t.Fatalf("expected four table rows, got %#v", table)
+	}
+	for i, name := range []string{"one", "two", "three", "four"} {
+		row := table.Children[i]
+		if row.Name != name || row.Results == nil || row.Results.Status != task.StatusPASS {
+			t.Fatalf("row %d: expected passing %q, got %q %#v", i, name, row.Name, row.Results)
+		}
+	}
+}
+
+func TestRunnerLockSerializesFixtures(t *testing.T) {
+	dir := t.TempDir()
+	// mkdir fails if another fixture holding the same lock is still running
+	path := writeFixtureFile(t, fmt.Sprintf(`
+---
+exec: bash
+args: ["-c", "mkdir %[1]s/held && sleep 0.3 && rmdir %[1]s/held"]
+parallel: 4
+---
+
+| Name | lock |
+|------|------|
+| first | db |
+| second | db |
+| third | db |
+| fourth | db |
+`, dir))
+
+	runner, err := fixtures.NewRunner(fixtures.RunnerOptions{
+		Paths:   []string{path},
+		WorkDir: filepath.Dir(path),
+	})
+	if err != nil {
+		t.Fatalf("new runner: %v", err)
+	}
+	runner.RunTesting(t)
+}
+
+func TestRunnerLockWaitDoesNotCountAgainstTimeout(t *testing.T) {
+	// The last row queues behind three others for longer than its own timeout
+	path := writeFixtureFile(t, `
+---
+exec: bash
+args: ["-c", "sleep 0.5"]
+parallel: 4
+---
+
+| Name | lock |
+|------|------|
+| first | db |
+| second | db |
+| third | db |
+| fourth | db |
+`)
+
+	runner, err := fixtures.NewRunner(fixtures.RunnerOptions{
+		Paths:   []string{path},
+		WorkDir: filepath.Dir(path),
+		Timeout: time.Second,
+	})
+	if err != nil {
+		t.Fatalf("new runner: %v", err)
+	}
+
+	tree := runner.RunTesting(t)
+	table := findFixtureNode(tree, fixtures.TableNode, "Table 1")
+	if table == nil || len(table.Children) != 4 {
+		t.Fatalf("expected four table rows, got %#v", table)
+	}
+	for _, row := range table.Children {
+		if row.Results == nil || row.Results.Status != task.StatusPASS {
+			t.Fatalf("row %q: expected to pass after waiting for the lock, got %#v", row.Name, row.Results)
+		}
+	}
+}
+
+func TestRunnerTimeoutBoundsFixtureHoldingLock(t *testing.T) {
+	// Holding the lock must not exempt a fixture from its own timeout
+	path := writeFixtureFile(t, `
+---
+exec: bash
+args: ["-c", "sleep 5"]
+---
+
+| Name | lock |
+|------|------|
+| slow | db |
+`)
+
+	runner, err := fixtures.NewRunner(fixtures.RunnerOptions{
+		Paths:   []string{path},
+		WorkDir: filepath.Dir(path),
+		Timeout: 500 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("new runner: %v", err)
+	}
+
+	start := time.Now()
+	tree, err := runner.Run()
+	if elapsed := time.Since(start); elapsed > 3*time.Second {
+		t.Fatalf("expected the fixture to be stopped at its timeout, took %s", elapsed)
+	}
+	if err == nil {
+		t.Fatalf("expected the run to fail")
+	}
+	row := findFixtureNode(tree, fixtures.TestNode, "slow")
+	if row == nil || row.Results == nil || row.Results.Status != task.StatusFAIL {
+		t.Fatalf("expected slow to fail, got %#v", row)
+	}
+	if !strings.Contains(row.Results.Error, "timed out") {
+		t.Fatalf("expected a timeout error, got %q", row.Results.Error)
+	}
+}
@@ -0,0 +1,201 @@
+package fixtures
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultScriptTimeout bounds each expect step and the wait for the program
+// to exit after the last step.
+const DefaultScriptTimeout = 10 * time.Second
+
+// ScriptStep is one step of an interactive script. Each step sets exactly one
+// of Expect, Send, Key or Resize.
+type ScriptStep struct {
+	// Expect waits until the settled screen matches this regex
+	Expect string `yaml:"expect,omitempty" json:"expect,omitempty"`
+	// Send writes text to the terminal as-is (use "\r" for enter in double-quoted YAML)
+	Send string `yaml:"send,omitempty" json:"send,omitempty"`
+	// Key sends space-separated named keys, e.g. "down down enter" or "ctrl+c"
+	Key string `yaml:"key,omitempty" json:"key,omitempty"`
+	// Resize changes the terminal size, as COLSxROWS (e.g. 120x40)
+	Resize string `yaml:"resize,omitempty" json:"resize,omitempty"`
+	// Timeout overrides DefaultScriptTimeout for an expect step
+	Timeout *time.Duration `yaml:"timeout,omitempty" json:"timeout,omitempty"`
+}
+
+func (s ScriptStep) String() string {
+	switch {
+	case s.Expect != "":
+		return fmt.Sprintf("expect /%s/", s.Expect)
+	case s.Send != "":
+		return fmt.Sprintf("send %q", s.Send)
+	case s.Key != "":
+		return "key " + s.Key
+	case s.Resize != "":
+		return "resize " + s.Resize
+	}
+	return "empty step"
+}
+
+var scriptKeys = map[string]string{
+	"enter":     "\r",
+	"return":    "\r",
+	"tab":       "\t",
+	"shift+tab": "\x1b[Z",
+	"esc":       "\x1b",
+	"escape":    "\x1b",
+	"space":     " ",
+	"backspace": "\x7f",
+	"delete":    "\x1b[3~",
+	"up":        "\x1b[A",
+	"down":      "\x1b[B",
+	"right":     "\x1b[C",
+	"left":      "\x1b[D",
+	"home":      "\x1b[H",
+	"end":       "\x1b[F",
+	"pgup":      "\x1b[5~",
+	"pgdown":    "\x1b[6~",
+}
+
+// ScriptKeys translates space-separated key names into the bytes a terminal
+// sends for them. ctrl+<letter> maps to the matching control character.
+func ScriptKeys(keys string) (string, error) {
+	var out strings.Builder
+	for _, name := range strings.Fields(strings.ToLower(keys)) {
+		if seq, ok := scriptKeys[name]; ok {
+			out.WriteString(seq)
+			continue
+		}
+		if letter, ok := strings.CutPrefix(name, "ctrl+"); ok && len(letter) == 1 && letter[0] >= 'a' && letter[0] <= 'z' {
+			out.WriteByte(letter[0] & 0x1f)
+			continue
+		}
+		return "", fmt.Errorf("unknown key %q", name)
+	}
+	return out.String(), nil
+}
+
+// ParseTerminalSize parses a COLSxROWS size such as 120x40.
+func ParseTerminalSize(size string) (cols, rows int, err error) {
+	c, r, ok := strings.Cut(strings.ToLower(strings.TrimSpace(size)), "x")
+	if ok {
+		cols, err = strconv.Atoi(c)
+		if err == nil {
+			rows, err = strconv.Atoi(r)
+		}
+	}
+	if !ok || err != nil || cols <= 0 || rows <= 0 {
+		return 0, 0, fmt.Errorf("invalid terminal size %q, expected COLSxROWS", size)
+	}
+	return cols, rows, nil
+}
+
+// Screen collects the output of a PTY and renders it the way a terminal
+// would display it, using the same settling as ansi.final_text.
+type Screen struct {
+	mu      sync.Mutex
+	raw     bytes.Buffer
+	changed chan struct{}
+}
+
+func NewScreen() *Screen {
+	return &Screen{changed: make(chan struct{}, 1)}
+}
+
+func (s *Screen) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	s.raw.Write(p)
+	s.mu.Unlock()
+	select {
+	case s.changed <- struct{}{}:
+	default:
+	}
+	return len(p), nil
+}
+
+// Raw returns everything written so far, escape sequences included.
+func (s *Screen) Raw() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.raw.String()
+}
+
+// Text returns the settled screen contents.
+func (s *Screen) Text() string {
+	return settleANSI(s.Raw())
+}
+
+// ScriptTerminal is the terminal a script writes keystrokes to.
+type ScriptTerminal interface {
+	io.Writer
+	Resize(cols, rows int) error
+}
+
+// RunScript plays steps against term, matching expect steps on screen. closed
+// is closed once the program's output has ended, so an expect that can no
+// longer match fails straight away instead of waiting for its timeout.
+func RunScript(ctx context.Context, term ScriptTerminal, screen *Screen, closed <-chan struct{}, steps []ScriptStep) error {
+	for i, step := range steps {
+		if err := runScriptStep(ctx, term, screen, closed, step); err != nil {
+			return fmt.Errorf("script step %d (%s): %w", i+1, step, err)
+		}
+	}
+	return nil
+}
+
+func runScriptStep(ctx context.Context, term ScriptTerminal, screen *Screen, closed <-chan struct{}, step ScriptStep) error {
+	switch {
+	case step.Expect != "":
+		re, err := regexp.Compile(step.Expect)
+		if err != nil {
+			return fmt.Errorf("invalid expect regex: %w", err)
+		}
+		timeout := DefaultScriptTimeout
+		if step.Timeout != nil {
+			timeout = *step.Timeout
+		}
+		deadline := time.After(timeout)
+		for {
+			if re.MatchString(screen.Text()) {
+				return nil
+			}
+			select {
+			case <-screen.changed:
+			case <-closed:
+				if re.MatchString(screen.Text()) {
+					return nil
+				}
+				return fmt.Errorf("program exited before the screen matched")
+			case <-deadline:
+				return fmt.Errorf("screen did not match within %s", timeout)
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+	case step.Send != "":
+		_, err := io.WriteString(term, step.Send)
+		return err
+	case step.Key != "":
+		keys, err := ScriptKeys(step.Key)
+		if err != nil {
+			return err
+		}
+		_, err = io.WriteString(term, keys)
+		return err
+	case step.Resize != "":
+		cols, rows, err := ParseTerminalSize(step.Resize)
+		if err != nil {
+			return err
+		}
+		return term.Resize(cols, rows)
+	}
+	return fmt.Errorf("step must set one of expect, send, key or resize")
+}
@@ -0,0 +1,37 @@
+package fixtures
+
+import "testing"
+
+func TestScriptKeys(t *testing.T) {
+	got, err := ScriptKeys("down Down enter ctrl+c tab")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := "\x1b[B\x1b[B\r\x03\t"; got != want {
+		t.Fatalf("ScriptKeys: got %q, want %q", got, want)
+	}
+	if _, err := ScriptKeys("ctrl+enter"); err == nil {
+		t.Fatalf("expected unknown key error")
+	}
+}
+
+func TestParseTerminalSize(t *testing.T) {
+	cols, rows, err := ParseTerminalSize("120x40")
+	if err != nil || cols != 120 || rows != 40 {
+		t.Fatalf("ParseTerminalSize: got %dx%d %v", cols, rows, err)
+	}
+	for _, bad := range []string{"", "120", "0x40", "wide x tall"} {
+		if _, _, err := ParseTerminalSize(bad); err == nil {
+			t.Fatalf("expected %q to be rejected", bad)
+		}
+	}
+}
+
+func TestScreenTextSettlesRedraws(t *testing.T) {
+	screen := NewScreen()
+	_, _ = screen.Write([]byte("> apple\n  banana\n"))
+	_, _ = screen.Write([]byte("\x1b[2A\x1b[2K  apple\n\x1b[2K> banana\n"))
+	if got, want := screen.Text(), "  apple\n> banana\n"; got != want {
+		t.Fatalf("Screen.Text: got %q, want %q", got, want)
+	}
+}
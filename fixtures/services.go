@@ -0,0 +1,375 @@
+package fixtures
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/flanksource/commons/logger"
+)
+
+const (
+	defaultServiceTimeout = 30 * time.Second
+	serviceLogLimit       = 64 * 1024
+)
+
+// ServiceSpec is a background process declared under services: in the
+// front-matter. It is started before the first fixture of its file runs and
+// stopped once the last one finishes.
+type ServiceSpec struct {
+	// Command is run with sh -c; {{.port}} is a free port reserved for it
+	Command string `yaml:"command" json:"command"`
+	// Env adds environment variables to the service process
+	Env map[string]any `yaml:"env,omitempty" json:"env,omitempty"`
+	// CWD is the working directory, relative to the fixture file
+	CWD string `yaml:"cwd,omitempty" json:"cwd,omitempty"`
+	// Port pins the service port instead of picking a free one
+	Port int `yaml:"port,omitempty" json:"port,omitempty"`
+	// Ready is the readiness probe; defaults to waiting for the port to open
+	Ready ReadinessProbe `yaml:"ready,omitempty" json:"ready,omitempty"`
+	// Timeout bounds the wait for readiness (default 30s)
+	Timeout *time.Duration `yaml:"timeout,omitempty" json:"timeout,omitempty"`
+}
+
+// ReadinessProbe lists the conditions that must all hold before a service is
+// considered ready.
+type ReadinessProbe struct {
+	// Port waits for the service port to accept TCP connections
+	Port bool `yaml:"port,omitempty" json:"port,omitempty"`
+	// HTTP waits for a 200 response; a path is resolved against the service URL
+	HTTP string `yaml:"http,omitempty" json:"http,omitempty"`
+	// Log waits for a line of service output matching this regex
+	Log string `yaml:"log,omitempty" json:"log,omitempty"`
+}
+
+func (p ReadinessProbe) IsEmpty() bool {
+	return !p.Port && p.HTTP == "" && p.Log == ""
+}
+
+// ServiceVarName returns the template variable prefix for a service, e.g.
+// "api-server" becomes "API_SERVER" and exposes API_SERVER_PORT,
+// API_SERVER_ADDR and API_SERVER_URL.
+func ServiceVarName(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		default:
+			return '_'
+		}
+	}, name)
+}
+
+// serviceLog keeps the last serviceLogLimit bytes of a service's output.
+type serviceLog struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (l *serviceLog) Write(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.buf.Write(p)
+	if over := l.buf.Len() - serviceLogLimit; over > 0 {
+		l.buf.Next(over)
+	}
+	return len(p), nil
+}
+
+func (l *serviceLog) String() string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.buf.String()
+}
+
+type runningService struct {
+	name string
+	port int
+	cmd  *exec.Cmd
+	logs *serviceLog
+	done chan struct{}
+}
+
+// fileServices owns the services of one fixture file. Start is idempotent;
+// the services stop when every fixture of the file has called finish.
+type fileServices struct {
+	file      string
+	sourceDir string
+	specs     map[string]ServiceSpec
+
+	once    sync.Once
+	err     error
+	vars    map[string]string
+	running []*runningService
+
+	mu      sync.Mutex
+	pending int
+	stopped bool
+}
+
+// newFileServices groups the fixtures that declare services by file. Files
+// without services are left out.
+func newFileServices(nodes []*FixtureNode) map[string]*fileServices {
+	out := map[string]*fileServices{}
+	for _, node := range nodes {
+		if len(node.Test.Services) == 0 {
+			continue
+		}
+		key := fixtureFileKey(node)
+		fs, ok := out[key]
+		if !ok {
+			fs = &fileServices{
+				file:      key,
+				sourceDir: node.Test.SourceDir,
+				specs:     node.Test.Services,
+			}
+			out[key] = fs
+		}
+		fs.pending++
+	}
+	return out
+}
+
+// Start launches the services in name order, so later services can use the
+// variables of earlier ones, and waits for each to become ready.
+func (fs *fileServices) Start(ctx context.Context, workDir string) error {
+	fs.once.Do(func() {
+		fs.vars = map[string]string{}
+		names := make([]string, 0, len(fs.specs))
+		for name := range fs.specs {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			svc, err := fs.startService(ctx, name, fs.specs[name], workDir)
+			if svc != nil {
+				fs.running = append(fs.running, svc)
+			}
+			if err != nil {
+				fs.err = fmt.Errorf("service %s: %w", name, err)
+				fs.Stop()
+				return
+			}
+			prefix := ServiceVarName(name)
+			addr := net.JoinHostPort("localhost", strconv.Itoa(svc.port))
+			fs.vars[prefix+"_PORT"] = strconv.Itoa(svc.port)
+			fs.vars[prefix+"_ADDR"] = addr
+			fs.vars[prefix+"_URL"] = "http://" + addr
+		}
+	})
+	return fs.err
+}
+
+func (fs *fileServices) startService(ctx context.Context, name string, spec ServiceSpec, workDir string) (*runningService, error) {
+	port := spec.Port
+	if port == 0 {
+		var err error
+		if port, err = freePort(); err != nil {
+			return nil, fmt.Errorf("failed to find free port: %w", err)
+		}
+	}
+
+	data := map[string]any{
+		"port":    strconv.Itoa(port),
+		"PWD":     workDir,
+		"WorkDir": workDir,
+		"GOOS":    runtime.GOOS,
+		"GOARCH":  runtime.GOARCH,
+	}
+	for k, v := range fs.vars {
+		data[k] = v
+	}
+	command, err := TemplateString(spec.Command, data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to template command: %w", err)
+	}
+	if strings.TrimSpace(command) == "" {
+		return nil, fmt.Errorf("no command specified")
+	}
+
+	dir := fs.sourceDir
+	if dir == "" {
+		dir = workDir
+	}
+	if spec.CWD != "" {
+		cwd, err := TemplateString(spec.CWD, data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to template cwd: %w", err)
+		}
+		if filepath.IsAbs(cwd) {
+			dir = cwd
+		} else {
+			dir = filepath.Join(dir, cwd)
+		}
+	}
+
+	svc := &runningService{name: name, port: port, logs: &serviceLog{}, done: make(chan struct{})}
+	svc.cmd = exec.Command("sh", "-c", command)
+	svc.cmd.Dir = dir
+	svc.cmd.Stdout = svc.logs
+	svc.cmd.Stderr = svc.logs
+	svc.cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	svc.cmd.Env = append(os.Environ(), "PORT="+strconv.Itoa(port))
+	for k, v := range fs.vars {
+		svc.cmd.Env = append(svc.cmd.Env, k+"="+v)
+	}
+	for k, v := range spec.Env {
+		value, err := TemplateString(fmt.Sprint(v), data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to template env %s: %w", k, err)
+		}
+		svc.cmd.Env = append(svc.cmd.Env, k+"="+value)
+	}
+
+	logger.Infof("Starting service %s on port %d: %s", name, port, command)
+	if err := svc.cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start: %w", err)
+	}
+	go func() {
+		_ = svc.cmd.Wait()
+		close(svc.done)
+	}()
+
+	timeout := defaultServiceTimeout
+	if spec.Timeout != nil {
+		timeout = *spec.Timeout
+	}
+	if err := svc.waitReady(ctx, spec.Ready, timeout); err != nil {
+		return svc, err
+	}
+	logger.Infof("Service %s ready on port %d", name, port)
+	return svc, nil
+}
+
+// waitReady polls the probe until every condition holds, the process exits
+// or the timeout passes.
+func (svc *runningService) waitReady(ctx context.Context, probe ReadinessProbe, timeout time.Duration) error {
+	if probe.IsEmpty() {
+		probe.Port = true
+	}
+	var logRe *regexp.Regexp
+	if probe.Log != "" {
+		var err error
+		if logRe, err = regexp.Compile(probe.Log); err != nil {
+			return fmt.Errorf("invalid log probe: %w", err)
+		}
+	}
+	url := probe.HTTP
+	if url != "" && !strings.Contains(url, "://") {
+		url = fmt.Sprintf("http://localhost:%d/%s", svc.port, strings.TrimPrefix(url, "/"))
+	}
+	client := &http.Client{Timeout: time.Second}
+	addr := net.JoinHostPort("localhost", strconv.Itoa(svc.port))
+
+	deadline := time.After(timeout)
+	for {
+		ready := true
+		if probe.Port {
+			conn, err := net.DialTimeout("tcp", addr, 200*time.Millisecond)
+			if err != nil {
+				ready = false
+			} else {
+				_ = conn.Close()
+			}
+		}
+		if ready && url != "" {
+			resp, err := client.Get(url)
+			if err != nil {
+				ready = false
+			} else {
+				_ = resp.Body.Close()
+				ready = resp.StatusCode == http.StatusOK
+			}
+		}
+		if ready && logRe != nil {
+			ready = logRe.MatchString(svc.logs.String())
+		}
+		if ready {
+			return nil
+		}
+
+		select {
+		case <-svc.done:
+			return fmt.Errorf("exited before becoming ready (exit code %d)", svc.cmd.ProcessState.ExitCode())
+		case <-deadline:
+			return fmt.Errorf("not ready within %s", timeout)
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(200 * time.Millisecond):
+		}
+	}
+}
+
+// Vars returns the template variables describing the running services.
+func (fs *fileServices) Vars() map[string]string {
+	return fs.vars
+}
+
+// Logs returns the captured output of every started service.
+func (fs *fileServices) Logs() map[string]string {
+	logs := make(map[string]string, len(fs.running))
+	for _, svc := range fs.running {
+		logs[svc.name] = svc.logs.String()
+	}
+	return logs
+}
+
+// Finish records that one fixture of the file is done and stops the services
+// after the last one.
+func (fs *fileServices) Finish() {
+	fs.mu.Lock()
+	fs.pending--
+	last := fs.pending <= 0
+	fs.mu.Unlock()
+	if last {
+		fs.Stop()
+	}
+}
+
+// Stop terminates the services in reverse start order. It is safe to call
+// more than once.
+func (fs *fileServices) Stop() {
+	fs.mu.Lock()
+	if fs.stopped {
+		fs.mu.Unlock()
+		return
+	}
+	fs.stopped = true
+	fs.mu.Unlock()
+
+	for i := len(fs.running) - 1; i >= 0; i-- {
+		fs.running[i].stop()
+	}
+}
+
+// stop sends SIGTERM to the process group, waits up to 5s, then SIGKILL.
+func (svc *runningService) stop() {
+	if svc.cmd.Process == nil {
+		return
+	}
+	logger.Infof("Stopping service %s (PID %d)", svc.name, svc.cmd.Process.Pid)
+	pgid := -svc.cmd.Process.Pid
+	_ = syscall.Kill(pgid, syscall.SIGTERM)
+	select {
+	case <-svc.done:
+	case <-time.After(5 * time.Second):
+		logger.Warnf("Service %s did not exit after SIGTERM, sending SIGKILL", svc.name)
+		_ = syscall.Kill(pgid, syscall.SIGKILL)
+		<-svc.done
+	}
+}
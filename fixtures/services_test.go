@@ -0,0 +1,111 @@
+package fixtures_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"testing"
+
+	"github.com/flanksource/clicky/task"
+	"github.com/flanksource/gavel/fixtures"
+)
+
+func TestRunnerServicesLifecycle(t *testing.T) {
+	pidFile := filepath.Join(t.TempDir(), "worker.pid")
+	path := writeFixtureFile(t, fmt.Sprintf(`
+---
+exec: bash
+args: ["-c", "echo port=$WORKER_PORT addr={{.WORKER_ADDR}}"]
+services:
+  worker:
+    command: echo $$ > %s; echo "worker listening on {{.port}}"; exec sleep 30
+    ready:
+      log: "worker listening on [0-9]+"
+    timeout: 10s
+---
+
+| Name | CEL Validation |
+|------|----------------|
+| sees service vars | stdout.matches("port=[0-9]+ addr=localhost:[0-9]+") |
+| fails | stdout.contains("nope") |
+`, pidFile))
+
+	runner, err := fixtures.NewRunner(fixtures.RunnerOptions{
+		Paths:   []string{path},
+		WorkDir: filepath.Dir(path),
+	})
+	if err != nil {
+		t.Fatalf("new runner: %v", err)
+	}
+	tree, _ := runner.Run()
+
+	table := findFixtureNode(tree, fixtures.TableNode, "Table 1")
+	if table == nil || len(table.Children) != 2 {
+		t.Fatalf("expected two table rows, got %#v", table)
+	}
+	ok, failed := table.Children[0].Results, table.Children[1].Results
+	if ok == nil || ok.Status != task.StatusPASS {
+		t.Fatalf("expected service vars to be injected, got %#v", ok)
+	}
+	if len(ok.ServiceLogs) != 0 {
+		t.Fatalf("expected no service logs on a passing fixture, got %v", ok.ServiceLogs)
+	}
+	if failed == nil || failed.Status != task.StatusFAIL {
+		t.Fatalf("expected second row to fail, got %#v", failed)
+	}
+	if !strings.Contains(failed.ServiceLogs["worker"], "worker listening on") {
+		t.Fatalf("expected worker logs on failed fixture, got %v", failed.ServiceLogs)
+	}
+
+	data, err := os.ReadFile(pidFile)
+	if err != nil {
+		t.Fatalf("read pid: %v", err)
+	}
+	pid, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil {
+		t.Fatalf("parse pid: %v", err)
+	}
+	if err := syscall.Kill(pid, 0); err == nil {
+		t.Fatalf("expected service process %d to be stopped after the run", pid)
+	}
+}
+
+func TestRunnerServiceExitingBeforeReady(t *testing.T) {
+	path := writeFixtureFile(t, `
+---
+exec: bash
+args: ["-c", "echo unreachable"]
+services:
+  broken:
+    command: echo "boom"; exit 3
+---
+
+| Name |
+|------|
+| never runs |
+`)
+
+	runner, err := fixtures.NewRunner(fixtures.RunnerOptions{
+		Paths:   []string{path},
+		WorkDir: filepath.Dir(path),
+	})
+	if err != nil {
+		t.Fatalf("new runner: %v", err)
+	}
+	tree, _ := runner.Run()
+
+	table := findFixtureNode(tree, fixtures.TableNode, "Table 1")
+	if table == nil || len(table.Children) != 1 || table.Children[0].Results == nil {
+		t.Fatalf("expected one row with a result, got %#v", table)
+	}
+	result := table.Children[0].Results
+	if result.Status != task.StatusERR || !strings.Contains(result.Error, "service broken: exited before becoming ready") {
+		t.Fatalf("expected service start error, got %s: %s", result.Status, result.Error)
+	}
+	if !strings.Contains(result.ServiceLogs["broken"], "boom") {
+		t.Fatalf("expected service output in logs, got %v", result.ServiceLogs)
+	}
+}
@@ -0,0 +1,425 @@
+package fixtures
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/flanksource/commons/logger"
+	"github.com/goccy/go-yaml"
+	"github.com/ohler55/ojg/alt"
+	"github.com/ohler55/ojg/jp"
+)
+
+// maxSnapshotDiffs caps the differences listed in a failure message.
+const maxSnapshotDiffs = 50
+
+// snapshotPlaceholders are the <name> tokens a snapshot may use in place of
+// values that change between runs. A string may mix literal text and
+// placeholders, e.g. "build-<number>" or "created at <timestamp>".
+var snapshotPlaceholders = map[string]string{
+	"any":       `(?s:.*)`,
+	"uuid":      `[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}`,
+	"timestamp": `\d{4}-\d{2}-\d{2}[T ]\d{2}:\d{2}:\d{2}(?:\.\d+)?(?:Z|[+-]\d{2}:?\d{2})?`,
+	"date":      `\d{4}-\d{2}-\d{2}`,
+	"number":    `-?\d+(?:\.\d+)?(?:[eE][+-]?\d+)?`,
+	"sha":       `[0-9a-f]{7,64}`,
+}
+
+var placeholderToken = regexp.MustCompile(`<([a-z]+)>`)
+
+// placeholderPattern compiles a snapshot string containing placeholders into
+// an anchored regex. "<regex:PATTERN>" as the whole string matches PATTERN.
+// Strings without a known placeholder return nil.
+func placeholderPattern(s string) (*regexp.Regexp, error) {
+	if strings.HasPrefix(s, "<regex:") && strings.HasSuffix(s, ">") {
+		return regexp.Compile("^(?:" + s[len("<regex:"):len(s)-1] + ")$")
+	}
+	matches := placeholderToken.FindAllStringSubmatchIndex(s, -1)
+	var pattern strings.Builder
+	last, found := 0, false
+	for _, m := range matches {
+		re, ok := snapshotPlaceholders[s[m[2]:m[3]]]
+		if !ok {
+			continue
+		}
+		pattern.WriteString(regexp.QuoteMeta(s[last:m[0]]))
+		pattern.WriteString(re)
+		last, found = m[1], true
+	}
+	if !found {
+		return nil, nil
+	}
+	pattern.WriteString(regexp.QuoteMeta(s[last:]))
+	return regexp.Compile("^" + pattern.String() + "$")
+}
+
+// matchPlaceholder reports whether want is a placeholder string and, if so,
+// whether got satisfies it. Non-string scalars are matched on their JSON text.
+func matchPlaceholder(want, got any) (isPlaceholder, ok bool, err error) {
+	s, isString := want.(string)
+	if !isString {
+		return false, false, nil
+	}
+	re, err := placeholderPattern(s)
+	if err != nil {
+		return false, false, err
+	}
+	if re == nil {
+		return false, false, nil
+	}
+	if s == "<any>" {
+		return true, true, nil
+	}
+	switch v := got.(type) {
+	case string:
+		return true, re.MatchString(v), nil
+	case float64, bool:
+		text, _ := json.Marshal(v)
+		return true, re.Match(text), nil
+	}
+	return true, false, nil
+}
+
+// isYAMLSnapshot reports whether a snapshot file should be read and written
+// as YAML rather than JSON.
+func isYAMLSnapshot(path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	return ext == ".yaml" || ext == ".yml"
+}
+
+// parseSnapshot decodes JSON or YAML into plain JSON values (maps, slices,
+// float64, string, bool, nil) so both sides compare the same way.
+func parseSnapshot(text string, asYAML bool) (any, error) {
+	var data any
+	if !asYAML {
+		jsonErr := json.Unmarshal([]byte(text), &data)
+		if jsonErr == nil {
+			return data, nil
+		}
+		// YAML is a superset of JSON, so only its error is less helpful
+		if err := yaml.Unmarshal([]byte(text), &data); err != nil {
+			return nil, jsonErr
+		}
+	} else if err := yaml.Unmarshal([]byte(text), &data); err != nil {
+		return nil, err
+	}
+	// Round-trip through JSON to normalize YAML's integer and map types
+	normalized, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	data = nil
+	if err := json.Unmarshal(normalized, &data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func formatSnapshot(data any, asYAML bool) (string, error) {
+	if asYAML {
+		out, err := yaml.Marshal(yamlIntegers(data))
+		return string(out), err
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	// Keep placeholders readable rather than escaping < and >
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// yamlIntegers turns whole float64 values back into integers so a YAML
+// snapshot keeps "replicas: 3" rather than "replicas: 3.0".
+func yamlIntegers(v any) any {
+	switch t := v.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(t))
+		for k, item := range t {
+			out[k] = yamlIntegers(item)
+		}
+		return out
+	case []any:
+		out := make([]any, len(t))
+		for i, item := range t {
+			out[i] = yamlIntegers(item)
+		}
+		return out
+	case float64:
+		if t == math.Trunc(t) && math.Abs(t) < 1<<53 {
+			return int64(t)
+		}
+	}
+	return v
+}
+
+// snapshotDiff collects the structural differences between want and got,
+// one line per differing JSON path.
+func snapshotDiff(path string, want, got any, diffs *[]string) error {
+	if isPlaceholder, ok, err := matchPlaceholder(want, got); err != nil {
+		return fmt.Errorf("%s: invalid placeholder %q: %w", path, want, err)
+	} else if isPlaceholder {
+		if !ok {
+			*diffs = append(*diffs, fmt.Sprintf("%s: %s does not match %s", path, snapshotValue(got), want))
+		}
+		return nil
+	}
+
+	switch w := want.(type) {
+	case map[string]any:
+		g, ok := got.(map[string]any)
+		if !ok {
+			break
+		}
+		keys := make([]string, 0, len(w)+len(g))
+		for k := range w {
+			keys = append(keys, k)
+		}
+		for k := range g {
+			if _, ok := w[k]; !ok {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			wv, inWant := w[k]
+			gv, inGot := g[k]
+			switch {
+			case !inGot:
+				*diffs = append(*diffs, fmt.Sprintf("%s: missing, expected %s", snapshotPath(path, k), snapshotValue(wv)))
+			case !inWant:
+				*diffs = append(*diffs, fmt.Sprintf("%s: unexpected %s", snapshotPath(path, k), snapshotValue(gv)))
+			default:
+				if err := snapshotDiff(snapshotPath(path, k), wv, gv, diffs); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	case []any:
+		g, ok := got.([]any)
+		if !ok {
+			break
+		}
+		for i := 0; i < len(w) || i < len(g); i++ {
+			itemPath := fmt.Sprintf("%s[%d]", path, i)
+			switch {
+			case i >= len(g):
+				*diffs = append(*diffs, fmt.Sprintf("%s: missing, expected %s", itemPath, snapshotValue(w[i])))
+			case i >= len(w):
+				*diffs = append(*diffs, fmt.Sprintf("%s: unexpected %s", itemPath, snapshotValue(g[i])))
+			default:
+				if err := snapshotDiff(itemPath, w[i], g[i], diffs); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	default:
+		if reflect.DeepEqual(want, got) {
+			return nil
+		}
+	}
+	*diffs = append(*diffs, fmt.Sprintf("%s: expected %s, got %s", path, snapshotValue(want), snapshotValue(got)))
+	return nil
+}
+
+var snapshotIdentifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+func snapshotPath(parent, key string) string {
+	if snapshotIdentifier.MatchString(key) {
+		return parent + "." + key
+	}
+	return fmt.Sprintf("%s[%q]", parent, key)
+}
+
+// snapshotValue renders a value on one line for a diff message.
+func snapshotValue(v any) string {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return fmt.Sprint(v)
+	}
+	out := strings.TrimSpace(buf.String())
+	if len(out) > 80 {
+		return out[:77] + "..."
+	}
+	return out
+}
+
+// mergeSnapshot returns got with the placeholders of want kept wherever they
+// still match, so updating a golden file does not bake in volatile values.
+func mergeSnapshot(want, got any) any {
+	if isPlaceholder, ok, _ := matchPlaceholder(want, got); isPlaceholder && ok {
+		return want
+	}
+	switch w := want.(type) {
+	case map[string]any:
+		g, ok := got.(map[string]any)
+		if !ok {
+			return got
+		}
+		merged := make(map[string]any, len(g))
+		for k, gv := range g {
+			if wv, ok := w[k]; ok {
+				merged[k] = mergeSnapshot(wv, gv)
+			} else {
+				merged[k] = gv
+			}
+		}
+		return merged
+	case []any:
+		g, ok := got.([]any)
+		if !ok {
+			return got
+		}
+		merged := make([]any, len(g))
+		for i, gv := range g {
+			if i < len(w) {
+				merged[i] = mergeSnapshot(w[i], gv)
+			} else {
+				merged[i] = gv
+			}
+		}
+		return merged
+	}
+	return got
+}
+
+// ParseIgnorePaths splits a table cell such as "$.id, $.items[*].created"
+// into JSONPath expressions. Commas inside brackets do not split.
+func ParseIgnorePaths(value string) []string {
+	var paths []string
+	depth, start := 0, 0
+	for i, r := range value {
+		switch r {
+		case '[':
+			depth++
+		case ']':
+			depth--
+		case ',':
+			if depth == 0 {
+				paths = append(paths, value[start:i])
+				start = i + 1
+			}
+		}
+	}
+	paths = append(paths, value[start:])
+	out := paths[:0]
+	for _, p := range paths {
+		if p = strings.TrimSpace(p); p != "" {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// removeIgnored deletes the values matched by the ignore JSONPath expressions
+// from a copy of data. Matches are located first and removed deepest and
+// last first, so recursive paths like $..id work and removing an array
+// element does not shift the indexes of the ones still to go.
+func removeIgnored(data any, ignore []string) (any, error) {
+	if len(ignore) == 0 {
+		return data, nil
+	}
+	data = alt.Dup(data)
+	for _, path := range ignore {
+		x, err := jp.ParseString(path)
+		if err != nil {
+			return nil, fmt.Errorf("invalid ignore path %q: %w", path, err)
+		}
+		locs := x.Locate(data, 0)
+		for i := len(locs) - 1; i >= 0; i-- {
+			if data, err = locs[i].RemoveOne(data); err != nil {
+				return nil, fmt.Errorf("ignore %q: %w", path, err)
+			}
+		}
+	}
+	return data, nil
+}
+
+// compareSnapshot lists the differences between want and got once the
+// ignored paths have been removed from both.
+func compareSnapshot(want, got any, ignore []string) ([]string, error) {
+	want, err := removeIgnored(want, ignore)
+	if err != nil {
+		return nil, err
+	}
+	if got, err = removeIgnored(got, ignore); err != nil {
+		return nil, err
+	}
+	var diffs []string
+	if err := snapshotDiff("$", want, got, &diffs); err != nil {
+		return nil, err
+	}
+	return diffs, nil
+}
+
+// evaluateSnapshot compares got structurally against the snapshot
+// expectation (literal or @file), skipping ignored paths and honouring
+// placeholders. Return values follow evaluateStream; under UpdateGolden a
+// mismatched file is rewritten with its matching placeholders kept.
+func evaluateSnapshot(fixture *FixtureResult, expected string, ignore []string, got, label string, opts EvaluateOptions) (updated, failed bool) {
+	if expected == "" {
+		return false, false
+	}
+	ref, err := ResolveFileRef(opts.SourceDir, expected)
+	if err != nil {
+		*fixture = fixture.Errorf(err, "load snapshot")
+		return false, true
+	}
+	wantText, wantLabel := ref.Raw, "snapshot"
+	if ref.IsFile {
+		wantText, wantLabel = ref.Contents, ref.Path
+	}
+	asYAML := ref.IsFile && isYAMLSnapshot(ref.Path)
+
+	want, err := parseSnapshot(wantText, asYAML)
+	if err != nil {
+		*fixture = fixture.Errorf(err, "parse snapshot %s", wantLabel)
+		return false, true
+	}
+	gotData, err := parseSnapshot(got, asYAML)
+	if err != nil {
+		*fixture = fixture.Failf("%s is not valid JSON/YAML: %v\n  %s: %s", label, err, label, truncateForError(got))
+		return false, true
+	}
+
+	diffs, err := compareSnapshot(want, gotData, ignore)
+	if err != nil {
+		*fixture = fixture.Errorf(err, "compare snapshot %s", wantLabel)
+		return false, true
+	}
+	if len(diffs) == 0 {
+		return false, false
+	}
+
+	if opts.UpdateGolden && ref.IsFile {
+		text, err := formatSnapshot(mergeSnapshot(want, gotData), asYAML)
+		if err == nil {
+			err = WriteGolden(ref.Path, text)
+		}
+		if err != nil {
+			*fixture = fixture.Errorf(err, "update snapshot %s", ref.Path)
+			return false, true
+		}
+		logger.Infof("snapshot updated: %s", ref.Path)
+		return true, false
+	}
+
+	if len(diffs) > maxSnapshotDiffs {
+		diffs = append(diffs[:maxSnapshotDiffs], fmt.Sprintf("... and %d more", len(diffs)-maxSnapshotDiffs))
+	}
+	*fixture = fixture.Failf("%s differs from %s:\n  %s", label, wantLabel, strings.Join(diffs, "\n  "))
+	return false, true
+}
@@ -0,0 +1,138 @@
+package fixtures
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/flanksource/clicky/exec"
+	"github.com/flanksource/clicky/task"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const snapshotGolden = `{
+  "id": "<uuid>",
+  "name": "alice",
+  "created": "<timestamp>",
+  "build": "v1-<number>",
+  "tags": ["admin", "dev"],
+  "meta": {"etag": "abc", "size": "<number>"}
+}
+`
+
+func evaluateSnapshotFixture(exp Expectations, stdout string, opts EvaluateOptions) FixtureResult {
+	fixture := FixtureResult{
+		Name:     "snapshot",
+		Status:   "pending",
+		Metadata: map[string]interface{}{},
+		Test:     FixtureTest{Name: "snapshot", SourceDir: opts.SourceDir},
+	}
+	return exp.Evaluate(fixture, exec.ExecResult{Stdout: stdout}, opts)
+}
+
+func TestEvaluateSnapshot(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "user.json"), snapshotGolden)
+	opts := EvaluateOptions{SourceDir: dir}
+
+	t.Run("placeholders and key order", func(t *testing.T) {
+		result := evaluateSnapshotFixture(Expectations{Snapshot: "@user.json"}, `{
+			"meta": {"size": 42, "etag": "abc"},
+			"tags": ["admin", "dev"],
+			"created": "2026-10-16T10:00:00.123Z",
+			"build": "v1-17",
+			"name": "alice",
+			"id": "0b8c2a4e-6f1d-4c39-9a57-3e2f1d0c9b8a"
+		}`, opts)
+		assert.Equal(t, task.StatusPASS, result.Status, result.Error)
+	})
+
+	t.Run("structural diff", func(t *testing.T) {
+		result := evaluateSnapshotFixture(Expectations{Snapshot: "@user.json"}, `{
+			"id": "not-a-uuid",
+			"name": "bob",
+			"created": "2026-10-16T10:00:00Z",
+			"build": "v1-17",
+			"tags": ["admin"],
+			"meta": {"size": 1, "etag": "abc", "extra": true}
+		}`, opts)
+		assert.Equal(t, task.StatusFAIL, result.Status)
+		assert.Contains(t, result.Error, "stdout differs from "+filepath.Join(dir, "user.json"))
+		assert.Contains(t, result.Error, `$.id: "not-a-uuid" does not match <uuid>`)
+		assert.Contains(t, result.Error, `$.name: expected "alice", got "bob"`)
+		assert.Contains(t, result.Error, `$.tags[1]: missing, expected "dev"`)
+		assert.Contains(t, result.Error, `$.meta.extra: unexpected true`)
+	})
+
+	t.Run("ignore paths", func(t *testing.T) {
+		exp := Expectations{Snapshot: `{"items": [{"id": 1, "name": "a"}, {"id": 2, "name": "b"}], "took": 5}`, Ignore: []string{"$..id", "$.took"}}
+		result := evaluateSnapshotFixture(exp, `{"items": [{"id": 9, "name": "a"}, {"id": 8, "name": "b"}], "took": 12}`, opts)
+		assert.Equal(t, task.StatusPASS, result.Status, result.Error)
+
+		result = evaluateSnapshotFixture(exp, `{"items": [{"id": 9, "name": "a"}], "took": 12}`, opts)
+		assert.Equal(t, task.StatusFAIL, result.Status)
+		assert.Contains(t, result.Error, "$.items[1]: missing")
+	})
+
+	t.Run("yaml golden", func(t *testing.T) {
+		writeFile(t, filepath.Join(dir, "config.yaml"), "name: api\nreplicas: 3\nrevision: <sha>\n")
+		result := evaluateSnapshotFixture(Expectations{Snapshot: "@config.yaml"}, "revision: 5d4384e\nreplicas: 3\nname: api\n", opts)
+		assert.Equal(t, task.StatusPASS, result.Status, result.Error)
+	})
+
+	t.Run("output that is not JSON fails", func(t *testing.T) {
+		result := evaluateSnapshotFixture(Expectations{Snapshot: "@user.json"}, "{not json", opts)
+		assert.Equal(t, task.StatusFAIL, result.Status)
+		assert.Contains(t, result.Error, "stdout is not valid JSON/YAML")
+	})
+}
+
+func TestEvaluateSnapshotUpdateKeepsPlaceholders(t *testing.T) {
+	dir := t.TempDir()
+	goldenPath := filepath.Join(dir, "user.json")
+	writeFile(t, goldenPath, snapshotGolden)
+
+	result := evaluateSnapshotFixture(Expectations{Snapshot: "@user.json"}, `{
+		"id": "0b8c2a4e-6f1d-4c39-9a57-3e2f1d0c9b8a",
+		"name": "bob",
+		"created": "yesterday",
+		"build": "v1-18",
+		"tags": ["admin", "dev", "<ops>"],
+		"meta": {"etag": "abc", "size": 7}
+	}`, EvaluateOptions{SourceDir: dir, UpdateGolden: true})
+	assert.Equal(t, task.StatusPASS, result.Status, result.Error)
+	assert.Equal(t, true, result.Metadata["golden_updated_snapshot"])
+
+	body, err := os.ReadFile(goldenPath)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"id": "<uuid>",
+		"name": "bob",
+		"created": "yesterday",
+		"build": "v1-<number>",
+		"tags": ["admin", "dev", "<ops>"],
+		"meta": {"etag": "abc", "size": "<number>"}
+	}`, string(body))
+	assert.Contains(t, string(body), `"id": "<uuid>"`)
+}
+
+func TestPlaceholderPattern(t *testing.T) {
+	re, err := placeholderPattern("<html>")
+	require.NoError(t, err)
+	assert.Nil(t, re, "unknown tokens are literal text")
+
+	re, err = placeholderPattern("<regex:v[0-9]+>")
+	require.NoError(t, err)
+	assert.True(t, re.MatchString("v12"))
+	assert.False(t, re.MatchString("v12-rc"))
+
+	re, err = placeholderPattern("run <number> of <number>")
+	require.NoError(t, err)
+	assert.True(t, re.MatchString("run 3 of 10"))
+	assert.False(t, re.MatchString("run 3 of ten"))
+}
+
+func TestParseIgnorePaths(t *testing.T) {
+	assert.Equal(t, []string{"$.id", "$['a','b']", "$..created"}, ParseIgnorePaths(" $.id, $['a','b'],, $..created "))
+}
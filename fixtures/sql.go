@@ -0,0 +1,152 @@
+package fixtures
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/flanksource/clicky"
+	"github.com/flanksource/clicky/api"
+	"github.com/flanksource/clicky/task"
+)
+
+// SQLFixtureBase describes the connection used by fixtures of type sql. Like
+// HTTPFixtureBase it is usually set in the file front-matter; setup and
+// teardown can also be written as ```sql setup / ```sql teardown blocks.
+type SQLFixtureBase struct {
+	// DSN is the connection string: postgres://... for postgres, or
+	// sqlite://path, a file path or :memory: for sqlite
+	DSN string `yaml:"dsn,omitempty" json:"dsn,omitempty"`
+	// Setup is executed before every query
+	Setup string `yaml:"setup,omitempty" json:"setup,omitempty"`
+	// Teardown is executed after every query, even when it fails
+	Teardown string `yaml:"teardown,omitempty" json:"teardown,omitempty"`
+}
+
+func (s SQLFixtureBase) IsEmpty() bool {
+	return s.DSN == "" && s.Setup == "" && s.Teardown == ""
+}
+
+// MergeInto overlays the non-empty fields of other onto s.
+func (s SQLFixtureBase) MergeInto(other SQLFixtureBase) SQLFixtureBase {
+	merged := s
+	if other.DSN != "" {
+		merged.DSN = other.DSN
+	}
+	if other.Setup != "" {
+		merged.Setup = other.Setup
+	}
+	if other.Teardown != "" {
+		merged.Teardown = other.Teardown
+	}
+	return merged
+}
+
+// Template expands $VAR references and gomplate templates in the DSN and the
+// setup and teardown scripts.
+func (s SQLFixtureBase) Template(data map[string]any) (SQLFixtureBase, error) {
+	var err error
+	if s.DSN, err = TemplateString(s.DSN, data); err != nil {
+		return SQLFixtureBase{}, fmt.Errorf("dsn: %w", err)
+	}
+	if s.Setup, err = TemplateString(s.Setup, data); err != nil {
+		return SQLFixtureBase{}, fmt.Errorf("setup: %w", err)
+	}
+	if s.Teardown, err = TemplateString(s.Teardown, data); err != nil {
+		return SQLFixtureBase{}, fmt.Errorf("teardown: %w", err)
+	}
+	return s, nil
+}
+
+// Driver returns the database/sql driver family ("postgres" or "sqlite") for
+// the DSN and the connection string to open it with. Relative sqlite paths
+// are resolved against sourceDir.
+func (s SQLFixtureBase) Driver(sourceDir string) (string, string) {
+	dsn := strings.TrimSpace(s.DSN)
+	switch {
+	case strings.HasPrefix(dsn, "postgres://"), strings.HasPrefix(dsn, "postgresql://"):
+		return "postgres", dsn
+	case strings.Contains(dsn, "host=") || strings.Contains(dsn, "dbname="):
+		return "postgres", dsn
+	}
+	dsn = strings.TrimPrefix(dsn, "sqlite://")
+	dsn = strings.TrimPrefix(dsn, "sqlite:")
+	if dsn != ":memory:" && !strings.HasPrefix(dsn, "file:") && !filepath.IsAbs(dsn) && sourceDir != "" {
+		dsn = filepath.Join(sourceDir, dsn)
+	}
+	return "sqlite", dsn
+}
+
+func (s SQLFixtureBase) Pretty() api.Text {
+	driver, _ := s.Driver("")
+	return clicky.Text(driver, "font-bold")
+}
+
+// SQLResult is the outcome of a sql fixture query.
+type SQLResult struct {
+	Query    string           `json:"query"`
+	Columns  []string         `json:"columns"`
+	Rows     []map[string]any `json:"rows"`
+	Duration time.Duration    `json:"duration,omitempty"`
+}
+
+// EvaluateSQL checks a sql fixture result. The row count must equal
+// Expectations.Count when set. The rows are rendered as indented JSON and
+// compared against Stdout (or Output) expectations, so @file goldens work on
+// query results. CEL expressions see rows, count and columns alongside the
+// fixture's template variables.
+func (e Expectations) EvaluateSQL(fixture FixtureResult, res SQLResult, opts EvaluateOptions) FixtureResult {
+	fixture.Command = strings.TrimSpace(res.Query)
+	rows := res.Rows
+	if rows == nil {
+		rows = []map[string]any{}
+	}
+	out, err := json.MarshalIndent(rows, "", "  ")
+	if err != nil {
+		return fixture.Errorf(err, "failed to encode rows")
+	}
+	fixture.Stdout = string(out)
+	if fixture.Metadata == nil {
+		fixture.Metadata = map[string]interface{}{}
+	}
+	fixture.Metadata["count"] = len(rows)
+
+	if e.Count != nil && len(rows) != *e.Count {
+		return fixture.Failf("expected %d rows, got %d\n  rows: %s", *e.Count, len(rows), truncateForError(fixture.Stdout))
+	}
+
+	expected := e.Stdout
+	if expected == "" {
+		expected = e.Output
+	}
+	if updated, failed := evaluateStream(&fixture, expected, fixture.Stdout, "rows", opts); failed {
+		return fixture
+	} else if updated {
+		fixture.Metadata["golden_updated_rows"] = true
+	}
+	if updated, failed := evaluateSnapshot(&fixture, e.Snapshot, e.snapshotIgnore(fixture), fixture.Stdout, "rows", opts); failed {
+		return fixture
+	} else if updated {
+		fixture.Metadata["golden_updated_snapshot"] = true
+	}
+
+	if e.CEL != "" {
+		t := fixture.Test.AsMap()
+		celRows := make([]any, len(rows))
+		for i, row := range rows {
+			celRows[i] = row
+		}
+		t["rows"] = celRows
+		t["count"] = len(rows)
+		t["columns"] = res.Columns
+		t["output"] = fixture.Stdout
+		t["stdout"] = fixture.Stdout
+		if failed := evaluateCEL(&fixture, e.CEL, t); failed {
+			return fixture
+		}
+	}
+	fixture.Status = task.StatusPASS
+	return fixture
+}
@@ -24,6 +24,10 @@ import (
 type FixtureTest struct {
 	FrontMatter     `json:"frontmatter,omitempty"`
 	ExecFixtureBase `json:",inline"`
+	// HTTP holds per-test request overrides for fixtures of type http
+	HTTP HTTPFixtureBase `json:"http,omitempty"`
+	// SQL holds per-test connection overrides for fixtures of type sql
+	SQL SQLFixtureBase `json:"sql,omitempty"`
 
 	// Name of the test to be displayed in reports
 	Name string `json:"name,omitempty"`
@@ -36,6 +40,9 @@ type FixtureTest struct {
 	TestOS   string `json:"test_os,omitempty"`
 	TestArch string `json:"test_arch,omitempty"`
 	TestSkip string `json:"test_skip,omitempty"`
+	// Per-test overrides for scheduling (override file-level FrontMatter values)
+	TestSerial bool   `json:"test_serial,omitempty"`
+	TestLock   string `json:"test_lock,omitempty"`
 
 	TemplateVars map[string]any           `json:"template_vars,omitempty"` // Template variables (.file, .filename, .dir)
 	TempFiles    map[string]*TempFileInfo `json:"temp_files,omitempty"`
@@ -105,10 +112,34 @@ func (fixture FixtureTest) ExecBase() ExecFixtureBase {
 	return fixture.FrontMatter.MergeInto(fixture.ExecFixtureBase)
 }
 
+// HTTPBase merges the test's request fields over the file front-matter.
+func (fixture FixtureTest) HTTPBase() HTTPFixtureBase {
+	return fixture.FrontMatter.HTTP.MergeInto(fixture.HTTP)
+}
+
+// SQLBase merges the test's connection fields over the file front-matter.
+func (fixture FixtureTest) SQLBase() SQLFixtureBase {
+	return fixture.FrontMatter.SQL.MergeInto(fixture.SQL)
+}
+
 func (fixture FixtureTest) Pretty() api.Text {
 	return clicky.Text(fixture.Name, "italic text-orange-500")
 }
 
+// IsSerial reports whether the fixture must run with no other fixture in flight.
+func (fixture FixtureTest) IsSerial() bool {
+	return fixture.TestSerial || fixture.Serial
+}
+
+// LockName returns the mutual-exclusion lock the fixture holds while running,
+// preferring the per-test value over the file front-matter.
+func (fixture FixtureTest) LockName() string {
+	if fixture.TestLock != "" {
+		return fixture.TestLock
+	}
+	return fixture.Lock
+}
+
 // ShouldSkip checks per-test overrides first, then falls back to file-level FrontMatter.
 func (fixture FixtureTest) ShouldSkip() string {
 	os := fixture.TestOS
@@ -144,6 +175,8 @@ type ExecFixtureBase struct {
 	CWD string `yaml:"cwd,omitempty" json:"cwd,omitempty"`
 	// Terminal mode: "pty" to allocate a pseudo-terminal, "" for piped (default)
 	Terminal string `yaml:"terminal,omitempty" json:"terminal,omitempty"`
+	// Script drives an interactive program with send/expect steps; implies terminal: pty
+	Script []ScriptStep `yaml:"script,omitempty" json:"script,omitempty"`
 }
 
 func relativePath(path string) string {
@@ -204,6 +237,22 @@ func (e ExecFixtureBase) Template(data map[string]any) (ExecFixtureBase, error)
 
 	e.CWD = ExpandVars(e.CWD, data)
 
+	// Copy Script so templating Send doesn't write into the frontmatter steps
+	script := make([]ScriptStep, len(e.Script))
+	copy(script, e.Script)
+	e.Script = script
+	for i := range e.Script {
+		if e.Script[i].Send == "" {
+			continue
+		}
+		e.Script[i].Send = ExpandVars(e.Script[i].Send, data)
+		if e.Script[i].Send, err = gomplate.RunTemplate(data, gomplate.Template{
+			Template: e.Script[i].Send,
+		}); err != nil {
+			return ExecFixtureBase{}, err
+		}
+	}
+
 	// Deep copy Args to avoid mutating the shared frontmatter slice
 	args := make([]string, len(e.Args))
 	copy(args, e.Args)
@@ -300,8 +349,11 @@ func (e ExecFixtureBase) MergeInto(other ExecFixtureBase) ExecFixtureBase {
 	if len(other.Args) > 0 {
 		merged.Args = other.Args
 	}
-	if merged.Env == nil {
-		merged.Env = make(map[string]any)
+	// Copy rather than write into e.Env: the front-matter map is shared by
+	// every fixture of the file, which may be merging concurrently
+	merged.Env = make(map[string]any, len(e.Env)+len(other.Env))
+	for k, v := range e.Env {
+		merged.Env[k] = v
 	}
 	for k, v := range other.Env {
 		merged.Env[k] = v
@@ -310,6 +362,9 @@ func (e ExecFixtureBase) MergeInto(other ExecFixtureBase) ExecFixtureBase {
 	if other.Terminal != "" {
 		merged.Terminal = other.Terminal
 	}
+	if len(other.Script) > 0 {
+		merged.Script = other.Script
+	}
 
 	if merged.Exec == "" {
 		merged.Exec = "bash"
@@ -324,6 +379,14 @@ func (e ExecFixtureBase) MergeInto(other ExecFixtureBase) ExecFixtureBase {
 type FrontMatter struct {
 	ExecFixtureBase `yaml:",inline" json:",inline"`
 
+	// Type selects the fixture type for every test in the file (e.g. "http");
+	// empty infers exec or query from the test fields
+	Type string `yaml:"type,omitempty" json:"type,omitempty"`
+	// HTTP is the request template for fixtures of type http
+	HTTP HTTPFixtureBase `yaml:",inline" json:"http,omitempty"`
+	// SQL is the connection for fixtures of type sql
+	SQL SQLFixtureBase `yaml:",inline" json:"sql,omitempty"`
+
 	Files string `yaml:"files,omitempty" json:"files,omitempty"` // Glob pattern to match files
 
 	// CodeBlocks specifies which code block languages to execute (defaults to ["bash"])
@@ -342,11 +405,31 @@ type FrontMatter struct {
 	// Skip is a bash command; if it exits 0 (true), the fixture is skipped.
 	Skip string `yaml:"skip,omitempty" json:"skip,omitempty"`
 
+	// Parallel is the number of fixtures from this file that may run at once,
+	// overriding the runner's --parallel default.
+	Parallel int `yaml:"parallel,omitempty" json:"parallel,omitempty"`
+
+	// Serial runs each fixture with no other fixture in flight.
+	Serial bool `yaml:"serial,omitempty" json:"serial,omitempty"`
+
+	// Lock names a mutex held while each fixture runs, so fixtures sharing a
+	// port or database across files never overlap.
+	Lock string `yaml:"lock,omitempty" json:"lock,omitempty"`
+
+	// Ignore lists JSONPath expressions left out of every snapshot comparison
+	// in the file, in addition to each test's own ignore paths.
+	Ignore []string `yaml:"ignore,omitempty" json:"ignore,omitempty"`
+
+	// Services are background processes started before the file's fixtures
+	// and stopped after them, keyed by name.
+	Services map[string]ServiceSpec `yaml:"services,omitempty" json:"services,omitempty"`
+
 	Metadata map[string]interface{} `yaml:",inline" json:"metadata,omitempty"`
 }
 
 // CleanMetadata removes keys from Metadata that match struct field yaml tags.
 // This fixes a goccy/go-yaml bug where inline maps capture ALL fields.
+// HTTP and SQL keys are only removed for files of that type.
 func (f *FrontMatter) CleanMetadata() {
 	if f.Metadata == nil {
 		return
@@ -359,13 +442,38 @@ func (f *FrontMatter) CleanMetadata() {
 	delete(f.Metadata, "env")
 	delete(f.Metadata, "cwd")
 	delete(f.Metadata, "terminal")
+	delete(f.Metadata, "script")
+	// Keys from HTTPFixtureBase and SQLFixtureBase belong to their fixture
+	// type; any other file keeps them as template variables.
+	if f.Type == "http" {
+		delete(f.Metadata, "method")
+		delete(f.Metadata, "url")
+		delete(f.Metadata, "headers")
+		delete(f.Metadata, "body")
+		delete(f.Metadata, "retries")
+	} else {
+		f.HTTP = HTTPFixtureBase{}
+	}
+	if f.Type == "sql" {
+		delete(f.Metadata, "dsn")
+		delete(f.Metadata, "setup")
+		delete(f.Metadata, "teardown")
+	} else {
+		f.SQL = SQLFixtureBase{}
+	}
 	// Keys from FrontMatter itself
+	delete(f.Metadata, "type")
 	delete(f.Metadata, "files")
 	delete(f.Metadata, "codeBlocks")
 	delete(f.Metadata, "timeout")
 	delete(f.Metadata, "os")
 	delete(f.Metadata, "arch")
 	delete(f.Metadata, "skip")
+	delete(f.Metadata, "parallel")
+	delete(f.Metadata, "ignore")
+	delete(f.Metadata, "serial")
+	delete(f.Metadata, "lock")
+	delete(f.Metadata, "services")
 }
 
 // ShouldSkip returns a non-empty reason string if the fixture should be skipped
@@ -450,6 +558,9 @@ type FixtureResult struct {
 	Metadata map[string]interface{} `json:"metadata,omitempty" pretty:"label=Metadata,omitempty"`
 	Start    *time.Time             `json:"start,omitempty" pretty:"label=Start Time,omitempty"`
 	Display  *DisplayOptions        `json:"-"`
+
+	// ServiceLogs holds the output of the file's services, attached on failure
+	ServiceLogs map[string]string `json:"service_logs,omitempty"`
 }
 
 func (f FixtureResult) Failf(format string, args ...interface{}) FixtureResult {
@@ -547,6 +658,17 @@ func (f FixtureResult) Pretty() api.Text {
 			Content: clicky.Text(f.Stderr, "text-red-500 font-mono text-xs whitespace-pre-wrap"),
 		})
 	}
+	services := make([]string, 0, len(f.ServiceLogs))
+	for name := range f.ServiceLogs {
+		services = append(services, name)
+	}
+	sort.Strings(services)
+	for _, name := range services {
+		t = t.NewLine().Add(api.Collapsed{
+			Label:   "service " + name,
+			Content: clicky.Text(f.ServiceLogs[name], "font-mono text-xs whitespace-pre-wrap"),
+		})
+	}
 
 	return t
 }
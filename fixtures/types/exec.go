@@ -9,6 +9,7 @@ import (
 	osExec "os/exec"
 	"path/filepath"
 	"runtime"
+	"syscall"
 	"time"
 
 	"github.com/creack/pty"
@@ -41,35 +42,7 @@ func (e *ExecFixture) Run(ctx context.Context, fixture fixtures.FixtureTest, opt
 	// Compute root dirs from the fixture source directory first. The CWD
 	// itself may reference these auto-injected vars, so it must be templated
 	// before we resolve the final working directory.
-	sourceDir := ResolveSourceDir(fixture, opts)
-	gitRoot := repomap.FindGitRoot(sourceDir)
-	goRoot := findGoModRoot(sourceDir)
-	rootDir := gitRoot
-	if rootDir == "" {
-		rootDir = goRoot
-	}
-	if rootDir == "" {
-		rootDir = sourceDir
-	}
-
-	if gitRoot != goRoot {
-		logger.V(3).Infof("Directories: source=%s git=%s go=%s root=%s", sourceDir, gitRoot, goRoot, rootDir)
-	}
-
-	// Inject auto-injected vars into TemplateVars so they're available
-	// in both Template() expansion and CEL evaluation via AsMap()
-	if fixture.TemplateVars == nil {
-		fixture.TemplateVars = make(map[string]any)
-	}
-	fixture.TemplateVars["workDir"] = sourceDir
-	fixture.TemplateVars["executablePath"] = opts.ExecutablePath
-	fixture.TemplateVars["GIT_ROOT_DIR"] = gitRoot
-	fixture.TemplateVars["GO_ROOT_DIR"] = goRoot
-	fixture.TemplateVars["ROOT_DIR"] = rootDir
-	fixture.TemplateVars["GOOS"] = runtime.GOOS
-	fixture.TemplateVars["GOARCH"] = runtime.GOARCH
-	fixture.TemplateVars["GOPATH"] = os.Getenv("GOPATH")
-	fixture.TemplateVars["CWD"] = sourceDir
+	sourceDir := injectTemplateVars(&fixture, opts)
 
 	result := fixtures.FixtureResult{
 		Test:     fixture,
@@ -112,10 +85,24 @@ func (e *ExecFixture) Run(ctx context.Context, fixture fixtures.FixtureTest, opt
 	}
 
 	var p *clickyExec.ExecResult
-	if exec.Terminal == "pty" {
+	if len(exec.Script) > 0 {
+		var screen string
+		p, screen, err = runScript(ctx, exec, workDir, fixture.Expected.Timeout)
+		if err != nil {
+			result.Actual = p
+			result.Stdout = p.Stdout
+			result.ExitCode = p.ExitCode
+			return result.Failf("%v\n  screen:\n%s", err, screen)
+		}
+	} else if exec.Terminal == "pty" {
 		p = runWithPTY(exec, workDir)
 	} else {
 		cmd := clicky.Exec(exec.Exec, exec.Args...).WithCwd(workDir)
+		// The runner bounds each fixture through ctx, but clicky processes
+		// only honour their own timeout
+		if deadline, ok := ctx.Deadline(); ok {
+			cmd = cmd.WithTimeout(time.Until(deadline))
+		}
 		if len(exec.Env) > 0 {
 			envMap := make(map[string]string, len(exec.Env))
 			for k, v := range exec.Env {
@@ -127,12 +114,52 @@ func (e *ExecFixture) Run(ctx context.Context, fixture fixtures.FixtureTest, opt
 	}
 
 	result.Actual = p
+	if p.Status == "timeout" {
+		result.Stdout = p.Stdout
+		result.Stderr = p.Stderr
+		result.ExitCode = p.ExitCode
+		return result.Failf("%v", p.Error)
+	}
 	return fixture.Expected.Evaluate(result, *p, fixtures.EvaluateOptions{
 		SourceDir:    fixture.SourceDir,
 		UpdateGolden: opts.UpdateGolden,
 	})
 }
 
+// injectTemplateVars adds the auto-injected variables (workDir, GIT_ROOT_DIR,
+// GOOS, ...) to fixture.TemplateVars so they are available in both template
+// expansion and CEL evaluation via AsMap(). Returns the fixture source dir.
+func injectTemplateVars(fixture *fixtures.FixtureTest, opts fixtures.RunOptions) string {
+	sourceDir := ResolveSourceDir(*fixture, opts)
+	gitRoot := repomap.FindGitRoot(sourceDir)
+	goRoot := findGoModRoot(sourceDir)
+	rootDir := gitRoot
+	if rootDir == "" {
+		rootDir = goRoot
+	}
+	if rootDir == "" {
+		rootDir = sourceDir
+	}
+
+	if gitRoot != goRoot {
+		logger.V(3).Infof("Directories: source=%s git=%s go=%s root=%s", sourceDir, gitRoot, goRoot, rootDir)
+	}
+
+	if fixture.TemplateVars == nil {
+		fixture.TemplateVars = make(map[string]any)
+	}
+	fixture.TemplateVars["workDir"] = sourceDir
+	fixture.TemplateVars["executablePath"] = opts.ExecutablePath
+	fixture.TemplateVars["GIT_ROOT_DIR"] = gitRoot
+	fixture.TemplateVars["GO_ROOT_DIR"] = goRoot
+	fixture.TemplateVars["ROOT_DIR"] = rootDir
+	fixture.TemplateVars["GOOS"] = runtime.GOOS
+	fixture.TemplateVars["GOARCH"] = runtime.GOARCH
+	fixture.TemplateVars["GOPATH"] = os.Getenv("GOPATH")
+	fixture.TemplateVars["CWD"] = sourceDir
+	return sourceDir
+}
+
 func runWithPTY(execBase fixtures.ExecFixtureBase, workDir string) *clickyExec.ExecResult {
 	// Invoke the configured executable directly so shells like bash/sh don't
 	// get double-wrapped (`bash -c "bash -c '<script>'"` mis-parses: the
@@ -183,6 +210,76 @@ func runWithPTY(execBase fixtures.ExecFixtureBase, workDir string) *clickyExec.E
 	}
 }
 
+// ptyTerminal lets a script resize the PTY it writes keystrokes to.
+type ptyTerminal struct {
+	*os.File
+}
+
+func (t ptyTerminal) Resize(cols, rows int) error {
+	return pty.Setsize(t.File, &pty.Winsize{Cols: uint16(cols), Rows: uint16(rows)})
+}
+
+// runScript runs the command in an 80x24 PTY and plays the script against it.
+// Once the script is done the program has until timeout to exit before it is
+// killed. The settled screen is returned for failure messages.
+func runScript(ctx context.Context, execBase fixtures.ExecFixtureBase, workDir string, timeout *time.Duration) (*clickyExec.ExecResult, string, error) {
+	cmd := osExec.Command(execBase.Exec, execBase.Args...)
+	cmd.Dir = workDir
+	cmd.Env = os.Environ()
+	for k, v := range execBase.Env {
+		cmd.Env = append(cmd.Env, fmt.Sprintf("%s=%v", k, v))
+	}
+	if _, ok := execBase.Env["TERM"]; !ok {
+		cmd.Env = append(cmd.Env, "TERM=xterm-256color")
+	}
+
+	now := time.Now()
+	result := &clickyExec.ExecResult{Started: &now}
+	ptmx, err := pty.StartWithSize(cmd, &pty.Winsize{Cols: 80, Rows: 24})
+	if err != nil {
+		result.Error = fmt.Errorf("failed to start PTY: %w", err)
+		return result, "", result.Error
+	}
+	defer ptmx.Close()
+
+	screen := fixtures.NewScreen()
+	closed := make(chan struct{})
+	go func() {
+		_, _ = io.Copy(screen, ptmx)
+		close(closed)
+	}()
+
+	scriptErr := fixtures.RunScript(ctx, ptyTerminal{ptmx}, screen, closed, execBase.Script)
+	if scriptErr == nil {
+		wait := fixtures.DefaultScriptTimeout
+		if timeout != nil {
+			wait = *timeout
+		}
+		select {
+		case <-closed:
+		case <-time.After(wait):
+			scriptErr = fmt.Errorf("program still running %s after the script finished", wait)
+		case <-ctx.Done():
+			scriptErr = ctx.Err()
+		}
+	}
+	if scriptErr != nil {
+		// The PTY makes the program a session leader, so this also reaches
+		// any children still holding the terminal open
+		_ = syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+	}
+	_ = cmd.Wait()
+	<-closed
+
+	if cmd.ProcessState != nil {
+		result.ExitCode = cmd.ProcessState.ExitCode()
+	}
+	// As in runWithPTY, the merged stream is reported as stdout only
+	result.Stdout = screen.Raw()
+	result.Duration = time.Since(now)
+	return result, screen.Text(), scriptErr
+}
+
 // ResolveWorkDir determines the working directory for fixture execution.
 // Priority: test-level CWD > file-level frontmatter CWD > SourceDir > opts.WorkDir
 // Relative CWD paths are resolved from SourceDir (fixture file location) or opts.WorkDir.
@@ -0,0 +1,168 @@
+package types
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/flanksource/commons/logger"
+	"github.com/flanksource/gavel/fixtures"
+)
+
+const (
+	defaultHTTPTimeout = 30 * time.Second
+	httpRetryDelay     = 500 * time.Millisecond
+)
+
+// HTTPFixture implements FixtureType for API contract tests: each fixture
+// sends one request and asserts on the status, headers and body.
+type HTTPFixture struct {
+	Client *http.Client
+}
+
+// ensure HTTPFixture implements FixtureType
+var _ fixtures.FixtureType = (*HTTPFixture)(nil)
+
+// Name returns the type identifier
+func (h *HTTPFixture) Name() string {
+	return "http"
+}
+
+// ValidateFixture checks that a URL is set once front-matter and test
+// overrides are merged.
+func (h *HTTPFixture) ValidateFixture(fixture fixtures.FixtureTest) error {
+	if fixture.HTTPBase().URL == "" {
+		return fmt.Errorf("http fixture %q has no url", fixture.Name)
+	}
+	return nil
+}
+
+// Run templates the request, sends it with retries, and evaluates the
+// response against the fixture's expectations.
+func (h *HTTPFixture) Run(ctx context.Context, fixture fixtures.FixtureTest, opts fixtures.RunOptions) fixtures.FixtureResult {
+	injectTemplateVars(&fixture, opts)
+	result := fixtures.FixtureResult{
+		Test:     fixture,
+		Name:     fixture.Name,
+		Type:     "http",
+		Metadata: make(map[string]interface{}),
+	}
+
+	req, err := fixture.HTTPBase().Template(fixture.AsMap())
+	if err != nil {
+		return result.Errorf(err, "failed to template request")
+	}
+	if req.URL == "" {
+		return result.Errorf(fmt.Errorf("no url specified"), "no url specified")
+	}
+
+	timeout := defaultHTTPTimeout
+	if fixture.Expected.Timeout != nil {
+		timeout = *fixture.Expected.Timeout
+	} else if fixture.FrontMatter.Timeout != nil {
+		timeout = *fixture.FrontMatter.Timeout
+	}
+
+	resp, err := h.send(ctx, req, timeout, fixture.Expected.Status)
+	if err != nil {
+		result.Command = req.Method + " " + req.URL
+		return result.Errorf(err, "request failed after %d attempts", req.Retries+1)
+	}
+	result.Actual = resp
+	return fixture.Expected.EvaluateHTTP(result, *resp, fixtures.EvaluateOptions{
+		SourceDir:    fixture.SourceDir,
+		UpdateGolden: opts.UpdateGolden,
+	})
+}
+
+// send makes up to req.Retries+1 attempts. Transport errors and 5xx
+// responses are retried unless the test expects exactly that status.
+func (h *HTTPFixture) send(ctx context.Context, req fixtures.HTTPFixtureBase, timeout time.Duration, wantStatus *int) (*fixtures.HTTPResponse, error) {
+	client := h.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	start := time.Now()
+	var lastErr error
+	for attempt := 1; attempt <= req.Retries+1; attempt++ {
+		if attempt > 1 {
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(time.Duration(attempt-1) * httpRetryDelay):
+			}
+		}
+
+		resp, err := h.do(ctx, client, req, timeout)
+		if err != nil {
+			lastErr = err
+			logger.V(3).Infof("http fixture %s %s attempt %d: %v", req.Method, req.URL, attempt, err)
+			continue
+		}
+		resp.Attempts = attempt
+		resp.Duration = time.Since(start)
+		retryable := resp.Status >= 500 && (wantStatus == nil || *wantStatus != resp.Status)
+		if retryable && attempt <= req.Retries {
+			logger.V(3).Infof("http fixture %s %s attempt %d: status %d, retrying", req.Method, req.URL, attempt, resp.Status)
+			continue
+		}
+		return resp, nil
+	}
+	return nil, lastErr
+}
+
+func (h *HTTPFixture) do(ctx context.Context, client *http.Client, req fixtures.HTTPFixtureBase, timeout time.Duration) (*fixtures.HTTPResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var body io.Reader
+	if req.Body != "" {
+		body = strings.NewReader(req.Body)
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, req.Method, req.URL, body)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range req.Headers {
+		if strings.EqualFold(k, "Host") {
+			httpReq.Host = v
+			continue
+		}
+		httpReq.Header.Set(k, v)
+	}
+
+	resp, err := client.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read response body: %w", err)
+	}
+	return &fixtures.HTTPResponse{
+		Method:  req.Method,
+		URL:     req.URL,
+		Status:  resp.StatusCode,
+		Headers: fixtures.ResponseHeaders(resp.Header),
+		Body:    string(data),
+	}, nil
+}
+
+// GetRequiredFields returns required fields
+func (h *HTTPFixture) GetRequiredFields() []string {
+	return []string{"url"}
+}
+
+// GetOptionalFields returns optional fields
+func (h *HTTPFixture) GetOptionalFields() []string {
+	return []string{"method", "headers", "body", "timeout", "retries", "Expected.Status", "Expected.Output", "CEL"}
+}
+
+func init() {
+	_ = fixtures.Register(&HTTPFixture{})
+}
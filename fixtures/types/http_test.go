@@ -0,0 +1,144 @@
+package types
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"github.com/flanksource/clicky/task"
+	"github.com/flanksource/gavel/fixtures"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newContractServer() *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/users", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == http.MethodPost {
+			body, _ := io.ReadAll(r.Body)
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprintf(w, `{"created":%s,"token":%q}`, body, r.Header.Get("X-Token"))
+			return
+		}
+		fmt.Fprint(w, `[{"name":"alice"}]`)
+	})
+	mux.HandleFunc("/missing", func(w http.ResponseWriter, r *http.Request) {
+		http.NotFound(w, r)
+	})
+	return httptest.NewServer(mux)
+}
+
+func collectTests(nodes []fixtures.FixtureNode) []fixtures.FixtureTest {
+	var out []fixtures.FixtureTest
+	var walk func(n *fixtures.FixtureNode)
+	walk = func(n *fixtures.FixtureNode) {
+		if n.Test != nil {
+			out = append(out, *n.Test)
+		}
+		for _, c := range n.Children {
+			walk(c)
+		}
+	}
+	for i := range nodes {
+		walk(&nodes[i])
+	}
+	return out
+}
+
+func TestHTTPFixtureTableRows(t *testing.T) {
+	srv := newContractServer()
+	defer srv.Close()
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "users.golden.json"), []byte(`[{"name":"alice"}]`), 0o644))
+	doc := fmt.Sprintf(`---
+type: http
+url: "{{.baseUrl}}{{.path}}"
+baseUrl: %s
+headers:
+  X-Token: secret
+---
+
+| Name | path | method | body | status | stdout | CEL |
+|------|------|--------|------|--------|--------|-----|
+| list users | /users | | | 200 | @users.golden.json | headers["content-type"] == "application/json" && json[0].name == "alice" |
+| create user | /users | post | {"name":"bob"} | 201 | | json.created.name == "bob" && json.token == "secret" |
+| missing | /missing | | | 404 | | |
+| wrong status | /missing | | | | | |
+`, srv.URL)
+	path := filepath.Join(dir, "api.md")
+	require.NoError(t, os.WriteFile(path, []byte(doc), 0o644))
+
+	nodes, err := fixtures.ParseMarkdownFixtures(path)
+	require.NoError(t, err)
+	tests := collectTests(nodes)
+	require.Len(t, tests, 4)
+
+	statuses := map[string]task.Status{}
+	for _, test := range tests {
+		ft, err := fixtures.DefaultRegistry.GetForFixture(test)
+		require.NoError(t, err)
+		assert.Equal(t, "http", ft.Name())
+		result := ft.Run(context.Background(), test, fixtures.RunOptions{WorkDir: dir})
+		statuses[test.Name] = result.Status
+		if test.Name != "wrong status" {
+			assert.Equal(t, task.StatusPASS, result.Status, "%s: %s", test.Name, result.Error)
+		}
+	}
+	assert.Equal(t, task.StatusFAIL, statuses["wrong status"], "a 404 without an expected status should fail")
+}
+
+func TestHTTPFixtureRetriesServerErrors(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprint(w, "ok")
+	}))
+	defer srv.Close()
+
+	fixture := fixtures.FixtureTest{
+		Name: "flaky",
+		HTTP: fixtures.HTTPFixtureBase{URL: srv.URL, Retries: 2},
+		Expected: fixtures.Expectations{
+			Stdout: "ok",
+		},
+	}
+	result := (&HTTPFixture{}).Run(context.Background(), fixture, fixtures.RunOptions{WorkDir: t.TempDir()})
+	assert.Equal(t, task.StatusPASS, result.Status, result.Error)
+	assert.Equal(t, int32(3), calls.Load())
+	assert.Equal(t, 3, result.Metadata["attempts"])
+}
+
+func TestHTTPFixtureUpdatesGoldenBody(t *testing.T) {
+	srv := newContractServer()
+	defer srv.Close()
+
+	dir := t.TempDir()
+	golden := filepath.Join(dir, "users.json")
+	require.NoError(t, os.WriteFile(golden, []byte("stale"), 0o644))
+
+	fixture := fixtures.FixtureTest{
+		Name:      "golden",
+		SourceDir: dir,
+		HTTP:      fixtures.HTTPFixtureBase{URL: srv.URL + "/users"},
+		Expected:  fixtures.Expectations{Stdout: "@users.json"},
+	}
+	result := (&HTTPFixture{}).Run(context.Background(), fixture, fixtures.RunOptions{WorkDir: dir})
+	assert.Equal(t, task.StatusFAIL, result.Status)
+
+	result = (&HTTPFixture{}).Run(context.Background(), fixture, fixtures.RunOptions{WorkDir: dir, UpdateGolden: true})
+	assert.Equal(t, task.StatusPASS, result.Status, result.Error)
+	data, err := os.ReadFile(golden)
+	require.NoError(t, err)
+	assert.Equal(t, `[{"name":"alice"}]`, string(data))
+}
@@ -0,0 +1,106 @@
+package types
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/flanksource/clicky/task"
+	"github.com/flanksource/gavel/fixtures"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const scriptFixtureDoc = "### command: prompt\n\n" +
+	"```bash\n" +
+	"read -p 'Name? ' name\n" +
+	"read -p 'Colour? ' colour\n" +
+	"echo \"hello $name, you picked $colour\"\n" +
+	"```\n\n" +
+	"```frontmatter\n" +
+	"script:\n" +
+	"  - expect: 'Name\\? $'\n" +
+	"  - send: \"{{.who}}\\r\"\n" +
+	"  - expect: Colour\n" +
+	"  - send: blue\n" +
+	"  - key: backspace backspace backspace backspace\n" +
+	"  - send: red\n" +
+	"  - key: enter\n" +
+	"  - expect: hello\n" +
+	"```\n\n" +
+	"Validations:\n" +
+	"* cel: screen.contains(\"hello bob, you picked red\") && exitCode == 0\n"
+
+func TestExecFixtureScript(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "prompt.md")
+	require.NoError(t, os.WriteFile(path, []byte(scriptFixtureDoc), 0o644))
+
+	nodes, err := fixtures.ParseMarkdownFixtures(path)
+	require.NoError(t, err)
+	tests := collectTests(nodes)
+	require.Len(t, tests, 1)
+	test := tests[0]
+	require.Len(t, test.ExecBase().Script, 8)
+	test.TemplateVars = map[string]any{"who": "bob"}
+
+	result := (&ExecFixture{}).Run(context.Background(), test, fixtures.RunOptions{WorkDir: dir})
+	assert.Equal(t, task.StatusPASS, result.Status, "%s\n%s", result.Error, result.Stdout)
+}
+
+func TestExecFixtureScriptFailures(t *testing.T) {
+	short := 300 * time.Millisecond
+	tests := []struct {
+		name   string
+		args   string
+		script []fixtures.ScriptStep
+		error  string
+	}{
+		{
+			name:   "expect times out",
+			args:   "echo waiting; sleep 30",
+			script: []fixtures.ScriptStep{{Expect: "ready", Timeout: &short}},
+			error:  "script step 1 (expect /ready/): screen did not match within 300ms",
+		},
+		{
+			name:   "program exits first",
+			args:   "echo bye",
+			script: []fixtures.ScriptStep{{Expect: "ready"}},
+			error:  "program exited before the screen matched",
+		},
+		{
+			name:   "program keeps running",
+			args:   "read line; sleep 30",
+			script: []fixtures.ScriptStep{{Send: "go\r"}},
+			error:  "program still running 300ms after the script finished",
+		},
+		{
+			name:   "resize",
+			args:   "trap 'stty size; exit 0' WINCH; echo ready; while true; do sleep 0.1; done",
+			script: []fixtures.ScriptStep{{Expect: "ready"}, {Resize: "120x40"}, {Expect: "40 120"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			result := (&ExecFixture{}).Run(context.Background(), fixtures.FixtureTest{
+				Name:      tt.name,
+				SourceDir: dir,
+				ExecFixtureBase: fixtures.ExecFixtureBase{
+					Exec:   "bash",
+					Args:   []string{"-c", tt.args},
+					Script: tt.script,
+				},
+				Expected: fixtures.Expectations{Timeout: &short},
+			}, fixtures.RunOptions{WorkDir: dir})
+			if tt.error == "" {
+				assert.Equal(t, task.StatusPASS, result.Status, result.Error)
+				return
+			}
+			assert.Equal(t, task.StatusFAIL, result.Status)
+			assert.Contains(t, result.Error, tt.error)
+		})
+	}
+}
@@ -0,0 +1,199 @@
+package types
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	commonsdb "github.com/flanksource/commons-db/db"
+	"github.com/flanksource/commons/logger"
+	"github.com/flanksource/gavel/fixtures"
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+	// Registers the "sqlite" database/sql driver the glebarez dialector opens
+	// against, see internal/cache/db.go.
+	_ "modernc.org/sqlite"
+)
+
+const defaultSQLTimeout = 30 * time.Second
+
+// SQLFixture implements FixtureType for database assertions: each fixture
+// runs setup, one query and teardown against the DSN from the front-matter,
+// and asserts on the returned rows.
+type SQLFixture struct{}
+
+// ensure SQLFixture implements FixtureType
+var _ fixtures.FixtureType = (*SQLFixture)(nil)
+
+// Name returns the type identifier
+func (s *SQLFixture) Name() string {
+	return "sql"
+}
+
+// ValidateFixture checks that a DSN and a query are set.
+func (s *SQLFixture) ValidateFixture(fixture fixtures.FixtureTest) error {
+	if fixture.SQLBase().DSN == "" {
+		return fmt.Errorf("sql fixture %q has no dsn", fixture.Name)
+	}
+	if strings.TrimSpace(fixture.Query) == "" {
+		return fmt.Errorf("sql fixture %q has no query", fixture.Name)
+	}
+	return nil
+}
+
+// Run opens a fresh connection, runs setup, the query and teardown, and
+// evaluates the rows against the fixture's expectations.
+func (s *SQLFixture) Run(ctx context.Context, fixture fixtures.FixtureTest, opts fixtures.RunOptions) fixtures.FixtureResult {
+	sourceDir := injectTemplateVars(&fixture, opts)
+	result := fixtures.FixtureResult{
+		Test:     fixture,
+		Name:     fixture.Name,
+		Type:     "sql",
+		Metadata: make(map[string]interface{}),
+	}
+
+	data := fixture.AsMap()
+	conn, err := fixture.SQLBase().Template(data)
+	if err != nil {
+		return result.Errorf(err, "failed to template connection")
+	}
+	query, err := fixtures.TemplateString(fixture.Query, data)
+	if err != nil {
+		return result.Errorf(err, "failed to template query")
+	}
+	result.Command = strings.TrimSpace(query)
+	if conn.DSN == "" {
+		return result.Errorf(fmt.Errorf("no dsn specified"), "no dsn specified")
+	}
+
+	timeout := defaultSQLTimeout
+	if fixture.Expected.Timeout != nil {
+		timeout = *fixture.Expected.Timeout
+	} else if fixture.FrontMatter.Timeout != nil {
+		timeout = *fixture.FrontMatter.Timeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	driver, dsn := conn.Driver(sourceDir)
+	db, err := openSQL(driver, dsn)
+	if err != nil {
+		return result.Errorf(err, "failed to open %s database", driver)
+	}
+	defer func() { _ = db.Close() }()
+
+	if conn.Setup != "" {
+		if _, err := db.ExecContext(ctx, conn.Setup); err != nil {
+			return result.Errorf(err, "setup failed")
+		}
+	}
+
+	res, queryErr := runQuery(ctx, db, query)
+
+	if conn.Teardown != "" {
+		// Teardown must run even when the query failed or the deadline passed
+		if _, err := db.ExecContext(context.Background(), conn.Teardown); err != nil {
+			if queryErr == nil {
+				return result.Errorf(err, "teardown failed")
+			}
+			logger.Warnf("sql fixture %s: teardown failed: %v", fixture.Name, err)
+		}
+	}
+	if queryErr != nil {
+		return result.Errorf(queryErr, "query failed")
+	}
+
+	result.Actual = res
+	return fixture.Expected.EvaluateSQL(result, *res, fixtures.EvaluateOptions{
+		SourceDir:    fixture.SourceDir,
+		UpdateGolden: opts.UpdateGolden,
+	})
+}
+
+// openSQL opens sqlite through the pure-Go glebarez dialector and postgres
+// through commons-db's pgx driver.
+func openSQL(driver, dsn string) (*sql.DB, error) {
+	switch driver {
+	case "postgres":
+		return commonsdb.NewDB(dsn)
+	case "sqlite":
+		gormDB, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{Logger: gormlogger.Default.LogMode(gormlogger.Silent)})
+		if err != nil {
+			return nil, err
+		}
+		db, err := gormDB.DB()
+		if err != nil {
+			return nil, err
+		}
+		// A single connection keeps :memory: databases shared between setup,
+		// query and teardown
+		db.SetMaxOpenConns(1)
+		return db, nil
+	default:
+		return nil, fmt.Errorf("unsupported sql driver %q", driver)
+	}
+}
+
+func runQuery(ctx context.Context, db *sql.DB, query string) (*fixtures.SQLResult, error) {
+	start := time.Now()
+	rows, err := db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+	res := &fixtures.SQLResult{Query: query, Columns: columns, Rows: []map[string]any{}}
+	for rows.Next() {
+		values := make([]any, len(columns))
+		ptrs := make([]any, len(columns))
+		for i := range values {
+			ptrs[i] = &values[i]
+		}
+		if err := rows.Scan(ptrs...); err != nil {
+			return nil, err
+		}
+		row := make(map[string]any, len(columns))
+		for i, col := range columns {
+			row[col] = sqlValue(values[i])
+		}
+		res.Rows = append(res.Rows, row)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	res.Duration = time.Since(start)
+	return res, nil
+}
+
+// sqlValue converts driver values into types CEL and JSON handle directly.
+func sqlValue(v any) any {
+	switch val := v.(type) {
+	case []byte:
+		return string(val)
+	case time.Time:
+		return val.Format(time.RFC3339Nano)
+	default:
+		return val
+	}
+}
+
+// GetRequiredFields returns required fields
+func (s *SQLFixture) GetRequiredFields() []string {
+	return []string{"dsn", "query"}
+}
+
+// GetOptionalFields returns optional fields
+func (s *SQLFixture) GetOptionalFields() []string {
+	return []string{"setup", "teardown", "timeout", "Expected.Count", "Expected.Output", "CEL"}
+}
+
+func init() {
+	_ = fixtures.Register(&SQLFixture{})
+}
@@ -0,0 +1,86 @@
+package types
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/flanksource/clicky/task"
+	"github.com/flanksource/gavel/fixtures"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const sqlFixtureDoc = "---\n" +
+	"type: sql\n" +
+	"dsn: \":memory:\"\n" +
+	"---\n\n" +
+	"```sql setup\n" +
+	"CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT, active BOOLEAN);\n" +
+	"INSERT INTO users (name, active) VALUES ('alice', 1), ('bob', 0);\n" +
+	"```\n\n" +
+	"## active users\n\n" +
+	"```sql\n" +
+	"SELECT name FROM users WHERE active = 1\n" +
+	"```\n\n" +
+	"* cel: count == 1 && rows[0].name == \"alice\" && columns == [\"name\"]\n\n" +
+	"## Table\n\n" +
+	"| Name | query | count | CEL |\n" +
+	"|------|-------|-------|-----|\n" +
+	"| all users | SELECT id, name FROM users ORDER BY id | 2 | rows[1].name == \"bob\" |\n" +
+	"| wrong count | SELECT * FROM users | 5 | |\n"
+
+func TestSQLFixtureMarkdown(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "users.md")
+	require.NoError(t, os.WriteFile(path, []byte(sqlFixtureDoc), 0o644))
+
+	nodes, err := fixtures.ParseMarkdownFixtures(path)
+	require.NoError(t, err)
+	tests := collectTests(nodes)
+	require.Len(t, tests, 3)
+
+	statuses := map[string]task.Status{}
+	for _, test := range tests {
+		assert.Contains(t, test.SQLBase().Setup, "CREATE TABLE users")
+		ft, err := fixtures.DefaultRegistry.GetForFixture(test)
+		require.NoError(t, err)
+		assert.Equal(t, "sql", ft.Name())
+		result := ft.Run(context.Background(), test, fixtures.RunOptions{WorkDir: dir})
+		statuses[test.Name] = result.Status
+		if test.Name != "wrong count" {
+			assert.Equal(t, task.StatusPASS, result.Status, "%s: %s", test.Name, result.Error)
+		}
+	}
+	assert.Equal(t, task.StatusPASS, statuses["active users"])
+	assert.Equal(t, task.StatusFAIL, statuses["wrong count"])
+}
+
+func TestSQLFixtureTeardownAndGolden(t *testing.T) {
+	dir := t.TempDir()
+	golden := filepath.Join(dir, "rows.json")
+	require.NoError(t, os.WriteFile(golden, []byte("[]"), 0o644))
+
+	fixture := fixtures.FixtureTest{
+		Name:      "golden",
+		SourceDir: dir,
+		Query:     "SELECT name FROM pets",
+		SQL: fixtures.SQLFixtureBase{
+			DSN:      "sqlite://pets.db",
+			Setup:    "CREATE TABLE pets (name TEXT); INSERT INTO pets VALUES ('rex');",
+			Teardown: "DROP TABLE pets;",
+		},
+		Expected: fixtures.Expectations{Stdout: "@rows.json"},
+	}
+	result := (&SQLFixture{}).Run(context.Background(), fixture, fixtures.RunOptions{WorkDir: dir})
+	assert.Equal(t, task.StatusFAIL, result.Status)
+
+	// Teardown dropped the table, so setup can run again
+	result = (&SQLFixture{}).Run(context.Background(), fixture, fixtures.RunOptions{WorkDir: dir, UpdateGolden: true})
+	assert.Equal(t, task.StatusPASS, result.Status, result.Error)
+	data, err := os.ReadFile(golden)
+	require.NoError(t, err)
+	assert.JSONEq(t, `[{"name":"rex"}]`, string(data))
+	assert.FileExists(t, filepath.Join(dir, "pets.db"))
+}
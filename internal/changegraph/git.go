@@ -125,6 +125,26 @@ func ComputeFileSet(workDir string, opts DiffOptions) (FileSet, error) {
 	return fs, nil
 }
 
+// ComputeRenames returns the files git detects as renamed between ref and
+// the working tree, keyed by their path at ref. Only tracked files take part,
+// so a rename is seen once the new path is staged or committed. Paths are
+// workdir-relative with forward slashes.
+func ComputeRenames(workDir, ref string) (map[string]string, error) {
+	lines, err := gitLines(workDir, "diff", "-M", "--name-status", "--relative", ref)
+	if err != nil {
+		return nil, fmt.Errorf("git diff -M %s: %w", ref, err)
+	}
+	renames := map[string]string{}
+	for _, line := range lines {
+		fields := strings.Split(line, "\t")
+		if len(fields) != 3 || !strings.HasPrefix(fields[0], "R") {
+			continue
+		}
+		renames[filepathToSlash(fields[1])] = filepathToSlash(fields[2])
+	}
+	return renames, nil
+}
+
 // mergeBase resolves `git merge-base a b` to a commit sha.
 func mergeBase(workDir, a, b string) (string, error) {
 	cmd := exec.Command("git", "merge-base", a, b)
@@ -104,6 +104,25 @@ var _ = Describe("ComputeFileSet", func() {
 	})
 })
 
+var _ = Describe("ComputeRenames", func() {
+	It("maps the old path of a staged rename to the new one", func() {
+		repo := GinkgoT().TempDir()
+		initRepo(repo)
+		run := func(args ...string) {
+			cmd := exec.Command("git", args...)
+			cmd.Dir = repo
+			out, err := cmd.CombinedOutput()
+			Expect(err).NotTo(HaveOccurred(), "git %v failed: %s", args, out)
+		}
+		Expect(os.MkdirAll(filepath.Join(repo, "pkg"), 0o755)).To(Succeed())
+		run("mv", "seed.txt", "pkg/moved.txt")
+
+		renames, err := changegraph.ComputeRenames(repo, "HEAD")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(renames).To(Equal(map[string]string{"seed.txt": "pkg/moved.txt"}))
+	})
+})
+
 var _ = Describe("FileSet", func() {
 	It("Add normalizes separators", func() {
 		fs := changegraph.NewFileSet()
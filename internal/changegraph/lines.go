@@ -0,0 +1,197 @@
+package changegraph
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// LineSet maps workdir-relative file paths (forward-slash separated) to the
+// set of 1-based line numbers added or modified in the working tree. It is
+// the line-level counterpart of FileSet, used for diff coverage.
+type LineSet map[string]map[int]struct{}
+
+// NewLineSet creates an empty LineSet.
+func NewLineSet() LineSet { return LineSet{} }
+
+// Add marks line in path as changed.
+func (ls LineSet) Add(path string, line int) {
+	path = filepathToSlash(strings.TrimSpace(path))
+	if path == "" || line <= 0 {
+		return
+	}
+	lines, ok := ls[path]
+	if !ok {
+		lines = map[int]struct{}{}
+		ls[path] = lines
+	}
+	lines[line] = struct{}{}
+}
+
+// Has reports whether line in path is changed.
+func (ls LineSet) Has(path string, line int) bool {
+	_, ok := ls[filepathToSlash(path)][line]
+	return ok
+}
+
+// Near reports whether path has a changed line within radius lines of line.
+// A radius of 0 is the same as Has.
+func (ls LineSet) Near(path string, line, radius int) bool {
+	lines := ls[filepathToSlash(path)]
+	if len(lines) == 0 {
+		return false
+	}
+	for l := line - radius; l <= line+radius; l++ {
+		if _, ok := lines[l]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Files returns the changed paths, sorted.
+func (ls LineSet) Files() []string {
+	out := make([]string, 0, len(ls))
+	for k := range ls {
+		out = append(out, k)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// Lines returns the changed lines of path, sorted.
+func (ls LineSet) Lines(path string) []int {
+	set := ls[filepathToSlash(path)]
+	out := make([]int, 0, len(set))
+	for l := range set {
+		out = append(out, l)
+	}
+	sort.Ints(out)
+	return out
+}
+
+// ComputeLineSet runs git in workDir to find the changed lines described by
+// opts. Line numbers always refer to the working tree, because that is what
+// tests ran against:
+//
+//   - Since diffs merge-base(HEAD, Since) against the working tree, which
+//     already includes any staged and unstaged edits.
+//   - Otherwise IncludeStaged / IncludeUnstaged diff HEAD against the working
+//     tree; the two views cannot be told apart once line numbers are mapped
+//     to the working tree, so either one enables both.
+//   - IncludeUntracked marks every line of each untracked file.
+//
+// Deleted lines have no working-tree line and are not reported.
+func ComputeLineSet(workDir string, opts DiffOptions) (LineSet, error) {
+	ls := NewLineSet()
+
+	ref := ""
+	switch {
+	case opts.Since != "":
+		base, err := mergeBase(workDir, "HEAD", opts.Since)
+		if err != nil {
+			return nil, fmt.Errorf("git merge-base HEAD %s: %w", opts.Since, err)
+		}
+		ref = base
+	case opts.IncludeStaged || opts.IncludeUnstaged:
+		ref = "HEAD"
+	}
+	if ref != "" {
+		if err := addDiffLines(ls, workDir, ref); err != nil {
+			return nil, fmt.Errorf("git diff -U0 %s: %w", ref, err)
+		}
+	}
+
+	if opts.IncludeUntracked {
+		files, err := gitLines(workDir, "ls-files", "--others", "--exclude-standard")
+		if err != nil {
+			return nil, fmt.Errorf("git ls-files --others: %w", err)
+		}
+		for _, f := range files {
+			n, err := countLines(filepath.Join(workDir, f))
+			if err != nil {
+				continue
+			}
+			for line := 1; line <= n; line++ {
+				ls.Add(f, line)
+			}
+		}
+	}
+
+	return ls, nil
+}
+
+// hunkHeaderRe matches a unified diff hunk header and captures the new-side
+// start line and optional line count: "@@ -10,2 +12,3 @@".
+var hunkHeaderRe = regexp.MustCompile(`^@@ -\d+(?:,\d+)? \+(\d+)(?:,(\d+))? @@`)
+
+// addDiffLines adds the new-side lines of every hunk in
+// `git diff -U0 --relative <ref>` to ls. With zero context lines each hunk
+// covers exactly the added/modified lines. --no-prefix keeps the paths
+// independent of the user's diff.noprefix and diff.mnemonicPrefix config.
+func addDiffLines(ls LineSet, workDir, ref string) error {
+	cmd := exec.Command("git", "diff", "-U0", "--no-color", "--no-ext-diff", "--no-prefix", "--relative", ref)
+	cmd.Dir = workDir
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+
+	file := ""
+	scanner := bufio.NewScanner(stdout)
+	// Diff bodies carry whole source lines; minified files can be huge.
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "+++ "):
+			file = strings.TrimPrefix(line, "+++ ")
+			if file == "/dev/null" {
+				file = ""
+			}
+		case strings.HasPrefix(line, "@@ ") && file != "":
+			m := hunkHeaderRe.FindStringSubmatch(line)
+			if m == nil {
+				continue
+			}
+			start, _ := strconv.Atoi(m[1])
+			count := 1
+			if m[2] != "" {
+				count, _ = strconv.Atoi(m[2])
+			}
+			for l := start; l < start+count; l++ {
+				ls.Add(file, l)
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		_ = cmd.Wait()
+		return err
+	}
+	return cmd.Wait()
+}
+
+func countLines(path string) (int, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	if len(data) == 0 {
+		return 0, nil
+	}
+	n := bytes.Count(data, []byte("\n"))
+	if data[len(data)-1] != '\n' {
+		n++
+	}
+	return n, nil
+}
@@ -0,0 +1,96 @@
+package changegraph_test
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/flanksource/gavel/internal/changegraph"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ComputeLineSet", func() {
+	var repo string
+
+	run := func(args ...string) {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = repo
+		out, err := cmd.CombinedOutput()
+		Expect(err).NotTo(HaveOccurred(), "git %v failed: %s", args, out)
+	}
+
+	BeforeEach(func() {
+		repo = GinkgoT().TempDir()
+		initRepo(repo)
+		Expect(os.WriteFile(filepath.Join(repo, "calc.go"), []byte("a\nb\nc\nd\ne\n"), 0o644)).To(Succeed())
+		run("add", "calc.go")
+		run("commit", "--quiet", "-m", "calc")
+	})
+
+	It("reports modified and inserted working-tree lines", func() {
+		Expect(os.WriteFile(filepath.Join(repo, "calc.go"), []byte("a\nB\nc\nnew\nd\ne\n"), 0o644)).To(Succeed())
+
+		ls, err := changegraph.ComputeLineSet(repo, changegraph.DiffOptions{IncludeUnstaged: true})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ls.Files()).To(Equal([]string{"calc.go"}))
+		Expect(ls.Lines("calc.go")).To(Equal([]int{2, 4}))
+	})
+
+	It("reads file paths regardless of the user's diff prefix config", func() {
+		Expect(os.MkdirAll(filepath.Join(repo, "pkg"), 0o755)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(repo, "pkg", "util.go"), []byte("a\nb\n"), 0o644)).To(Succeed())
+		run("add", "pkg/util.go")
+		run("commit", "--quiet", "-m", "util")
+		Expect(os.WriteFile(filepath.Join(repo, "pkg", "util.go"), []byte("a\nB\n"), 0o644)).To(Succeed())
+
+		for _, config := range [][]string{
+			{"diff.noprefix", "true"},
+			{"diff.mnemonicPrefix", "true"},
+		} {
+			run("config", config[0], config[1])
+			ls, err := changegraph.ComputeLineSet(repo, changegraph.DiffOptions{IncludeUnstaged: true})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ls.Files()).To(Equal([]string{"pkg/util.go"}), "with %s", config[0])
+			Expect(ls.Lines("pkg/util.go")).To(Equal([]int{2}))
+			run("config", "--unset", config[0])
+		}
+	})
+
+	It("matches lines within a context radius", func() {
+		Expect(os.WriteFile(filepath.Join(repo, "calc.go"), []byte("a\nb\nc\nd\nE\n"), 0o644)).To(Succeed())
+
+		ls, err := changegraph.ComputeLineSet(repo, changegraph.DiffOptions{IncludeUnstaged: true})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ls.Near("calc.go", 5, 0)).To(BeTrue())
+		Expect(ls.Near("calc.go", 3, 0)).To(BeFalse())
+		Expect(ls.Near("calc.go", 3, 2)).To(BeTrue())
+		Expect(ls.Near("other.go", 5, 10)).To(BeFalse())
+	})
+
+	It("ignores pure deletions", func() {
+		Expect(os.WriteFile(filepath.Join(repo, "calc.go"), []byte("a\nb\nd\ne\n"), 0o644)).To(Succeed())
+
+		ls, err := changegraph.ComputeLineSet(repo, changegraph.DiffOptions{IncludeUnstaged: true})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ls.Files()).To(BeEmpty())
+	})
+
+	It("marks every line of untracked files", func() {
+		Expect(os.WriteFile(filepath.Join(repo, "new.go"), []byte("x\ny\nz"), 0o644)).To(Succeed())
+
+		ls, err := changegraph.ComputeLineSet(repo, changegraph.DiffOptions{IncludeUntracked: true})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ls.Lines("new.go")).To(Equal([]int{1, 2, 3}))
+	})
+
+	It("combines committed and uncommitted changes since a ref", func() {
+		Expect(os.WriteFile(filepath.Join(repo, "calc.go"), []byte("A\nb\nc\nd\ne\n"), 0o644)).To(Succeed())
+		run("commit", "--quiet", "-am", "edit first line")
+		Expect(os.WriteFile(filepath.Join(repo, "calc.go"), []byte("A\nb\nc\nd\nE\n"), 0o644)).To(Succeed())
+
+		ls, err := changegraph.ComputeLineSet(repo, changegraph.DiffOptions{Since: "HEAD~1"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ls.Lines("calc.go")).To(Equal([]int{1, 5}))
+	})
+})
@@ -0,0 +1,156 @@
+package runcache
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// StoredEntry is one file in a Store, as listed by Entries. Valid is false
+// for files that no longer decode or carry an old schema version; Lookup
+// already treats those as misses and Prune removes them. For invalid files
+// Entry.RecordedAt holds the file mtime.
+type StoredEntry struct {
+	Fingerprint string `json:"fingerprint"`
+	Path        string `json:"path"`
+	Size        int64  `json:"size"`
+	Valid       bool   `json:"valid"`
+	Entry       Entry  `json:"entry"`
+}
+
+// Entries lists every entry in the store, sorted by fingerprint.
+func (s *Store) Entries() ([]StoredEntry, error) {
+	var out []StoredEntry
+	err := filepath.WalkDir(s.Dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if d.IsDir() || !strings.HasSuffix(d.Name(), ".json") || strings.HasPrefix(d.Name(), ".") {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		se := StoredEntry{
+			Fingerprint: strings.TrimSuffix(d.Name(), ".json"),
+			Path:        path,
+			Size:        info.Size(),
+		}
+		if data, err := os.ReadFile(path); err == nil {
+			se.Valid = json.Unmarshal(data, &se.Entry) == nil && se.Entry.Version == schemaVersion
+		}
+		if !se.Valid && se.Entry.RecordedAt == 0 {
+			se.Entry.RecordedAt = info.ModTime().UnixNano()
+		}
+		out = append(out, se)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("walk %s: %w", s.Dir, err)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Fingerprint < out[j].Fingerprint })
+	return out, nil
+}
+
+// Remove deletes the entry stored under fingerprint. A missing entry is not
+// an error.
+func (s *Store) Remove(fingerprint string) error {
+	if err := os.Remove(s.entryPath(fingerprint)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// Stats summarizes the contents of a Store.
+type Stats struct {
+	Dir         string         `json:"dir"`
+	Entries     int            `json:"entries"`
+	Invalid     int            `json:"invalid,omitempty"`
+	Bytes       int64          `json:"bytes"`
+	ByFramework map[string]int `json:"by_framework,omitempty"`
+	Oldest      time.Time      `json:"oldest,omitempty"`
+	Newest      time.Time      `json:"newest,omitempty"`
+	// SavedNanos is the summed duration of the original runs — the test time
+	// one hit on every entry would save.
+	SavedNanos int64 `json:"saved_nanos,omitempty"`
+}
+
+// Stats walks the store and aggregates its entries.
+func (s *Store) Stats() (Stats, error) {
+	entries, err := s.Entries()
+	if err != nil {
+		return Stats{}, err
+	}
+	st := Stats{Dir: s.Dir, ByFramework: map[string]int{}}
+	for _, e := range entries {
+		st.Entries++
+		st.Bytes += e.Size
+		if !e.Valid {
+			st.Invalid++
+			continue
+		}
+		st.ByFramework[e.Entry.Framework]++
+		st.SavedNanos += e.Entry.DurationNanos
+		at := time.Unix(0, e.Entry.RecordedAt)
+		if st.Oldest.IsZero() || at.Before(st.Oldest) {
+			st.Oldest = at
+		}
+		if at.After(st.Newest) {
+			st.Newest = at
+		}
+	}
+	return st, nil
+}
+
+// PruneOptions selects the entries Prune removes. Invalid entries (corrupt
+// or from an older schema) are always removed.
+type PruneOptions struct {
+	// OlderThan removes entries recorded more than this long ago.
+	OlderThan time.Duration
+	// MaxEntries keeps only the newest N valid entries when > 0.
+	MaxEntries int
+	// DryRun reports what would be removed without deleting anything.
+	DryRun bool
+}
+
+// Prune removes entries according to opts and returns the removed ones.
+func (s *Store) Prune(opts PruneOptions) ([]StoredEntry, error) {
+	entries, err := s.Entries()
+	if err != nil {
+		return nil, err
+	}
+	var removed, kept []StoredEntry
+	cutoff := time.Time{}
+	if opts.OlderThan > 0 {
+		cutoff = time.Now().Add(-opts.OlderThan)
+	}
+	for _, e := range entries {
+		if !e.Valid || (!cutoff.IsZero() && time.Unix(0, e.Entry.RecordedAt).Before(cutoff)) {
+			removed = append(removed, e)
+			continue
+		}
+		kept = append(kept, e)
+	}
+	if opts.MaxEntries > 0 && len(kept) > opts.MaxEntries {
+		sort.Slice(kept, func(i, j int) bool { return kept[i].Entry.RecordedAt > kept[j].Entry.RecordedAt })
+		removed = append(removed, kept[opts.MaxEntries:]...)
+	}
+	if opts.DryRun {
+		return removed, nil
+	}
+	for _, e := range removed {
+		if err := os.Remove(e.Path); err != nil && !os.IsNotExist(err) {
+			return removed, fmt.Errorf("remove %s: %w", e.Path, err)
+		}
+	}
+	return removed, nil
+}
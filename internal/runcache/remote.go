@@ -0,0 +1,185 @@
+package runcache
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// Remote access modes. Laptops typically read a cache that CI populates.
+const (
+	ModeRead      = "read"
+	ModeReadWrite = "readwrite"
+)
+
+// Environment variables that configure the shared remote cache. They take
+// precedence over .gavel.yaml so CI can switch to ModeReadWrite and inject a
+// token without touching the repo.
+const (
+	EnvURL   = "GAVEL_CACHE_URL"
+	EnvToken = "GAVEL_CACHE_TOKEN"
+	EnvMode  = "GAVEL_CACHE_MODE"
+)
+
+// maxEntryBytes bounds entry bodies on both sides of the wire. Real entries
+// are a few hundred bytes.
+const maxEntryBytes = 64 << 10
+
+// Options selects the backend returned by OpenBackend.
+type Options struct {
+	// Dir is the local store directory; empty means DefaultDir.
+	Dir string
+	// URL of a `gavel cache serve` endpoint. Empty disables the remote.
+	URL string
+	// Token is sent as a bearer token on every remote request.
+	Token string
+	// Mode is ModeRead (default) or ModeReadWrite.
+	Mode string
+}
+
+// WithEnv overlays the GAVEL_CACHE_* environment variables onto o.
+func (o Options) WithEnv() Options {
+	if v := os.Getenv(EnvURL); v != "" {
+		o.URL = v
+	}
+	if v := os.Getenv(EnvToken); v != "" {
+		o.Token = v
+	}
+	if v := os.Getenv(EnvMode); v != "" {
+		o.Mode = v
+	}
+	return o
+}
+
+// OpenBackend opens the local store and, when o.URL is set, layers the
+// remote store on top of it.
+func OpenBackend(o Options) (Backend, error) {
+	local, err := Open(o.Dir)
+	if err != nil {
+		return nil, err
+	}
+	if o.URL == "" {
+		return local, nil
+	}
+	var push bool
+	switch strings.ToLower(o.Mode) {
+	case "", ModeRead:
+	case ModeReadWrite:
+		push = true
+	default:
+		return nil, fmt.Errorf("invalid run-cache mode %q (want %s or %s)", o.Mode, ModeRead, ModeReadWrite)
+	}
+	return &Tiered{Local: local, Remote: NewHTTPStore(o.URL, o.Token), Push: push}, nil
+}
+
+// HTTPStore is a Backend on a remote server that answers
+// GET/PUT <URL>/<fingerprint> with an Entry JSON body, such as
+// `gavel cache serve`.
+type HTTPStore struct {
+	URL    string
+	Token  string
+	Client *http.Client
+}
+
+// NewHTTPStore returns an HTTPStore with a short request timeout: a slow
+// cache must never hold up a test run.
+func NewHTTPStore(baseURL, token string) *HTTPStore {
+	return &HTTPStore{
+		URL:    strings.TrimRight(baseURL, "/"),
+		Token:  token,
+		Client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (h *HTTPStore) request(method, fingerprint string, body io.Reader) (*http.Response, error) {
+	req, err := http.NewRequest(method, h.URL+"/"+url.PathEscape(fingerprint), body)
+	if err != nil {
+		return nil, err
+	}
+	if h.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+h.Token)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	return h.Client.Do(req)
+}
+
+// Lookup fetches the entry for fingerprint. Network errors, auth failures
+// and undecodable bodies are all misses.
+func (h *HTTPStore) Lookup(fingerprint string) (Entry, bool) {
+	resp, err := h.request(http.MethodGet, fingerprint, nil)
+	if err != nil {
+		return Entry{}, false
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return Entry{}, false
+	}
+	var e Entry
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxEntryBytes)).Decode(&e); err != nil {
+		return Entry{}, false
+	}
+	if e.Version != schemaVersion {
+		return Entry{}, false
+	}
+	return e, true
+}
+
+// Record uploads a passing run.
+func (h *HTTPStore) Record(fingerprint string, tooRecent bool, entry Entry) error {
+	entry, ok := recordable(entry, tooRecent)
+	if !ok {
+		return nil
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("marshal entry: %w", err)
+	}
+	resp, err := h.request(http.MethodPut, fingerprint, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("PUT %s: %w", h.URL, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("PUT %s: %s: %s", h.URL, resp.Status, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
+
+// Tiered checks the local store before the remote one. Remote hits are
+// copied into Local so repeat lookups stay offline. Passing runs are always
+// recorded locally, and also pushed to Remote when Push is set.
+type Tiered struct {
+	Local  *Store
+	Remote Backend
+	Push   bool
+}
+
+func (t *Tiered) Lookup(fingerprint string) (Entry, bool) {
+	if e, ok := t.Local.Lookup(fingerprint); ok {
+		return e, true
+	}
+	e, ok := t.Remote.Lookup(fingerprint)
+	if !ok {
+		return Entry{}, false
+	}
+	_ = t.Local.Record(fingerprint, false, e)
+	return e, true
+}
+
+func (t *Tiered) Record(fingerprint string, tooRecent bool, entry Entry) error {
+	err := t.Local.Record(fingerprint, tooRecent, entry)
+	if t.Push {
+		err = errors.Join(err, t.Remote.Record(fingerprint, tooRecent, entry))
+	}
+	return err
+}
@@ -0,0 +1,210 @@
+package runcache_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/flanksource/gavel/internal/runcache"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("HTTPStore", func() {
+	var (
+		server *runcache.Server
+		remote *runcache.Store
+		ts     *httptest.Server
+	)
+
+	BeforeEach(func() {
+		var err error
+		remote, err = runcache.Open(GinkgoT().TempDir())
+		Expect(err).NotTo(HaveOccurred())
+		server = &runcache.Server{Store: remote, Token: "ci-secret"}
+		ts = httptest.NewServer(server)
+		DeferCleanup(ts.Close)
+	})
+
+	passing := runcache.Entry{ImportPath: "example.com/fix/a", Framework: "go test", PassCount: 2}
+
+	It("round-trips an entry written with the write token", func() {
+		ci := runcache.NewHTTPStore(ts.URL, "ci-secret")
+		Expect(ci.Record("go test:abc123", false, passing)).To(Succeed())
+
+		laptop := runcache.NewHTTPStore(ts.URL, "")
+		got, ok := laptop.Lookup("go test:abc123")
+		Expect(ok).To(BeTrue())
+		Expect(got.ImportPath).To(Equal("example.com/fix/a"))
+		Expect(got.PassCount).To(Equal(2))
+
+		_, ok = remote.Lookup("go test:abc123")
+		Expect(ok).To(BeTrue(), "entry should land in the served directory")
+	})
+
+	It("rejects writes without the token", func() {
+		laptop := runcache.NewHTTPStore(ts.URL, "wrong")
+		Expect(laptop.Record("go test:abc123", false, passing)).To(MatchError(ContainSubstring("401")))
+		_, ok := remote.Lookup("go test:abc123")
+		Expect(ok).To(BeFalse())
+	})
+
+	It("refuses unauthenticated writes when no token is set", func() {
+		server.Token = ""
+		req, err := http.NewRequest(http.MethodPut, ts.URL+"/go%20test:abc123", strings.NewReader(`{"version":1,"pass_count":1}`))
+		Expect(err).NotTo(HaveOccurred())
+		resp, err := http.DefaultClient.Do(req)
+		Expect(err).NotTo(HaveOccurred())
+		resp.Body.Close()
+		Expect(resp.StatusCode).To(Equal(http.StatusForbidden))
+		Expect(runcache.NewHTTPStore(ts.URL, "").Record("go test:abc123", false, passing)).To(MatchError(ContainSubstring("403")))
+		_, ok := remote.Lookup("go test:abc123")
+		Expect(ok).To(BeFalse())
+
+		server.InsecureWrites = true
+		Expect(runcache.NewHTTPStore(ts.URL, "").Record("go test:abc123", false, passing)).To(Succeed())
+	})
+
+	It("rejects every write when read-only", func() {
+		server.ReadOnly = true
+		ci := runcache.NewHTTPStore(ts.URL, "ci-secret")
+		Expect(ci.Record("go test:abc123", false, passing)).To(MatchError(ContainSubstring("403")))
+	})
+
+	It("requires the read token for reads when one is set", func() {
+		server.ReadToken = "laptop"
+		Expect(runcache.NewHTTPStore(ts.URL, "ci-secret").Record("fp", false, passing)).To(Succeed())
+
+		_, ok := runcache.NewHTTPStore(ts.URL, "").Lookup("fp")
+		Expect(ok).To(BeFalse())
+		_, ok = runcache.NewHTTPStore(ts.URL, "laptop").Lookup("fp")
+		Expect(ok).To(BeTrue())
+		Expect(runcache.NewHTTPStore(ts.URL, "laptop").Record("fp2", false, passing)).NotTo(Succeed())
+	})
+
+	It("refuses fingerprints that would escape the store directory", func() {
+		req, err := http.NewRequest(http.MethodGet, ts.URL+"/..%2F..%2Fetc", nil)
+		Expect(err).NotTo(HaveOccurred())
+		resp, err := http.DefaultClient.Do(req)
+		Expect(err).NotTo(HaveOccurred())
+		resp.Body.Close()
+		Expect(resp.StatusCode).To(Equal(http.StatusBadRequest))
+	})
+
+	It("treats an unreachable server as a miss", func() {
+		_, ok := runcache.NewHTTPStore("http://127.0.0.1:1", "").Lookup("fp")
+		Expect(ok).To(BeFalse())
+	})
+})
+
+var _ = Describe("Tiered", func() {
+	var (
+		remote *runcache.Store
+		ts     *httptest.Server
+	)
+
+	BeforeEach(func() {
+		var err error
+		remote, err = runcache.Open(GinkgoT().TempDir())
+		Expect(err).NotTo(HaveOccurred())
+		ts = httptest.NewServer(&runcache.Server{Store: remote, InsecureWrites: true})
+		DeferCleanup(ts.Close)
+	})
+
+	open := func(mode string) *runcache.Tiered {
+		b, err := runcache.OpenBackend(runcache.Options{Dir: GinkgoT().TempDir(), URL: ts.URL, Mode: mode})
+		Expect(err).NotTo(HaveOccurred())
+		return b.(*runcache.Tiered)
+	}
+
+	It("only records locally in read mode", func() {
+		t := open(runcache.ModeRead)
+		Expect(t.Record("fp", false, runcache.Entry{PassCount: 1})).To(Succeed())
+		_, ok := t.Local.Lookup("fp")
+		Expect(ok).To(BeTrue())
+		_, ok = remote.Lookup("fp")
+		Expect(ok).To(BeFalse())
+	})
+
+	It("pushes to the remote in readwrite mode", func() {
+		t := open(runcache.ModeReadWrite)
+		Expect(t.Record("fp", false, runcache.Entry{PassCount: 1})).To(Succeed())
+		_, ok := remote.Lookup("fp")
+		Expect(ok).To(BeTrue())
+	})
+
+	It("copies remote hits into the local store", func() {
+		Expect(remote.Record("fp", false, runcache.Entry{PassCount: 4})).To(Succeed())
+		t := open(runcache.ModeRead)
+		got, ok := t.Lookup("fp")
+		Expect(ok).To(BeTrue())
+		Expect(got.PassCount).To(Equal(4))
+		_, ok = t.Local.Lookup("fp")
+		Expect(ok).To(BeTrue())
+	})
+
+	It("rejects an unknown mode", func() {
+		_, err := runcache.OpenBackend(runcache.Options{Dir: GinkgoT().TempDir(), URL: ts.URL, Mode: "write-only"})
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("Store management", func() {
+	var store *runcache.Store
+
+	BeforeEach(func() {
+		var err error
+		store, err = runcache.Open(GinkgoT().TempDir())
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	record := func(fp string, age time.Duration) {
+		Expect(store.Record(fp, false, runcache.Entry{
+			ImportPath:    "example.com/" + fp,
+			Framework:     "go test",
+			PassCount:     1,
+			DurationNanos: int64(time.Second),
+			RecordedAt:    time.Now().Add(-age).UnixNano(),
+		})).To(Succeed())
+	}
+
+	It("aggregates stats and flags invalid entries", func() {
+		record("aa1", time.Hour)
+		record("bb2", time.Minute)
+		Expect(os.WriteFile(store.Dir+"/bb/bb3.json", []byte("{"), 0o644)).To(Succeed())
+
+		st, err := store.Stats()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(st.Entries).To(Equal(3))
+		Expect(st.Invalid).To(Equal(1))
+		Expect(st.ByFramework).To(HaveKeyWithValue("go test", 2))
+		Expect(st.SavedNanos).To(Equal(int64(2 * time.Second)))
+	})
+
+	It("prunes by age, count and validity", func() {
+		record("aa1", 48*time.Hour)
+		record("aa2", 2*time.Hour)
+		record("aa3", time.Hour)
+		record("aa4", time.Minute)
+		Expect(os.WriteFile(store.Dir+"/aa/aa5.json", []byte("{"), 0o644)).To(Succeed())
+
+		dry, err := store.Prune(runcache.PruneOptions{OlderThan: 24 * time.Hour, DryRun: true})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(dry).To(HaveLen(2))
+		entries, _ := store.Entries()
+		Expect(entries).To(HaveLen(5), "dry run must not delete")
+
+		removed, err := store.Prune(runcache.PruneOptions{OlderThan: 24 * time.Hour, MaxEntries: 2})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(removed).To(HaveLen(3))
+
+		entries, _ = store.Entries()
+		var left []string
+		for _, e := range entries {
+			left = append(left, e.Fingerprint)
+		}
+		Expect(left).To(ConsistOf("aa3", "aa4"))
+	})
+})
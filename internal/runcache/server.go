@@ -0,0 +1,140 @@
+package runcache
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+// Server exposes a Store over HTTP for HTTPStore clients:
+//
+//	GET  /<fingerprint>  200 + Entry JSON, or 404
+//	PUT  /<fingerprint>  stores an Entry JSON body
+//	GET  /               Stats JSON
+//
+// Token guards writes and ReadToken, when set, guards reads (Token is
+// accepted for reads too). Handing laptops only the ReadToken — or nothing,
+// when ReadToken is empty — lets CI populate a cache everyone else can only
+// read. ReadOnly rejects every write regardless of token.
+//
+// Without a Token writes are refused: a passing entry makes gavel test
+// --cache skip the package, so anonymous writes would let anyone who can
+// reach the server hide failing tests. InsecureWrites opts back into them.
+type Server struct {
+	Store          *Store
+	Token          string
+	ReadToken      string
+	ReadOnly       bool
+	InsecureWrites bool
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	fingerprint := strings.TrimPrefix(r.URL.Path, "/")
+	switch r.Method {
+	case http.MethodGet, http.MethodHead:
+		if !s.canRead(r) {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		if fingerprint == "" {
+			s.serveStats(w)
+			return
+		}
+		s.serveLookup(w, r, fingerprint)
+	case http.MethodPut:
+		if s.ReadOnly {
+			http.Error(w, "cache is read-only", http.StatusForbidden)
+			return
+		}
+		if s.Token == "" && !s.InsecureWrites {
+			http.Error(w, "cache writes need a server token", http.StatusForbidden)
+			return
+		}
+		if !s.canWrite(r) {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		s.serveRecord(w, r, fingerprint)
+	default:
+		w.Header().Set("Allow", "GET, HEAD, PUT")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *Server) serveLookup(w http.ResponseWriter, r *http.Request, fingerprint string) {
+	if !validFingerprint(fingerprint) {
+		http.Error(w, "invalid fingerprint", http.StatusBadRequest)
+		return
+	}
+	e, ok := s.Store.Lookup(fingerprint)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	writeJSON(w, e)
+}
+
+func (s *Server) serveRecord(w http.ResponseWriter, r *http.Request, fingerprint string) {
+	if !validFingerprint(fingerprint) {
+		http.Error(w, "invalid fingerprint", http.StatusBadRequest)
+		return
+	}
+	var e Entry
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxEntryBytes)).Decode(&e); err != nil {
+		http.Error(w, "invalid entry: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if e.Version != schemaVersion {
+		http.Error(w, "unsupported entry version", http.StatusBadRequest)
+		return
+	}
+	if err := s.Store.Record(fingerprint, false, e); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) serveStats(w http.ResponseWriter) {
+	st, err := s.Store.Stats()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	st.Dir = ""
+	writeJSON(w, st)
+}
+
+func (s *Server) canRead(r *http.Request) bool {
+	if s.ReadToken == "" {
+		return true
+	}
+	return hasToken(r, s.ReadToken) || (s.Token != "" && hasToken(r, s.Token))
+}
+
+func (s *Server) canWrite(r *http.Request) bool {
+	if s.Token == "" {
+		return s.InsecureWrites
+	}
+	return hasToken(r, s.Token)
+}
+
+func hasToken(r *http.Request, token string) bool {
+	got, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return ok && subtle.ConstantTimeCompare([]byte(got), []byte(token)) == 1
+}
+
+// validFingerprint rejects anything that could escape the store directory
+// once sharded into a file path.
+func validFingerprint(fp string) bool {
+	if fp == "" || len(fp) > 256 || strings.HasPrefix(fp, ".") {
+		return false
+	}
+	return !strings.ContainsAny(fp, "/\\\x00")
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
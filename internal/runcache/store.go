@@ -44,6 +44,18 @@ func (e Entry) Duration() time.Duration { return time.Duration(e.DurationNanos)
 // prior entries (Load returns miss on mismatch).
 const schemaVersion = 1
 
+// Backend persists run-cache entries by fingerprint. Store keeps them in a
+// local directory, HTTPStore talks to a shared `gavel cache serve` endpoint,
+// and Tiered layers the two.
+type Backend interface {
+	// Lookup returns the entry for fingerprint, or (zero, false) on a miss.
+	// Implementations report every failure as a miss.
+	Lookup(fingerprint string) (Entry, bool)
+	// Record persists a successful run. Failing entries and tooRecent
+	// fingerprints are skipped without error.
+	Record(fingerprint string, tooRecent bool, entry Entry) error
+}
+
 // Store is a content-addressed cache of per-package run outcomes. It is safe
 // for concurrent use by multiple goroutines; filesystem atomicity is provided
 // by rename().
@@ -100,16 +112,10 @@ func (s *Store) Lookup(fingerprint string) (Entry, bool) {
 // inputs are silently ignored. If tooRecent is true (see Fingerprint), the
 // write is skipped to defend against mtime granularity races.
 func (s *Store) Record(fingerprint string, tooRecent bool, entry Entry) error {
-	if entry.ExitCode != 0 || entry.FailCount > 0 {
-		return nil
-	}
-	if tooRecent {
+	entry, ok := recordable(entry, tooRecent)
+	if !ok {
 		return nil
 	}
-	entry.Version = schemaVersion
-	if entry.RecordedAt == 0 {
-		entry.RecordedAt = time.Now().UnixNano()
-	}
 
 	path := s.entryPath(fingerprint)
 	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
@@ -142,6 +148,19 @@ func (s *Store) Record(fingerprint string, tooRecent bool, entry Entry) error {
 	return nil
 }
 
+// recordable applies the Record contract shared by every Backend: only
+// passing, settled runs are stored, stamped with the current schema version.
+func recordable(entry Entry, tooRecent bool) (Entry, bool) {
+	if entry.ExitCode != 0 || entry.FailCount > 0 || tooRecent {
+		return entry, false
+	}
+	entry.Version = schemaVersion
+	if entry.RecordedAt == 0 {
+		entry.RecordedAt = time.Now().UnixNano()
+	}
+	return entry, true
+}
+
 // entryPath returns the file path for a given fingerprint. We shard by the
 // first two hex characters to avoid blowing up a single directory — same
 // pattern Go's build cache uses (cmd/go/internal/cache/cache.go:117).
@@ -0,0 +1,204 @@
+// Package watch turns filesystem events under a directory tree into
+// debounced batches of changed files for gavel's --watch modes.
+package watch
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/flanksource/commons/logger"
+	"github.com/flanksource/gavel/internal/changegraph"
+	"github.com/fsnotify/fsnotify"
+)
+
+// DefaultDebounce is how long the tree must be quiet before a batch is
+// delivered. Long enough to coalesce an editor's save (write + rename +
+// chmod) and a formatter run into one batch.
+const DefaultDebounce = 300 * time.Millisecond
+
+// skippedDirs are never watched: VCS metadata, gavel's own output, and
+// dependency or build trees whose churn would trigger constant reruns.
+var skippedDirs = map[string]bool{
+	".git":         true,
+	".gavel":       true,
+	".todos":       true,
+	"node_modules": true,
+	"vendor":       true,
+	"__pycache__":  true,
+	"target":       true,
+	"dist":         true,
+}
+
+// Options configures a Watcher.
+type Options struct {
+	// Root is the directory tree to watch. Batches are relative to it.
+	Root string
+	// Debounce overrides DefaultDebounce.
+	Debounce time.Duration
+	// Skip reports whether a root-relative, slash-separated directory should
+	// not be watched, in addition to the built-in skipped directories.
+	Skip func(rel string) bool
+}
+
+// Watcher watches every directory under Options.Root, including ones
+// created after it starts.
+type Watcher struct {
+	opts    Options
+	root    string
+	watcher *fsnotify.Watcher
+}
+
+// New starts watching opts.Root recursively.
+func New(opts Options) (*Watcher, error) {
+	root, err := filepath.Abs(opts.Root)
+	if err != nil {
+		return nil, err
+	}
+	if opts.Debounce <= 0 {
+		opts.Debounce = DefaultDebounce
+	}
+	fw, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+	w := &Watcher{opts: opts, root: root, watcher: fw}
+	if err := w.addTree(root, nil); err != nil {
+		_ = fw.Close()
+		return nil, err
+	}
+	return w, nil
+}
+
+// Close stops watching.
+func (w *Watcher) Close() error {
+	return w.watcher.Close()
+}
+
+// Run calls fn with each debounced batch of changed files until ctx is done.
+// fn runs synchronously: changes made while it runs are delivered in the next
+// batch, so a slow test run never overlaps the next one.
+func (w *Watcher) Run(ctx context.Context, fn func(changegraph.FileSet)) error {
+	pending := changegraph.NewFileSet()
+	timer := time.NewTimer(time.Hour)
+	timer.Stop()
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case ev, ok := <-w.watcher.Events:
+			if !ok {
+				return nil
+			}
+			if w.record(ev, pending) {
+				timer.Reset(w.opts.Debounce)
+			}
+		case err, ok := <-w.watcher.Errors:
+			if !ok {
+				return nil
+			}
+			if errors.Is(err, fsnotify.ErrEventOverflow) {
+				logger.Warnf("watch: event queue overflowed, some changes may have been missed")
+				continue
+			}
+			logger.Warnf("watch: %v", err)
+		case <-timer.C:
+			if pending.Len() == 0 {
+				continue
+			}
+			batch := pending
+			pending = changegraph.NewFileSet()
+			fn(batch)
+		}
+	}
+}
+
+// record adds the file behind ev to pending, returning false for events
+// that do not change content (chmod) or files no tool reads (editor swap
+// and backup files). New directories are watched and their files recorded,
+// since a checkout or an unpacked archive creates them before any event
+// could be seen inside.
+func (w *Watcher) record(ev fsnotify.Event, pending changegraph.FileSet) bool {
+	if ev.Op == fsnotify.Chmod || isScratchFile(filepath.Base(ev.Name)) {
+		return false
+	}
+	rel, ok := w.rel(ev.Name)
+	if !ok {
+		return false
+	}
+	if ev.Has(fsnotify.Create) {
+		if info, err := os.Stat(ev.Name); err == nil && info.IsDir() {
+			if w.skipDir(rel) {
+				return false
+			}
+			if err := w.addTree(ev.Name, pending); err != nil {
+				logger.V(2).Infof("watch: add %s: %v", rel, err)
+			}
+			return true
+		}
+	}
+	pending.Add(rel)
+	return true
+}
+
+// addTree watches dir and every non-skipped directory below it. When files
+// is non-nil the regular files found are added to it.
+func (w *Watcher) addTree(dir string, files changegraph.FileSet) error {
+	return filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			// A directory removed mid-walk is not an error worth stopping for.
+			return nil
+		}
+		rel, _ := w.rel(path)
+		if !d.IsDir() {
+			if files != nil && !isScratchFile(d.Name()) {
+				files.Add(rel)
+			}
+			return nil
+		}
+		if path != w.root && w.skipDir(rel) {
+			return filepath.SkipDir
+		}
+		if err := w.watcher.Add(path); err != nil {
+			return err
+		}
+		return nil
+	})
+}
+
+func (w *Watcher) skipDir(rel string) bool {
+	base := filepath.Base(rel)
+	if skippedDirs[base] || (strings.HasPrefix(base, ".") && base != ".") {
+		return true
+	}
+	return w.opts.Skip != nil && w.opts.Skip(rel)
+}
+
+func (w *Watcher) rel(path string) (string, bool) {
+	rel, err := filepath.Rel(w.root, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+	return filepath.ToSlash(rel), true
+}
+
+// isScratchFile matches the temporary files editors write next to the file
+// being saved (vim swap files and its 4913 write probe, emacs lock and
+// backup files).
+func isScratchFile(name string) bool {
+	switch {
+	case name == "4913",
+		strings.HasSuffix(name, "~"),
+		strings.HasPrefix(name, ".#"),
+		strings.HasSuffix(name, ".swp"),
+		strings.HasSuffix(name, ".swx"):
+		return true
+	}
+	return false
+}
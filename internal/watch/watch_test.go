@@ -0,0 +1,66 @@
+package watch_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/flanksource/gavel/internal/changegraph"
+	"github.com/flanksource/gavel/internal/watch"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Watcher", func() {
+	var (
+		root    string
+		batches chan []string
+	)
+
+	write := func(rel, content string) {
+		path := filepath.Join(root, rel)
+		Expect(os.MkdirAll(filepath.Dir(path), 0o755)).To(Succeed())
+		Expect(os.WriteFile(path, []byte(content), 0o644)).To(Succeed())
+	}
+
+	BeforeEach(func() {
+		root = GinkgoT().TempDir()
+		write("pkg/a/a.go", "package a\n")
+		Expect(os.MkdirAll(filepath.Join(root, "node_modules/dep"), 0o755)).To(Succeed())
+
+		w, err := watch.New(watch.Options{Root: root, Debounce: 50 * time.Millisecond})
+		Expect(err).NotTo(HaveOccurred())
+		DeferCleanup(w.Close)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		DeferCleanup(cancel)
+		batches = make(chan []string, 8)
+		go func() {
+			defer GinkgoRecover()
+			_ = w.Run(ctx, func(fs changegraph.FileSet) { batches <- fs.Sorted() })
+		}()
+	})
+
+	It("coalesces a burst of writes into one batch", func() {
+		write("pkg/a/a.go", "package a\n\nvar X = 1\n")
+		write("pkg/a/a_test.go", "package a\n")
+		Eventually(batches).Should(Receive(ConsistOf("pkg/a/a.go", "pkg/a/a_test.go")))
+		Consistently(batches, 200*time.Millisecond).ShouldNot(Receive())
+	})
+
+	It("watches directories created after it started", func() {
+		write("pkg/b/b.go", "package b\n")
+		Eventually(batches).Should(Receive(ContainElement("pkg/b/b.go")))
+
+		write("pkg/b/b_test.go", "package b\n")
+		Eventually(batches).Should(Receive(Equal([]string{"pkg/b/b_test.go"})))
+	})
+
+	It("ignores skipped directories and editor scratch files", func() {
+		write("node_modules/dep/index.js", "")
+		write("pkg/a/.a.go.swp", "")
+		write("pkg/a/a.go~", "")
+		Consistently(batches, 300*time.Millisecond).ShouldNot(Receive())
+	})
+})
@@ -0,0 +1,269 @@
+// Package arch enforces the import rules of arch.yaml (the arch-unit rule
+// format parsed by models.Config) across Go, TypeScript/JavaScript and Python
+// sources, without shelling out to an external arch-unit binary.
+package arch
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/bmatcuk/doublestar/v4"
+	"github.com/flanksource/clicky"
+	commonsContext "github.com/flanksource/commons/context"
+	"github.com/flanksource/commons/logger"
+	"github.com/flanksource/gavel/linters"
+	"github.com/flanksource/gavel/models"
+	"gopkg.in/yaml.v3"
+)
+
+// ConfigFiles are the file names the rules are loaded from, in order of
+// preference. Their directory is the root rule patterns are relative to.
+var ConfigFiles = []string{"arch.yaml", "arch-unit.yaml"}
+
+// Arch implements the Linter interface for arch.yaml import rules
+type Arch struct {
+	linters.RunOptions
+}
+
+// NewArch creates a new arch linter
+func NewArch(workDir string) *Arch {
+	return &Arch{
+		RunOptions: linters.RunOptions{
+			WorkDir: workDir,
+		},
+	}
+}
+
+// SetOptions sets the run options for the linter
+func (a *Arch) SetOptions(opts linters.RunOptions) {
+	a.RunOptions = opts
+}
+
+// Name returns the linter name
+func (a *Arch) Name() string {
+	return "arch"
+}
+
+// InProcess reports that the rules are evaluated by gavel itself.
+func (a *Arch) InProcess() bool {
+	return true
+}
+
+// ProjectRootMarkers anchors the linter at the directory holding arch.yaml,
+// since rule patterns are relative to it.
+func (a *Arch) ProjectRootMarkers() []string {
+	return ConfigFiles
+}
+
+// DefaultIncludes returns the source files whose imports are checked
+func (a *Arch) DefaultIncludes() []string {
+	return []string{"**/*.go", "**/*.ts", "**/*.tsx", "**/*.js", "**/*.jsx", "**/*.mjs", "**/*.cjs", "**/*.py"}
+}
+
+// DefaultExcludes returns dependency, build and virtualenv directories
+func (a *Arch) DefaultExcludes() []string {
+	return []string{
+		"**/node_modules/**",
+		"**/vendor/**",
+		"**/dist/**",
+		"**/build/**",
+		"**/.venv/**",
+		"**/venv/**",
+		"**/__pycache__/**",
+		"**/testdata/**",
+		"**/*.d.ts",
+	}
+}
+
+// SupportsJSON returns false; there is no external output to parse
+func (a *Arch) SupportsJSON() bool {
+	return false
+}
+
+// JSONArgs returns nil
+func (a *Arch) JSONArgs() []string {
+	return nil
+}
+
+// SupportsFix returns false; import violations need a design decision
+func (a *Arch) SupportsFix() bool {
+	return false
+}
+
+// FixArgs returns nil
+func (a *Arch) FixArgs() []string {
+	return nil
+}
+
+// ValidateConfig validates linter-specific configuration
+func (a *Arch) ValidateConfig(config *models.LinterConfig) error {
+	if config == nil {
+		return fmt.Errorf("config cannot be nil")
+	}
+	return nil
+}
+
+// DryRunCommand reports the rules file and files the linter would check.
+func (a *Arch) DryRunCommand() (string, []string) {
+	args := []string{ConfigFiles[0]}
+	if path := findConfig(a.WorkDir); path != "" {
+		args[0] = filepath.Base(path)
+	}
+	return a.Name(), append(args, a.Files...)
+}
+
+// LoadConfig reads the first of ConfigFiles found in dir. It returns nil
+// without an error when dir has none.
+func LoadConfig(dir string) (*models.Config, error) {
+	path := findConfig(dir)
+	if path == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var cfg models.Config
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	cfg.Path = path
+	return &cfg, nil
+}
+
+func findConfig(dir string) string {
+	for _, name := range ConfigFiles {
+		path := filepath.Join(dir, name)
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			return path
+		}
+	}
+	return ""
+}
+
+// Run collects the imports of every matching source file under WorkDir (or
+// of Files when set) and reports the ones the rules for that file deny.
+func (a *Arch) Run(ctx commonsContext.Context, task *clicky.Task) ([]models.Violation, error) {
+	cfg := a.ArchConfig
+	if cfg == nil || len(cfg.Rules) == 0 {
+		loaded, err := LoadConfig(a.WorkDir)
+		if err != nil {
+			return nil, err
+		}
+		cfg = loaded
+	}
+	if cfg == nil || len(cfg.Rules) == 0 {
+		logger.Debugf("arch: no import rules in %s", a.WorkDir)
+		return []models.Violation{}, nil
+	}
+
+	root, err := filepath.Abs(a.WorkDir)
+	if err != nil {
+		return nil, err
+	}
+	refs, err := collectImports(root, a.fileFilter(root, cfg))
+	if err != nil {
+		return nil, err
+	}
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	return checkImports(root, cfg, refs)
+}
+
+// fileFilter returns the predicate deciding which root-relative files are
+// checked: the includes, minus the default and global excludes, restricted
+// to Files when the run was scoped.
+func (a *Arch) fileFilter(root string, cfg *models.Config) func(string) bool {
+	excludes := append(a.DefaultExcludes(), cfg.GlobalExcludes...)
+	var scoped map[string]bool
+	if len(a.Files) > 0 {
+		scoped = make(map[string]bool, len(a.Files))
+		for _, f := range a.Files {
+			if !filepath.IsAbs(f) {
+				f = filepath.Join(root, f)
+			}
+			if rel, err := filepath.Rel(root, f); err == nil {
+				scoped[filepath.ToSlash(rel)] = true
+			}
+		}
+	}
+	return func(rel string) bool {
+		if scoped != nil && !scoped[rel] {
+			return false
+		}
+		for _, pattern := range excludes {
+			if ok, _ := doublestar.Match(pattern, rel); ok {
+				return false
+			}
+		}
+		for _, pattern := range a.DefaultIncludes() {
+			if ok, _ := doublestar.Match(pattern, rel); ok {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// checkImports evaluates each import against the rules for its file. A
+// local import is checked under every name a rule may use for it (the
+// import as written and its root-relative path), and the first denial wins.
+func checkImports(root string, cfg *models.Config, refs []importRef) ([]models.Violation, error) {
+	ruleSets := map[string]*models.RuleSet{}
+	violations := []models.Violation{}
+	for _, ref := range refs {
+		rs, ok := ruleSets[ref.File]
+		if !ok {
+			var err error
+			if rs, err = cfg.GetRulesForFile(ref.File); err != nil {
+				return nil, err
+			}
+			ruleSets[ref.File] = rs
+		}
+		if len(rs.Rules) == 0 {
+			continue
+		}
+		for _, name := range ref.names() {
+			allowed, rule := rs.IsAllowedForFile(name, "", ref.File)
+			if allowed || rule == nil {
+				continue
+			}
+			violations = append(violations, violation(root, ref, *rule))
+			break
+		}
+	}
+	sort.SliceStable(violations, func(i, j int) bool {
+		if violations[i].File != violations[j].File {
+			return violations[i].File < violations[j].File
+		}
+		return violations[i].Line < violations[j].Line
+	})
+	return violations, nil
+}
+
+func violation(root string, ref importRef, rule models.Rule) models.Violation {
+	file := filepath.Join(root, filepath.FromSlash(ref.File))
+	var message string
+	if ref.Local != "" {
+		message = fmt.Sprintf("layering violation: %s imports %s, denied by %q (%s)",
+			filepath.ToSlash(filepath.Dir(ref.File)), ref.Local, rule.OriginalLine, rule.SourceFile)
+	} else {
+		message = fmt.Sprintf("forbidden import %q, denied by %q (%s)", ref.Path, rule.OriginalLine, rule.SourceFile)
+	}
+	v := models.NewViolationBuilder().
+		WithFile(file).
+		WithLocation(ref.Line, 0).
+		WithCaller(filepath.Dir(file), "").
+		WithCalled(ref.Path, "").
+		WithMessage(message).
+		WithSource("arch").
+		WithCode(strings.TrimSpace(ref.Statement)).
+		Build()
+	v.Rule = &rule
+	v.Severity = models.SeverityError
+	return v
+}
@@ -0,0 +1,173 @@
+package arch
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	commonsContext "github.com/flanksource/commons/context"
+	"github.com/flanksource/gavel/linters"
+	"github.com/flanksource/gavel/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeFiles(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	}
+}
+
+func run(t *testing.T, root string, files ...string) []models.Violation {
+	t.Helper()
+	a := NewArch(root)
+	a.SetOptions(linters.RunOptions{WorkDir: root, Files: files})
+	violations, err := a.Run(commonsContext.NewContext(context.Background()), nil)
+	require.NoError(t, err)
+	return violations
+}
+
+func rel(t *testing.T, root string, v models.Violation) string {
+	t.Helper()
+	r, err := filepath.Rel(root, v.File)
+	require.NoError(t, err)
+	return filepath.ToSlash(r)
+}
+
+func TestArchTypeScriptAndPython(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{
+		"arch.yaml": `rules:
+  "web/src/db/**":
+    imports:
+      - "!web/src/ui"
+  "app/db/**":
+    imports:
+      - "!app.api"
+      - "!requests"
+`,
+		"web/src/db/store.ts": `import { z } from "zod";
+import {
+  Button,
+} from '../ui/button';
+// import { old } from '../ui/old';
+export * from "./schema";
+const lazy = () => import('../ui/lazy');
+`,
+		"web/src/ui/button.ts": `import { store } from "../db/store";
+`,
+		"app/db/models.py": `import os, requests as r
+from ..api import views
+from . import base
+`,
+		"app/api/views.py": `from app.db import models
+`,
+		"web/node_modules/x/index.js": `require("../../src/ui/button")
+`,
+	})
+
+	violations := run(t, root)
+	require.Len(t, violations, 4)
+
+	got := map[string]int{}
+	for _, v := range violations {
+		got[rel(t, root, v)+":"+v.Rule.OriginalLine]++
+		assert.Equal(t, "arch", v.Source)
+		assert.Equal(t, models.SeverityError, v.Severity)
+		assert.Equal(t, models.RuleTypeDeny, v.Rule.Type)
+		assert.True(t, strings.HasPrefix(v.Rule.SourceFile, "arch.yaml:"), v.Rule.SourceFile)
+	}
+	assert.Equal(t, map[string]int{
+		"app/db/models.py:!requests":      1,
+		"app/db/models.py:!app.api":       1,
+		"web/src/db/store.ts:!web/src/ui": 2,
+	}, got)
+
+	for _, v := range violations {
+		if rel(t, root, v) == "web/src/db/store.ts" && v.Line == 4 {
+			assert.Contains(t, *v.Message, "layering violation: web/src/db imports web/src/ui/button")
+		}
+		if v.Rule.OriginalLine == "!requests" {
+			assert.Contains(t, *v.Message, `forbidden import "requests"`)
+			assert.Equal(t, 1, v.Line)
+		}
+	}
+
+	scoped := run(t, root, "app/db/models.py")
+	assert.Len(t, scoped, 2)
+}
+
+func TestArchGo(t *testing.T) {
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go toolchain not available")
+	}
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{
+		"go.mod": "module example.com/m\n\ngo 1.21\n",
+		"arch-unit.yaml": `rules:
+  "**":
+    imports:
+      - "!os/exec"
+  "internal/db/**":
+    imports:
+      - "!internal/api"
+`,
+		"internal/api/api.go": `package api
+
+import "example.com/m/internal/db"
+
+var X = db.Name
+`,
+		"internal/db/db.go": `package db
+
+import (
+	"fmt"
+	"os/exec"
+)
+
+var Name = fmt.Sprint(exec.ErrNotFound)
+`,
+		"internal/db/db_test.go": `package db_test
+
+import (
+	"testing"
+
+	"example.com/m/internal/api"
+)
+
+func TestX(t *testing.T) { _ = api.X }
+`,
+	})
+
+	violations := run(t, root)
+	require.Len(t, violations, 2)
+
+	assert.Equal(t, "internal/db/db.go", rel(t, root, violations[0]))
+	assert.Equal(t, 5, violations[0].Line)
+	assert.Equal(t, "!os/exec", violations[0].Rule.OriginalLine)
+	assert.Equal(t, "arch-unit.yaml:**", violations[0].Rule.SourceFile)
+
+	assert.Equal(t, "internal/db/db_test.go", rel(t, root, violations[1]))
+	assert.Equal(t, 6, violations[1].Line)
+	assert.Equal(t, "!internal/api", violations[1].Rule.OriginalLine)
+	assert.Contains(t, *violations[1].Message, "layering violation: internal/db imports internal/api")
+}
+
+func TestArchWithoutRules(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{"a.py": "import os\n"})
+	assert.Empty(t, run(t, root))
+}
+
+func TestResolvePyRelative(t *testing.T) {
+	assert.Equal(t, "app.db", resolvePyRelative("app/api/views.py", "..db"))
+	assert.Equal(t, "app.api", resolvePyRelative("app/api/views.py", "."))
+	assert.Equal(t, "util", resolvePyRelative("main.py", ".util"))
+	assert.Equal(t, "", resolvePyRelative("main.py", "...x"))
+}
@@ -0,0 +1,274 @@
+package arch
+
+import (
+	"bufio"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/flanksource/commons/logger"
+	"github.com/flanksource/gavel/internal/changegraph"
+	"github.com/flanksource/gavel/utils"
+)
+
+// importRef is one import statement found in File (root-relative, slash
+// separated) at Line. Path is the import as written (Go import path, JS
+// module specifier, Python module). Local is set for imports that resolve
+// inside the root: the root-relative directory of a Go package, the
+// root-relative path of a relative JS import, or the dotted module of a
+// project Python module.
+type importRef struct {
+	File      string
+	Line      int
+	Path      string
+	Local     string
+	Statement string
+}
+
+// names returns every name a rule may refer to the import by.
+func (r importRef) names() []string {
+	var names []string
+	add := func(name string) {
+		if name == "" {
+			return
+		}
+		for _, n := range names {
+			if n == name {
+				return
+			}
+		}
+		names = append(names, name)
+	}
+	if !strings.HasPrefix(r.Path, ".") {
+		add(r.Path)
+	}
+	add(r.Local)
+	if strings.HasSuffix(r.File, ".py") {
+		add(strings.ReplaceAll(r.Local, ".", "/"))
+	}
+	return names
+}
+
+// collectImports gathers the imports of every root-relative file keep
+// accepts: Go through the package graph `go list` reports, TS/JS and Python
+// by scanning import statements.
+func collectImports(root string, keep func(string) bool) ([]importRef, error) {
+	refs, err := goImports(root, keep)
+	if err != nil {
+		return nil, err
+	}
+
+	err = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.IsDir() {
+			if name := d.Name(); path != root && (name == ".git" || name == "node_modules") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return nil
+		}
+		rel = filepath.ToSlash(rel)
+		var scan func(string, string) ([]importRef, error)
+		switch filepath.Ext(rel) {
+		case ".ts", ".tsx", ".js", ".jsx", ".mjs", ".cjs":
+			scan = jsImports
+		case ".py":
+			scan = pyImports
+		default:
+			return nil
+		}
+		if !keep(rel) {
+			return nil
+		}
+		found, err := scan(root, rel)
+		if err != nil {
+			logger.Debugf("arch: skipping %s: %v", rel, err)
+			return nil
+		}
+		refs = append(refs, found...)
+		return nil
+	})
+	return refs, err
+}
+
+// goImports loads the package graph with changegraph (go list -json -deps)
+// and parses the import block of each file in a package under root, so
+// violations point at the import line.
+func goImports(root string, keep func(string) bool) ([]importRef, error) {
+	if utils.FindNearestGoModRoot(root) == "" {
+		return nil, nil
+	}
+	graph, err := changegraph.Load(root)
+	if err != nil {
+		return nil, err
+	}
+
+	var refs []importRef
+	fset := token.NewFileSet()
+	for _, pkg := range graph.Packages {
+		if pkg.Standard || !within(root, pkg.Dir) {
+			continue
+		}
+		var files []string
+		for _, group := range [][]string{pkg.GoFiles, pkg.CgoFiles, pkg.TestGoFiles, pkg.XTestGoFiles} {
+			files = append(files, group...)
+		}
+		for _, name := range files {
+			abs := filepath.Join(pkg.Dir, name)
+			rel, err := filepath.Rel(root, abs)
+			if err != nil {
+				continue
+			}
+			rel = filepath.ToSlash(rel)
+			if !keep(rel) {
+				continue
+			}
+			f, err := parser.ParseFile(fset, abs, nil, parser.ImportsOnly)
+			if err != nil {
+				logger.Debugf("arch: skipping %s: %v", rel, err)
+				continue
+			}
+			for _, spec := range f.Imports {
+				path, err := strconv.Unquote(spec.Path.Value)
+				if err != nil {
+					continue
+				}
+				ref := importRef{
+					File:      rel,
+					Line:      fset.Position(spec.Pos()).Line,
+					Path:      path,
+					Statement: "import " + spec.Path.Value,
+				}
+				if dep, ok := graph.Packages[path]; ok && !dep.Standard && within(root, dep.Dir) {
+					if local, err := filepath.Rel(root, dep.Dir); err == nil {
+						ref.Local = filepath.ToSlash(local)
+					}
+				}
+				refs = append(refs, ref)
+			}
+		}
+	}
+	return refs, nil
+}
+
+var jsImportPattern = regexp.MustCompile(`(?:\bfrom\s*|\bimport\s*|\bimport\s*\(\s*|\brequire\s*\(\s*)['"]([^'"\n]+)['"]`)
+
+// jsImports scans ES module imports/re-exports, dynamic import() and
+// require() calls. Relative specifiers are resolved to a root-relative path
+// without extension.
+func jsImports(root, rel string) ([]importRef, error) {
+	var refs []importRef
+	err := scanLines(filepath.Join(root, filepath.FromSlash(rel)), func(n int, line string) {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "//") || strings.HasPrefix(trimmed, "*") {
+			return
+		}
+		for _, m := range jsImportPattern.FindAllStringSubmatch(line, -1) {
+			ref := importRef{File: rel, Line: n, Path: m[1], Statement: trimmed}
+			if strings.HasPrefix(m[1], ".") {
+				target := filepath.ToSlash(filepath.Clean(filepath.Join(filepath.Dir(filepath.FromSlash(rel)), filepath.FromSlash(m[1]))))
+				if target != ".." && !strings.HasPrefix(target, "../") {
+					ref.Local = strings.TrimSuffix(target, filepath.Ext(target))
+				}
+			}
+			refs = append(refs, ref)
+		}
+	})
+	return refs, err
+}
+
+var (
+	pyImportPattern     = regexp.MustCompile(`^\s*import\s+(.+)$`)
+	pyFromImportPattern = regexp.MustCompile(`^\s*from\s+(\.*[\w.]*)\s+import\b`)
+)
+
+// pyImports scans import and from-import statements. Relative imports are
+// resolved against the file's package; absolute imports whose top-level
+// package exists under root are treated as local.
+func pyImports(root, rel string) ([]importRef, error) {
+	var refs []importRef
+	add := func(n int, module, statement string) {
+		ref := importRef{File: rel, Line: n, Path: module, Statement: statement}
+		if strings.HasPrefix(module, ".") {
+			ref.Local = resolvePyRelative(rel, module)
+		} else if isPyLocal(root, module) {
+			ref.Local = module
+		}
+		refs = append(refs, ref)
+	}
+	err := scanLines(filepath.Join(root, filepath.FromSlash(rel)), func(n int, line string) {
+		trimmed := strings.TrimSpace(line)
+		if m := pyFromImportPattern.FindStringSubmatch(line); m != nil {
+			add(n, m[1], trimmed)
+			return
+		}
+		if m := pyImportPattern.FindStringSubmatch(line); m != nil {
+			for _, part := range strings.Split(m[1], ",") {
+				module := strings.Fields(strings.TrimSpace(part))
+				if len(module) > 0 {
+					add(n, module[0], trimmed)
+				}
+			}
+		}
+	})
+	return refs, err
+}
+
+// resolvePyRelative turns "from ..db import x" in app/api/views.py into
+// "app.db".
+func resolvePyRelative(rel, module string) string {
+	dots := len(module) - len(strings.TrimLeft(module, "."))
+	parts := strings.Split(filepath.ToSlash(filepath.Dir(rel)), "/")
+	if parts[0] == "." {
+		parts = nil
+	}
+	if dots-1 > len(parts) {
+		return ""
+	}
+	parts = parts[:len(parts)-(dots-1)]
+	if rest := module[dots:]; rest != "" {
+		parts = append(parts, strings.Split(rest, ".")...)
+	}
+	return strings.Join(parts, ".")
+}
+
+func isPyLocal(root, module string) bool {
+	top := filepath.Join(root, strings.SplitN(module, ".", 2)[0])
+	if info, err := os.Stat(top); err == nil && info.IsDir() {
+		return true
+	}
+	_, err := os.Stat(top + ".py")
+	return err == nil
+}
+
+func scanLines(path string, fn func(n int, line string)) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for n := 1; scanner.Scan(); n++ {
+		fn(n, scanner.Text())
+	}
+	return scanner.Err()
+}
+
+func within(root, dir string) bool {
+	if dir == "" {
+		return false
+	}
+	rel, err := filepath.Rel(root, dir)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
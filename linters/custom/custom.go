@@ -0,0 +1,361 @@
+// Package custom runs linters declared under lint.custom in .gavel.yaml. Each
+// declaration becomes a linters.Linter, so it goes through the same
+// selection, fix, timeout, ignore and reporting paths as the built-ins.
+package custom
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/bmatcuk/doublestar/v4"
+	"github.com/flanksource/clicky"
+	commonsContext "github.com/flanksource/commons/context"
+	"github.com/flanksource/commons/logger"
+	"github.com/flanksource/gavel/linters"
+	"github.com/flanksource/gavel/models"
+	"github.com/flanksource/gavel/verify"
+)
+
+// FilesArg is the Args element replaced by the files the linter should check.
+const FilesArg = "{files}"
+
+// Linter implements linters.Linter for a verify.CustomLinter declaration.
+type Linter struct {
+	linters.RunOptions
+	def     verify.CustomLinter
+	pattern *regexp.Regexp
+	timeout time.Duration
+}
+
+// New validates def and returns a linter rooted at workDir.
+func New(workDir string, def verify.CustomLinter) (*Linter, error) {
+	if strings.TrimSpace(def.Name) == "" {
+		return nil, fmt.Errorf("custom linter has no name")
+	}
+	if strings.TrimSpace(def.Command) == "" {
+		return nil, fmt.Errorf("custom linter %s: command is required", def.Name)
+	}
+	l := &Linter{
+		RunOptions: linters.RunOptions{WorkDir: workDir},
+		def:        def,
+	}
+
+	switch {
+	case def.Output.Regex != "" && def.Output.JSON != nil:
+		return nil, fmt.Errorf("custom linter %s: output.regex and output.json are mutually exclusive", def.Name)
+	case def.Output.Regex != "":
+		re, err := regexp.Compile(def.Output.Regex)
+		if err != nil {
+			return nil, fmt.Errorf("custom linter %s: output.regex: %w", def.Name, err)
+		}
+		if re.SubexpIndex("message") < 0 {
+			return nil, fmt.Errorf("custom linter %s: output.regex needs a (?P<message>...) group", def.Name)
+		}
+		l.pattern = re
+	case def.Output.JSON != nil:
+		if def.Output.JSON.Message == "" {
+			return nil, fmt.Errorf("custom linter %s: output.json.message is required", def.Name)
+		}
+	default:
+		return nil, fmt.Errorf("custom linter %s: one of output.regex or output.json is required", def.Name)
+	}
+
+	if def.Timeout != "" {
+		timeout, err := time.ParseDuration(def.Timeout)
+		if err != nil {
+			return nil, fmt.Errorf("custom linter %s: timeout: %w", def.Name, err)
+		}
+		l.timeout = timeout
+	}
+	return l, nil
+}
+
+// Register adds every declaration in defs to registry. Declarations that are
+// invalid or reuse the name of a linter already in the registry are skipped
+// and reported in the returned errors, so one bad entry does not disable the
+// rest. Registered on linters.DefaultRegistry they are also debounced and
+// cached by Runner.RunWithIntelligentDebounce like any other linter.
+func Register(registry *linters.Registry, workDir string, defs []verify.CustomLinter) []error {
+	var errs []error
+	for _, def := range defs {
+		l, err := New(workDir, def)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if registry.Has(l.Name()) {
+			errs = append(errs, fmt.Errorf("custom linter %s: name is already used by another linter", l.Name()))
+			continue
+		}
+		registry.Register(l)
+	}
+	return errs
+}
+
+// SetOptions sets the run options for the linter
+func (l *Linter) SetOptions(opts linters.RunOptions) {
+	l.RunOptions = opts
+}
+
+// Name returns the linter name
+func (l *Linter) Name() string {
+	return l.def.Name
+}
+
+// Command returns the configured command, before PATH resolution.
+func (l *Linter) Command() string {
+	return l.def.Command
+}
+
+// Timeout returns the declared per-run timeout, or 0 when the CLI default
+// applies.
+func (l *Linter) Timeout() time.Duration {
+	return l.timeout
+}
+
+// ProjectRootMarkers returns the declared markers; with none the linter runs
+// once from the work dir.
+func (l *Linter) ProjectRootMarkers() []string {
+	return l.def.Markers
+}
+
+// DefaultIncludes returns the declared includes, or every file when none are
+// declared.
+func (l *Linter) DefaultIncludes() []string {
+	if len(l.def.Includes) == 0 {
+		return []string{"**/*"}
+	}
+	return l.def.Includes
+}
+
+// DefaultExcludes returns the declared excludes
+func (l *Linter) DefaultExcludes() []string {
+	return l.def.Excludes
+}
+
+// SupportsJSON reports whether the output is mapped from JSON
+func (l *Linter) SupportsJSON() bool {
+	return l.def.Output.JSON != nil
+}
+
+// JSONArgs returns nil; JSON output is requested through the declared args.
+func (l *Linter) JSONArgs() []string {
+	return nil
+}
+
+// SupportsFix returns true when fixArgs are declared
+func (l *Linter) SupportsFix() bool {
+	return len(l.def.FixArgs) > 0
+}
+
+// FixArgs returns the args used instead of Args under --fix
+func (l *Linter) FixArgs() []string {
+	return l.def.FixArgs
+}
+
+// ValidateConfig validates linter-specific configuration
+func (l *Linter) ValidateConfig(config *models.LinterConfig) error {
+	if config == nil {
+		return fmt.Errorf("config cannot be nil")
+	}
+	return nil
+}
+
+// buildArgs assembles the argv (without the command name), expanding
+// FilesArg to files.
+func (l *Linter) buildArgs(files []string) []string {
+	template := l.def.Args
+	if l.Fix && l.SupportsFix() {
+		template = l.def.FixArgs
+	}
+	var args []string
+	for _, arg := range template {
+		if arg == FilesArg {
+			args = append(args, files...)
+			continue
+		}
+		args = append(args, arg)
+	}
+	if l.Config != nil {
+		args = append(args, l.Config.Args...)
+	}
+	return append(args, l.ExtraArgs...)
+}
+
+func (l *Linter) wantsFiles() bool {
+	template := l.def.Args
+	if l.Fix && l.SupportsFix() {
+		template = l.def.FixArgs
+	}
+	for _, arg := range template {
+		if arg == FilesArg {
+			return true
+		}
+	}
+	return false
+}
+
+// DryRunCommand reports the command the linter would execute.
+func (l *Linter) DryRunCommand() (string, []string) {
+	var files []string
+	if l.wantsFiles() {
+		files, _ = l.targetFiles()
+	}
+	return l.def.Command, l.buildArgs(files)
+}
+
+// targetFiles returns the files (relative to WorkDir) passed in place of
+// FilesArg: the requested files filtered by includes/excludes, or every
+// matching file under WorkDir when none were requested.
+func (l *Linter) targetFiles() ([]string, error) {
+	if len(l.Files) > 0 {
+		var files []string
+		for _, f := range l.Files {
+			if l.matches(filepath.ToSlash(f)) {
+				files = append(files, f)
+			}
+		}
+		return files, nil
+	}
+
+	var files []string
+	err := filepath.WalkDir(l.WorkDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.IsDir() {
+			if name := d.Name(); path != l.WorkDir && (name == ".git" || name == "node_modules") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		rel, err := filepath.Rel(l.WorkDir, path)
+		if err != nil {
+			return nil
+		}
+		if rel = filepath.ToSlash(rel); l.matches(rel) {
+			files = append(files, rel)
+		}
+		return nil
+	})
+	return files, err
+}
+
+func (l *Linter) matches(file string) bool {
+	for _, pattern := range l.def.Excludes {
+		if ok, _ := doublestar.Match(pattern, file); ok {
+			return false
+		}
+	}
+	for _, pattern := range l.DefaultIncludes() {
+		if ok, _ := doublestar.Match(pattern, file); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Run executes the command and maps its output onto violations. A non-zero
+// exit is expected when the checker reports findings; it is only an error
+// when no violations could be parsed from the output.
+func (l *Linter) Run(ctx commonsContext.Context, task *clicky.Task) ([]models.Violation, error) {
+	var files []string
+	if l.wantsFiles() {
+		var err error
+		if files, err = l.targetFiles(); err != nil {
+			return nil, fmt.Errorf("%s: list files: %w", l.Name(), err)
+		}
+		if len(files) == 0 {
+			logger.Debugf("%s: no files match %v", l.Name(), l.DefaultIncludes())
+			return []models.Violation{}, nil
+		}
+	}
+
+	command := l.def.Command
+	if l.Executable != "" {
+		command = l.Executable
+	}
+	args := l.buildArgs(files)
+
+	cmd := exec.CommandContext(ctx, command, args...)
+	cmd.Dir = l.WorkDir
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = l.WrapWriter(&stdout)
+	cmd.Stderr = l.WrapWriter(&stderr)
+
+	logger.Infof("Executing: %s %s", command, strings.Join(args, " "))
+	runErr := cmd.Run()
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	if _, ok := runErr.(*exec.ExitError); runErr != nil && !ok {
+		return nil, fmt.Errorf("%s execution failed: %w", l.Name(), runErr)
+	}
+
+	var violations []models.Violation
+	var parseErr error
+	if l.pattern != nil {
+		violations = l.parseRegex(stdout.String() + "\n" + stderr.String())
+	} else {
+		violations, parseErr = l.parseJSON(stdout.Bytes())
+	}
+
+	if runErr != nil && len(violations) == 0 {
+		return nil, fmt.Errorf("%s execution failed: %w\nOutput:\n%s%s", l.Name(), runErr, stdout.String(), stderr.String())
+	}
+	if parseErr != nil {
+		return nil, parseErr
+	}
+	return violations, nil
+}
+
+// violation builds a violation from the fields extracted from one finding.
+func (l *Linter) violation(file string, line, column int, severity, rule, message string) models.Violation {
+	if file != "" && !filepath.IsAbs(file) {
+		file = filepath.Join(l.WorkDir, file)
+	}
+	if rule == "" {
+		rule = l.Name()
+	}
+	v := models.NewViolationBuilder().
+		WithFile(file).
+		WithLocation(line, column).
+		WithCaller(filepath.Dir(file), "unknown").
+		WithCalled(l.Name(), rule).
+		WithMessage(message).
+		WithSource(l.Name()).
+		WithRuleFromLinter(l.Name(), rule).
+		Build()
+	v.Severity = l.severity(severity)
+	return v
+}
+
+// severity normalizes a tool's severity label, falling back to the declared
+// default and then to warning.
+func (l *Linter) severity(value string) models.ViolationSeverity {
+	if s, ok := parseSeverity(value); ok {
+		return s
+	}
+	if s, ok := parseSeverity(l.def.Output.Severity); ok {
+		return s
+	}
+	return models.SeverityWarning
+}
+
+func parseSeverity(value string) (models.ViolationSeverity, bool) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "error", "err", "e", "fatal", "critical", "high":
+		return models.SeverityError, true
+	case "warning", "warn", "w", "medium":
+		return models.SeverityWarning, true
+	case "info", "note", "style", "hint", "i", "n", "low":
+		return models.SeverityInfo, true
+	}
+	return "", false
+}
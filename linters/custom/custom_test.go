@@ -0,0 +1,220 @@
+package custom
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	commonsContext "github.com/flanksource/commons/context"
+	"github.com/flanksource/gavel/linters"
+	"github.com/flanksource/gavel/models"
+	"github.com/flanksource/gavel/verify"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const shellcheckRegex = `^(?P<file>[^:]+):(?P<line>\d+):(?P<col>\d+): (?P<severity>\w+): (?P<message>.*) \[(?P<rule>SC\d+)\]$`
+
+func TestNewValidation(t *testing.T) {
+	tests := []struct {
+		name string
+		def  verify.CustomLinter
+		err  string
+	}{
+		{"missing name", verify.CustomLinter{Command: "x"}, "no name"},
+		{"missing command", verify.CustomLinter{Name: "x"}, "command is required"},
+		{"missing output", verify.CustomLinter{Name: "x", Command: "x"}, "one of output.regex or output.json"},
+		{"both outputs", verify.CustomLinter{Name: "x", Command: "x", Output: verify.CustomLinterOutput{
+			Regex: "(?P<message>.*)", JSON: &verify.CustomLinterJSON{Message: "m"},
+		}}, "mutually exclusive"},
+		{"no message group", verify.CustomLinter{Name: "x", Command: "x", Output: verify.CustomLinterOutput{Regex: "(?P<file>.*)"}}, "message"},
+		{"bad regex", verify.CustomLinter{Name: "x", Command: "x", Output: verify.CustomLinterOutput{Regex: "("}}, "output.regex"},
+		{"bad timeout", verify.CustomLinter{Name: "x", Command: "x", Timeout: "soon", Output: verify.CustomLinterOutput{Regex: "(?P<message>.*)"}}, "timeout"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := New("/repo", tt.def)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.err)
+		})
+	}
+}
+
+func TestRegisterSkipsInvalidAndDuplicateNames(t *testing.T) {
+	registry := linters.NewRegistry()
+	existing, err := New("/repo", verify.CustomLinter{Name: "ruff", Command: "ruff", Output: verify.CustomLinterOutput{Regex: "(?P<message>.*)"}})
+	require.NoError(t, err)
+	registry.Register(existing)
+
+	errs := Register(registry, "/repo", []verify.CustomLinter{
+		{Name: "shellcheck", Command: "shellcheck", Output: verify.CustomLinterOutput{Regex: shellcheckRegex}},
+		{Name: "ruff", Command: "ruff", Output: verify.CustomLinterOutput{Regex: "(?P<message>.*)"}},
+		{Name: "broken", Command: "broken"},
+	})
+	assert.Len(t, errs, 2)
+	assert.True(t, registry.Has("shellcheck"))
+	assert.False(t, registry.Has("broken"))
+}
+
+func TestParseRegex(t *testing.T) {
+	l, err := New("/repo", verify.CustomLinter{
+		Name:    "shellcheck",
+		Command: "shellcheck",
+		Output:  verify.CustomLinterOutput{Regex: shellcheckRegex},
+	})
+	require.NoError(t, err)
+
+	output := `hack/build.sh:12:5: warning: Double quote to prevent globbing and word splitting. [SC2086]
+In hack/build.sh line 12: some context shellcheck prints
+hack/release.sh:3:1: error: Couldn't parse this function. [SC1073]
+`
+	violations := l.parseRegex(output)
+	require.Len(t, violations, 2)
+
+	v := violations[0]
+	assert.Equal(t, "/repo/hack/build.sh", v.File)
+	assert.Equal(t, 12, v.Line)
+	assert.Equal(t, 5, v.Column)
+	assert.Equal(t, models.SeverityWarning, v.Severity)
+	assert.Equal(t, "shellcheck", v.Source)
+	require.NotNil(t, v.Rule)
+	assert.Equal(t, "SC2086", v.Rule.Method)
+	require.NotNil(t, v.Message)
+	assert.Equal(t, "Double quote to prevent globbing and word splitting.", *v.Message)
+
+	assert.Equal(t, models.SeverityError, violations[1].Severity)
+}
+
+func TestParseRegexDefaults(t *testing.T) {
+	l, err := New("/repo", verify.CustomLinter{
+		Name:    "todo-grep",
+		Command: "./hack/todo.sh",
+		Output:  verify.CustomLinterOutput{Regex: `^(?P<file>[^:]+):(?P<line>\d+):(?P<message>.*)$`, Severity: "info"},
+	})
+	require.NoError(t, err)
+
+	violations := l.parseRegex("/abs/main.go:7:// TODO: remove\n")
+	require.Len(t, violations, 1)
+	assert.Equal(t, "/abs/main.go", violations[0].File)
+	assert.Equal(t, models.SeverityInfo, violations[0].Severity)
+	require.NotNil(t, violations[0].Rule)
+	assert.Equal(t, "todo-grep", violations[0].Rule.Method, "rule falls back to the linter name")
+}
+
+func TestParseJSON(t *testing.T) {
+	l, err := New("/repo", verify.CustomLinter{
+		Name:    "hadolint",
+		Command: "hadolint",
+		Output: verify.CustomLinterOutput{JSON: &verify.CustomLinterJSON{
+			File: "file", Line: "line", Column: "column", Severity: "level", Rule: "code", Message: "message",
+		}},
+	})
+	require.NoError(t, err)
+
+	output := []byte(`[
+		{"code":"DL3007","column":1,"file":"Dockerfile","level":"warning","line":1,"message":"Using latest is prone to errors"},
+		{"code":"DL3008","column":1,"file":"build/Dockerfile","level":"info","line":4,"message":"Pin versions in apt get install"}
+	]`)
+	violations, err := l.parseJSON(output)
+	require.NoError(t, err)
+	require.Len(t, violations, 2)
+	assert.Equal(t, "/repo/Dockerfile", violations[0].File)
+	assert.Equal(t, 1, violations[0].Line)
+	assert.Equal(t, "DL3007", violations[0].Rule.Method)
+	assert.Equal(t, models.SeverityInfo, violations[1].Severity)
+
+	empty, err := l.parseJSON([]byte("[]\n"))
+	require.NoError(t, err)
+	assert.Empty(t, empty)
+}
+
+func TestParseJSONItemsPath(t *testing.T) {
+	l, err := New("/repo", verify.CustomLinter{
+		Name:    "semgrep",
+		Command: "semgrep",
+		Output: verify.CustomLinterOutput{JSON: &verify.CustomLinterJSON{
+			Items: "results", File: "path", Line: "start.line", Column: "start.col",
+			Severity: "extra.severity", Rule: "check_id", Message: "extra.message",
+		}},
+	})
+	require.NoError(t, err)
+
+	violations, err := l.parseJSON([]byte(`{"results":[{"check_id":"no-eval","path":"web/app.js",
+		"start":{"line":9,"col":3},"extra":{"severity":"ERROR","message":"eval is dangerous"}}]}`))
+	require.NoError(t, err)
+	require.Len(t, violations, 1)
+	v := violations[0]
+	assert.Equal(t, "/repo/web/app.js", v.File)
+	assert.Equal(t, 9, v.Line)
+	assert.Equal(t, 3, v.Column)
+	assert.Equal(t, models.SeverityError, v.Severity)
+	assert.Equal(t, "no-eval", v.Rule.Method)
+
+	_, err = l.parseJSON([]byte(`{"errors":[]}`))
+	assert.ErrorContains(t, err, `"results" not found`)
+}
+
+func TestParseJSONLines(t *testing.T) {
+	l, err := New("/repo", verify.CustomLinter{
+		Name:    "checker",
+		Command: "checker",
+		Output:  verify.CustomLinterOutput{JSON: &verify.CustomLinterJSON{File: "f", Line: "l", Message: "m"}},
+	})
+	require.NoError(t, err)
+
+	violations, err := l.parseJSON([]byte("{\"f\":\"a.txt\",\"l\":1,\"m\":\"one\"}\n\n{\"f\":\"b.txt\",\"l\":2,\"m\":\"two\"}\n"))
+	require.NoError(t, err)
+	require.Len(t, violations, 2)
+	assert.Equal(t, "/repo/b.txt", violations[1].File)
+	assert.Equal(t, 2, violations[1].Line)
+}
+
+func TestBuildArgs(t *testing.T) {
+	dir := t.TempDir()
+	for _, f := range []string{"a.sh", "lib/b.sh", "vendor/c.sh", "README.md"} {
+		require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(dir, f)), 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, f), nil, 0o644))
+	}
+
+	l, err := New(dir, verify.CustomLinter{
+		Name:     "shellcheck",
+		Command:  "shellcheck",
+		Args:     []string{"-f", "gcc", FilesArg},
+		FixArgs:  []string{"-f", "diff", FilesArg},
+		Includes: []string{"**/*.sh"},
+		Excludes: []string{"vendor/**"},
+		Output:   verify.CustomLinterOutput{Regex: shellcheckRegex},
+	})
+	require.NoError(t, err)
+
+	cmd, args := l.DryRunCommand()
+	assert.Equal(t, "shellcheck", cmd)
+	assert.Equal(t, []string{"-f", "gcc", "a.sh", "lib/b.sh"}, args)
+
+	l.SetOptions(linters.RunOptions{WorkDir: dir, Fix: true, Files: []string{"lib/b.sh", "README.md"}})
+	_, args = l.DryRunCommand()
+	assert.Equal(t, []string{"-f", "diff", "lib/b.sh"}, args)
+	assert.True(t, l.SupportsFix())
+}
+
+func TestRunTreatsFindingsExitAsSuccess(t *testing.T) {
+	def := verify.CustomLinter{
+		Name:    "script",
+		Command: "sh",
+		Args:    []string{"-c", "echo 'a.sh:1:2: error: bad quoting [SC1000]'; exit 1"},
+		Output:  verify.CustomLinterOutput{Regex: shellcheckRegex},
+	}
+	l, err := New(t.TempDir(), def)
+	require.NoError(t, err)
+	violations, err := l.Run(commonsContext.NewContext(context.Background()), nil)
+	require.NoError(t, err)
+	require.Len(t, violations, 1)
+	assert.Equal(t, "SC1000", violations[0].Rule.Method)
+
+	def.Args = []string{"-c", "echo 'crashed' >&2; exit 2"}
+	l, err = New(t.TempDir(), def)
+	require.NoError(t, err)
+	_, err = l.Run(commonsContext.NewContext(context.Background()), nil)
+	assert.ErrorContains(t, err, "crashed")
+}
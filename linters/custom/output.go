@@ -0,0 +1,141 @@
+package custom
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/flanksource/gavel/models"
+)
+
+// parseRegex matches the output pattern against every line. Lines that do
+// not match are progress or summary output and are ignored.
+func (l *Linter) parseRegex(output string) []models.Violation {
+	group := func(match []string, name string) string {
+		if i := l.pattern.SubexpIndex(name); i >= 0 && i < len(match) {
+			return strings.TrimSpace(match[i])
+		}
+		return ""
+	}
+
+	violations := []models.Violation{}
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		match := l.pattern.FindStringSubmatch(scanner.Text())
+		if match == nil {
+			continue
+		}
+		line, _ := strconv.Atoi(group(match, "line"))
+		column, _ := strconv.Atoi(group(match, "col"))
+		violations = append(violations, l.violation(
+			group(match, "file"), line, column,
+			group(match, "severity"), group(match, "rule"), group(match, "message"),
+		))
+	}
+	return violations
+}
+
+// parseJSON maps a JSON report onto violations. When no items path is
+// declared and stdout is not a single JSON document, each non-empty line is
+// decoded as one finding (JSON Lines).
+func (l *Linter) parseJSON(output []byte) ([]models.Violation, error) {
+	mapping := l.def.Output.JSON
+	output = bytes.TrimSpace(output)
+	if len(output) == 0 {
+		return []models.Violation{}, nil
+	}
+
+	var items []any
+	var doc any
+	if err := json.Unmarshal(output, &doc); err == nil {
+		found, ok := lookup(doc, mapping.Items)
+		if !ok {
+			return nil, fmt.Errorf("%s: output.json.items %q not found in output", l.Name(), mapping.Items)
+		}
+		switch v := found.(type) {
+		case []any:
+			items = v
+		case nil:
+		default:
+			items = []any{v}
+		}
+	} else if mapping.Items == "" {
+		for _, line := range bytes.Split(output, []byte("\n")) {
+			if line = bytes.TrimSpace(line); len(line) == 0 {
+				continue
+			}
+			var item any
+			if err := json.Unmarshal(line, &item); err != nil {
+				return nil, fmt.Errorf("%s: failed to parse JSON output: %w", l.Name(), err)
+			}
+			items = append(items, item)
+		}
+	} else {
+		return nil, fmt.Errorf("%s: failed to parse JSON output: %w", l.Name(), err)
+	}
+
+	violations := make([]models.Violation, 0, len(items))
+	for _, item := range items {
+		field := func(path string) string {
+			if path == "" {
+				return ""
+			}
+			v, _ := lookup(item, path)
+			return scalar(v)
+		}
+		line, _ := strconv.Atoi(field(mapping.Line))
+		column, _ := strconv.Atoi(field(mapping.Column))
+		violations = append(violations, l.violation(
+			field(mapping.File), line, column,
+			field(mapping.Severity), field(mapping.Rule), field(mapping.Message),
+		))
+	}
+	return violations, nil
+}
+
+// lookup walks a dotted path through decoded JSON. Numeric segments index
+// arrays. An empty path returns v itself.
+func lookup(v any, path string) (any, bool) {
+	if path == "" {
+		return v, true
+	}
+	for _, key := range strings.Split(path, ".") {
+		switch node := v.(type) {
+		case map[string]any:
+			next, ok := node[key]
+			if !ok {
+				return nil, false
+			}
+			v = next
+		case []any:
+			i, err := strconv.Atoi(key)
+			if err != nil || i < 0 || i >= len(node) {
+				return nil, false
+			}
+			v = node[i]
+		default:
+			return nil, false
+		}
+	}
+	return v, true
+}
+
+func scalar(v any) string {
+	switch s := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return s
+	case float64:
+		return strconv.FormatFloat(s, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(s)
+	default:
+		data, _ := json.Marshal(s)
+		return string(data)
+	}
+}
@@ -8,7 +8,8 @@
 //
 // # Supported Linters
 //
-//   - arch-unit: Architecture dependency rules (built-in)
+//   - arch: Import and layering rules from arch.yaml (built-in, see linters/arch)
+//   - quality: File length, naming and comment rules from arch.yaml (built-in, see linters/quality)
 //   - golangci-lint: Comprehensive Go linter
 //   - ruff: Fast Python linter and formatter
 //   - pyright: Python type checker
@@ -5,6 +5,7 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/flanksource/gavel/internal/changegraph"
 	"github.com/flanksource/gavel/verify"
 )
 
@@ -158,3 +159,59 @@ func pathMatchesAnyScope(absPath string, scopes []string) bool {
 	}
 	return false
 }
+
+// FilterViolationsToChangedLines keeps only violations on a line added or
+// modified in changed, or within radius lines of one. changed holds paths
+// relative to root, as returned by changegraph.ComputeLineSet(root, ...).
+// Violations without a line (whole-file findings) are kept when their file
+// changed at all. Relative violation paths are tried against the result's
+// WorkDir and against root, since linters anchor them differently. Returns
+// the total number of dropped violations.
+func FilterViolationsToChangedLines(results []*LinterResult, root string, changed changegraph.LineSet, radius int) int {
+	dropped := 0
+	for _, result := range results {
+		if result == nil {
+			continue
+		}
+		kept := result.Violations[:0]
+		for _, v := range result.Violations {
+			if violationOnChangedLine(v.File, v.Line, result.WorkDir, root, changed, radius) {
+				kept = append(kept, v)
+			} else {
+				dropped++
+			}
+		}
+		result.Violations = kept
+	}
+	return dropped
+}
+
+func violationOnChangedLine(file string, line int, resultWorkDir, root string, changed changegraph.LineSet, radius int) bool {
+	if file == "" {
+		return false
+	}
+	candidates := []string{file}
+	if !filepath.IsAbs(file) {
+		candidates = nil
+		if resultWorkDir != "" {
+			candidates = append(candidates, filepath.Join(resultWorkDir, file))
+		}
+		candidates = append(candidates, filepath.Join(root, file))
+	}
+	for _, abs := range candidates {
+		rel, err := filepath.Rel(root, filepath.Clean(abs))
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			continue
+		}
+		if line <= 0 {
+			if len(changed[filepath.ToSlash(rel)]) > 0 {
+				return true
+			}
+			continue
+		}
+		if changed.Near(rel, line, radius) {
+			return true
+		}
+	}
+	return false
+}
@@ -5,6 +5,7 @@ import (
 	"path/filepath"
 	"testing"
 
+	"github.com/flanksource/gavel/internal/changegraph"
 	"github.com/flanksource/gavel/models"
 	"github.com/flanksource/gavel/verify"
 	"github.com/stretchr/testify/assert"
@@ -296,3 +297,47 @@ func TestFilterViolationsByUserScope(t *testing.T) {
 		})
 	}
 }
+
+func TestFilterViolationsToChangedLines(t *testing.T) {
+	root := "/repo"
+	changed := changegraph.NewLineSet()
+	changed.Add("pkg/a.go", 10)
+	changed.Add("web/src/app.ts", 3)
+
+	results := []*LinterResult{
+		{
+			Linter:  "golangci-lint",
+			WorkDir: "/repo/pkg",
+			Violations: []models.Violation{
+				{File: "/repo/pkg/a.go", Line: 10},
+				{File: "/repo/pkg/a.go", Line: 12},
+				{File: "a.go", Line: 10},
+				{File: "/repo/pkg/b.go", Line: 10},
+			},
+		},
+		{
+			Linter:  "tsc",
+			WorkDir: "/repo/web",
+			Violations: []models.Violation{
+				{File: "web/src/app.ts", Line: 3},
+				{File: "web/src/app.ts"},
+				{File: "web/src/other.ts"},
+			},
+		},
+	}
+
+	dropped := FilterViolationsToChangedLines(results, root, changed, 0)
+	assert.Equal(t, 3, dropped)
+	assert.Equal(t, []models.Violation{
+		{File: "/repo/pkg/a.go", Line: 10},
+		{File: "a.go", Line: 10},
+	}, results[0].Violations)
+	assert.Equal(t, []models.Violation{
+		{File: "web/src/app.ts", Line: 3},
+		{File: "web/src/app.ts"},
+	}, results[1].Violations, "root-relative paths and line-less findings on changed files are kept")
+
+	results[0].Violations = []models.Violation{{File: "/repo/pkg/a.go", Line: 12}, {File: "/repo/pkg/a.go", Line: 13}}
+	assert.Equal(t, 1, FilterViolationsToChangedLines(results[:1], root, changed, 2))
+	assert.Equal(t, 12, results[0].Violations[0].Line)
+}
@@ -0,0 +1,314 @@
+// Package fixpreview turns the edits auto-fixing linters make into a
+// reviewable set of unified-diff hunks, and applies the accepted hunks to the
+// real tree. The edits are produced in a Sandbox, so previewing never touches
+// the files being linted.
+package fixpreview
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// contextLines is the number of unchanged lines kept around each change,
+// matching `git diff`.
+const contextLines = 3
+
+// HunkStatus records what happened to a hunk after it was offered.
+type HunkStatus string
+
+const (
+	HunkPending  HunkStatus = ""
+	HunkApplied  HunkStatus = "applied"
+	HunkConflict HunkStatus = "conflict"
+)
+
+// Hunk is one contiguous change. Lines carry a " ", "-" or "+" prefix and
+// keep their line terminator, so the old and new text can be rebuilt exactly.
+type Hunk struct {
+	ID       string     `json:"id"`
+	OldStart int        `json:"old_start"`
+	OldLines int        `json:"old_lines"`
+	NewStart int        `json:"new_start"`
+	NewLines int        `json:"new_lines"`
+	Lines    []string   `json:"lines"`
+	Status   HunkStatus `json:"status,omitempty"`
+	Error    string     `json:"error,omitempty"`
+}
+
+// FileDiff holds the hunks one linter's fixes produced in one file. File is
+// relative to WorkDir, the git root the preview was taken from.
+type FileDiff struct {
+	Linter  string `json:"linter"`
+	WorkDir string `json:"work_dir"`
+	File    string `json:"file"`
+	Hunks   []Hunk `json:"hunks"`
+}
+
+// Preview is the full set of proposed fixes of a `gavel lint --fix --preview`
+// run.
+type Preview struct {
+	Files []FileDiff `json:"files"`
+}
+
+// ApplyResult lists the hunk ids Apply wrote and the ones it could not.
+type ApplyResult struct {
+	Applied   []string          `json:"applied"`
+	Conflicts map[string]string `json:"conflicts,omitempty"`
+}
+
+// Compute diffs before and after, the content of file in workDir before and
+// after linter fixed it. It returns nil when they are identical.
+func Compute(linter, workDir, file, before, after string) *FileDiff {
+	a, b := splitLines(before), splitLines(after)
+	groups := difflib.NewMatcher(a, b).GetGroupedOpCodes(contextLines)
+	if len(groups) == 0 {
+		return nil
+	}
+
+	diff := &FileDiff{Linter: linter, WorkDir: workDir, File: filepath.ToSlash(file)}
+	for i, group := range groups {
+		first, last := group[0], group[len(group)-1]
+		h := Hunk{
+			ID:       fmt.Sprintf("%s:%s#%d", linter, diff.File, i+1),
+			OldStart: first.I1 + 1,
+			OldLines: last.I2 - first.I1,
+			NewStart: first.J1 + 1,
+			NewLines: last.J2 - first.J1,
+		}
+		for _, op := range group {
+			if op.Tag == 'e' {
+				for _, line := range a[op.I1:op.I2] {
+					h.Lines = append(h.Lines, " "+line)
+				}
+				continue
+			}
+			if op.Tag == 'r' || op.Tag == 'd' {
+				for _, line := range a[op.I1:op.I2] {
+					h.Lines = append(h.Lines, "-"+line)
+				}
+			}
+			if op.Tag == 'r' || op.Tag == 'i' {
+				for _, line := range b[op.J1:op.J2] {
+					h.Lines = append(h.Lines, "+"+line)
+				}
+			}
+		}
+		diff.Hunks = append(diff.Hunks, h)
+	}
+	return diff
+}
+
+// Header returns the hunk's `@@ -a,b +c,d @@` line.
+func (h Hunk) Header() string {
+	return fmt.Sprintf("@@ -%s +%s @@", hunkRange(h.OldStart, h.OldLines), hunkRange(h.NewStart, h.NewLines))
+}
+
+// Text renders the hunk as unified diff text.
+func (h Hunk) Text() string {
+	var sb strings.Builder
+	sb.WriteString(h.Header())
+	sb.WriteString("\n")
+	for _, line := range h.Lines {
+		sb.WriteString(line)
+		if !strings.HasSuffix(line, "\n") {
+			sb.WriteString("\n\\ No newline at end of file\n")
+		}
+	}
+	return sb.String()
+}
+
+// Unified renders the preview as a unified diff, grouped by linter. The
+// output can be fed to `git apply` from the git root.
+func (p *Preview) Unified() string {
+	var sb strings.Builder
+	linter := ""
+	for _, f := range p.Files {
+		if f.Linter != linter {
+			linter = f.Linter
+			fmt.Fprintf(&sb, "# %s\n", linter)
+		}
+		fmt.Fprintf(&sb, "--- a/%s\n+++ b/%s\n", f.File, f.File)
+		for _, h := range f.Hunks {
+			sb.WriteString(h.Text())
+		}
+	}
+	return sb.String()
+}
+
+// HunkCount returns the number of hunks in the preview.
+func (p *Preview) HunkCount() int {
+	n := 0
+	for _, f := range p.Files {
+		n += len(f.Hunks)
+	}
+	return n
+}
+
+// Add appends diffs, keeping the files of one linter together.
+func (p *Preview) Add(diffs ...FileDiff) {
+	p.Files = append(p.Files, diffs...)
+	sort.SliceStable(p.Files, func(i, j int) bool {
+		if p.Files[i].Linter != p.Files[j].Linter {
+			return p.Files[i].Linter < p.Files[j].Linter
+		}
+		return p.Files[i].File < p.Files[j].File
+	})
+}
+
+// Apply writes the hunks with the given ids to the real tree and records the
+// outcome on each hunk. Every hunk was computed against the file as it was
+// when the preview was taken, so a hunk whose old lines no longer match, or
+// that overlaps another accepted hunk of the same file, is reported as a
+// conflict and the rest of the file is still applied.
+func (p *Preview) Apply(ids []string) (ApplyResult, error) {
+	accepted := map[string]bool{}
+	for _, id := range ids {
+		accepted[id] = true
+	}
+
+	type ref struct{ file, hunk int }
+	byPath := map[string][]ref{}
+	var paths []string
+	for fi, f := range p.Files {
+		path := filepath.Join(f.WorkDir, filepath.FromSlash(f.File))
+		for hi, h := range f.Hunks {
+			if !accepted[h.ID] || h.Status == HunkApplied {
+				continue
+			}
+			if _, ok := byPath[path]; !ok {
+				paths = append(paths, path)
+			}
+			byPath[path] = append(byPath[path], ref{fi, hi})
+		}
+	}
+
+	result := ApplyResult{Conflicts: map[string]string{}}
+	for _, path := range paths {
+		refs := byPath[path]
+		hunks := make([]*Hunk, len(refs))
+		for i, r := range refs {
+			hunks[i] = &p.Files[r.file].Hunks[r.hunk]
+		}
+		if err := applyFile(path, hunks); err != nil {
+			return result, err
+		}
+		for _, h := range hunks {
+			if h.Status == HunkApplied {
+				result.Applied = append(result.Applied, h.ID)
+			} else {
+				result.Conflicts[h.ID] = h.Error
+			}
+		}
+	}
+	return result, nil
+}
+
+// applyFile applies hunks to path, marking each one applied or conflicting.
+// Hunks are located by their old lines, nearest to where the preview saw
+// them, so hunks applied earlier to the same file do not invalidate the rest.
+func applyFile(path string, hunks []*Hunk) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	lines := splitLines(string(content))
+
+	sort.SliceStable(hunks, func(i, j int) bool { return hunks[i].OldStart < hunks[j].OldStart })
+	var out []string
+	next := 0
+	for _, h := range hunks {
+		old, replacement := h.sides()
+		start := locate(lines, old, h.OldStart-1, next)
+		if start < 0 {
+			h.Status, h.Error = HunkConflict, "file changed since the preview"
+			if h.OldStart-1 < next {
+				h.Error = "overlaps another accepted hunk"
+			}
+			continue
+		}
+		out = append(out, lines[next:start]...)
+		out = append(out, replacement...)
+		next = start + len(old)
+		h.Status, h.Error = HunkApplied, ""
+	}
+	out = append(out, lines[next:]...)
+	return os.WriteFile(path, []byte(strings.Join(out, "")), info.Mode().Perm())
+}
+
+// sides returns the hunk's old and new lines without their prefixes.
+func (h Hunk) sides() (old, replacement []string) {
+	for _, line := range h.Lines {
+		if line == "" {
+			continue
+		}
+		text := line[1:]
+		switch line[0] {
+		case ' ':
+			old = append(old, text)
+			replacement = append(replacement, text)
+		case '-':
+			old = append(old, text)
+		case '+':
+			replacement = append(replacement, text)
+		}
+	}
+	return old, replacement
+}
+
+// locate returns the index at or after from where old occurs in lines,
+// searching outwards from want, or -1.
+func locate(lines, old []string, want, from int) int {
+	for d := 0; want-d >= from || want+d+len(old) <= len(lines); d++ {
+		for _, at := range []int{want - d, want + d} {
+			if at >= from && at+len(old) <= len(lines) && equalLines(lines[at:at+len(old)], old) {
+				return at
+			}
+		}
+	}
+	return -1
+}
+
+func equalLines(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// splitLines splits s after each newline; the last line keeps no terminator
+// when s does not end with one.
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	lines := strings.SplitAfter(s, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+func hunkRange(start, count int) string {
+	if count == 0 {
+		// Unified diffs number an empty range after the line it follows.
+		start--
+	}
+	if count == 1 {
+		return fmt.Sprintf("%d", start)
+	}
+	return fmt.Sprintf("%d,%d", start, count)
+}
@@ -0,0 +1,163 @@
+package fixpreview
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func numbered(n int, edit map[int]string) string {
+	var sb strings.Builder
+	for i := 1; i <= n; i++ {
+		line, ok := edit[i]
+		if !ok {
+			line = "line " + string(rune('a'+i-1))
+		}
+		if line != "-" {
+			sb.WriteString(line + "\n")
+		}
+	}
+	return sb.String()
+}
+
+func TestComputeSplitsDistantChangesIntoHunks(t *testing.T) {
+	before := numbered(20, nil)
+	after := numbered(20, map[int]string{2: "LINE B", 18: "-"})
+
+	diff := Compute("ruff", "/repo", "pkg/a.py", before, after)
+	require.NotNil(t, diff)
+	require.Len(t, diff.Hunks, 2)
+	assert.Equal(t, "ruff:pkg/a.py#1", diff.Hunks[0].ID)
+	assert.Equal(t, "@@ -1,5 +1,5 @@", diff.Hunks[0].Header())
+	assert.Equal(t, "@@ -15,6 +15,5 @@", diff.Hunks[1].Header())
+	assert.Contains(t, diff.Hunks[0].Text(), "-line b\n+LINE B\n")
+
+	assert.Nil(t, Compute("ruff", "/repo", "pkg/a.py", before, before))
+
+	p := &Preview{}
+	p.Add(*diff)
+	assert.Equal(t, 2, p.HunkCount())
+	assert.True(t, strings.HasPrefix(p.Unified(), "# ruff\n--- a/pkg/a.py\n+++ b/pkg/a.py\n@@ -1,5 +1,5 @@\n"))
+}
+
+func TestApplyOnlyAcceptedHunks(t *testing.T) {
+	root := t.TempDir()
+	before := numbered(20, nil)
+	require.NoError(t, os.WriteFile(filepath.Join(root, "a.txt"), []byte(before), 0o600))
+
+	p := &Preview{}
+	p.Add(*Compute("eslint", root, "a.txt", before, numbered(20, map[int]string{2: "LINE B", 18: "LINE R"})))
+	first, second := p.Files[0].Hunks[0].ID, p.Files[0].Hunks[1].ID
+
+	result, err := p.Apply([]string{second})
+	require.NoError(t, err)
+	assert.Equal(t, []string{second}, result.Applied)
+	assert.Empty(t, result.Conflicts)
+
+	got, err := os.ReadFile(filepath.Join(root, "a.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, numbered(20, map[int]string{18: "LINE R"}), string(got))
+	info, err := os.Stat(filepath.Join(root, "a.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+	// The first hunk still applies after the file was edited above it.
+	require.NoError(t, os.WriteFile(filepath.Join(root, "a.txt"), []byte("header\n"+string(got)), 0o600))
+	result, err = p.Apply([]string{first, second})
+	require.NoError(t, err)
+	assert.Equal(t, []string{first}, result.Applied, "applied hunks are not applied twice")
+	got, _ = os.ReadFile(filepath.Join(root, "a.txt"))
+	assert.Equal(t, "header\n"+numbered(20, map[int]string{2: "LINE B", 18: "LINE R"}), string(got))
+}
+
+func TestApplyReportsConflicts(t *testing.T) {
+	root := t.TempDir()
+	before := numbered(6, nil)
+	require.NoError(t, os.WriteFile(filepath.Join(root, "a.txt"), []byte(before), 0o644))
+
+	p := &Preview{}
+	p.Add(*Compute("eslint", root, "a.txt", before, numbered(6, map[int]string{3: "ESLINT"})))
+	p.Add(*Compute("prettier", root, "a.txt", before, numbered(6, map[int]string{4: "PRETTIER"})))
+	eslint, prettier := p.Files[0].Hunks[0].ID, p.Files[1].Hunks[0].ID
+
+	result, err := p.Apply([]string{eslint, prettier})
+	require.NoError(t, err)
+	assert.Equal(t, []string{eslint}, result.Applied)
+	assert.Equal(t, map[string]string{prettier: "overlaps another accepted hunk"}, result.Conflicts)
+	assert.Equal(t, HunkConflict, p.Files[1].Hunks[0].Status)
+
+	require.NoError(t, os.WriteFile(filepath.Join(root, "a.txt"), []byte("rewritten\n"), 0o644))
+	result, err = p.Apply([]string{prettier})
+	require.NoError(t, err)
+	assert.Empty(t, result.Applied)
+	assert.Equal(t, "file changed since the preview", result.Conflicts[prettier])
+}
+
+func TestSandboxMirrorsWorkingTree(t *testing.T) {
+	root := t.TempDir()
+	run := func(args ...string) {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = root
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, "git %v: %s", args, out)
+	}
+	write := func(name, content string) {
+		path := filepath.Join(root, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	}
+	run("init", "--quiet", "-b", "main")
+	run("config", "user.email", "test@example.com")
+	run("config", "user.name", "Test")
+	run("config", "commit.gpgsign", "false")
+	write(".gitignore", "node_modules/\n")
+	write("a.go", "package a\n")
+	write("gone.go", "package a\n")
+	run("add", ".")
+	run("commit", "--quiet", "-m", "init")
+
+	write("a.go", "package a\n\nvar  x = 1\n")
+	write("new.go", "package a\nvar  y = 2\n")
+	write("node_modules/plugin/index.js", "module.exports = {}\n")
+	require.NoError(t, os.Remove(filepath.Join(root, "gone.go")))
+
+	s, err := NewSandbox(root)
+	require.NoError(t, err)
+	defer s.Close()
+
+	data, err := os.ReadFile(s.Path("a.go"))
+	require.NoError(t, err)
+	assert.Equal(t, "package a\n\nvar  x = 1\n", string(data), "uncommitted edits are mirrored")
+	assert.FileExists(t, s.Path(filepath.Join(root, "new.go")))
+	assert.NoFileExists(t, s.Path("gone.go"))
+	assert.FileExists(t, s.Path("node_modules/plugin/index.js"))
+
+	// Simulate a fixer reformatting two files.
+	require.NoError(t, os.WriteFile(s.Path("a.go"), []byte("package a\n\nvar x = 1\n"), 0o644))
+	require.NoError(t, os.WriteFile(s.Path("new.go"), []byte("package a\nvar y = 2\n"), 0o644))
+	diffs, err := s.Collect("gofmt")
+	require.NoError(t, err)
+	require.Len(t, diffs, 2)
+	assert.Equal(t, "a.go", diffs[0].File)
+	assert.Equal(t, root, diffs[0].WorkDir)
+	assert.Equal(t, []string{" package a\n", " \n", "-var  x = 1\n", "+var x = 1\n"}, diffs[0].Hunks[0].Lines)
+
+	data, err = os.ReadFile(s.Path("a.go"))
+	require.NoError(t, err)
+	assert.Equal(t, "package a\n\nvar  x = 1\n", string(data), "Collect resets the sandbox")
+
+	diffs, err = s.Collect("gofmt")
+	require.NoError(t, err)
+	assert.Empty(t, diffs)
+
+	s.Close()
+	assert.NoDirExists(t, s.Dir)
+	data, err = os.ReadFile(filepath.Join(root, "a.go"))
+	require.NoError(t, err)
+	assert.Equal(t, "package a\n\nvar  x = 1\n", string(data), "the real tree is untouched")
+}
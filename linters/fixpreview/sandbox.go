@@ -0,0 +1,186 @@
+package fixpreview
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// dependencyDirs are the gitignored directories linked into the sandbox so
+// linters resolve plugins, virtualenvs and gavel-installed tools the way they
+// do in the real tree.
+var dependencyDirs = map[string]bool{
+	"node_modules": true,
+	".venv":        true,
+	"venv":         true,
+	".gavel":       true,
+}
+
+// Sandbox is a detached git worktree of Root that mirrors its working tree,
+// including uncommitted and untracked files. Fixers run inside Dir; Collect
+// turns what they changed into diffs against Root and resets the sandbox for
+// the next linter.
+type Sandbox struct {
+	Root string
+	Dir  string
+	tmp  string
+}
+
+// NewSandbox creates the worktree for the git root root. Close removes it.
+func NewSandbox(root string) (*Sandbox, error) {
+	tmp, err := os.MkdirTemp("", "gavel-fix-preview-")
+	if err != nil {
+		return nil, err
+	}
+	s := &Sandbox{Root: root, Dir: filepath.Join(tmp, "tree"), tmp: tmp}
+	if _, err := git(root, nil, "worktree", "add", "--detach", "--quiet", s.Dir, "HEAD"); err != nil {
+		_ = os.RemoveAll(tmp)
+		return nil, fmt.Errorf("create preview worktree: %w", err)
+	}
+	if err := s.mirror(); err != nil {
+		s.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+// mirror copies the modified and untracked files of Root into the sandbox,
+// removes the deleted ones, links dependency directories, and stages the
+// result so Collect can diff against it.
+func (s *Sandbox) mirror() error {
+	out, err := git(s.Root, nil, "ls-files", "-z", "--modified", "--deleted", "--others", "--exclude-standard")
+	if err != nil {
+		return fmt.Errorf("list working tree changes: %w", err)
+	}
+	paths := splitNul(out)
+	for _, rel := range paths {
+		if err := copyFile(filepath.Join(s.Root, rel), filepath.Join(s.Dir, rel)); err != nil {
+			return err
+		}
+	}
+	if len(paths) > 0 {
+		stdin := strings.Join(paths, "\x00")
+		if _, err := git(s.Dir, strings.NewReader(stdin), "add", "-A", "--pathspec-from-file=-", "--pathspec-file-nul"); err != nil {
+			return fmt.Errorf("stage working tree changes: %w", err)
+		}
+	}
+
+	out, err = git(s.Root, nil, "ls-files", "-z", "--others", "--ignored", "--exclude-standard", "--directory")
+	if err != nil {
+		return fmt.Errorf("list ignored directories: %w", err)
+	}
+	for _, rel := range splitNul(out) {
+		rel = strings.TrimSuffix(rel, "/")
+		if !dependencyDirs[filepath.Base(rel)] {
+			continue
+		}
+		link := filepath.Join(s.Dir, rel)
+		if err := os.MkdirAll(filepath.Dir(link), 0o755); err != nil {
+			return err
+		}
+		if err := os.Symlink(filepath.Join(s.Root, rel), link); err != nil && !os.IsExist(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// Path maps a path in Root to the same path in the sandbox. Paths outside
+// Root are returned unchanged.
+func (s *Sandbox) Path(path string) string {
+	if !filepath.IsAbs(path) {
+		return filepath.Join(s.Dir, path)
+	}
+	rel, err := filepath.Rel(s.Root, path)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return path
+	}
+	return filepath.Join(s.Dir, rel)
+}
+
+// Collect returns the diffs of every file linter changed in the sandbox,
+// then restores those files so the next linter starts from Root's state.
+func (s *Sandbox) Collect(linter string) ([]FileDiff, error) {
+	out, err := git(s.Dir, nil, "diff", "--name-only", "-z")
+	if err != nil {
+		return nil, fmt.Errorf("list %s fixes: %w", linter, err)
+	}
+	var diffs []FileDiff
+	for _, rel := range splitNul(out) {
+		before, err := git(s.Dir, nil, "show", ":"+rel)
+		if err != nil {
+			return nil, fmt.Errorf("read %s: %w", rel, err)
+		}
+		after, err := os.ReadFile(filepath.Join(s.Dir, rel))
+		if err != nil {
+			// A fixer deleting a file is not something the preview offers.
+			continue
+		}
+		if d := Compute(linter, s.Root, rel, before, string(after)); d != nil {
+			diffs = append(diffs, *d)
+		}
+	}
+	if _, err := git(s.Dir, nil, "checkout", "--quiet", "--", "."); err != nil {
+		return nil, fmt.Errorf("reset preview worktree: %w", err)
+	}
+	return diffs, nil
+}
+
+// Close removes the worktree and its temporary directory.
+func (s *Sandbox) Close() {
+	_, _ = git(s.Root, nil, "worktree", "remove", "--force", s.Dir)
+	_ = os.RemoveAll(s.tmp)
+	_, _ = git(s.Root, nil, "worktree", "prune")
+}
+
+func git(dir string, stdin io.Reader, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Stdin = stdin
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("git %s: %w: %s", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+	return string(out), nil
+}
+
+func splitNul(s string) []string {
+	var out []string
+	for _, part := range strings.Split(s, "\x00") {
+		if part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
+
+// copyFile copies src to dst, or removes dst when src no longer exists.
+func copyFile(src, dst string) error {
+	info, err := os.Lstat(src)
+	if os.IsNotExist(err) {
+		if err := os.Remove(dst); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if info.IsDir() {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+		return err
+	}
+	data, err := os.ReadFile(src)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(dst, data, info.Mode().Perm())
+}
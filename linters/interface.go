@@ -68,6 +68,12 @@ type ProjectRooted interface {
 	ProjectRootMarkers() []string
 }
 
+// InProcess is implemented by linters that gavel evaluates itself, so there
+// is no executable to resolve on PATH before they run.
+type InProcess interface {
+	InProcess() bool
+}
+
 // MetadataProvider provides file and rule count information from linters
 type MetadataProvider interface {
 	GetFileCount() int
@@ -0,0 +1,191 @@
+package quality
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	clickyai "github.com/flanksource/clicky/ai"
+	"github.com/flanksource/commons/logger"
+	gavelai "github.com/flanksource/gavel/ai"
+	"github.com/flanksource/gavel/models"
+)
+
+// commentScore is the model's verdict on one comment.
+type commentScore struct {
+	Line   int     `json:"line"`
+	Score  float64 `json:"score"`
+	Reason string  `json:"reason,omitempty"`
+}
+
+type commentScoresSchema struct {
+	Comments []commentScore `json:"comments" description:"One entry per comment, identified by its line number"`
+}
+
+var (
+	newAgentFunc = func(cfg clickyai.AgentConfig) (clickyai.Agent, error) { return gavelai.NewAgent(cfg) }
+	// scoreCommentsFunc asks the model to rate how much each comment adds
+	// beyond the code it describes.
+	scoreCommentsFunc = scoreComments
+)
+
+func scoreComments(ctx context.Context, agent clickyai.Agent, cfg models.CommentAnalysisConfig, file string, comments []comment) ([]commentScore, error) {
+	schema := &commentScoresSchema{}
+	resp, err := agent.ExecutePrompt(ctx, clickyai.PromptRequest{
+		Name:             "comment quality: " + file,
+		Prompt:           commentPrompt(cfg, file, comments),
+		StructuredOutput: schema,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("execute AI comment prompt: %w", err)
+	}
+	if resp.Error != "" {
+		return nil, fmt.Errorf("AI comment prompt returned error: %s", resp.Error)
+	}
+	return schema.Comments, nil
+}
+
+func commentPrompt(cfg models.CommentAnalysisConfig, file string, comments []comment) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Rate each source comment from %s on how much it tells a reader that the code does not.\n", file)
+	sb.WriteString("Score from 0.0 (restates the code, stale, or noise) to 1.0 (explains intent, constraints or non-obvious behaviour).\n")
+	if cfg.CheckVerbosity {
+		sb.WriteString("Lower the score of comments that are much longer than what they convey.\n")
+	}
+	sb.WriteString("Give a one-sentence reason for any score below ")
+	fmt.Fprintf(&sb, "%.2f, and return one entry per comment using its line number.\n", cfg.MinDescriptiveScore)
+	for _, c := range comments {
+		fmt.Fprintf(&sb, "\n--- line %d\n%s\n", c.Line, c.Text)
+		if c.Context != "" {
+			fmt.Fprintf(&sb, "--- followed by code\n%s\n", c.Context)
+		}
+	}
+	return sb.String()
+}
+
+// commentAnalyzer scores the comments longer than the word limit and caches
+// the scores per file content, so unchanged files are not sent again.
+type commentAnalyzer struct {
+	noCache  bool
+	cacheDir string
+	agents   map[string]clickyai.Agent
+	disabled bool
+}
+
+func newCommentAnalyzer(noCache bool) *commentAnalyzer {
+	a := &commentAnalyzer{noCache: noCache, agents: map[string]clickyai.Agent{}}
+	if dir, err := os.UserCacheDir(); err == nil {
+		a.cacheDir = filepath.Join(dir, "gavel", "quality-comments")
+	}
+	return a
+}
+
+// analyze returns the scored comments of one file that fall below
+// MinDescriptiveScore. AI failures are logged once and turn the analysis off
+// for the rest of the run; the other quality checks are unaffected.
+func (a *commentAnalyzer) analyze(ctx context.Context, file string, content []byte, comments []comment, cfg models.CommentAnalysisConfig) []commentScore {
+	var candidates []comment
+	for _, c := range comments {
+		if len(strings.Fields(c.Text)) > cfg.WordLimit {
+			candidates = append(candidates, c)
+		}
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	key := cacheKey(content, cfg)
+	scores, ok := a.load(key)
+	if !ok {
+		if a.disabled {
+			return nil
+		}
+		agent, err := a.agent(cfg.AIModel)
+		if err == nil {
+			scores, err = scoreCommentsFunc(ctx, agent, cfg, file, candidates)
+		}
+		if err != nil {
+			logger.Warnf("quality: comment analysis disabled: %v", err)
+			a.disabled = true
+			return nil
+		}
+		a.store(key, scores)
+	}
+
+	var flagged []commentScore
+	for _, s := range scores {
+		if s.Score < cfg.MinDescriptiveScore {
+			flagged = append(flagged, s)
+		}
+	}
+	return flagged
+}
+
+// agent returns the agent for model, creating it on first use.
+func (a *commentAnalyzer) agent(model string) (clickyai.Agent, error) {
+	if agent, ok := a.agents[model]; ok {
+		return agent, nil
+	}
+	cfg := clickyai.DefaultConfig()
+	cfg.Model = model
+	agent, err := newAgentFunc(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("create AI agent: %w", err)
+	}
+	a.agents[model] = agent
+	return agent, nil
+}
+
+// close releases the agents created during the run.
+func (a *commentAnalyzer) close() {
+	for _, agent := range a.agents {
+		if agent != nil {
+			_ = agent.Close()
+		}
+	}
+}
+
+// cacheKey hashes the file content together with the settings that change
+// the verdict.
+func cacheKey(content []byte, cfg models.CommentAnalysisConfig) string {
+	h := sha256.New()
+	h.Write(content)
+	fmt.Fprintf(h, "\x00%s\x00%d\x00%t", cfg.AIModel, cfg.WordLimit, cfg.CheckVerbosity)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func (a *commentAnalyzer) load(key string) ([]commentScore, bool) {
+	if a.noCache || a.cacheDir == "" {
+		return nil, false
+	}
+	data, err := os.ReadFile(filepath.Join(a.cacheDir, key+".json"))
+	if err != nil {
+		return nil, false
+	}
+	var scores []commentScore
+	if err := json.Unmarshal(data, &scores); err != nil {
+		return nil, false
+	}
+	return scores, true
+}
+
+func (a *commentAnalyzer) store(key string, scores []commentScore) {
+	if a.cacheDir == "" {
+		return
+	}
+	data, err := json.Marshal(scores)
+	if err == nil {
+		err = os.MkdirAll(a.cacheDir, 0o755)
+	}
+	if err == nil {
+		err = os.WriteFile(filepath.Join(a.cacheDir, key+".json"), data, 0o644)
+	}
+	if err != nil {
+		logger.Debugf("quality: failed to cache comment scores: %v", err)
+	}
+}
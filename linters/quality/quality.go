@@ -0,0 +1,325 @@
+// Package quality applies the quality section of arch.yaml rules (file
+// length, name length limits, disallowed names and comment analysis) to Go,
+// TypeScript/JavaScript and Python sources.
+package quality
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/bmatcuk/doublestar/v4"
+	"github.com/flanksource/clicky"
+	commonsContext "github.com/flanksource/commons/context"
+	"github.com/flanksource/commons/logger"
+	"github.com/flanksource/gavel/linters"
+	"github.com/flanksource/gavel/linters/arch"
+	"github.com/flanksource/gavel/models"
+)
+
+// Quality implements the Linter interface for arch.yaml quality rules
+type Quality struct {
+	linters.RunOptions
+}
+
+// NewQuality creates a new quality linter
+func NewQuality(workDir string) *Quality {
+	return &Quality{
+		RunOptions: linters.RunOptions{
+			WorkDir: workDir,
+		},
+	}
+}
+
+// SetOptions sets the run options for the linter
+func (q *Quality) SetOptions(opts linters.RunOptions) {
+	q.RunOptions = opts
+}
+
+// Name returns the linter name
+func (q *Quality) Name() string {
+	return "quality"
+}
+
+// InProcess reports that the checks are evaluated by gavel itself.
+func (q *Quality) InProcess() bool {
+	return true
+}
+
+// ProjectRootMarkers anchors the linter at the directory holding arch.yaml,
+// since the quality patterns are relative to it.
+func (q *Quality) ProjectRootMarkers() []string {
+	return arch.ConfigFiles
+}
+
+// DefaultIncludes returns the source files that are checked
+func (q *Quality) DefaultIncludes() []string {
+	return []string{"**/*.go", "**/*.ts", "**/*.tsx", "**/*.js", "**/*.jsx", "**/*.mjs", "**/*.cjs", "**/*.py"}
+}
+
+// DefaultExcludes returns dependency, build and generated sources
+func (q *Quality) DefaultExcludes() []string {
+	return []string{
+		"**/node_modules/**",
+		"**/vendor/**",
+		"**/dist/**",
+		"**/build/**",
+		"**/.venv/**",
+		"**/venv/**",
+		"**/__pycache__/**",
+		"**/testdata/**",
+		"**/*.d.ts",
+		"**/*.min.js",
+		"**/*.pb.go",
+		"**/zz_generated*.go",
+	}
+}
+
+// SupportsJSON returns false; there is no external output to parse
+func (q *Quality) SupportsJSON() bool {
+	return false
+}
+
+// JSONArgs returns nil
+func (q *Quality) JSONArgs() []string {
+	return nil
+}
+
+// SupportsFix returns false; renames and comment rewrites are left to the author
+func (q *Quality) SupportsFix() bool {
+	return false
+}
+
+// FixArgs returns nil
+func (q *Quality) FixArgs() []string {
+	return nil
+}
+
+// ValidateConfig validates linter-specific configuration
+func (q *Quality) ValidateConfig(config *models.LinterConfig) error {
+	if config == nil {
+		return fmt.Errorf("config cannot be nil")
+	}
+	return nil
+}
+
+// DryRunCommand reports the rules file and files the linter would check.
+func (q *Quality) DryRunCommand() (string, []string) {
+	args := []string{arch.ConfigFiles[0]}
+	if cfg, err := arch.LoadConfig(q.WorkDir); err == nil && cfg != nil {
+		args[0] = filepath.Base(cfg.Path)
+	}
+	return q.Name(), append(args, q.Files...)
+}
+
+// Run checks every matching source file under WorkDir (or Files when set)
+// that a rule with a quality section applies to.
+func (q *Quality) Run(ctx commonsContext.Context, task *clicky.Task) ([]models.Violation, error) {
+	cfg := q.ArchConfig
+	if cfg == nil || len(cfg.Rules) == 0 {
+		loaded, err := arch.LoadConfig(q.WorkDir)
+		if err != nil {
+			return nil, err
+		}
+		cfg = loaded
+	}
+	if cfg == nil || len(cfg.Rules) == 0 {
+		logger.Debugf("quality: no rules in %s", q.WorkDir)
+		return []models.Violation{}, nil
+	}
+
+	root, err := filepath.Abs(q.WorkDir)
+	if err != nil {
+		return nil, err
+	}
+	files, err := q.targetFiles(root, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	source := filepath.Base(cfg.Path)
+	if cfg.Path == "" {
+		source = arch.ConfigFiles[0]
+	}
+	comments := newCommentAnalyzer(q.NoCache)
+	defer comments.close()
+	violations := []models.Violation{}
+	for _, rel := range files {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		qc := cfg.GetQualityConfig(rel)
+		if qc == nil {
+			continue
+		}
+		found, err := q.checkFile(ctx, root, rel, source, qc, comments)
+		if err != nil {
+			logger.Debugf("quality: skipping %s: %v", rel, err)
+			continue
+		}
+		violations = append(violations, found...)
+	}
+
+	sort.SliceStable(violations, func(i, j int) bool {
+		if violations[i].File != violations[j].File {
+			return violations[i].File < violations[j].File
+		}
+		return violations[i].Line < violations[j].Line
+	})
+	return violations, nil
+}
+
+// checkFile parses one root-relative file and applies qc to it.
+func (q *Quality) checkFile(ctx commonsContext.Context, root, rel, source string, qc *models.QualityConfig, comments *commentAnalyzer) ([]models.Violation, error) {
+	abs := filepath.Join(root, filepath.FromSlash(rel))
+	content, err := os.ReadFile(abs)
+	if err != nil {
+		return nil, err
+	}
+	parsed, err := parse(rel, content)
+	if err != nil {
+		return nil, err
+	}
+
+	var violations []models.Violation
+	report := func(line, column int, rule models.Rule, message string) {
+		rule.SourceFile = source
+		violations = append(violations, violation(abs, line, column, rule, message))
+	}
+
+	if ok, message := qc.ValidateFileLength(countLines(content)); !ok {
+		report(0, 0, models.Rule{
+			Type:         models.RuleTypeMaxFileLength,
+			Method:       "max-file-length",
+			MaxFileLines: qc.MaxFileLength,
+		}, message)
+	}
+
+	for _, sym := range parsed.symbols {
+		if disallowed, reason := qc.IsNameDisallowed(sym.Name); disallowed {
+			report(sym.Line, sym.Column, models.Rule{
+				Type:               models.RuleTypeDisallowedName,
+				Method:             "disallowed-name",
+				DisallowedPatterns: qc.GetDisallowedNamePatterns(),
+			}, fmt.Sprintf("%s name '%s' is disallowed: %s", sym.Kind, sym.Name, reason))
+		}
+
+		var ok bool
+		var message string
+		var limit int
+		switch sym.Kind {
+		case kindFunction:
+			ok, message = qc.ValidateFunctionNameLength(sym.Name)
+			limit = qc.MaxFunctionNameLen
+		case kindVariable:
+			ok, message = qc.ValidateVariableNameLength(sym.Name)
+			limit = qc.MaxVariableNameLen
+		case kindParameter:
+			ok, message = qc.ValidateParameterNameLength(sym.Name)
+			limit = qc.MaxParameterNameLen
+		default:
+			continue
+		}
+		if !ok {
+			report(sym.Line, sym.Column, models.Rule{
+				Type:          models.RuleTypeMaxNameLength,
+				Method:        "max-name-length",
+				MaxNameLength: limit,
+			}, message)
+		}
+	}
+
+	if qc.CommentAnalysis.Enabled {
+		for _, f := range comments.analyze(ctx, rel, content, parsed.comments, qc.CommentAnalysis) {
+			report(f.Line, 0, models.Rule{
+				Type:                models.RuleTypeCommentQuality,
+				Method:              "comment-quality",
+				CommentWordLimit:    qc.CommentAnalysis.WordLimit,
+				CommentAIModel:      qc.CommentAnalysis.AIModel,
+				MinDescriptiveScore: qc.CommentAnalysis.MinDescriptiveScore,
+			}, fmt.Sprintf("low-value comment (score %.2f, minimum %.2f): %s",
+				f.Score, qc.CommentAnalysis.MinDescriptiveScore, f.Reason))
+		}
+	}
+	return violations, nil
+}
+
+// targetFiles lists the root-relative files to check: the includes, minus
+// the default and global excludes, restricted to Files when the run was
+// scoped.
+func (q *Quality) targetFiles(root string, cfg *models.Config) ([]string, error) {
+	excludes := append(q.DefaultExcludes(), cfg.GlobalExcludes...)
+	keep := func(rel string) bool {
+		for _, pattern := range excludes {
+			if ok, _ := doublestar.Match(pattern, rel); ok {
+				return false
+			}
+		}
+		for _, pattern := range q.DefaultIncludes() {
+			if ok, _ := doublestar.Match(pattern, rel); ok {
+				return true
+			}
+		}
+		return false
+	}
+
+	var files []string
+	if len(q.Files) > 0 {
+		for _, f := range q.Files {
+			if !filepath.IsAbs(f) {
+				f = filepath.Join(root, f)
+			}
+			if rel, err := filepath.Rel(root, f); err == nil && keep(filepath.ToSlash(rel)) {
+				files = append(files, filepath.ToSlash(rel))
+			}
+		}
+		return files, nil
+	}
+
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.IsDir() {
+			if name := d.Name(); path != root && (name == ".git" || name == "node_modules") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return nil
+		}
+		if rel = filepath.ToSlash(rel); keep(rel) {
+			files = append(files, rel)
+		}
+		return nil
+	})
+	return files, err
+}
+
+func countLines(content []byte) int {
+	if len(content) == 0 {
+		return 0
+	}
+	n := bytes.Count(content, []byte("\n"))
+	if content[len(content)-1] != '\n' {
+		n++
+	}
+	return n
+}
+
+func violation(file string, line, column int, rule models.Rule, message string) models.Violation {
+	v := models.NewViolationBuilder().
+		WithFile(file).
+		WithLocation(line, column).
+		WithMessage(message).
+		WithSource("quality").
+		Build()
+	v.Rule = &rule
+	v.Severity = models.SeverityWarning
+	return v
+}
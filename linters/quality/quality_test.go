@@ -0,0 +1,226 @@
+package quality
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	clickyai "github.com/flanksource/clicky/ai"
+	commonsContext "github.com/flanksource/commons/context"
+	"github.com/flanksource/gavel/linters"
+	"github.com/flanksource/gavel/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeFiles(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	}
+}
+
+func run(t *testing.T, opts linters.RunOptions) []models.Violation {
+	t.Helper()
+	q := NewQuality(opts.WorkDir)
+	q.SetOptions(opts)
+	violations, err := q.Run(commonsContext.NewContext(context.Background()), nil)
+	require.NoError(t, err)
+	return violations
+}
+
+// findings keys violations by root-relative file, line and rule method.
+func findings(t *testing.T, root string, violations []models.Violation) map[string]string {
+	t.Helper()
+	got := map[string]string{}
+	for _, v := range violations {
+		rel, err := filepath.Rel(root, v.File)
+		require.NoError(t, err)
+		require.NotNil(t, v.Rule)
+		require.NotNil(t, v.Message)
+		assert.Equal(t, "quality", v.Source)
+		assert.Equal(t, "arch.yaml", v.Rule.SourceFile)
+		got[fmt.Sprintf("%s:%d:%s", filepath.ToSlash(rel), v.Line, v.Rule.Method)] = *v.Message
+	}
+	return got
+}
+
+const qualityRules = `rules:
+  "**":
+    quality:
+      max_file_length: 100
+      max_function_name_length: 12
+      max_variable_name_length: 8
+      max_parameter_name_length: 6
+      disallowed_names:
+        - pattern: "*Manager"
+          reason: "say what it manages"
+  "internal/**":
+    quality:
+      max_file_length: 5
+`
+
+func TestQualityGo(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{
+		"arch.yaml": qualityRules,
+		"internal/store/store.go": `package store
+
+type SessionManager struct{}
+
+func (m *SessionManager) OpenAll(identifier string) error {
+	connection, err := open(identifier)
+	if err != nil {
+		return err
+	}
+	connection, second := open(identifier)
+	_, _ = connection, second
+	return err
+}
+
+func open(id string) (string, error) { return id, nil }
+`,
+		"main.go": "package main\n\nfunc main() {\n\tfor index, v := range []int{1} {\n\t\t_, _ = index, v\n\t}\n}\n",
+	})
+
+	got := findings(t, root, run(t, linters.RunOptions{WorkDir: root}))
+	assert.Equal(t, map[string]string{
+		"internal/store/store.go:0:max-file-length": "file has 15 lines, exceeds maximum of 5",
+		"internal/store/store.go:3:disallowed-name": "type name 'SessionManager' is disallowed: say what it manages",
+		"internal/store/store.go:5:max-name-length": "parameter name 'identifier' has 10 characters, exceeds maximum of 6",
+		"internal/store/store.go:6:max-name-length": "variable name 'connection' has 10 characters, exceeds maximum of 8",
+	}, got, "the := redeclaring connection is not a new declaration")
+}
+
+func TestQualityTypeScriptAndPython(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{
+		"arch.yaml": qualityRules,
+		"web/app.ts": `// function veryLongFunctionName() {}
+export function renderEverything(props: Props) {
+  const selection = props.value;
+}
+const handleClickEvent = async (event) => {};
+class PageManager {
+  update(nextState?: State) {
+    if (nextState) {
+    }
+  }
+}
+`,
+		"app/jobs.py": `#!/usr/bin/env python3
+import os
+
+class JobManager:
+    def run_all_the_jobs(self, queue, *args):
+        results = []
+        results = call(
+            keyword_argument=1,
+        )
+        return results
+`,
+	})
+
+	got := findings(t, root, run(t, linters.RunOptions{WorkDir: root}))
+	assert.Equal(t, map[string]string{
+		"web/app.ts:2:max-name-length":  "function name 'renderEverything' has 16 characters, exceeds maximum of 12",
+		"web/app.ts:3:max-name-length":  "variable name 'selection' has 9 characters, exceeds maximum of 8",
+		"web/app.ts:5:max-name-length":  "function name 'handleClickEvent' has 16 characters, exceeds maximum of 12",
+		"web/app.ts:6:disallowed-name":  "type name 'PageManager' is disallowed: say what it manages",
+		"web/app.ts:7:max-name-length":  "parameter name 'nextState' has 9 characters, exceeds maximum of 6",
+		"app/jobs.py:4:disallowed-name": "type name 'JobManager' is disallowed: say what it manages",
+		"app/jobs.py:5:max-name-length": "function name 'run_all_the_jobs' has 16 characters, exceeds maximum of 12",
+	}, got)
+
+	for _, v := range run(t, linters.RunOptions{WorkDir: root}) {
+		if v.Line == 5 && filepath.Base(v.File) == "jobs.py" {
+			assert.Equal(t, models.RuleTypeMaxNameLength, v.Rule.Type)
+			assert.Equal(t, 12, v.Rule.MaxNameLength)
+		}
+	}
+
+	scoped := run(t, linters.RunOptions{WorkDir: root, Files: []string{"app/jobs.py"}})
+	assert.Len(t, scoped, 2)
+}
+
+func TestQualityWithoutQualityRules(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{
+		"arch.yaml": "rules:\n  \"**\":\n    imports:\n      - \"!os/exec\"\n",
+		"a.go":      "package a\n\nvar anExtremelyLongVariableNameThatGoesOnAndOn = 1\n",
+	})
+	assert.Empty(t, run(t, linters.RunOptions{WorkDir: root}))
+}
+
+type fakeAgent struct{ clickyai.Agent }
+
+func (fakeAgent) Close() error { return nil }
+
+func TestQualityCommentAnalysis(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{
+		"arch.yaml": `rules:
+  "**":
+    quality:
+      comment_analysis:
+        enabled: true
+        word_limit: 5
+        ai_model: test-model
+`,
+		"a.go": `package a
+
+// Add adds.
+func Add(a, b int) int { return a + b }
+
+// Sub subtracts b from a and returns the result of the subtraction.
+func Sub(a, b int) int { return a - b }
+
+// Clamp keeps callers from overflowing the uint8 buffers the codec expects.
+func Clamp(v int) int { return v }
+`,
+	})
+
+	var requested []string
+	calls := 0
+	previousAgent, previousScore := newAgentFunc, scoreCommentsFunc
+	defer func() { newAgentFunc, scoreCommentsFunc = previousAgent, previousScore }()
+	newAgentFunc = func(cfg clickyai.AgentConfig) (clickyai.Agent, error) {
+		requested = append(requested, cfg.Model)
+		return fakeAgent{}, nil
+	}
+	scoreCommentsFunc = func(_ context.Context, _ clickyai.Agent, _ models.CommentAnalysisConfig, file string, comments []comment) ([]commentScore, error) {
+		calls++
+		assert.Equal(t, "a.go", file)
+		require.Len(t, comments, 2, "comments within the word limit are not sent")
+		assert.Equal(t, "func Sub(a, b int) int { return a - b }", comments[0].Context)
+		return []commentScore{
+			{Line: comments[0].Line, Score: 0.2, Reason: "restates the code"},
+			{Line: comments[1].Line, Score: 0.9},
+		}, nil
+	}
+
+	violations := run(t, linters.RunOptions{WorkDir: root})
+	require.Len(t, violations, 1)
+	assert.Equal(t, 6, violations[0].Line)
+	assert.Equal(t, models.RuleTypeCommentQuality, violations[0].Rule.Type)
+	assert.Equal(t, "test-model", violations[0].Rule.CommentAIModel)
+	assert.Equal(t, "low-value comment (score 0.20, minimum 0.70): restates the code", *violations[0].Message)
+	assert.Equal(t, []string{"test-model"}, requested)
+
+	assert.Len(t, run(t, linters.RunOptions{WorkDir: root}), 1)
+	assert.Equal(t, 1, calls, "unchanged files are served from the cache")
+
+	assert.Len(t, run(t, linters.RunOptions{WorkDir: root, NoCache: true}), 1)
+	assert.Equal(t, 2, calls)
+
+	scoreCommentsFunc = func(context.Context, clickyai.Agent, models.CommentAnalysisConfig, string, []comment) ([]commentScore, error) {
+		return nil, errors.New("no API key")
+	}
+	assert.Empty(t, run(t, linters.RunOptions{WorkDir: root, NoCache: true}), "AI failures skip comment analysis")
+}
@@ -0,0 +1,353 @@
+package quality
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// symbolKind says which name-length limit applies to a symbol.
+type symbolKind string
+
+const (
+	kindFunction  symbolKind = "function"
+	kindVariable  symbolKind = "variable"
+	kindParameter symbolKind = "parameter"
+	kindType      symbolKind = "type"
+)
+
+// symbol is a name declared in a file. Types are only checked against the
+// disallowed names.
+type symbol struct {
+	Name   string
+	Kind   symbolKind
+	Line   int
+	Column int
+}
+
+// comment is a comment block with its markers stripped. Line is where the
+// block starts, Context the first line of code that follows it.
+type comment struct {
+	Line    int
+	Text    string
+	Context string
+}
+
+type parsedFile struct {
+	symbols  []symbol
+	comments []comment
+}
+
+// parse extracts declarations and comments: Go through go/ast, TS/JS and
+// Python by scanning declaration patterns line by line.
+func parse(rel string, content []byte) (*parsedFile, error) {
+	switch filepath.Ext(rel) {
+	case ".go":
+		return parseGo(rel, content)
+	case ".py":
+		return parsePython(content), nil
+	default:
+		return parseJS(content), nil
+	}
+}
+
+func parseGo(rel string, content []byte) (*parsedFile, error) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, rel, content, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+
+	out := &parsedFile{}
+	add := func(ident *ast.Ident, kind symbolKind) {
+		if ident == nil || ident.Name == "_" {
+			return
+		}
+		pos := fset.Position(ident.Pos())
+		out.symbols = append(out.symbols, symbol{Name: ident.Name, Kind: kind, Line: pos.Line, Column: pos.Column})
+	}
+	addFields := func(fields *ast.FieldList, kind symbolKind) {
+		if fields == nil {
+			return
+		}
+		for _, field := range fields.List {
+			for _, name := range field.Names {
+				add(name, kind)
+			}
+		}
+	}
+
+	ast.Inspect(f, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.FuncDecl:
+			add(node.Name, kindFunction)
+			addFields(node.Recv, kindParameter)
+			addFields(node.Type.Params, kindParameter)
+		case *ast.FuncLit:
+			addFields(node.Type.Params, kindParameter)
+		case *ast.TypeSpec:
+			add(node.Name, kindType)
+		case *ast.ValueSpec:
+			for _, name := range node.Names {
+				add(name, kindVariable)
+			}
+		case *ast.AssignStmt:
+			if node.Tok != token.DEFINE {
+				return true
+			}
+			for _, lhs := range node.Lhs {
+				// := redeclares names already in scope; only report the
+				// declaration itself
+				if ident, ok := lhs.(*ast.Ident); ok && (ident.Obj == nil || ident.Obj.Pos() == ident.Pos()) {
+					add(ident, kindVariable)
+				}
+			}
+		case *ast.RangeStmt:
+			if node.Tok == token.DEFINE {
+				for _, expr := range []ast.Expr{node.Key, node.Value} {
+					if ident, ok := expr.(*ast.Ident); ok {
+						add(ident, kindVariable)
+					}
+				}
+			}
+		}
+		return true
+	})
+
+	lines := strings.Split(string(content), "\n")
+	for _, group := range f.Comments {
+		text := strings.TrimSpace(group.Text())
+		if text == "" {
+			continue
+		}
+		start := fset.Position(group.Pos()).Line
+		end := fset.Position(group.End()).Line
+		out.comments = append(out.comments, comment{Line: start, Text: text, Context: nextCode(lines, end)})
+	}
+	return out, nil
+}
+
+var (
+	jsFunctionPattern = regexp.MustCompile(`\bfunction\s*\*?\s*([A-Za-z_$][\w$]*)\s*(?:<[^>]*>)?\s*\(([^)]*)`)
+	jsArrowPattern    = regexp.MustCompile(`\b(?:const|let|var)\s+([A-Za-z_$][\w$]*)\s*(?::[^=]+)?=\s*(?:async\s+)?(?:function\b[^(]*\(([^)]*)|\(([^)]*)\)\s*(?::[^=]+)?=>|([A-Za-z_$][\w$]*)\s*=>)`)
+	jsVariablePattern = regexp.MustCompile(`\b(?:const|let|var)\s+([A-Za-z_$][\w$]*)`)
+	jsMethodPattern   = regexp.MustCompile(`^\s*(?:(?:public|private|protected|static|async|readonly|override|get|set)\s+)*\*?\s*([A-Za-z_$][\w$]*)\s*(?:<[^>]*>)?\s*\(([^)]*)\)\s*(?::[^{;]+)?\{\s*$`)
+	jsTypePattern     = regexp.MustCompile(`\b(?:class|interface|type|enum)\s+([A-Za-z_$][\w$]*)`)
+)
+
+// jsKeywords start statements that look like method declarations.
+var jsKeywords = map[string]bool{
+	"if": true, "for": true, "while": true, "switch": true, "catch": true,
+	"function": true, "return": true, "with": true,
+}
+
+func parseJS(content []byte) *parsedFile {
+	out := &parsedFile{}
+	lines := strings.Split(string(content), "\n")
+	code := stripJSComments(lines, out)
+
+	for i, line := range code {
+		n := i + 1
+		if m := jsFunctionPattern.FindStringSubmatchIndex(line); m != nil {
+			out.addMatch(line, n, m, 2, kindFunction)
+			out.addParams(line, n, m, 4, jsParamName)
+		}
+		if m := jsArrowPattern.FindStringSubmatchIndex(line); m != nil {
+			out.addMatch(line, n, m, 2, kindFunction)
+			for _, group := range []int{4, 6, 8} {
+				out.addParams(line, n, m, group, jsParamName)
+			}
+		} else if m := jsVariablePattern.FindStringSubmatchIndex(line); m != nil {
+			out.addMatch(line, n, m, 2, kindVariable)
+		}
+		if m := jsMethodPattern.FindStringSubmatchIndex(line); m != nil && !jsKeywords[line[m[2]:m[3]]] {
+			out.addMatch(line, n, m, 2, kindFunction)
+			out.addParams(line, n, m, 4, jsParamName)
+		}
+		if m := jsTypePattern.FindStringSubmatchIndex(line); m != nil {
+			out.addMatch(line, n, m, 2, kindType)
+		}
+	}
+	return out
+}
+
+// stripJSComments collects // and /* */ comment blocks into out and returns
+// the lines with comments blanked, so declarations inside them are ignored.
+func stripJSComments(lines []string, out *parsedFile) []string {
+	code := make([]string, len(lines))
+	var block []string
+	start, inBlock := 0, false
+	flush := func(end int) {
+		if text := strings.TrimSpace(strings.Join(block, "\n")); text != "" {
+			out.comments = append(out.comments, comment{Line: start, Text: text, Context: nextCode(lines, end)})
+		}
+		block = nil
+	}
+
+	for i, line := range lines {
+		n := i + 1
+		trimmed := strings.TrimSpace(line)
+		if inBlock {
+			if idx := strings.Index(line, "*/"); idx >= 0 {
+				block = append(block, trimBlockLine(line[:idx]))
+				inBlock = false
+				flush(n)
+				code[i] = line[idx+2:]
+				continue
+			}
+			block = append(block, trimBlockLine(line))
+			continue
+		}
+		switch {
+		case strings.HasPrefix(trimmed, "//"):
+			if len(block) == 0 {
+				start = n
+			}
+			block = append(block, strings.TrimSpace(strings.TrimPrefix(trimmed, "//")))
+			if i+1 >= len(lines) || !strings.HasPrefix(strings.TrimSpace(lines[i+1]), "//") {
+				flush(n)
+			}
+		case strings.HasPrefix(trimmed, "/*"):
+			start = n
+			body := strings.TrimPrefix(trimmed, "/*")
+			if idx := strings.Index(body, "*/"); idx >= 0 {
+				block = append(block, trimBlockLine(body[:idx]))
+				flush(n)
+				continue
+			}
+			block = append(block, trimBlockLine(body))
+			inBlock = true
+		default:
+			code[i] = line
+		}
+	}
+	return code
+}
+
+func trimBlockLine(line string) string {
+	return strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line), "*"))
+}
+
+func jsParamName(param string) string {
+	param = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(param), "..."))
+	for _, modifier := range []string{"public ", "private ", "protected ", "readonly "} {
+		param = strings.TrimPrefix(param, modifier)
+	}
+	if end := strings.IndexAny(param, ":=?"); end >= 0 {
+		param = param[:end]
+	}
+	return strings.TrimSpace(param)
+}
+
+var (
+	pyDefPattern    = regexp.MustCompile(`^\s*(?:async\s+)?def\s+([A-Za-z_]\w*)\s*\(([^)]*)`)
+	pyClassPattern  = regexp.MustCompile(`^\s*class\s+([A-Za-z_]\w*)`)
+	pyAssignPattern = regexp.MustCompile(`^\s*([A-Za-z_]\w*)\s*(?::[^=]+)?=[^=]`)
+)
+
+func parsePython(content []byte) *parsedFile {
+	out := &parsedFile{}
+	lines := strings.Split(string(content), "\n")
+	seen := map[string]bool{}
+	var block []string
+	start, depth := 0, 0
+
+	for i, line := range lines {
+		n := i + 1
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "#") {
+			if len(block) == 0 {
+				start = n
+			}
+			if !(n == 1 && strings.HasPrefix(trimmed, "#!")) {
+				block = append(block, strings.TrimSpace(strings.TrimPrefix(trimmed, "#")))
+			}
+			continue
+		}
+		if len(block) > 0 {
+			if text := strings.TrimSpace(strings.Join(block, "\n")); text != "" {
+				out.comments = append(out.comments, comment{Line: start, Text: text, Context: nextCode(lines, n-1)})
+			}
+			block = nil
+		}
+
+		// keyword arguments inside a multi-line call look like assignments
+		nested := depth > 0
+		depth += strings.Count(line, "(") + strings.Count(line, "[") + strings.Count(line, "{") -
+			strings.Count(line, ")") - strings.Count(line, "]") - strings.Count(line, "}")
+		if depth < 0 {
+			depth = 0
+		}
+		if nested {
+			continue
+		}
+
+		if m := pyDefPattern.FindStringSubmatchIndex(line); m != nil {
+			out.addMatch(line, n, m, 2, kindFunction)
+			out.addParams(line, n, m, 4, pyParamName)
+		} else if m := pyClassPattern.FindStringSubmatchIndex(line); m != nil {
+			out.addMatch(line, n, m, 2, kindType)
+		} else if m := pyAssignPattern.FindStringSubmatchIndex(line); m != nil {
+			// Python rebinds names freely; report the first assignment only
+			if name := line[m[2]:m[3]]; !seen[name] {
+				seen[name] = true
+				out.addMatch(line, n, m, 2, kindVariable)
+			}
+		}
+	}
+	return out
+}
+
+func pyParamName(param string) string {
+	param = strings.TrimLeft(strings.TrimSpace(param), "*")
+	if end := strings.IndexAny(param, ":="); end >= 0 {
+		param = param[:end]
+	}
+	param = strings.TrimSpace(param)
+	if param == "self" || param == "cls" || param == "/" {
+		return ""
+	}
+	return param
+}
+
+// addMatch records the submatch starting at index group of m as a symbol.
+func (p *parsedFile) addMatch(line string, n int, m []int, group int, kind symbolKind) {
+	if m[group] < 0 {
+		return
+	}
+	p.symbols = append(p.symbols, symbol{Name: line[m[group]:m[group+1]], Kind: kind, Line: n, Column: m[group] + 1})
+}
+
+// addParams records each name of the comma separated parameter list in the
+// submatch at index group. Destructured parameters are skipped.
+func (p *parsedFile) addParams(line string, n int, m []int, group int, name func(string) string) {
+	if m[group] < 0 {
+		return
+	}
+	offset := m[group]
+	for _, param := range strings.Split(line[m[group]:m[group+1]], ",") {
+		column := offset + len(param) - len(strings.TrimLeft(param, " \t.*")) + 1
+		offset += len(param) + 1
+		if strings.ContainsAny(param, "{}[]") {
+			continue
+		}
+		if id := name(param); identPattern.MatchString(id) {
+			p.symbols = append(p.symbols, symbol{Name: id, Kind: kindParameter, Line: n, Column: column})
+		}
+	}
+}
+
+var identPattern = regexp.MustCompile(`^[A-Za-z_$][\w$]*$`)
+
+// nextCode returns the first non-blank line after line end (1-based).
+func nextCode(lines []string, end int) string {
+	for i := end; i < len(lines); i++ {
+		if trimmed := strings.TrimSpace(lines[i]); trimmed != "" {
+			return trimmed
+		}
+	}
+	return ""
+}
@@ -25,6 +25,10 @@ type Config struct {
 	Build          BuildConfig                  `yaml:"build,omitempty"`
 	Golang         GolangConfig                 `yaml:"golang,omitempty"`
 	Scopes         ScopesConfig                 `yaml:"scopes,omitempty"`
+
+	// Path is the file the config was loaded from; rules cite it as their
+	// source. Empty means arch-unit.yaml.
+	Path string `yaml:"-"`
 }
 
 // RuleConfig represents configuration for a specific path pattern
@@ -141,22 +145,10 @@ func (c *Config) GetRulesForFile(filePath string) (*RuleSet, error) {
 
 	for pattern, ruleConfig := range c.Rules {
 		if c.patternMatches(pattern, absPath, filePath) {
-			// Calculate specificity: more specific patterns should be processed last
-			specificity := 0
-			if pattern == "**" {
-				specificity = 0 // Most general
-			} else if strings.Contains(pattern, "**") {
-				specificity = 1 // Glob patterns
-			} else if strings.Contains(pattern, "*") {
-				specificity = 2 // Wildcard patterns
-			} else {
-				specificity = 3 // Exact file paths are most specific
-			}
-
 			matches = append(matches, patternMatch{
 				pattern:     pattern,
 				ruleConfig:  ruleConfig,
-				specificity: specificity,
+				specificity: patternSpecificity(pattern),
 			})
 		}
 	}
@@ -190,6 +182,21 @@ func (c *Config) GetRulesForFile(filePath string) (*RuleSet, error) {
 	}, nil
 }
 
+// patternSpecificity ranks a rule pattern so more specific patterns are
+// processed last and override more general ones.
+func patternSpecificity(pattern string) int {
+	switch {
+	case pattern == "**":
+		return 0 // Most general
+	case strings.Contains(pattern, "**"):
+		return 1 // Glob patterns
+	case strings.Contains(pattern, "*"):
+		return 2 // Wildcard patterns
+	default:
+		return 3 // Exact file paths are most specific
+	}
+}
+
 // patternMatches checks if a file path matches a given pattern
 func (c *Config) patternMatches(pattern, absPath, relPath string) bool {
 	// Handle special "**" pattern (matches everything)
@@ -264,8 +271,12 @@ func (c *Config) patternMatches(pattern, absPath, relPath string) bool {
 // parseImportRule converts an import rule string to a Rule struct
 func (c *Config) parseImportRule(importRule, sourcePattern string, lineNum int) (*Rule, error) {
 	originalRule := importRule
+	source := "arch-unit.yaml"
+	if c.Path != "" {
+		source = filepath.Base(c.Path)
+	}
 	rule := &Rule{
-		SourceFile:   "arch-unit.yaml:" + sourcePattern,
+		SourceFile:   source + ":" + sourcePattern,
 		LineNumber:   lineNum + 1,
 		Scope:        ".",
 		OriginalLine: originalRule,
@@ -355,35 +366,49 @@ func (c *Config) GetEnabledLinters() []string {
 func (c *Config) GetQualityConfig(filePath string) *QualityConfig {
 	var config *QualityConfig
 
-	// Find the most specific pattern match
+	var patterns []string
 	for pattern, ruleConfig := range c.Rules {
-		if c.patternMatches(pattern, filePath, filePath) && ruleConfig.Quality != nil {
-			if config == nil {
-				// First match - create a copy
-				configCopy := *ruleConfig.Quality
-				config = &configCopy
-			} else {
-				// Merge with more specific pattern (override non-zero values)
-				if ruleConfig.Quality.MaxFileLength != 0 {
-					config.MaxFileLength = ruleConfig.Quality.MaxFileLength
-				}
-				if ruleConfig.Quality.MaxFunctionNameLen != 0 {
-					config.MaxFunctionNameLen = ruleConfig.Quality.MaxFunctionNameLen
-				}
-				if ruleConfig.Quality.MaxVariableNameLen != 0 {
-					config.MaxVariableNameLen = ruleConfig.Quality.MaxVariableNameLen
-				}
-				if ruleConfig.Quality.MaxParameterNameLen != 0 {
-					config.MaxParameterNameLen = ruleConfig.Quality.MaxParameterNameLen
-				}
-				if len(ruleConfig.Quality.DisallowedNames) > 0 {
-					config.DisallowedNames = append(config.DisallowedNames, ruleConfig.Quality.DisallowedNames...)
-				}
-				// Override comment analysis config
-				if ruleConfig.Quality.CommentAnalysis.Enabled {
-					config.CommentAnalysis = ruleConfig.Quality.CommentAnalysis
-				}
-			}
+		if ruleConfig.Quality != nil && c.patternMatches(pattern, filePath, filePath) {
+			patterns = append(patterns, pattern)
+		}
+	}
+	// Merge from the least to the most specific pattern
+	sort.Slice(patterns, func(i, j int) bool {
+		si, sj := patternSpecificity(patterns[i]), patternSpecificity(patterns[j])
+		if si != sj {
+			return si < sj
+		}
+		return patterns[i] < patterns[j]
+	})
+
+	for _, pattern := range patterns {
+		quality := c.Rules[pattern].Quality
+		if config == nil {
+			// First match - create a copy
+			configCopy := *quality
+			configCopy.DisallowedNames = append([]DisallowedNamePattern(nil), quality.DisallowedNames...)
+			config = &configCopy
+			continue
+		}
+		// Merge with more specific pattern (override non-zero values)
+		if quality.MaxFileLength != 0 {
+			config.MaxFileLength = quality.MaxFileLength
+		}
+		if quality.MaxFunctionNameLen != 0 {
+			config.MaxFunctionNameLen = quality.MaxFunctionNameLen
+		}
+		if quality.MaxVariableNameLen != 0 {
+			config.MaxVariableNameLen = quality.MaxVariableNameLen
+		}
+		if quality.MaxParameterNameLen != 0 {
+			config.MaxParameterNameLen = quality.MaxParameterNameLen
+		}
+		if len(quality.DisallowedNames) > 0 {
+			config.DisallowedNames = append(config.DisallowedNames, quality.DisallowedNames...)
+		}
+		// Override comment analysis config
+		if quality.CommentAnalysis.Enabled {
+			config.CommentAnalysis = quality.CommentAnalysis
 		}
 	}
 
@@ -0,0 +1,174 @@
+package sarif
+
+import (
+	"sort"
+
+	"github.com/flanksource/gavel/linters"
+	"github.com/flanksource/gavel/models"
+)
+
+// FromLint builds one run per linter. Results that share a linter name (the
+// per-project fan-out) are folded into the same run. Skipped linters are left
+// out, but linters that ran clean get an empty run so code scanning closes
+// alerts they previously raised. root is the directory file URIs are made
+// relative to.
+//
+// The results are rendered as given: callers apply lint ignores and baselines
+// first, so the report matches what gavel enforces.
+func FromLint(results []*linters.LinterResult, root string) *Log {
+	var order []string
+	byLinter := map[string]*lintRun{}
+	for _, r := range results {
+		if r == nil || r.Skipped {
+			continue
+		}
+		run, ok := byLinter[r.Linter]
+		if !ok {
+			run = &lintRun{rules: map[string]int{}, success: true}
+			run.run.Tool.Driver.Name = r.Linter
+			run.run.OriginalURIBaseIDs = rootBase(root)
+			run.run.Results = []Result{}
+			byLinter[r.Linter] = run
+			order = append(order, r.Linter)
+		}
+		run.add(r, root)
+	}
+
+	log := NewLog()
+	for _, name := range order {
+		run := byLinter[name]
+		run.run.Invocations = []Invocation{{ExecutionSuccessful: run.success, ToolExecutionNotifications: run.notes}}
+		log.Runs = append(log.Runs, run.run)
+	}
+	return log
+}
+
+type lintRun struct {
+	run     Run
+	rules   map[string]int
+	success bool
+	notes   []Notification
+}
+
+func (l *lintRun) add(r *linters.LinterResult, root string) {
+	if r.Error != "" || r.TimedOut {
+		l.success = false
+		msg := r.Error
+		if msg == "" {
+			msg = "timed out"
+		}
+		l.notes = append(l.notes, Notification{Level: LevelError, Message: Message{Text: msg}})
+	}
+
+	start := len(l.run.Results)
+	for _, v := range r.Violations {
+		id := ruleID(v, r.Linter)
+		index := l.rule(id, v)
+		l.run.Results = append(l.run.Results, Result{
+			RuleID:     id,
+			RuleIndex:  &index,
+			Level:      level(v.Severity),
+			Message:    Message{Text: message(v, id)},
+			Locations:  location(v.File, r.WorkDir, root, v.Line, v.Column, v.Code),
+			Properties: fixProperties(v),
+		})
+	}
+	added := l.run.Results[start:]
+	sort.SliceStable(added, func(i, j int) bool {
+		fi, li := sortKey(added[i])
+		fj, lj := sortKey(added[j])
+		if fi != fj {
+			return fi < fj
+		}
+		return li < lj
+	})
+}
+
+func sortKey(r Result) (string, int) {
+	if len(r.Locations) == 0 {
+		return "", 0
+	}
+	loc := r.Locations[0].PhysicalLocation
+	if loc.Region == nil {
+		return loc.ArtifactLocation.URI, 0
+	}
+	return loc.ArtifactLocation.URI, loc.Region.StartLine
+}
+
+// rule returns the index of id in the driver's rules, registering it with
+// the metadata carried by v.Rule on first sight.
+func (l *lintRun) rule(id string, v models.Violation) int {
+	if i, ok := l.rules[id]; ok {
+		return i
+	}
+	desc := ReportingDescriptor{ID: id}
+	if r := v.Rule; r != nil {
+		desc.Name = r.Method
+		props := map[string]any{}
+		for key, value := range map[string]string{
+			"package":     r.Package,
+			"type":        string(r.Type),
+			"pattern":     r.Pattern,
+			"scope":       r.Scope,
+			"sourceFile":  r.SourceFile,
+			"filePattern": r.FilePattern,
+		} {
+			if value != "" {
+				props[key] = value
+			}
+		}
+		if len(props) > 0 {
+			desc.Properties = props
+		}
+		if r.OriginalLine != "" {
+			desc.ShortDescription = &Message{Text: r.OriginalLine}
+		}
+	}
+	l.run.Tool.Driver.Rules = append(l.run.Tool.Driver.Rules, desc)
+	l.rules[id] = len(l.run.Tool.Driver.Rules) - 1
+	return l.rules[id]
+}
+
+// ruleID is the rule's method (the linter's own rule code), falling back to
+// the rule pattern and then the linter name, since code scanning needs a
+// rule for every result.
+func ruleID(v models.Violation, linter string) string {
+	if v.Rule != nil {
+		if v.Rule.Method != "" {
+			return v.Rule.Method
+		}
+		if v.Rule.Pattern != "" {
+			return v.Rule.Pattern
+		}
+	}
+	return linter
+}
+
+func level(s models.ViolationSeverity) string {
+	switch s {
+	case models.SeverityError:
+		return LevelError
+	case models.SeverityInfo:
+		return LevelNote
+	default:
+		return LevelWarning
+	}
+}
+
+func message(v models.Violation, ruleID string) string {
+	if v.Message != nil && *v.Message != "" {
+		return *v.Message
+	}
+	return ruleID
+}
+
+func fixProperties(v models.Violation) map[string]any {
+	if !v.Fixable {
+		return nil
+	}
+	props := map[string]any{"fixable": true}
+	if v.FixApplicability != "" {
+		props["fixApplicability"] = v.FixApplicability
+	}
+	return props
+}
@@ -0,0 +1,172 @@
+// Package sarif renders gavel lint and verify results as SARIF 2.1.0 logs,
+// the format GitHub code scanning and most security dashboards ingest.
+package sarif
+
+import (
+	"encoding/json"
+	"net/url"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	Version = "2.1.0"
+	Schema  = "https://json.schemastore.org/sarif-2.1.0.json"
+
+	// SrcRoot is the uriBaseId every artifact location is relative to. It is
+	// resolved through the run's originalUriBaseIds, so the report stays valid
+	// when uploaded from a different checkout path.
+	SrcRoot = "%SRCROOT%"
+)
+
+type Log struct {
+	Version string `json:"version"`
+	Schema  string `json:"$schema"`
+	Runs    []Run  `json:"runs"`
+}
+
+type Run struct {
+	Tool               Tool                        `json:"tool"`
+	OriginalURIBaseIDs map[string]ArtifactLocation `json:"originalUriBaseIds,omitempty"`
+	Invocations        []Invocation                `json:"invocations,omitempty"`
+	Results            []Result                    `json:"results"`
+}
+
+type Tool struct {
+	Driver Driver `json:"driver"`
+}
+
+type Driver struct {
+	Name           string                `json:"name"`
+	InformationURI string                `json:"informationUri,omitempty"`
+	Rules          []ReportingDescriptor `json:"rules,omitempty"`
+}
+
+type ReportingDescriptor struct {
+	ID                   string         `json:"id"`
+	Name                 string         `json:"name,omitempty"`
+	ShortDescription     *Message       `json:"shortDescription,omitempty"`
+	DefaultConfiguration *Configuration `json:"defaultConfiguration,omitempty"`
+	Properties           map[string]any `json:"properties,omitempty"`
+}
+
+type Configuration struct {
+	Level string `json:"level,omitempty"`
+}
+
+type Invocation struct {
+	ExecutionSuccessful        bool           `json:"executionSuccessful"`
+	ToolExecutionNotifications []Notification `json:"toolExecutionNotifications,omitempty"`
+}
+
+type Notification struct {
+	Level   string  `json:"level,omitempty"`
+	Message Message `json:"message"`
+}
+
+type Result struct {
+	RuleID     string         `json:"ruleId,omitempty"`
+	RuleIndex  *int           `json:"ruleIndex,omitempty"`
+	Level      string         `json:"level,omitempty"`
+	Message    Message        `json:"message"`
+	Locations  []Location     `json:"locations,omitempty"`
+	Properties map[string]any `json:"properties,omitempty"`
+}
+
+type Message struct {
+	Text string `json:"text"`
+}
+
+type Location struct {
+	PhysicalLocation PhysicalLocation `json:"physicalLocation"`
+}
+
+type PhysicalLocation struct {
+	ArtifactLocation ArtifactLocation `json:"artifactLocation"`
+	Region           *Region          `json:"region,omitempty"`
+}
+
+type ArtifactLocation struct {
+	URI       string `json:"uri"`
+	URIBaseID string `json:"uriBaseId,omitempty"`
+}
+
+type Region struct {
+	StartLine   int      `json:"startLine,omitempty"`
+	StartColumn int      `json:"startColumn,omitempty"`
+	Snippet     *Message `json:"snippet,omitempty"`
+}
+
+// Levels used in results and rule configurations.
+const (
+	LevelError   = "error"
+	LevelWarning = "warning"
+	LevelNote    = "note"
+)
+
+func NewLog(runs ...Run) *Log {
+	if runs == nil {
+		runs = []Run{}
+	}
+	return &Log{Version: Version, Schema: Schema, Runs: runs}
+}
+
+func (l *Log) JSON() (string, error) {
+	data, err := json.MarshalIndent(l, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(data) + "\n", nil
+}
+
+// rootBase maps SrcRoot to root, as a directory URI.
+func rootBase(root string) map[string]ArtifactLocation {
+	if root == "" {
+		return nil
+	}
+	abs, err := filepath.Abs(root)
+	if err != nil {
+		return nil
+	}
+	u := url.URL{Scheme: "file", Path: filepath.ToSlash(abs)}
+	s := u.String()
+	if !strings.HasSuffix(s, "/") {
+		s += "/"
+	}
+	return map[string]ArtifactLocation{SrcRoot: {URI: s}}
+}
+
+// artifact resolves file against base (when relative) and expresses it
+// relative to root. Files outside root keep their absolute path.
+func artifact(file, base, root string) ArtifactLocation {
+	if !filepath.IsAbs(file) && base != "" {
+		file = filepath.Join(base, file)
+	}
+	if filepath.IsAbs(file) && root != "" {
+		absRoot, _ := filepath.Abs(root)
+		rel, err := filepath.Rel(absRoot, file)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			u := url.URL{Scheme: "file", Path: filepath.ToSlash(file)}
+			return ArtifactLocation{URI: u.String()}
+		}
+		file = rel
+	}
+	return ArtifactLocation{URI: filepath.ToSlash(filepath.Clean(file)), URIBaseID: SrcRoot}
+}
+
+func location(file, base, root string, line, column int, snippet *string) []Location {
+	if file == "" {
+		return nil
+	}
+	loc := PhysicalLocation{ArtifactLocation: artifact(file, base, root)}
+	if line > 0 {
+		loc.Region = &Region{StartLine: line}
+		if column > 0 {
+			loc.Region.StartColumn = column
+		}
+		if snippet != nil && *snippet != "" {
+			loc.Region.Snippet = &Message{Text: *snippet}
+		}
+	}
+	return []Location{{PhysicalLocation: loc}}
+}
@@ -0,0 +1,148 @@
+package sarif
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/flanksource/gavel/linters"
+	"github.com/flanksource/gavel/models"
+	"github.com/flanksource/gavel/verify"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFromLintOneRunPerLinter(t *testing.T) {
+	results := []*linters.LinterResult{
+		{
+			Linter:  "golangci-lint",
+			WorkDir: "/repo",
+			Success: true,
+			Violations: []models.Violation{
+				{
+					File: "pkg/a.go", Line: 12, Column: 3,
+					Message:  models.StringPtr("unused variable"),
+					Rule:     &models.Rule{Package: "golangci-lint", Method: "unused"},
+					Severity: models.SeverityError,
+					Fixable:  true, FixApplicability: "safe",
+				},
+				{
+					File:    "/repo/pkg/b.go",
+					Line:    4,
+					Message: models.StringPtr("also unused"),
+					Rule:    &models.Rule{Package: "golangci-lint", Method: "unused"},
+				},
+			},
+		},
+		{
+			Linter:  "golangci-lint",
+			WorkDir: "/repo/sub",
+			Violations: []models.Violation{
+				{File: "c.go", Line: 1, Severity: models.SeverityInfo, Rule: &models.Rule{Method: "godot"}},
+			},
+		},
+		{Linter: "eslint", Skipped: true},
+		{Linter: "ruff", WorkDir: "/repo", Error: "ruff crashed"},
+	}
+
+	log := FromLint(results, "/repo")
+	require.Len(t, log.Runs, 2)
+	assert.Equal(t, Version, log.Version)
+
+	golangci := log.Runs[0]
+	assert.Equal(t, "golangci-lint", golangci.Tool.Driver.Name)
+	require.Len(t, golangci.Tool.Driver.Rules, 2)
+	assert.Equal(t, "unused", golangci.Tool.Driver.Rules[0].ID)
+	assert.Equal(t, "golangci-lint", golangci.Tool.Driver.Rules[0].Properties["package"])
+	assert.Equal(t, "file:///repo/", golangci.OriginalURIBaseIDs[SrcRoot].URI)
+
+	require.Len(t, golangci.Results, 3)
+	first := golangci.Results[0]
+	assert.Equal(t, "unused", first.RuleID)
+	assert.Equal(t, 0, *first.RuleIndex)
+	assert.Equal(t, LevelError, first.Level)
+	assert.Equal(t, "unused variable", first.Message.Text)
+	assert.Equal(t, ArtifactLocation{URI: "pkg/a.go", URIBaseID: SrcRoot}, first.Locations[0].PhysicalLocation.ArtifactLocation)
+	assert.Equal(t, &Region{StartLine: 12, StartColumn: 3}, first.Locations[0].PhysicalLocation.Region)
+	assert.Equal(t, map[string]any{"fixable": true, "fixApplicability": "safe"}, first.Properties)
+
+	assert.Equal(t, "pkg/b.go", golangci.Results[1].Locations[0].PhysicalLocation.ArtifactLocation.URI)
+	assert.Equal(t, LevelWarning, golangci.Results[1].Level)
+	assert.Nil(t, golangci.Results[1].Properties)
+
+	third := golangci.Results[2]
+	assert.Equal(t, "sub/c.go", third.Locations[0].PhysicalLocation.ArtifactLocation.URI)
+	assert.Equal(t, LevelNote, third.Level)
+	assert.Equal(t, "godot", third.Message.Text, "falls back to the rule id without a message")
+
+	ruff := log.Runs[1]
+	assert.Equal(t, "ruff", ruff.Tool.Driver.Name)
+	assert.Empty(t, ruff.Results)
+	require.Len(t, ruff.Invocations, 1)
+	assert.False(t, ruff.Invocations[0].ExecutionSuccessful)
+	assert.Equal(t, "ruff crashed", ruff.Invocations[0].ToolExecutionNotifications[0].Message.Text)
+}
+
+func TestFromLintCleanRunKeepsEmptyResults(t *testing.T) {
+	log := FromLint([]*linters.LinterResult{{Linter: "vale", Success: true}}, "/repo")
+	out, err := log.JSON()
+	require.NoError(t, err)
+
+	var decoded map[string]any
+	require.NoError(t, json.Unmarshal([]byte(out), &decoded))
+	runs := decoded["runs"].([]any)
+	require.Len(t, runs, 1)
+	assert.Equal(t, []any{}, runs[0].(map[string]any)["results"])
+}
+
+func TestFromLintRuleFallsBackToLinter(t *testing.T) {
+	log := FromLint([]*linters.LinterResult{{
+		Linter:     "betterleaks",
+		Violations: []models.Violation{{File: "x.env", Message: models.StringPtr("secret")}},
+	}}, "")
+	require.Len(t, log.Runs[0].Results, 1)
+	assert.Equal(t, "betterleaks", log.Runs[0].Results[0].RuleID)
+	assert.Nil(t, log.Runs[0].Results[0].Locations[0].PhysicalLocation.Region)
+	assert.Nil(t, log.Runs[0].OriginalURIBaseIDs)
+}
+
+func TestFromVerifyReportsFailedChecks(t *testing.T) {
+	result := &verify.VerifyResult{
+		Checks: map[string]verify.CheckResult{
+			"tests-added": {Pass: false, Evidence: []verify.Evidence{
+				{File: "pkg/a.go", Line: 10, Message: "new branch has no test"},
+				{File: "pkg/b.go", Message: "new function has no test"},
+			}},
+			"no-hardcoded-secrets": {Pass: false},
+			"todos-resolved":       {Pass: true, Evidence: []verify.Evidence{{File: "x.go", Message: "ok"}}},
+		},
+		Completeness: verify.CompletenessResult{Pass: false, Summary: "docs missing"},
+	}
+
+	log := FromVerify(result, "/repo")
+	require.Len(t, log.Runs, 1)
+	run := log.Runs[0]
+	assert.Equal(t, VerifyTool, run.Tool.Driver.Name)
+
+	var ids []string
+	for _, r := range run.Tool.Driver.Rules {
+		ids = append(ids, r.ID)
+	}
+	assert.Equal(t, []string{"no-hardcoded-secrets", "tests-added", "completeness"}, ids)
+	assert.Equal(t, "security", run.Tool.Driver.Rules[0].Properties["category"])
+	assert.Equal(t, "No API keys, passwords, tokens committed", run.Tool.Driver.Rules[0].ShortDescription.Text)
+
+	require.Len(t, run.Results, 4)
+	assert.Equal(t, "No API keys, passwords, tokens committed", run.Results[0].Message.Text)
+	assert.Empty(t, run.Results[0].Locations)
+	assert.Equal(t, "new branch has no test", run.Results[1].Message.Text)
+	assert.Equal(t, "pkg/a.go", run.Results[1].Locations[0].PhysicalLocation.ArtifactLocation.URI)
+	assert.Equal(t, 10, run.Results[1].Locations[0].PhysicalLocation.Region.StartLine)
+	assert.Equal(t, 1, *run.Results[2].RuleIndex)
+	assert.Equal(t, "docs missing", run.Results[3].Message.Text)
+}
+
+func TestFromVerifyNilResult(t *testing.T) {
+	log := FromVerify(nil, "")
+	require.Len(t, log.Runs, 1)
+	assert.Empty(t, log.Runs[0].Results)
+}
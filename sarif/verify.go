@@ -0,0 +1,86 @@
+package sarif
+
+import (
+	"sort"
+
+	"github.com/flanksource/gavel/verify"
+)
+
+// VerifyTool is the driver name of the run FromVerify produces.
+const VerifyTool = "gavel-verify"
+
+// FromVerify builds a single run with one result per piece of evidence on a
+// failed check (or one unlocated result when a check failed without
+// evidence). A failed completeness assessment is reported the same way under
+// the "completeness" rule. Rule descriptions come from the checks the review
+// evaluated, custom ones included.
+func FromVerify(result *verify.VerifyResult, root string) *Log {
+	run := Run{
+		Tool:               Tool{Driver: Driver{Name: VerifyTool}},
+		OriginalURIBaseIDs: rootBase(root),
+		Invocations:        []Invocation{{ExecutionSuccessful: true}},
+		Results:            []Result{},
+	}
+	if result == nil {
+		return NewLog(run)
+	}
+
+	rules := map[string]int{}
+	addRule := func(id string) int {
+		if i, ok := rules[id]; ok {
+			return i
+		}
+		desc := ReportingDescriptor{ID: id, DefaultConfiguration: &Configuration{Level: LevelError}}
+		if c, ok := result.CheckByID(id); ok {
+			desc.ShortDescription = &Message{Text: c.Description}
+			desc.Properties = map[string]any{"category": c.Category}
+		}
+		run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, desc)
+		rules[id] = len(run.Tool.Driver.Rules) - 1
+		return rules[id]
+	}
+	addFailure := func(id, fallback string, evidence []verify.Evidence) {
+		index := addRule(id)
+		if len(evidence) == 0 {
+			run.Results = append(run.Results, Result{
+				RuleID: id, RuleIndex: &index, Level: LevelError,
+				Message: Message{Text: fallback},
+			})
+			return
+		}
+		for _, e := range evidence {
+			text := e.Message
+			if text == "" {
+				text = fallback
+			}
+			run.Results = append(run.Results, Result{
+				RuleID: id, RuleIndex: &index, Level: LevelError,
+				Message:   Message{Text: text},
+				Locations: location(e.File, root, root, e.Line, 0, nil),
+			})
+		}
+	}
+
+	ids := make([]string, 0, len(result.Checks))
+	for id, c := range result.Checks {
+		if !c.Pass {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		fallback := id
+		if c, ok := result.CheckByID(id); ok {
+			fallback = c.Description
+		}
+		addFailure(id, fallback, result.Checks[id].Evidence)
+	}
+	if !result.Completeness.Pass && (result.Completeness.Summary != "" || len(result.Completeness.Evidence) > 0) {
+		summary := result.Completeness.Summary
+		if summary == "" {
+			summary = "The change is incomplete"
+		}
+		addFailure("completeness", summary, result.Completeness.Evidence)
+	}
+	return NewLog(run)
+}
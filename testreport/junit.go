@@ -0,0 +1,144 @@
+package testreport
+
+import (
+	"encoding/xml"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// JUnitReport is a JUnit XML document in the shape Jenkins, GitLab and the
+// GitHub test-reporter actions read: one <testsuite> per suite, in the order
+// the suites first appear.
+type JUnitReport struct {
+	XMLName  xml.Name     `xml:"testsuites"`
+	Name     string       `xml:"name,attr"`
+	Tests    int          `xml:"tests,attr"`
+	Failures int          `xml:"failures,attr"`
+	Errors   int          `xml:"errors,attr"`
+	Skipped  int          `xml:"skipped,attr"`
+	Time     string       `xml:"time,attr"`
+	Suites   []JUnitSuite `xml:"testsuite"`
+}
+
+type JUnitSuite struct {
+	Name     string      `xml:"name,attr"`
+	Tests    int         `xml:"tests,attr"`
+	Failures int         `xml:"failures,attr"`
+	Errors   int         `xml:"errors,attr"`
+	Skipped  int         `xml:"skipped,attr"`
+	Time     string      `xml:"time,attr"`
+	Cases    []JUnitCase `xml:"testcase"`
+
+	duration time.Duration
+}
+
+type JUnitCase struct {
+	Name      string       `xml:"name,attr"`
+	ClassName string       `xml:"classname,attr"`
+	File      string       `xml:"file,attr,omitempty"`
+	Line      int          `xml:"line,attr,omitempty"`
+	Time      string       `xml:"time,attr"`
+	Failure   *JUnitResult `xml:"failure"`
+	Error     *JUnitResult `xml:"error"`
+	Skipped   *JUnitResult `xml:"skipped"`
+	SystemOut *JUnitOutput `xml:"system-out"`
+	SystemErr *JUnitOutput `xml:"system-err"`
+}
+
+// JUnitResult is the body of a <failure>, <error> or <skipped> element.
+type JUnitResult struct {
+	Message string `xml:"message,attr,omitempty"`
+	Text    string `xml:",cdata"`
+}
+
+// JUnitOutput is captured stdout or stderr. Bodies are written as CDATA so
+// multi-line output stays readable in the raw report.
+type JUnitOutput struct {
+	Text string `xml:",cdata"`
+}
+
+// JUnit groups cases into suites and totals them.
+func JUnit(cases []Case) *JUnitReport {
+	report := &JUnitReport{Name: "gavel", Suites: []JUnitSuite{}}
+	index := map[string]int{}
+	var total time.Duration
+	for _, c := range cases {
+		i, ok := index[c.Suite]
+		if !ok {
+			i = len(report.Suites)
+			index[c.Suite] = i
+			report.Suites = append(report.Suites, JUnitSuite{Name: c.Suite})
+		}
+		suite := &report.Suites[i]
+		jc := JUnitCase{
+			Name:      c.Name,
+			ClassName: c.Suite,
+			File:      c.File,
+			Line:      c.Line,
+			Time:      seconds(c.Duration),
+			SystemOut: output(c.Stdout),
+			SystemErr: output(c.Stderr),
+		}
+		result := &JUnitResult{Message: c.Message, Text: xmlText(c.Details)}
+		switch c.Status {
+		case StatusFailed:
+			jc.Failure = result
+			suite.Failures++
+		case StatusError:
+			jc.Error = result
+			suite.Errors++
+		case StatusSkipped:
+			jc.Skipped = &JUnitResult{Message: c.Message}
+			suite.Skipped++
+		}
+		suite.Cases = append(suite.Cases, jc)
+		suite.Tests++
+		suite.duration += c.Duration
+		total += c.Duration
+	}
+	for i := range report.Suites {
+		suite := &report.Suites[i]
+		suite.Time = seconds(suite.duration)
+		report.Tests += suite.Tests
+		report.Failures += suite.Failures
+		report.Errors += suite.Errors
+		report.Skipped += suite.Skipped
+	}
+	report.Time = seconds(total)
+	return report
+}
+
+func (r *JUnitReport) XML() (string, error) {
+	data, err := xml.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return xml.Header + string(data) + "\n", nil
+}
+
+func output(s string) *JUnitOutput {
+	if s == "" {
+		return nil
+	}
+	return &JUnitOutput{Text: xmlText(s)}
+}
+
+// xmlText replaces characters XML 1.0 cannot carry. Attributes and chardata
+// get this from encoding/xml, but CDATA sections are written verbatim.
+func xmlText(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r == '\t' || r == '\n' || r == '\r',
+			r >= 0x20 && r <= 0xD7FF,
+			r >= 0xE000 && r <= 0xFFFD,
+			r >= 0x10000 && r <= 0x10FFFF:
+			return r
+		}
+		return '\uFFFD'
+	}, s)
+}
+
+func seconds(d time.Duration) string {
+	return strconv.FormatFloat(d.Seconds(), 'f', 3, 64)
+}
@@ -0,0 +1,207 @@
+// Package testreport renders gavel test, fixture and lint results as JUnit
+// XML and TAP, the formats CI systems read to annotate a build with
+// per-test results.
+package testreport
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/flanksource/gavel/linters"
+	"github.com/flanksource/gavel/models"
+	"github.com/flanksource/gavel/testrunner/parsers"
+	"github.com/flanksource/gavel/utils"
+)
+
+// LintSuite is the suite every lint finding is reported under.
+const LintSuite = "lint"
+
+type Status string
+
+const (
+	StatusPassed  Status = "passed"
+	StatusFailed  Status = "failed"
+	StatusError   Status = "error"
+	StatusSkipped Status = "skipped"
+)
+
+// Case is one reported test: a leaf of the test tree, a lint violation or
+// a linter that ran clean.
+type Case struct {
+	Suite    string
+	Name     string
+	File     string
+	Line     int
+	Duration time.Duration
+	Status   Status
+	// Message is the one-line reason for a failure, error or skip.
+	Message string
+	// Details is the full failure output.
+	Details string
+	Stdout  string
+	Stderr  string
+}
+
+// Collect flattens tests into cases, one per leaf of the tree, followed by
+// one case per lint violation under LintSuite. root is the directory lint
+// file paths are made relative to.
+//
+// A test's suite is its package joined with its Suite path. Tests without a
+// package (fixtures) use the names of the nodes above them instead.
+func Collect(tests []parsers.Test, lint []*linters.LinterResult, root string) []Case {
+	var cases []Case
+	var walk func(tests []parsers.Test, parents []string)
+	walk = func(tests []parsers.Test, parents []string) {
+		for _, t := range tests {
+			if len(t.Children) > 0 {
+				walk(t.Children, append(parents[:len(parents):len(parents)], strings.TrimSuffix(t.Name, "/")))
+				continue
+			}
+			cases = append(cases, testCase(t, parents))
+		}
+	}
+	walk(tests, nil)
+	return append(cases, lintCases(lint, root)...)
+}
+
+func testCase(t parsers.Test, parents []string) Case {
+	c := Case{
+		Suite:    suiteName(t, parents),
+		Name:     t.Name,
+		File:     t.File,
+		Line:     t.Line,
+		Duration: t.Duration,
+		Status:   StatusPassed,
+		Details:  strings.TrimSpace(parsers.StripANSI(t.Message)),
+		Stdout:   parsers.StripANSI(t.Stdout),
+		Stderr:   parsers.StripANSI(t.Stderr),
+	}
+	c.Message = utils.FirstLine(c.Details)
+	switch {
+	case t.Failed && t.Quarantined:
+		// Quarantined failures never fail the build, so CI must not see them
+		// as failures either.
+		c.Status = StatusSkipped
+		c.Message = strings.TrimSpace("quarantined: " + c.Message)
+	case t.TimedOut:
+		c.Status = StatusError
+		if c.Message == "" {
+			c.Message = "timed out"
+		}
+	case t.Failed:
+		c.Status = StatusFailed
+		if c.Message == "" {
+			c.Message = "failed"
+		}
+	case t.Skipped:
+		c.Status = StatusSkipped
+	case t.Pending || t.Running:
+		c.Status = StatusSkipped
+		c.Message = "not run"
+	}
+	return c
+}
+
+func suiteName(t parsers.Test, parents []string) string {
+	pkg := t.Package
+	if pkg == "" {
+		pkg = strings.TrimPrefix(t.PackagePath, "./")
+	}
+	if pkg == "" && len(parents) > 0 {
+		pkg = strings.Join(parents, "/")
+	}
+	if pkg == "" {
+		pkg = string(t.Framework)
+	}
+	if pkg == "" {
+		pkg = "tests"
+	}
+	return strings.Join(append([]string{pkg}, t.Suite...), "/")
+}
+
+// lintCases reports each violation as a failed case. Linters that ran clean
+// get a single passing case, and linters that crashed or timed out an error
+// case, so the suite shows every linter that ran.
+func lintCases(results []*linters.LinterResult, root string) []Case {
+	var cases []Case
+	for _, r := range results {
+		if r == nil || r.Skipped {
+			continue
+		}
+		if r.Error != "" || r.TimedOut {
+			msg := strings.TrimSpace(parsers.StripANSI(r.Error))
+			if msg == "" {
+				msg = "timed out"
+			}
+			cases = append(cases, Case{
+				Suite:    LintSuite,
+				Name:     r.Linter,
+				Duration: r.Duration,
+				Status:   StatusError,
+				Message:  utils.FirstLine(msg),
+				Details:  msg,
+			})
+		} else if len(r.Violations) == 0 {
+			cases = append(cases, Case{Suite: LintSuite, Name: r.Linter, Duration: r.Duration, Status: StatusPassed})
+		}
+		for _, v := range r.Violations {
+			file := lintFile(v.File, r.WorkDir, root)
+			rule := ruleName(v, r.Linter)
+			msg := rule
+			if v.Message != nil && strings.TrimSpace(*v.Message) != "" {
+				msg = strings.TrimSpace(parsers.StripANSI(*v.Message))
+			}
+			cases = append(cases, Case{
+				Suite:   LintSuite,
+				Name:    fmt.Sprintf("%s %s %s:%d", r.Linter, rule, file, v.Line),
+				File:    file,
+				Line:    v.Line,
+				Status:  StatusFailed,
+				Message: utils.FirstLine(msg),
+				Details: violationDetails(msg, v.Code),
+			})
+		}
+	}
+	return cases
+}
+
+// lintFile resolves file against workDir and expresses it relative to root
+// when it lies inside it.
+func lintFile(file, workDir, root string) string {
+	if file == "" {
+		return ""
+	}
+	if !filepath.IsAbs(file) && workDir != "" {
+		file = filepath.Join(workDir, file)
+	}
+	if filepath.IsAbs(file) && root != "" {
+		absRoot, _ := filepath.Abs(root)
+		if rel, err := filepath.Rel(absRoot, file); err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			file = rel
+		}
+	}
+	return filepath.ToSlash(file)
+}
+
+func ruleName(v models.Violation, linter string) string {
+	if v.Rule != nil {
+		if v.Rule.Method != "" {
+			return v.Rule.Method
+		}
+		if v.Rule.Pattern != "" {
+			return v.Rule.Pattern
+		}
+	}
+	return linter
+}
+
+// violationDetails appends the offending source, when the linter captured
+// it, to msg.
+func violationDetails(msg string, code *string) string {
+	if code == nil || strings.TrimSpace(*code) == "" {
+		return msg
+	}
+	return msg + "\n\n" + strings.TrimRight(*code, "\n")
+}
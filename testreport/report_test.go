@@ -0,0 +1,157 @@
+package testreport
+
+import (
+	"encoding/xml"
+	"testing"
+	"time"
+
+	"github.com/flanksource/gavel/linters"
+	"github.com/flanksource/gavel/models"
+	"github.com/flanksource/gavel/testrunner/parsers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func sampleTests() []parsers.Test {
+	return []parsers.Test{
+		{
+			Name:        "pkg/",
+			PackagePath: "pkg",
+			Children: parsers.Tests{
+				{Name: "TestAdd", Package: "example.com/pkg", File: "pkg/add_test.go", Line: 12, Framework: parsers.GoTest, Duration: 1500 * time.Millisecond, Passed: true},
+				{Name: "TestSub", Package: "example.com/pkg", Framework: parsers.GoTest, Failed: true, Message: "\x1b[31msub_test.go:9: want 1, got 2\x1b[0m\nmore", Stdout: "=== RUN TestSub\n"},
+				{Name: "TestSkip", Package: "example.com/pkg", Framework: parsers.GoTest, Skipped: true, Message: "needs docker"},
+				{Name: "TestSlow", Package: "example.com/pkg", Framework: parsers.GoTest, Failed: true, TimedOut: true},
+				{Name: "TestFlaky", Package: "example.com/pkg", Framework: parsers.GoTest, Failed: true, Quarantined: true, Message: "boom"},
+			},
+		},
+		{
+			Name: "Outer",
+			Children: parsers.Tests{
+				{Name: "adds", Package: "example.com/spec", Suite: []string{"Outer", "Inner"}, Framework: parsers.Ginkgo, Passed: true},
+			},
+		},
+		{
+			Name:      "cli.md",
+			Framework: "fixture",
+			Children: parsers.Tests{
+				{Name: "Section", Framework: "fixture", Children: parsers.Tests{
+					{Name: "prints help", Framework: "fixture", Passed: true, Stderr: "warning: debug\n"},
+				}},
+			},
+		},
+	}
+}
+
+func sampleLint() []*linters.LinterResult {
+	return []*linters.LinterResult{
+		{
+			Linter:  "golangci-lint",
+			WorkDir: "/repo/pkg",
+			Violations: []models.Violation{
+				{File: "a.go", Line: 4, Message: models.StringPtr("unused variable x"), Rule: &models.Rule{Method: "unused"}, Code: models.StringPtr("x := 1")},
+			},
+		},
+		{Linter: "ruff", WorkDir: "/repo", Success: true, Duration: 2 * time.Second},
+		{Linter: "eslint", Skipped: true},
+		{Linter: "vale", WorkDir: "/repo", TimedOut: true},
+	}
+}
+
+func TestCollect(t *testing.T) {
+	cases := Collect(sampleTests(), sampleLint(), "/repo")
+	require.Len(t, cases, 10)
+
+	add := cases[0]
+	assert.Equal(t, "example.com/pkg", add.Suite)
+	assert.Equal(t, StatusPassed, add.Status)
+	assert.Equal(t, "pkg/add_test.go", add.File)
+	assert.Equal(t, 12, add.Line)
+
+	sub := cases[1]
+	assert.Equal(t, StatusFailed, sub.Status)
+	assert.Equal(t, "sub_test.go:9: want 1, got 2", sub.Message)
+	assert.Equal(t, "sub_test.go:9: want 1, got 2\nmore", sub.Details)
+
+	assert.Equal(t, StatusSkipped, cases[2].Status)
+	assert.Equal(t, "needs docker", cases[2].Message)
+	assert.Equal(t, StatusError, cases[3].Status)
+	assert.Equal(t, "timed out", cases[3].Message)
+	assert.Equal(t, StatusSkipped, cases[4].Status, "quarantined failures do not fail the report")
+	assert.Equal(t, "quarantined: boom", cases[4].Message)
+
+	assert.Equal(t, "example.com/spec/Outer/Inner", cases[5].Suite)
+	assert.Equal(t, "cli.md/Section", cases[6].Suite, "fixtures are grouped by their file and section")
+
+	violation := cases[7]
+	assert.Equal(t, LintSuite, violation.Suite)
+	assert.Equal(t, "golangci-lint unused pkg/a.go:4", violation.Name)
+	assert.Equal(t, StatusFailed, violation.Status)
+	assert.Equal(t, "unused variable x", violation.Message)
+	assert.Equal(t, "unused variable x\n\nx := 1", violation.Details)
+
+	assert.Equal(t, Case{Suite: LintSuite, Name: "ruff", Duration: 2 * time.Second, Status: StatusPassed}, cases[8])
+	assert.Equal(t, StatusError, cases[9].Status)
+	assert.Equal(t, "vale", cases[9].Name)
+}
+
+func TestJUnit(t *testing.T) {
+	out, err := JUnit(Collect(sampleTests(), sampleLint(), "/repo")).XML()
+	require.NoError(t, err)
+	assert.Contains(t, out, xml.Header)
+	assert.NotContains(t, out, "\x1b")
+
+	var report JUnitReport
+	require.NoError(t, xml.Unmarshal([]byte(out), &report))
+	assert.Equal(t, 10, report.Tests)
+	assert.Equal(t, 2, report.Failures)
+	assert.Equal(t, 2, report.Errors)
+	assert.Equal(t, 2, report.Skipped)
+	assert.Equal(t, "3.500", report.Time)
+
+	var names []string
+	for _, s := range report.Suites {
+		names = append(names, s.Name)
+	}
+	assert.Equal(t, []string{"example.com/pkg", "example.com/spec/Outer/Inner", "cli.md/Section", LintSuite}, names)
+
+	pkg := report.Suites[0]
+	assert.Equal(t, 5, pkg.Tests)
+	assert.Equal(t, "1.500", pkg.Time)
+	assert.Equal(t, "1.500", pkg.Cases[0].Time)
+	assert.Equal(t, "pkg/add_test.go", pkg.Cases[0].File)
+	require.NotNil(t, pkg.Cases[1].Failure)
+	assert.Equal(t, "sub_test.go:9: want 1, got 2", pkg.Cases[1].Failure.Message)
+	require.NotNil(t, pkg.Cases[1].SystemOut)
+	assert.Equal(t, "=== RUN TestSub\n", pkg.Cases[1].SystemOut.Text)
+	assert.Nil(t, pkg.Cases[0].SystemOut)
+	require.NotNil(t, pkg.Cases[2].Skipped)
+	require.NotNil(t, pkg.Cases[3].Error)
+	require.NotNil(t, report.Suites[2].Cases[0].SystemErr)
+	assert.Equal(t, "warning: debug\n", report.Suites[2].Cases[0].SystemErr.Text)
+}
+
+func TestTAP(t *testing.T) {
+	out, err := TAP([]Case{
+		{Suite: "pkg", Name: "TestAdd", Status: StatusPassed},
+		{Suite: "pkg", Name: "TestSkip #2", Status: StatusSkipped, Message: "needs docker"},
+		{Suite: "pkg", Name: "TestSub", Status: StatusFailed, Message: "want 1", Details: "want 1\ngot 2", File: "sub_test.go", Line: 9, Duration: 20 * time.Millisecond},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, `TAP version 13
+1..3
+ok 1 - pkg/TestAdd
+ok 2 - pkg/TestSkip \#2 # SKIP needs docker
+not ok 3 - pkg/TestSub
+  ---
+  message: want 1
+  severity: failed
+  details: |-
+    want 1
+    got 2
+  file: sub_test.go
+  line: 9
+  duration_ms: 20
+  ...
+`, out)
+}
@@ -0,0 +1,74 @@
+package testreport
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+)
+
+// TAP renders cases as a TAP version 13 stream. Skipped cases carry a SKIP
+// directive, and failures and errors a YAML diagnostic block with the
+// message, location and captured output.
+func TAP(cases []Case) (string, error) {
+	var out strings.Builder
+	out.WriteString("TAP version 13\n")
+	fmt.Fprintf(&out, "1..%d\n", len(cases))
+	for i, c := range cases {
+		desc := tapEscape(c.Suite + "/" + c.Name)
+		switch c.Status {
+		case StatusPassed:
+			fmt.Fprintf(&out, "ok %d - %s\n", i+1, desc)
+		case StatusSkipped:
+			fmt.Fprintf(&out, "ok %d - %s # SKIP %s\n", i+1, desc, tapEscape(c.Message))
+		default:
+			fmt.Fprintf(&out, "not ok %d - %s\n", i+1, desc)
+			diag, err := tapDiagnostic(c)
+			if err != nil {
+				return "", err
+			}
+			out.WriteString(diag)
+		}
+	}
+	return out.String(), nil
+}
+
+func tapDiagnostic(c Case) (string, error) {
+	fields := yaml.MapSlice{{Key: "message", Value: c.Message}, {Key: "severity", Value: string(c.Status)}}
+	if c.Details != "" && c.Details != c.Message {
+		fields = append(fields, yaml.MapItem{Key: "details", Value: c.Details})
+	}
+	if c.File != "" {
+		fields = append(fields, yaml.MapItem{Key: "file", Value: c.File})
+	}
+	if c.Line > 0 {
+		fields = append(fields, yaml.MapItem{Key: "line", Value: c.Line})
+	}
+	if c.Duration > 0 {
+		fields = append(fields, yaml.MapItem{Key: "duration_ms", Value: c.Duration.Milliseconds()})
+	}
+	if c.Stdout != "" {
+		fields = append(fields, yaml.MapItem{Key: "stdout", Value: c.Stdout})
+	}
+	if c.Stderr != "" {
+		fields = append(fields, yaml.MapItem{Key: "stderr", Value: c.Stderr})
+	}
+	data, err := yaml.MarshalWithOptions(fields, yaml.UseLiteralStyleIfMultiline(true))
+	if err != nil {
+		return "", err
+	}
+	var out strings.Builder
+	out.WriteString("  ---\n")
+	for _, line := range strings.Split(strings.TrimRight(string(data), "\n"), "\n") {
+		out.WriteString("  " + line + "\n")
+	}
+	out.WriteString("  ...\n")
+	return out.String(), nil
+}
+
+// tapEscape keeps a description on one line and escapes '#', which would
+// otherwise start a directive.
+func tapEscape(s string) string {
+	s = strings.Join(strings.Fields(s), " ")
+	return strings.ReplaceAll(s, "#", `\#`)
+}
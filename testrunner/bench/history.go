@@ -0,0 +1,471 @@
+package bench
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/flanksource/clicky"
+	"github.com/flanksource/clicky/api"
+	"github.com/flanksource/clicky/api/icons"
+)
+
+// HistoryFile is the append-only store of benchmark results, one JSON record
+// per line, kept next to the run snapshots in .gavel/.
+const HistoryFile = "bench-history.jsonl"
+
+// HistoryRecord is one benchmark run appended to the history store.
+type HistoryRecord struct {
+	Commit      string     `json:"commit"`
+	Uncommitted string     `json:"uncommitted,omitempty"` // checksum of local changes on top of Commit
+	Subject     string     `json:"subject,omitempty"`
+	CommitTime  time.Time  `json:"commit_time,omitempty"` // committer date of Commit
+	Time        time.Time  `json:"time"`                  // when the benchmarks ran
+	Runs        []BenchRun `json:"runs"`
+}
+
+// TrendPoint is one benchmark at one commit. Runs repeated at the same commit
+// (and the same uncommitted state) are pooled into one point.
+type TrendPoint struct {
+	Commit      string    `json:"commit"`
+	Uncommitted string    `json:"uncommitted,omitempty"`
+	Subject     string    `json:"subject,omitempty"`
+	Time        time.Time `json:"time"`
+	Mean        float64   `json:"mean"`             // ns/op
+	Stddev      float64   `json:"stddev,omitempty"` // ns/op
+	Samples     int       `json:"samples"`
+	BytesPerOp  int64     `json:"bytes_per_op,omitempty"`
+	AllocsPerOp int64     `json:"allocs_per_op,omitempty"`
+
+	samples []float64
+}
+
+// ChangePoint is a significant shift in ns/op between the points before Index
+// and the points from Index on. Commit is the first commit after the shift.
+type ChangePoint struct {
+	Index      int     `json:"index"`
+	Commit     string  `json:"commit"`
+	Subject    string  `json:"subject,omitempty"`
+	BeforeMean float64 `json:"before_mean"`
+	AfterMean  float64 `json:"after_mean"`
+	DeltaPct   float64 `json:"delta_pct"`
+	PValue     float64 `json:"p_value"`
+	Regression bool    `json:"regression,omitempty"`
+}
+
+// BenchTrend is the history of one benchmark across commits, oldest first.
+type BenchTrend struct {
+	Name         string        `json:"name"`
+	Package      string        `json:"package,omitempty"`
+	Points       []TrendPoint  `json:"points"`
+	ChangePoints []ChangePoint `json:"change_points,omitempty"`
+}
+
+// BenchHistory is the per-benchmark trend report built from the history store.
+type BenchHistory struct {
+	Threshold float64      `json:"threshold"`
+	Commits   int          `json:"commits"`
+	Trends    []BenchTrend `json:"trends"`
+}
+
+// AppendHistory appends rec to the history store at path, creating the file
+// and its directory on first use.
+func AppendHistory(path string, rec HistoryRecord) error {
+	if rec.Commit == "" {
+		return errors.New("bench.AppendHistory: commit is required")
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("create %s: %w", filepath.Dir(path), err)
+	}
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("open %s: %w", path, err)
+	}
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("append %s: %w", path, err)
+	}
+	return f.Close()
+}
+
+// LoadHistory reads every record of the history store at path in the order
+// they were appended. A missing store yields no records and no error.
+func LoadHistory(path string) ([]HistoryRecord, error) {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var records []HistoryRecord
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 64*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		var rec HistoryRecord
+		if err := json.Unmarshal([]byte(text), &rec); err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, line, err)
+		}
+		records = append(records, rec)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read %s: %w", path, err)
+	}
+	return records, nil
+}
+
+// BuildHistory groups records into per-benchmark trends over the last limit
+// commits (all commits when limit <= 0) and detects change points in each.
+// threshold is the minimum shift in percent; pass 0 for the default (5%).
+//
+// Commits are ordered by ancestry, not by when they were benchmarked, so
+// benchmarking an older commit or a bisect step does not put it after its
+// descendants. ancestry lists commits oldest first, as printed by
+// `git rev-list --topo-order --reverse`; commits missing from it (or every
+// commit, when it is empty) are placed by their commit time.
+func BuildHistory(records []HistoryRecord, threshold float64, limit int, ancestry []string) BenchHistory {
+	if threshold <= 0 {
+		threshold = DefaultThreshold
+	}
+
+	order := orderCommits(records, ancestry)
+	if limit > 0 && len(order) > limit {
+		order = order[len(order)-limit:]
+	}
+	position := make(map[commitKey]int, len(order))
+	for i, k := range order {
+		position[k] = i
+	}
+
+	type benchKey struct{ pkg, name string }
+	trends := map[benchKey]*BenchTrend{}
+	points := map[benchKey]map[int]*TrendPoint{}
+	for _, rec := range records {
+		pos, ok := position[commitKey{rec.Commit, rec.Uncommitted}]
+		if !ok {
+			continue
+		}
+		for _, run := range rec.Runs {
+			if len(run.Samples) == 0 {
+				continue
+			}
+			bk := benchKey{run.Package, run.Name}
+			if trends[bk] == nil {
+				trends[bk] = &BenchTrend{Name: run.Name, Package: run.Package}
+				points[bk] = map[int]*TrendPoint{}
+			}
+			p := points[bk][pos]
+			if p == nil {
+				p = &TrendPoint{Commit: rec.Commit, Uncommitted: rec.Uncommitted, Time: rec.Time}
+				points[bk][pos] = p
+			}
+			p.Subject = rec.Subject
+			p.samples = append(p.samples, run.Samples...)
+			p.BytesPerOp = run.BytesPerOp
+			p.AllocsPerOp = run.AllocsPerOp
+		}
+	}
+
+	history := BenchHistory{Threshold: threshold, Commits: len(order)}
+	for bk, trend := range trends {
+		positions := make([]int, 0, len(points[bk]))
+		for pos := range points[bk] {
+			positions = append(positions, pos)
+		}
+		sort.Ints(positions)
+		for _, pos := range positions {
+			p := points[bk][pos]
+			p.Mean = Mean(p.samples)
+			p.Stddev = Stddev(p.samples)
+			p.Samples = len(p.samples)
+			trend.Points = append(trend.Points, *p)
+		}
+		trend.ChangePoints = detectChangePoints(trend.Points, threshold)
+		history.Trends = append(history.Trends, *trend)
+	}
+	sort.Slice(history.Trends, func(i, j int) bool {
+		if history.Trends[i].Package != history.Trends[j].Package {
+			return history.Trends[i].Package < history.Trends[j].Package
+		}
+		return history.Trends[i].Name < history.Trends[j].Name
+	})
+	return history
+}
+
+type commitKey struct{ commit, uncommitted string }
+
+// orderCommits returns the distinct commit states in records, oldest first.
+// Commits found in ancestry keep its order; the rest are sorted by commit
+// time (falling back to the run time for records without one) and merged in
+// before the first ancestry commit that is newer. Uncommitted states of the
+// same commit follow it in the order they ran.
+func orderCommits(records []HistoryRecord, ancestry []string) []commitKey {
+	rank := make(map[string]int, len(ancestry))
+	for i, sha := range ancestry {
+		rank[sha] = i
+	}
+	type entry struct {
+		key     commitKey
+		when    time.Time
+		ran     time.Time
+		ranked  bool
+		rankPos int
+	}
+	index := map[commitKey]*entry{}
+	var ranked, unranked []*entry
+	for _, rec := range records {
+		k := commitKey{rec.Commit, rec.Uncommitted}
+		if e, ok := index[k]; ok {
+			if rec.Time.Before(e.ran) {
+				e.ran = rec.Time
+			}
+			continue
+		}
+		e := &entry{key: k, when: rec.CommitTime, ran: rec.Time}
+		if e.when.IsZero() {
+			e.when = rec.Time
+		}
+		e.rankPos, e.ranked = rank[rec.Commit]
+		index[k] = e
+		if e.ranked {
+			ranked = append(ranked, e)
+		} else {
+			unranked = append(unranked, e)
+		}
+	}
+	sameCommit := func(a, b *entry) bool {
+		if (a.key.uncommitted == "") != (b.key.uncommitted == "") {
+			return a.key.uncommitted == ""
+		}
+		return a.ran.Before(b.ran)
+	}
+	sort.SliceStable(ranked, func(i, j int) bool {
+		if ranked[i].rankPos != ranked[j].rankPos {
+			return ranked[i].rankPos < ranked[j].rankPos
+		}
+		return sameCommit(ranked[i], ranked[j])
+	})
+	sort.SliceStable(unranked, func(i, j int) bool {
+		a, b := unranked[i], unranked[j]
+		if a.key.commit != b.key.commit && !a.when.Equal(b.when) {
+			return a.when.Before(b.when)
+		}
+		if a.key.commit != b.key.commit {
+			return a.key.commit < b.key.commit
+		}
+		return sameCommit(a, b)
+	})
+
+	order := make([]commitKey, 0, len(index))
+	for len(ranked) > 0 || len(unranked) > 0 {
+		if len(unranked) == 0 || (len(ranked) > 0 && !unranked[0].when.Before(ranked[0].when)) {
+			order = append(order, ranked[0].key)
+			ranked = ranked[1:]
+			continue
+		}
+		order = append(order, unranked[0].key)
+		unranked = unranked[1:]
+	}
+	return order
+}
+
+// detectChangePoints finds shifts in ns/op along points by binary
+// segmentation: the split with the lowest Welch t-test p-value that is both
+// significant and larger than threshold percent is kept, then each side is
+// searched again. Points need -count >= 2 samples for a p-value.
+func detectChangePoints(points []TrendPoint, threshold float64) []ChangePoint {
+	var out []ChangePoint
+	var segment func(lo, hi int)
+	segment = func(lo, hi int) {
+		best, ok := bestSplit(points, lo, hi, threshold)
+		if !ok {
+			return
+		}
+		out = append(out, best)
+		segment(lo, best.Index)
+		segment(best.Index, hi)
+	}
+	segment(0, len(points))
+	sort.Slice(out, func(i, j int) bool { return out[i].Index < out[j].Index })
+	return out
+}
+
+func bestSplit(points []TrendPoint, lo, hi int, threshold float64) (ChangePoint, bool) {
+	var best ChangePoint
+	found := false
+	for k := lo + 1; k < hi; k++ {
+		before := pooledSamples(points[lo:k])
+		after := pooledSamples(points[k:hi])
+		if len(before) < 2 || len(after) < 2 {
+			continue
+		}
+		t, df := WelchT(before, after)
+		if df <= 0 || math.IsNaN(t) || math.IsInf(t, 0) {
+			continue
+		}
+		p := StudentTwoTailP(t, df)
+		if p >= DefaultPValue {
+			continue
+		}
+		beforeMean, afterMean := Mean(before), Mean(after)
+		if beforeMean <= 0 {
+			continue
+		}
+		delta := (afterMean - beforeMean) / beforeMean * 100
+		if math.Abs(delta) <= threshold {
+			continue
+		}
+		if found && p >= best.PValue {
+			continue
+		}
+		found = true
+		best = ChangePoint{
+			Index:      k,
+			Commit:     points[k].Commit,
+			Subject:    points[k].Subject,
+			BeforeMean: beforeMean,
+			AfterMean:  afterMean,
+			DeltaPct:   delta,
+			PValue:     p,
+			Regression: delta > 0,
+		}
+	}
+	return best, found
+}
+
+func pooledSamples(points []TrendPoint) []float64 {
+	var out []float64
+	for _, p := range points {
+		out = append(out, p.samples...)
+	}
+	return out
+}
+
+// HasRegression reports whether any trend's latest change point is a regression.
+func (h BenchHistory) HasRegression() bool {
+	for _, t := range h.Trends {
+		if n := len(t.ChangePoints); n > 0 && t.ChangePoints[n-1].Regression {
+			return true
+		}
+	}
+	return false
+}
+
+var sparkBars = []rune("▁▂▃▄▅▆▇█")
+
+// Sparkline renders values as a row of block characters scaled between their
+// minimum and maximum.
+func Sparkline(values []float64) string {
+	if len(values) == 0 {
+		return ""
+	}
+	lo, hi := values[0], values[0]
+	for _, v := range values {
+		lo = math.Min(lo, v)
+		hi = math.Max(hi, v)
+	}
+	var b strings.Builder
+	for _, v := range values {
+		i := 0
+		if hi > lo {
+			i = int((v - lo) / (hi - lo) * float64(len(sparkBars)-1))
+		}
+		b.WriteRune(sparkBars[i])
+	}
+	return b.String()
+}
+
+// Pretty renders one trend: ns/op, B/op and allocs/op sparklines followed by
+// the detected change points.
+func (t BenchTrend) Pretty() api.Text {
+	s := clicky.Text("")
+	n := len(t.ChangePoints)
+	switch {
+	case n > 0 && t.ChangePoints[n-1].Regression:
+		s = s.Append(icons.Fail, "text-red-500")
+	case n > 0:
+		s = s.Append(icons.Pass, "text-green-600")
+	default:
+		s = s.Append("·", "text-muted")
+	}
+	s = s.Space().Append(t.Name, "bold")
+	if len(t.Points) == 0 {
+		return s
+	}
+	last := t.Points[len(t.Points)-1]
+	s = s.Space().Append(fmt.Sprintf("(%d commits)", len(t.Points)), "text-muted")
+
+	var ns, bytes, allocs []float64
+	for _, p := range t.Points {
+		ns = append(ns, p.Mean)
+		bytes = append(bytes, float64(p.BytesPerOp))
+		allocs = append(allocs, float64(p.AllocsPerOp))
+	}
+	s = s.NewLine().Append("  ns/op     ", "text-muted").Append(Sparkline(ns)).Space().Append(formatNs(last.Mean))
+	if last.BytesPerOp > 0 || last.AllocsPerOp > 0 {
+		s = s.NewLine().Append("  B/op      ", "text-muted").Append(Sparkline(bytes)).Space().Append(fmt.Sprintf("%d", last.BytesPerOp))
+		s = s.NewLine().Append("  allocs/op ", "text-muted").Append(Sparkline(allocs)).Space().Append(fmt.Sprintf("%d", last.AllocsPerOp))
+	}
+	for _, cp := range t.ChangePoints {
+		style := "text-green-600"
+		label := "improved"
+		if cp.Regression {
+			style = "text-red-500 bold"
+			label = "regressed"
+		}
+		s = s.NewLine().Append("  ").Append(fmt.Sprintf("%s %+.2f%%", label, cp.DeltaPct), style).
+			Space().Append(fmt.Sprintf("%s → %s", formatNs(cp.BeforeMean), formatNs(cp.AfterMean)), "text-muted").
+			Space().Append("at").Space().Append(shortSHA(cp.Commit), "bold")
+		if cp.Subject != "" {
+			s = s.Space().Append(cp.Subject)
+		}
+		s = s.Space().Append(fmt.Sprintf("(p=%.3g)", cp.PValue), "text-muted")
+	}
+	return s
+}
+
+// Pretty renders every trend, regressed benchmarks first.
+func (h BenchHistory) Pretty() api.Text {
+	text := clicky.Text("Benchmark history", "bold").Space().
+		Append(fmt.Sprintf("(%d commits, threshold=±%.1f%%)", h.Commits, h.Threshold), "text-muted").NewLine()
+	if len(h.Trends) == 0 {
+		return text.NewLine().Append("No benchmark history recorded yet; run gavel bench run", "text-muted")
+	}
+	trends := append([]BenchTrend(nil), h.Trends...)
+	sort.SliceStable(trends, func(i, j int) bool {
+		return trends[i].regressed() && !trends[j].regressed()
+	})
+	for _, t := range trends {
+		text = text.NewLine().Add(t.Pretty()).NewLine()
+	}
+	return text
+}
+
+func (t BenchTrend) regressed() bool {
+	n := len(t.ChangePoints)
+	return n > 0 && t.ChangePoints[n-1].Regression
+}
+
+func shortSHA(sha string) string {
+	if len(sha) > 8 {
+		return sha[:8]
+	}
+	return sha
+}
@@ -0,0 +1,174 @@
+package bench
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func historyRecord(commit string, samples ...float64) HistoryRecord {
+	return HistoryRecord{
+		Commit:  commit,
+		Subject: "subject of " + commit,
+		Time:    time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		Runs:    []BenchRun{{Name: "BenchmarkFoo", Package: "example.com/pkg", Samples: samples, BytesPerOp: 64, AllocsPerOp: 2}},
+	}
+}
+
+func TestHistory_AppendAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".gavel", HistoryFile)
+
+	records, err := LoadHistory(path)
+	if err != nil || len(records) != 0 {
+		t.Fatalf("missing store: records=%v err=%v, want none", records, err)
+	}
+	for _, rec := range []HistoryRecord{historyRecord("aaa", 100, 101), historyRecord("bbb", 120, 121)} {
+		if err := AppendHistory(path, rec); err != nil {
+			t.Fatal(err)
+		}
+	}
+	records, err = LoadHistory(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 2 || records[0].Commit != "aaa" || records[1].Commit != "bbb" {
+		t.Fatalf("records = %+v, want aaa then bbb", records)
+	}
+	if err := AppendHistory(path, HistoryRecord{}); err == nil {
+		t.Error("a record without a commit should be rejected")
+	}
+}
+
+func TestBuildHistory_DetectsRegressionCommit(t *testing.T) {
+	records := []HistoryRecord{
+		historyRecord("c1", 100, 101, 99, 100),
+		historyRecord("c2", 101, 100, 99, 100),
+		historyRecord("c3", 99, 100, 101, 100),
+		historyRecord("c4", 130, 131, 129, 130),
+		historyRecord("c5", 129, 130, 131, 130),
+		historyRecord("c6", 130, 129, 131, 130),
+	}
+	h := BuildHistory(records, 5, 0, nil)
+	if h.Commits != 6 || len(h.Trends) != 1 {
+		t.Fatalf("commits=%d trends=%d, want 6 and 1", h.Commits, len(h.Trends))
+	}
+	trend := h.Trends[0]
+	if len(trend.Points) != 6 {
+		t.Fatalf("points = %d, want 6", len(trend.Points))
+	}
+	if len(trend.ChangePoints) != 1 {
+		t.Fatalf("change points = %+v, want exactly one", trend.ChangePoints)
+	}
+	cp := trend.ChangePoints[0]
+	if cp.Commit != "c4" || cp.Index != 3 || !cp.Regression {
+		t.Errorf("change point = %+v, want a regression introduced by c4", cp)
+	}
+	if cp.DeltaPct < 25 || cp.DeltaPct > 35 {
+		t.Errorf("DeltaPct = %v, want ≈30", cp.DeltaPct)
+	}
+	if !h.HasRegression() {
+		t.Error("HasRegression should be true")
+	}
+}
+
+func TestBuildHistory_NoiseAndRecovery(t *testing.T) {
+	noisy := BuildHistory([]HistoryRecord{
+		historyRecord("c1", 100, 103, 97, 100),
+		historyRecord("c2", 101, 98, 102, 100),
+		historyRecord("c3", 99, 102, 98, 101),
+	}, 5, 0, nil)
+	if cps := noisy.Trends[0].ChangePoints; len(cps) != 0 {
+		t.Errorf("noise within the threshold should not produce change points, got %+v", cps)
+	}
+
+	recovered := BuildHistory([]HistoryRecord{
+		historyRecord("c1", 100, 101, 99, 100),
+		historyRecord("c2", 150, 151, 149, 150),
+		historyRecord("c3", 150, 149, 151, 150),
+		historyRecord("c4", 100, 99, 101, 100),
+	}, 5, 0, nil)
+	cps := recovered.Trends[0].ChangePoints
+	if len(cps) != 2 || cps[0].Commit != "c2" || !cps[0].Regression || cps[1].Commit != "c4" || cps[1].Regression {
+		t.Fatalf("change points = %+v, want a regression at c2 and an improvement at c4", cps)
+	}
+	if recovered.HasRegression() {
+		t.Error("a regression that was later fixed should not count")
+	}
+}
+
+func TestBuildHistory_PoolsRepeatedCommitsAndLimits(t *testing.T) {
+	h := BuildHistory([]HistoryRecord{
+		historyRecord("c1", 100, 100),
+		historyRecord("c2", 100, 102),
+		historyRecord("c2", 104, 106),
+		historyRecord("c3", 100, 100),
+	}, 5, 2, nil)
+	if h.Commits != 2 {
+		t.Fatalf("commits = %d, want 2 after --limit", h.Commits)
+	}
+	points := h.Trends[0].Points
+	if len(points) != 2 || points[0].Commit != "c2" || points[1].Commit != "c3" {
+		t.Fatalf("points = %+v, want c2 and c3", points)
+	}
+	if points[0].Samples != 4 || points[0].Mean != 103 {
+		t.Errorf("c2 = %+v, want 4 pooled samples with mean 103", points[0])
+	}
+}
+
+func TestBuildHistory_OrdersCommitsByAncestry(t *testing.T) {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	at := func(rec HistoryRecord, commitDay, ranDay int) HistoryRecord {
+		rec.CommitTime = base.AddDate(0, 0, commitDay)
+		rec.Time = base.AddDate(0, 0, ranDay)
+		return rec
+	}
+	// c4 introduced the regression; c1 and c2 were benchmarked last, e.g.
+	// while bisecting, so appearance order would put them after c6.
+	records := []HistoryRecord{
+		at(historyRecord("c3", 99, 100, 101, 100), 3, 1),
+		at(historyRecord("c4", 130, 131, 129, 130), 4, 2),
+		at(historyRecord("c5", 129, 130, 131, 130), 5, 3),
+		at(historyRecord("c6", 130, 129, 131, 130), 6, 4),
+		at(historyRecord("c1", 100, 101, 99, 100), 1, 5),
+		at(historyRecord("c2", 101, 100, 99, 100), 2, 6),
+	}
+	commits := func(h BenchHistory) []string {
+		var out []string
+		for _, p := range h.Trends[0].Points {
+			out = append(out, p.Commit)
+		}
+		return out
+	}
+	want := []string{"c1", "c2", "c3", "c4", "c5", "c6"}
+
+	for name, ancestry := range map[string][]string{
+		"commit time": nil,
+		"ancestry":    {"c1", "c2", "c3", "c4", "c5", "c6"},
+		"partial":     {"c1", "c3", "c4", "c6"}, // c2 and c5 are off the current branch
+	} {
+		h := BuildHistory(records, 5, 0, ancestry)
+		if got := commits(h); strings.Join(got, ",") != strings.Join(want, ",") {
+			t.Errorf("%s: points = %v, want %v", name, got, want)
+			continue
+		}
+		if cps := h.Trends[0].ChangePoints; len(cps) != 1 || cps[0].Commit != "c4" {
+			t.Errorf("%s: change points = %+v, want a regression at c4", name, cps)
+		}
+	}
+
+	// Ancestry wins over commit time, e.g. after a rebase rewrote dates
+	h := BuildHistory(records, 5, 2, []string{"c1", "c2", "c3", "c4", "c6", "c5"})
+	if got := commits(h); strings.Join(got, ",") != "c6,c5" {
+		t.Errorf("limit after ancestry order = %v, want c6,c5", got)
+	}
+}
+
+func TestSparkline(t *testing.T) {
+	if got := Sparkline([]float64{1, 2, 3, 4, 5, 6, 7, 8}); got != "▁▂▃▄▅▆▇█" {
+		t.Errorf("Sparkline = %q", got)
+	}
+	if got := Sparkline([]float64{5, 5, 5}); got != "▁▁▁" {
+		t.Errorf("flat Sparkline = %q", got)
+	}
+}
@@ -0,0 +1,171 @@
+package testrunner
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/flanksource/commons/logger"
+	"github.com/flanksource/gavel/internal/changegraph"
+	"github.com/flanksource/gavel/snapshots"
+	"github.com/flanksource/gavel/testrunner/coverage"
+	"github.com/flanksource/gavel/testrunner/parsers"
+)
+
+// coverageCollector accumulates the coverage written by each package run of
+// one orchestration. Packages run concurrently, so merges are serialised.
+type coverageCollector struct {
+	mu      sync.Mutex
+	profile coverage.Profile
+}
+
+func (c *coverageCollector) merge(p coverage.Profile) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.profile.Merge(p)
+}
+
+// coverageTarget is where a package run was told to write its coverage.
+type coverageTarget struct {
+	framework Framework
+	// path is a file for Go frameworks and a report directory for jest and
+	// vitest.
+	path string
+	// baseDir is the directory relative report paths resolve against.
+	baseDir string
+}
+
+// coverageArgs returns the flags that make framework write a coverage report
+// for pkgPath, and where to read it back. Frameworks without coverage
+// support return a nil target and run unchanged.
+func (o *TestOrchestrator) coverageArgs(framework Framework, pkgPath string) ([]string, *coverageTarget, error) {
+	dir := filepath.Join(o.WorkDir, snapshots.Dir, "coverage")
+	slug := strings.Trim(strings.ReplaceAll(strings.TrimPrefix(pkgPath, "./"), "/", "-"), "-.")
+	if slug == "" {
+		slug = "root"
+	}
+	name := fmt.Sprintf("%s-%s-%d", framework, slug, time.Now().UnixNano())
+
+	switch framework {
+	case parsers.GoTest:
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, nil, fmt.Errorf("create coverage dir: %w", err)
+		}
+		path := filepath.Join(dir, name+".out")
+		return []string{"-coverprofile=" + path}, &coverageTarget{framework: framework, path: path}, nil
+	case parsers.Ginkgo:
+		// ginkgo joins --coverprofile onto each suite directory before merging
+		// the per-suite profiles into the invocation directory, so it only
+		// accepts a bare file name.
+		name = "." + name + ".coverprofile"
+		return []string{"--coverprofile=" + name}, &coverageTarget{framework: framework, path: filepath.Join(o.WorkDir, name)}, nil
+	case parsers.Jest:
+		path := filepath.Join(dir, name)
+		return []string{"--coverage", "--coverageReporters=json", "--coverageDirectory=" + path},
+			&coverageTarget{framework: framework, path: path, baseDir: filepath.Join(o.WorkDir, pkgPath)}, nil
+	case parsers.Vitest:
+		path := filepath.Join(dir, name)
+		return []string{"--coverage.enabled=true", "--coverage.reporter=json", "--coverage.reportsDirectory=" + path},
+			&coverageTarget{framework: framework, path: path, baseDir: filepath.Join(o.WorkDir, pkgPath)}, nil
+	}
+	return nil, nil, nil
+}
+
+// collectCoverage parses the report a package run wrote to target, merges
+// it into the orchestration's profile and removes it. A missing report
+// (build failure, no statements) is not an error: the package simply
+// contributes no coverage.
+func (o *TestOrchestrator) collectCoverage(target *coverageTarget) {
+	if target == nil {
+		return
+	}
+	defer func() { _ = os.RemoveAll(target.path) }()
+
+	var (
+		profile coverage.Profile
+		err     error
+	)
+	switch target.framework {
+	case parsers.GoTest, parsers.Ginkgo:
+		profile, err = readCoverageFile(target.path, func(f *os.File) (coverage.Profile, error) {
+			return coverage.ParseGoProfile(f, goImportPathResolver(o.WorkDir))
+		})
+	default:
+		profile, err = readCoverageFile(filepath.Join(target.path, "coverage-final.json"), func(f *os.File) (coverage.Profile, error) {
+			return coverage.ParseIstanbul(f, target.baseDir, o.WorkDir)
+		})
+		if os.IsNotExist(err) {
+			profile, err = readCoverageFile(filepath.Join(target.path, "lcov.info"), func(f *os.File) (coverage.Profile, error) {
+				return coverage.ParseLcov(f, target.baseDir, o.WorkDir)
+			})
+		}
+	}
+	if err != nil {
+		if !os.IsNotExist(err) {
+			logger.Warnf("failed to read %s coverage from %s: %v", target.framework, target.path, err)
+		}
+		return
+	}
+	o.coverage.merge(profile)
+}
+
+func readCoverageFile(path string, parse func(*os.File) (coverage.Profile, error)) (coverage.Profile, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+	return parse(f)
+}
+
+// goImportPathResolver maps the import-path file names in a Go coverprofile
+// to workDir-relative paths using the module that encloses workDir.
+func goImportPathResolver(workDir string) func(string) string {
+	modRoot := findNearestGoModRoot(workDir)
+	modPath, ok := goModuleName(modRoot)
+	if !ok {
+		return func(string) string { return "" }
+	}
+	absWorkDir, _ := filepath.Abs(workDir)
+	return func(importPath string) string {
+		rel, ok := strings.CutPrefix(importPath, modPath+"/")
+		if !ok {
+			return ""
+		}
+		path, err := filepath.Rel(absWorkDir, filepath.Join(modRoot, filepath.FromSlash(rel)))
+		if err != nil || strings.HasPrefix(path, "..") {
+			return ""
+		}
+		return filepath.ToSlash(path)
+	}
+}
+
+// BuildCoverageReport summarises profile and, when the run is scoped to a
+// diff (--changed / --since) or a threshold is set, computes the coverage of
+// the changed lines using the same diff selection as the change graph.
+// Without --changed / --since the diff is the uncommitted working tree.
+// When the changed lines cannot be computed the error is returned and, with
+// a threshold, recorded on report.Diff so the gate fails instead of passing.
+func BuildCoverageReport(opts RunOptions, profile coverage.Profile) (*coverage.Report, error) {
+	report := coverage.NewReport(profile)
+	if !opts.hasChangeSelector() && opts.DiffCoverage <= 0 {
+		return report, nil
+	}
+	workDir := opts.WorkDir
+	if workDir == "" {
+		workDir, _ = os.Getwd()
+	}
+	changed, err := changegraph.ComputeLineSet(workDir, diffOptionsFromRunOptions(opts))
+	if err != nil {
+		err = fmt.Errorf("compute changed lines: %w", err)
+		if opts.DiffCoverage > 0 {
+			report.Diff = &coverage.DiffCoverage{Threshold: opts.DiffCoverage, Error: err.Error()}
+		}
+		return report, err
+	}
+	report.ApplyDiff(profile, changed, opts.DiffCoverage, workDir)
+	return report, nil
+}
@@ -0,0 +1,364 @@
+// Package coverage merges line coverage from the formats gavel's test
+// runners emit (Go coverprofiles, lcov, istanbul JSON) into one Profile and
+// summarises it per file, per package and for the lines changed in the
+// working tree.
+package coverage
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/flanksource/clicky"
+	"github.com/flanksource/clicky/api"
+	"github.com/flanksource/clicky/api/icons"
+	"github.com/flanksource/gavel/internal/changegraph"
+)
+
+// Profile is line-level coverage keyed by workDir-relative file path
+// (forward slashes) and 1-based line number. Only instrumented lines are
+// present; the value is the hit count. When two sources report the same
+// line the higher count wins — reports only distinguish covered from
+// uncovered, so summing buys nothing.
+type Profile map[string]map[int]int
+
+// Add records hits for line in file.
+func (p *Profile) Add(file string, line, hits int) {
+	if file == "" || line <= 0 {
+		return
+	}
+	if *p == nil {
+		*p = Profile{}
+	}
+	lines, ok := (*p)[file]
+	if !ok {
+		lines = map[int]int{}
+		(*p)[file] = lines
+	}
+	if cur, ok := lines[line]; !ok || hits > cur {
+		lines[line] = hits
+	}
+}
+
+// Merge folds other into p.
+func (p *Profile) Merge(other Profile) {
+	for file, lines := range other {
+		for line, hits := range lines {
+			p.Add(file, line, hits)
+		}
+	}
+}
+
+// Rebase returns a copy of p with prefix prepended to every path. Used to
+// move a nested execution root's profile into its parent's path space.
+func (p Profile) Rebase(prefix string) Profile {
+	prefix = strings.Trim(prefix, "/")
+	if prefix == "" || prefix == "." {
+		return p
+	}
+	out := make(Profile, len(p))
+	for file, lines := range p {
+		out[prefix+"/"+file] = lines
+	}
+	return out
+}
+
+// Summary is a covered/total line count.
+type Summary struct {
+	Covered int     `json:"covered"`
+	Total   int     `json:"total"`
+	Percent float64 `json:"percent"`
+}
+
+func newSummary(covered, total int) Summary {
+	s := Summary{Covered: covered, Total: total}
+	if total > 0 {
+		s.Percent = float64(covered) * 100 / float64(total)
+	}
+	return s
+}
+
+type FileCoverage struct {
+	File    string `json:"file"`
+	Package string `json:"package"`
+	Summary
+}
+
+type PackageCoverage struct {
+	Package string `json:"package"`
+	Summary
+}
+
+// DiffFileCoverage is the coverage of one file's changed lines. Uncovered
+// lists the changed, instrumented lines no test executed. Unmeasured marks a
+// source file no runner reported on, such as one in a package without tests;
+// all of its changed code lines are uncovered.
+type DiffFileCoverage struct {
+	File string `json:"file"`
+	Summary
+	Uncovered  []int `json:"uncovered,omitempty"`
+	Unmeasured bool  `json:"unmeasured,omitempty"`
+}
+
+// DiffCoverage is the coverage of the lines changed in the working tree.
+// Changed lines that are not instrumented (comments, blank lines, files in
+// languages no runner measured) are left out of Total. Threshold is the
+// --min-diff-coverage percentage; 0 means no gate. Error is set when the
+// changed lines could not be computed.
+type DiffCoverage struct {
+	Summary
+	Threshold int                `json:"threshold,omitempty"`
+	Files     []DiffFileCoverage `json:"files,omitempty"`
+	Error     string             `json:"error,omitempty"`
+}
+
+// Failed reports whether diff coverage is below the configured threshold.
+// A diff with no instrumented lines has nothing to cover and passes, but a
+// diff that could not be computed fails the gate rather than passing blind.
+func (d *DiffCoverage) Failed() bool {
+	if d == nil || d.Threshold <= 0 {
+		return false
+	}
+	return d.Error != "" || (d.Total > 0 && d.Percent < float64(d.Threshold))
+}
+
+// Report is the coverage section of a gavel run.
+type Report struct {
+	Summary  Summary           `json:"summary"`
+	Packages []PackageCoverage `json:"packages,omitempty"`
+	Files    []FileCoverage    `json:"files,omitempty"`
+	Diff     *DiffCoverage     `json:"diff,omitempty"`
+}
+
+// NewReport summarises p per file and per package (the file's directory).
+func NewReport(p Profile) *Report {
+	r := &Report{}
+	pkgs := map[string]*[2]int{}
+	var covered, total int
+	for file, lines := range p {
+		c := 0
+		for _, hits := range lines {
+			if hits > 0 {
+				c++
+			}
+		}
+		pkg := path.Dir(file)
+		r.Files = append(r.Files, FileCoverage{File: file, Package: pkg, Summary: newSummary(c, len(lines))})
+		if pkgs[pkg] == nil {
+			pkgs[pkg] = &[2]int{}
+		}
+		pkgs[pkg][0] += c
+		pkgs[pkg][1] += len(lines)
+		covered += c
+		total += len(lines)
+	}
+	for pkg, counts := range pkgs {
+		r.Packages = append(r.Packages, PackageCoverage{Package: pkg, Summary: newSummary(counts[0], counts[1])})
+	}
+	sort.Slice(r.Files, func(i, j int) bool { return r.Files[i].File < r.Files[j].File })
+	sort.Slice(r.Packages, func(i, j int) bool { return r.Packages[i].Package < r.Packages[j].Package })
+	r.Summary = newSummary(covered, total)
+	return r
+}
+
+// MergeReports combines reports from runs over disjoint sets of packages,
+// such as the shards of one `gavel test --shard` run. Reports carry no
+// line data, so a file present in several reports keeps the best-covered
+// copy, and a changed line counts as uncovered only if every report that
+// measured the file left it uncovered. Returns nil when every report is nil.
+func MergeReports(reports ...*Report) *Report {
+	files := map[string]FileCoverage{}
+	diffFiles := map[string]DiffFileCoverage{}
+	var seen, hasDiff bool
+	threshold := 0
+	diffErr := ""
+	for _, r := range reports {
+		if r == nil {
+			continue
+		}
+		seen = true
+		for _, f := range r.Files {
+			if cur, ok := files[f.File]; !ok || f.Covered > cur.Covered {
+				files[f.File] = f
+			}
+		}
+		if r.Diff == nil {
+			continue
+		}
+		hasDiff = true
+		if r.Diff.Threshold > threshold {
+			threshold = r.Diff.Threshold
+		}
+		if diffErr == "" {
+			diffErr = r.Diff.Error
+		}
+		for _, f := range r.Diff.Files {
+			cur, ok := diffFiles[f.File]
+			// A shard that measured the file knows better than one that
+			// only saw it changed.
+			if !ok || (cur.Unmeasured && !f.Unmeasured) {
+				diffFiles[f.File] = f
+				continue
+			}
+			if f.Unmeasured {
+				continue
+			}
+			cur.Uncovered = intersectLines(cur.Uncovered, f.Uncovered)
+			cur.Summary = newSummary(cur.Total-len(cur.Uncovered), cur.Total)
+			diffFiles[f.File] = cur
+		}
+	}
+	if !seen {
+		return nil
+	}
+
+	r := &Report{}
+	pkgs := map[string]*[2]int{}
+	var covered, total int
+	for _, f := range files {
+		r.Files = append(r.Files, f)
+		if pkgs[f.Package] == nil {
+			pkgs[f.Package] = &[2]int{}
+		}
+		pkgs[f.Package][0] += f.Covered
+		pkgs[f.Package][1] += f.Total
+		covered += f.Covered
+		total += f.Total
+	}
+	for pkg, counts := range pkgs {
+		r.Packages = append(r.Packages, PackageCoverage{Package: pkg, Summary: newSummary(counts[0], counts[1])})
+	}
+	sort.Slice(r.Files, func(i, j int) bool { return r.Files[i].File < r.Files[j].File })
+	sort.Slice(r.Packages, func(i, j int) bool { return r.Packages[i].Package < r.Packages[j].Package })
+	r.Summary = newSummary(covered, total)
+
+	if hasDiff {
+		d := &DiffCoverage{Threshold: threshold, Error: diffErr}
+		var dc, dt int
+		for _, f := range diffFiles {
+			d.Files = append(d.Files, f)
+			dc += f.Covered
+			dt += f.Total
+		}
+		sort.Slice(d.Files, func(i, j int) bool { return d.Files[i].File < d.Files[j].File })
+		d.Summary = newSummary(dc, dt)
+		r.Diff = d
+	}
+	return r
+}
+
+func intersectLines(a, b []int) []int {
+	in := make(map[int]bool, len(b))
+	for _, l := range b {
+		in[l] = true
+	}
+	var out []int
+	for _, l := range a {
+		if in[l] {
+			out = append(out, l)
+		}
+	}
+	return out
+}
+
+// ApplyDiff computes coverage of the changed lines in changed and stores it
+// as r.Diff with threshold as the gate. A changed source file missing from p
+// — in a language p has coverage for, under workDir, and not a test file —
+// was never run by any test, so its changed code lines all count as
+// uncovered instead of dropping out of the total.
+func (r *Report) ApplyDiff(p Profile, changed changegraph.LineSet, threshold int, workDir string) {
+	d := &DiffCoverage{Threshold: threshold}
+	measured := measuredExtensions(p)
+	var covered, total int
+	for _, file := range changed.Files() {
+		lines, ok := p[file]
+		if !ok {
+			if f, ok := unmeasuredDiff(file, changed.Lines(file), measured, workDir); ok {
+				d.Files = append(d.Files, f)
+				total += f.Total
+			}
+			continue
+		}
+		f := DiffFileCoverage{File: file}
+		c, n := 0, 0
+		for _, line := range changed.Lines(file) {
+			hits, instrumented := lines[line]
+			if !instrumented {
+				continue
+			}
+			n++
+			if hits > 0 {
+				c++
+			} else {
+				f.Uncovered = append(f.Uncovered, line)
+			}
+		}
+		if n == 0 {
+			continue
+		}
+		f.Summary = newSummary(c, n)
+		d.Files = append(d.Files, f)
+		covered += c
+		total += n
+	}
+	d.Summary = newSummary(covered, total)
+	r.Diff = d
+}
+
+func unmeasuredDiff(file string, changed []int, measured map[string]bool, workDir string) (DiffFileCoverage, bool) {
+	if !measured[path.Ext(file)] || isTestSource(file) {
+		return DiffFileCoverage{}, false
+	}
+	code, ok := codeLines(filepath.Join(workDir, filepath.FromSlash(file)))
+	if !ok {
+		return DiffFileCoverage{}, false
+	}
+	f := DiffFileCoverage{File: file, Unmeasured: true}
+	for _, line := range changed {
+		if code[line] {
+			f.Uncovered = append(f.Uncovered, line)
+		}
+	}
+	if len(f.Uncovered) == 0 {
+		return DiffFileCoverage{}, false
+	}
+	f.Summary = newSummary(0, len(f.Uncovered))
+	return f, true
+}
+
+func (s Summary) Pretty() api.Text {
+	style := "text-green-600"
+	switch {
+	case s.Total == 0:
+		style = "text-muted"
+	case s.Percent < 50:
+		style = "text-red-500"
+	case s.Percent < 80:
+		style = "text-orange-500"
+	}
+	return clicky.Text(fmt.Sprintf("%.1f%%", s.Percent), style).
+		Space().Append(fmt.Sprintf("(%d/%d lines)", s.Covered, s.Total), "text-muted")
+}
+
+// Pretty renders the one-line coverage summary printed after a run.
+func (r Report) Pretty() api.Text {
+	t := clicky.Text("Coverage: ", "bold").Add(r.Summary.Pretty())
+	if r.Diff == nil {
+		return t
+	}
+	if r.Diff.Error != "" {
+		t = t.Space().Append("diff: ", "bold").Append("unavailable", "text-red-500").Space().Append(r.Diff.Error, "text-muted")
+		return t
+	}
+	t = t.Space().Append("diff: ", "bold").Add(r.Diff.Summary.Pretty())
+	if r.Diff.Threshold > 0 {
+		if r.Diff.Failed() {
+			t = t.Space().Add(icons.Fail).Append(fmt.Sprintf(" below %d%%", r.Diff.Threshold), "text-red-500")
+		} else {
+			t = t.Space().Add(icons.Pass).Append(fmt.Sprintf(" ≥ %d%%", r.Diff.Threshold), "text-muted")
+		}
+	}
+	return t
+}
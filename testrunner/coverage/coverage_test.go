@@ -0,0 +1,241 @@
+package coverage
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/flanksource/gavel/internal/changegraph"
+)
+
+func openFixture(t *testing.T, name string) *os.File {
+	t.Helper()
+	f, err := os.Open("testdata/" + name)
+	if err != nil {
+		t.Fatalf("open fixture: %v", err)
+	}
+	t.Cleanup(func() { _ = f.Close() })
+	return f
+}
+
+func TestParseGoProfile(t *testing.T) {
+	resolve := func(importPath string) string {
+		rel, ok := strings.CutPrefix(importPath, "example.com/app/")
+		if !ok {
+			return ""
+		}
+		return rel
+	}
+	p, err := ParseGoProfile(openFixture(t, "go.cover"), resolve)
+	if err != nil {
+		t.Fatalf("ParseGoProfile: %v", err)
+	}
+	if _, ok := p["other/lib.go"]; ok || len(p) != 1 {
+		t.Fatalf("expected only calc/calc.go, got %v", p)
+	}
+	// Line 8 is shared by a covered and an uncovered block; line 14 is a
+	// zero-statement block and not instrumented.
+	want := map[int]int{3: 1, 4: 1, 5: 1, 7: 1, 8: 1, 9: 0, 10: 0, 11: 1}
+	if got := p["calc/calc.go"]; !reflect.DeepEqual(got, want) {
+		t.Errorf("calc.go = %v, want %v", got, want)
+	}
+}
+
+func TestParseGoProfile_Malformed(t *testing.T) {
+	_, err := ParseGoProfile(strings.NewReader("mode: set\nfoo.go:1.1 1\n"), func(s string) string { return s })
+	if err == nil {
+		t.Fatal("expected error for malformed block")
+	}
+}
+
+func TestParseLcov(t *testing.T) {
+	p, err := ParseLcov(openFixture(t, "lcov.info"), "/repo/web", "/repo")
+	if err != nil {
+		t.Fatalf("ParseLcov: %v", err)
+	}
+	want := Profile{"web/src/sum.ts": {1: 3, 2: 3, 4: 0}}
+	if !reflect.DeepEqual(p, want) {
+		t.Errorf("profile = %v, want %v", p, want)
+	}
+}
+
+func TestParseIstanbul(t *testing.T) {
+	p, err := ParseIstanbul(openFixture(t, "coverage-final.json"), "/repo/web", "/repo")
+	if err != nil {
+		t.Fatalf("ParseIstanbul: %v", err)
+	}
+	want := Profile{"web/src/sum.ts": {1: 1, 2: 4, 5: 0}}
+	if !reflect.DeepEqual(p, want) {
+		t.Errorf("profile = %v, want %v", p, want)
+	}
+}
+
+func TestProfileMergeAndRebase(t *testing.T) {
+	var p Profile
+	p.Merge(Profile{"a.go": {1: 0, 2: 1}})
+	p.Merge(Profile{"a.go": {1: 2, 2: 0}, "b.go": {1: 0}})
+	want := Profile{"a.go": {1: 2, 2: 1}, "b.go": {1: 0}}
+	if !reflect.DeepEqual(p, want) {
+		t.Errorf("merged = %v, want %v", p, want)
+	}
+	rebased := p.Rebase("sub/mod")
+	if _, ok := rebased["sub/mod/a.go"]; !ok || len(rebased) != 2 {
+		t.Errorf("rebased = %v", rebased)
+	}
+	if got := p.Rebase("."); !reflect.DeepEqual(got, p) {
+		t.Errorf("Rebase(.) changed paths: %v", got)
+	}
+}
+
+func TestNewReport(t *testing.T) {
+	r := NewReport(Profile{
+		"pkg/a.go":    {1: 1, 2: 0, 3: 1, 4: 1},
+		"pkg/b.go":    {1: 0},
+		"cmd/main.go": {1: 1},
+	})
+	if r.Summary.Covered != 4 || r.Summary.Total != 6 {
+		t.Errorf("summary = %+v", r.Summary)
+	}
+	if len(r.Files) != 3 || r.Files[0].File != "cmd/main.go" {
+		t.Fatalf("files = %+v", r.Files)
+	}
+	if len(r.Packages) != 2 || r.Packages[1].Package != "pkg" || r.Packages[1].Covered != 3 || r.Packages[1].Total != 5 {
+		t.Errorf("packages = %+v", r.Packages)
+	}
+	if r.Packages[1].Percent != 60 {
+		t.Errorf("pkg percent = %v, want 60", r.Packages[1].Percent)
+	}
+}
+
+func TestApplyDiff(t *testing.T) {
+	p := Profile{
+		"pkg/a.go": {1: 1, 2: 0, 3: 1, 4: 0},
+		"pkg/b.go": {1: 1},
+	}
+	changed := changegraph.NewLineSet()
+	for _, l := range []int{2, 3, 4, 9} { // 9 is a comment line: not instrumented
+		changed.Add("pkg/a.go", l)
+	}
+	changed.Add("README.md", 1)
+
+	r := NewReport(p)
+	r.ApplyDiff(p, changed, 50, t.TempDir())
+	d := r.Diff
+	if d.Covered != 1 || d.Total != 3 {
+		t.Fatalf("diff = %+v", d.Summary)
+	}
+	if len(d.Files) != 1 || !reflect.DeepEqual(d.Files[0].Uncovered, []int{2, 4}) {
+		t.Errorf("files = %+v", d.Files)
+	}
+	if !d.Failed() {
+		t.Errorf("33%% diff coverage should fail a 50%% threshold")
+	}
+
+	d.Threshold = 30
+	if d.Failed() {
+		t.Errorf("33%% diff coverage should pass a 30%% threshold")
+	}
+	d.Threshold = 0
+	if d.Failed() {
+		t.Errorf("no threshold should never fail")
+	}
+	empty := &DiffCoverage{Threshold: 90}
+	if empty.Failed() {
+		t.Errorf("a diff without instrumented lines should pass")
+	}
+}
+
+func TestApplyDiffCountsUnmeasuredSourceAsUncovered(t *testing.T) {
+	dir := t.TempDir()
+	writeSource(t, dir, "newpkg/new.go", "package newpkg\n\n// Double doubles n.\nfunc Double(n int) int {\n\tm := n * 2\n\treturn m\n}\n")
+	writeSource(t, dir, "newpkg/new_test.go", "package newpkg\n")
+	writeSource(t, dir, "web/app.ts", "export const x = 1;\n")
+
+	p := Profile{"pkg/a.go": {1: 1, 2: 1}}
+	changed := changegraph.NewLineSet()
+	changed.Add("pkg/a.go", 1)
+	for l := 1; l <= 7; l++ {
+		changed.Add("newpkg/new.go", l)
+	}
+	changed.Add("newpkg/new_test.go", 1)
+	changed.Add("web/app.ts", 1) // no runner measured .ts files
+
+	r := NewReport(p)
+	r.ApplyDiff(p, changed, 80, dir)
+	d := r.Diff
+	if d.Covered != 1 || d.Total != 3 {
+		t.Fatalf("diff = %+v files=%+v", d.Summary, d.Files)
+	}
+	want := DiffFileCoverage{File: "newpkg/new.go", Summary: newSummary(0, 2), Uncovered: []int{5, 6}, Unmeasured: true}
+	if len(d.Files) != 2 || !reflect.DeepEqual(d.Files[0], want) {
+		t.Errorf("files = %+v", d.Files)
+	}
+	if !d.Failed() {
+		t.Errorf("an untested package should fail the gate")
+	}
+
+	// A shard that measured the file wins over one that did not.
+	measured := NewReport(Profile{"newpkg/new.go": {5: 1, 6: 1}})
+	measured.ApplyDiff(Profile{"newpkg/new.go": {5: 1, 6: 1}}, changed, 80, dir)
+	m := MergeReports(r, measured)
+	for _, f := range m.Diff.Files {
+		if f.File == "newpkg/new.go" && (f.Unmeasured || len(f.Uncovered) != 0) {
+			t.Errorf("merged new.go = %+v", f)
+		}
+	}
+}
+
+func TestDiffErrorFailsGate(t *testing.T) {
+	d := &DiffCoverage{Threshold: 80, Error: "git diff failed"}
+	if !d.Failed() {
+		t.Errorf("a diff that could not be computed should fail a threshold")
+	}
+	d.Threshold = 0
+	if d.Failed() {
+		t.Errorf("without a threshold a diff error is only informational")
+	}
+}
+
+func writeSource(t *testing.T, dir, file, content string) {
+	t.Helper()
+	path := filepath.Join(dir, filepath.FromSlash(file))
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMergeReports(t *testing.T) {
+	changed := changegraph.NewLineSet()
+	for _, l := range []int{1, 2, 3} {
+		changed.Add("shared/util.go", l)
+	}
+	// Two shards: each covers its own package and part of a shared file.
+	p1 := Profile{"a/a.go": {1: 1, 2: 1}, "shared/util.go": {1: 1, 2: 0, 3: 0}}
+	p2 := Profile{"b/b.go": {1: 0}, "shared/util.go": {1: 0, 2: 1, 3: 0}}
+	r1, r2 := NewReport(p1), NewReport(p2)
+	r1.ApplyDiff(p1, changed, 80, t.TempDir())
+	r2.ApplyDiff(p2, changed, 80, t.TempDir())
+
+	m := MergeReports(r1, nil, r2)
+	if len(m.Files) != 3 || m.Summary.Total != 6 || m.Summary.Covered != 3 {
+		t.Fatalf("merged = %+v files=%+v", m.Summary, m.Files)
+	}
+	if len(m.Packages) != 3 {
+		t.Errorf("packages = %+v", m.Packages)
+	}
+	d := m.Diff
+	if d == nil || d.Threshold != 80 || len(d.Files) != 1 {
+		t.Fatalf("diff = %+v", d)
+	}
+	if !reflect.DeepEqual(d.Files[0].Uncovered, []int{3}) || d.Covered != 2 || d.Total != 3 {
+		t.Errorf("a changed line is uncovered only if no shard covered it: %+v", d.Files[0])
+	}
+	if MergeReports(nil, nil) != nil {
+		t.Error("merging only nil reports should return nil")
+	}
+}
@@ -0,0 +1,161 @@
+package coverage
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// ParseGoProfile reads a `go test -coverprofile` file. Profile entries name
+// files by import path; resolve maps each one to a workDir-relative path and
+// returns "" for files outside workDir, which are dropped. Every line of a
+// block gets the block's count, so a line shared by two blocks is covered
+// when either ran.
+func ParseGoProfile(r io.Reader, resolve func(importPath string) string) (Profile, error) {
+	p := Profile{}
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "mode:") {
+			continue
+		}
+		// name.go:L1.C1,L2.C2 numStmt count
+		colon := strings.LastIndex(line, ":")
+		if colon < 0 {
+			return nil, fmt.Errorf("go coverprofile line %d: missing file separator", lineNo)
+		}
+		fields := strings.Fields(line[colon+1:])
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("go coverprofile line %d: expected block, statements and count", lineNo)
+		}
+		startLine, endLine, err := parseGoBlock(fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("go coverprofile line %d: %w", lineNo, err)
+		}
+		numStmt, err1 := strconv.Atoi(fields[1])
+		count, err2 := strconv.Atoi(fields[2])
+		if err1 != nil || err2 != nil {
+			return nil, fmt.Errorf("go coverprofile line %d: invalid counts", lineNo)
+		}
+		if numStmt == 0 {
+			continue
+		}
+		file := resolve(line[:colon])
+		if file == "" {
+			continue
+		}
+		for l := startLine; l <= endLine; l++ {
+			p.Add(file, l, count)
+		}
+	}
+	return p, scanner.Err()
+}
+
+// parseGoBlock parses "L1.C1,L2.C2" into its start and end lines.
+func parseGoBlock(block string) (int, int, error) {
+	start, end, ok := strings.Cut(block, ",")
+	if !ok {
+		return 0, 0, fmt.Errorf("invalid block %q", block)
+	}
+	startLine, err := strconv.Atoi(strings.SplitN(start, ".", 2)[0])
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid block %q", block)
+	}
+	endLine, err := strconv.Atoi(strings.SplitN(end, ".", 2)[0])
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid block %q", block)
+	}
+	return startLine, endLine, nil
+}
+
+// ParseLcov reads an lcov tracefile. SF paths are resolved against baseDir
+// (the directory the tool ran in) and made relative to workDir.
+func ParseLcov(r io.Reader, baseDir, workDir string) (Profile, error) {
+	p := Profile{}
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	file := ""
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case strings.HasPrefix(line, "SF:"):
+			file = relPath(baseDir, workDir, strings.TrimPrefix(line, "SF:"))
+		case line == "end_of_record":
+			file = ""
+		case strings.HasPrefix(line, "DA:") && file != "":
+			// DA:<line>,<hits>[,<checksum>]
+			parts := strings.Split(strings.TrimPrefix(line, "DA:"), ",")
+			if len(parts) < 2 {
+				continue
+			}
+			n, err1 := strconv.Atoi(parts[0])
+			hits, err2 := strconv.Atoi(parts[1])
+			if err1 != nil || err2 != nil {
+				continue
+			}
+			p.Add(file, n, hits)
+		}
+	}
+	return p, scanner.Err()
+}
+
+type istanbulPosition struct {
+	Line int `json:"line"`
+}
+
+type istanbulFile struct {
+	Path         string `json:"path"`
+	StatementMap map[string]struct {
+		Start istanbulPosition `json:"start"`
+	} `json:"statementMap"`
+	S map[string]int `json:"s"`
+}
+
+// ParseIstanbul reads an istanbul coverage-final.json as written by jest's
+// and vitest's "json" reporter. A line's count is that of the statements
+// starting on it.
+func ParseIstanbul(r io.Reader, baseDir, workDir string) (Profile, error) {
+	var files map[string]istanbulFile
+	if err := json.NewDecoder(r).Decode(&files); err != nil {
+		return nil, fmt.Errorf("parse istanbul coverage: %w", err)
+	}
+	p := Profile{}
+	for key, f := range files {
+		path := f.Path
+		if path == "" {
+			path = key
+		}
+		file := relPath(baseDir, workDir, path)
+		if file == "" {
+			continue
+		}
+		for id, stmt := range f.StatementMap {
+			p.Add(file, stmt.Start.Line, f.S[id])
+		}
+	}
+	return p, nil
+}
+
+// relPath resolves path against baseDir and returns it relative to workDir,
+// or "" when it falls outside workDir.
+func relPath(baseDir, workDir, path string) string {
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return ""
+	}
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(baseDir, path)
+	}
+	rel, err := filepath.Rel(workDir, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return ""
+	}
+	return filepath.ToSlash(rel)
+}
@@ -0,0 +1,92 @@
+package coverage
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path"
+	"strings"
+)
+
+// measuredExtensions returns the file extensions p has coverage for, i.e.
+// the languages some runner instrumented in this run.
+func measuredExtensions(p Profile) map[string]bool {
+	exts := map[string]bool{}
+	for file := range p {
+		if ext := path.Ext(file); ext != "" {
+			exts[ext] = true
+		}
+	}
+	return exts
+}
+
+// isTestSource reports whether file is a test file by the naming conventions
+// of the supported runners. Test files are never instrumented.
+func isTestSource(file string) bool {
+	base := path.Base(file)
+	switch {
+	case strings.HasSuffix(base, "_test.go"),
+		strings.Contains(base, ".test."), strings.Contains(base, ".spec."),
+		strings.HasPrefix(base, "test_") && strings.HasSuffix(base, ".py"),
+		strings.HasSuffix(base, "_test.py"), base == "conftest.py":
+		return true
+	}
+	return false
+}
+
+// codeLines approximates the lines a coverage tool would instrument in a
+// file no runner measured: statement lines inside Go function bodies, and
+// lines that are neither blank nor comments in any other language. ok is
+// false when the file cannot be read.
+func codeLines(filename string) (map[int]bool, bool) {
+	src, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, false
+	}
+	if strings.HasSuffix(filename, ".go") {
+		if lines, ok := goStatementLines(filename, src); ok {
+			return lines, true
+		}
+	}
+	lines := map[int]bool{}
+	for i, line := range strings.Split(string(src), "\n") {
+		line = strings.TrimSpace(line)
+		switch {
+		case line == "",
+			strings.HasPrefix(line, "//"), strings.HasPrefix(line, "#"),
+			strings.HasPrefix(line, "/*"), strings.HasPrefix(line, "*"):
+			continue
+		}
+		lines[i+1] = true
+	}
+	return lines, true
+}
+
+func goStatementLines(filename string, src []byte) (map[int]bool, bool) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, filename, src, parser.SkipObjectResolution)
+	if err != nil {
+		return nil, false
+	}
+	lines := map[int]bool{}
+	mark := func(body *ast.BlockStmt) {
+		if body == nil {
+			return
+		}
+		ast.Inspect(body, func(n ast.Node) bool {
+			if stmt, ok := n.(ast.Stmt); ok {
+				if _, block := stmt.(*ast.BlockStmt); !block {
+					lines[fset.Position(stmt.Pos()).Line] = true
+				}
+			}
+			return true
+		})
+	}
+	for _, decl := range f.Decls {
+		if fn, ok := decl.(*ast.FuncDecl); ok {
+			mark(fn.Body)
+		}
+	}
+	return lines, true
+}
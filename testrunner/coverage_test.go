@@ -0,0 +1,35 @@
+package testrunner
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/flanksource/gavel/testrunner/coverage"
+)
+
+func TestBuildCoverageReportFailsGateWhenDiffUnavailable(t *testing.T) {
+	// Not a git repository, so the changed lines cannot be computed
+	dir := t.TempDir()
+	t.Setenv("GIT_CEILING_DIRECTORIES", dir)
+	profile := coverage.Profile{"a.go": {1: 1}}
+
+	report, err := BuildCoverageReport(RunOptions{WorkDir: dir, DiffCoverage: 80}, profile)
+	if err == nil {
+		t.Fatal("expected an error outside a git repository")
+	}
+	if report.Diff == nil || !report.Diff.Failed() {
+		t.Fatalf("a diff error must fail --min-diff-coverage, got %+v", report.Diff)
+	}
+
+	report, err = BuildCoverageReport(RunOptions{WorkDir: dir, Changed: true}, profile)
+	if err == nil || report.Diff.Failed() {
+		t.Fatalf("without a threshold a diff error only warns, got %v %+v", err, report.Diff)
+	}
+}
+
+func TestRunRejectsCacheWithMinDiffCoverage(t *testing.T) {
+	_, err := Run(RunOptions{WorkDir: t.TempDir(), Cache: true, DiffCoverage: 80})
+	if err == nil || !strings.Contains(err.Error(), "--cache cannot be combined with --min-diff-coverage") {
+		t.Fatalf("expected --cache to be rejected with --min-diff-coverage, got %v", err)
+	}
+}
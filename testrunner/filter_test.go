@@ -55,6 +55,19 @@ func TestFilterFrameworks(t *testing.T) {
 		}
 	})
 
+	t.Run("custom framework matches by exact name", func(t *testing.T) {
+		got, err := filterFrameworks(append(detected, "bats"), []string{"bats"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(got) != 1 || got[0] != "bats" {
+			t.Fatalf("got %v, want [bats]", got)
+		}
+		if _, err := filterFrameworks(detected, []string{"bats"}); err == nil {
+			t.Fatal("expected error when the custom framework isn't detected")
+		}
+	})
+
 	t.Run("not-detected hard-fails", func(t *testing.T) {
 		_, err := filterFrameworks(detected, []string{"playwright"})
 		if err == nil {
@@ -43,6 +43,8 @@ type Entry struct {
 	ExecutionCount int               `json:"execution_count"`
 	PassCount      int               `json:"pass_count"`
 	FailCount      int               `json:"fail_count"`
+	FlakyCount     int               `json:"flaky_count,omitempty"` // runs where the test only passed on a --retries attempt
+	Quarantined    bool              `json:"quarantined,omitempty"` // quarantined in .gavel.yaml as of the latest run
 	PassRate       float64           `json:"pass_rate"`
 	MinDuration    time.Duration     `json:"min_duration"`
 	AvgDuration    time.Duration     `json:"avg_duration"`
@@ -234,6 +236,12 @@ func makeKey(test parsers.Test, workDir string) entryKey {
 
 func (e *Entry) record(test parsers.Test, ranAt time.Time) {
 	e.ExecutionCount++
+	if test.Flaky {
+		e.FlakyCount++
+	}
+	// Runs are recorded oldest first, so the last write reflects the
+	// current quarantine list.
+	e.Quarantined = test.Quarantined
 	if e.Line == 0 && test.Line > 0 {
 		e.Line = test.Line
 	}
@@ -363,6 +371,21 @@ func sortEntries(entries []Entry) {
 func (r Report) Pretty() api.Text {
 	label := fmt.Sprintf("Test history: %d tests across %d runs", len(r.Tests), r.RunCount)
 	t := clicky.Text(label, "bold text-blue-500")
+	var flaky, quarantined int
+	for _, e := range r.Tests {
+		if e.FlakyCount > 0 {
+			flaky++
+		}
+		if e.Quarantined {
+			quarantined++
+		}
+	}
+	if flaky > 0 {
+		t = t.Space().Append(fmt.Sprintf("%d flaky", flaky), "text-orange-500")
+	}
+	if quarantined > 0 {
+		t = t.Space().Append(fmt.Sprintf("%d quarantined", quarantined), "text-muted")
+	}
 	if !r.FirstRunAt.IsZero() && !r.LastRunAt.IsZero() {
 		t = t.Space().Append(fmt.Sprintf("(%s to %s)", formatDate(r.FirstRunAt), formatDate(r.LastRunAt)), "text-muted")
 	}
@@ -433,6 +456,12 @@ func (n *testNode) Pretty() api.Text {
 	t := clicky.Text(prefix, style).Append(e.Name, "bold wrap-space")
 	t = t.Space().Append(fmt.Sprintf("exec %d", e.ExecutionCount), "text-muted")
 	t = t.Space().Append(fmt.Sprintf("pass %.0f%%", e.PassRate*100), passRateStyle(e.PassRate))
+	if e.FlakyCount > 0 {
+		t = t.Space().Append(fmt.Sprintf("flaky %d", e.FlakyCount), "text-orange-500")
+	}
+	if e.Quarantined {
+		t = t.Space().Append("quarantined", "text-muted")
+	}
 	t = t.Space().Append(fmt.Sprintf("min %s", e.MinDuration), "text-muted")
 	t = t.Space().Append(fmt.Sprintf("avg %s", e.AvgDuration), "text-muted")
 	t = t.Space().Append(fmt.Sprintf("max %s", e.MaxDuration), "text-muted")
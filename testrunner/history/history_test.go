@@ -78,6 +78,33 @@ func TestLoadAggregatesLeafExecutions(t *testing.T) {
 	assert.Equal(t, ts2, timeout.LastFailedAt)
 }
 
+func TestLoadCountsFlakyAndQuarantined(t *testing.T) {
+	workDir := t.TempDir()
+	ts1 := time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)
+	ts2 := time.Date(2026, 1, 2, 10, 0, 0, 0, time.UTC)
+
+	flaky := fooTest(workDir, "TestFoo", true, false, time.Millisecond)
+	flaky.Flaky = true
+	quarantined := fooTest(workDir, "TestBar", false, true, time.Millisecond)
+	quarantined.Quarantined = true
+	writeRun(t, workDir, ts1, []parsers.Test{flaky, fooTest(workDir, "TestBar", false, true, time.Millisecond)})
+	writeRun(t, workDir, ts2, []parsers.Test{fooTest(workDir, "TestFoo", true, false, time.Millisecond), quarantined})
+
+	report, err := Load(Options{WorkDir: workDir})
+	require.NoError(t, err)
+
+	foo := findEntry(t, report, "TestFoo")
+	assert.Equal(t, 1, foo.FlakyCount)
+	assert.Equal(t, 2, foo.PassCount)
+	assert.False(t, foo.Quarantined)
+	assert.True(t, findEntry(t, report, "TestBar").Quarantined)
+
+	out := clicky.MustFormat(report, clicky.FormatOptions{Pretty: true})
+	for _, want := range []string{"1 flaky", "1 quarantined", "flaky 1"} {
+		assert.Contains(t, out, want)
+	}
+}
+
 func TestLoadFiltersByPackageAndFile(t *testing.T) {
 	workDir := t.TempDir()
 	ts := time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)
@@ -96,6 +96,12 @@ func compactCounts(sum parsers.TestSummary) string {
 	if sum.Failed > 0 {
 		parts = append(parts, fmt.Sprintf("%d failed", sum.Failed))
 	}
+	if sum.Flaky > 0 {
+		parts = append(parts, fmt.Sprintf("%d flaky", sum.Flaky))
+	}
+	if sum.Quarantined > 0 {
+		parts = append(parts, fmt.Sprintf("%d quarantined", sum.Quarantined))
+	}
 	if sum.Skipped > 0 {
 		parts = append(parts, fmt.Sprintf("%d skipped", sum.Skipped))
 	}
@@ -173,7 +179,8 @@ func runeLen(s string) int {
 }
 
 // firstFailingLeaf walks a package's test results and returns a pointer
-// to the first leaf test where Failed==true. Returns nil if none.
+// to the first leaf test where Failed==true. Quarantined failures are
+// skipped since they do not fail the package. Returns nil if none.
 func firstFailingLeaf(results parsers.TestSuiteResults) *parsers.Test {
 	for _, tr := range results {
 		for i := range tr.Tests {
@@ -194,7 +201,7 @@ func findFailingLeaf(t *parsers.Test) *parsers.Test {
 	if len(t.Children) > 0 {
 		return nil
 	}
-	if t.Failed {
+	if t.Failed && !t.Quarantined {
 		return t
 	}
 	return nil
@@ -0,0 +1,289 @@
+package parsers
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/flanksource/clicky/task"
+	"github.com/flanksource/commons/logger"
+)
+
+// CargoTestJSON parses libtest's JSON event stream, emitted by
+// `cargo test -- -Z unstable-options --format json --report-time`. cargo
+// runs one test binary per target (lib, each integration test, doc-tests)
+// and every binary writes its own suite started/finished pair to stdout, so
+// the stream is a concatenation of suites.
+type CargoTestJSON struct {
+	workDir string
+	// rootDir is the directory cargo was run from. rustc records source
+	// paths relative to the workspace root, so panic locations are resolved
+	// against it.
+	rootDir string
+}
+
+// NewCargoTestJSON returns a libtest JSON parser. An empty rootDir means
+// cargo ran in workDir.
+func NewCargoTestJSON(workDir, rootDir string) *CargoTestJSON {
+	if rootDir == "" {
+		rootDir = workDir
+	}
+	return &CargoTestJSON{workDir: workDir, rootDir: rootDir}
+}
+
+func (p *CargoTestJSON) Name() string {
+	return "cargo json"
+}
+
+// cargoEvent is one line of libtest JSON output. Suite events carry the
+// counters; test events carry the name and, on failure, captured stdout.
+type cargoEvent struct {
+	Type     string   `json:"type"`  // "suite" | "test" | "bench"
+	Event    string   `json:"event"` // "started" | "ok" | "failed" | "ignored" | "timeout"
+	Name     string   `json:"name,omitempty"`
+	ExecTime *float64 `json:"exec_time,omitempty"`
+	Stdout   string   `json:"stdout,omitempty"`
+	Message  string   `json:"message,omitempty"`
+	Count    int      `json:"test_count,omitempty"`
+}
+
+// cargoMaxLine is the scanner limit for one event. libtest inlines captured
+// stdout into the failed event, which can run well past bufio's 64K default.
+const cargoMaxLine = 16 * 1024 * 1024
+
+func (p *CargoTestJSON) ParseStream(output io.Reader, stdout io.Writer, t *task.Task) (int, int, error) {
+	passed, failed, total := 0, 0, 0
+	scanner := bufio.NewScanner(output)
+	scanner.Buffer(make([]byte, 0, 64*1024), cargoMaxLine)
+	for scanner.Scan() {
+		line := scanner.Text()
+		_, _ = stdout.Write([]byte(line + "\n"))
+
+		var event cargoEvent
+		if err := json.Unmarshal([]byte(line), &event); err != nil {
+			continue
+		}
+		if event.Type == "suite" && event.Event == "started" {
+			total += event.Count
+		}
+		if event.Type != "test" {
+			continue
+		}
+		switch event.Event {
+		case "ok":
+			passed++
+			if t != nil && logger.V(2).Enabled() {
+				t.Warnf("✓ %s", event.Name)
+			}
+		case "failed":
+			failed++
+			if t != nil && logger.V(1).Enabled() {
+				t.Warnf("✗ %s", event.Name)
+			}
+		}
+		if t != nil && total > 0 {
+			t.SetName(cargoProgressLabel(passed, failed, total))
+		}
+	}
+	return passed, failed, scanner.Err()
+}
+
+func cargoProgressLabel(passed, failed, total int) string {
+	if failed > 0 {
+		return fmt.Sprintf("tests (%d passed, %d failed, %d total)", passed, failed, total)
+	}
+	return fmt.Sprintf("tests (%d passed, %d total)", passed, total)
+}
+
+func (p *CargoTestJSON) Parse(output io.Reader) ([]Test, error) {
+	var tests []Test
+	// A slow test reports "timeout" and later its final ok/failed event;
+	// index lets the final event replace the earlier one. Names are only
+	// unique within one test binary, so index resets at each suite start.
+	index := map[string]int{}
+	scanner := bufio.NewScanner(output)
+	scanner.Buffer(make([]byte, 0, 64*1024), cargoMaxLine)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if !strings.HasPrefix(line, "{") {
+			continue
+		}
+		var event cargoEvent
+		if err := json.Unmarshal([]byte(line), &event); err != nil {
+			return nil, fmt.Errorf("parse cargo json line: %w", err)
+		}
+		if event.Type == "suite" && event.Event == "started" {
+			index = map[string]int{}
+			continue
+		}
+		if event.Type != "test" || event.Event == "started" {
+			continue
+		}
+		t := p.toTest(event)
+		if i, ok := index[event.Name]; ok {
+			if event.Event == "timeout" {
+				continue
+			}
+			tests[i] = t
+			continue
+		}
+		index[event.Name] = len(tests)
+		tests = append(tests, t)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read cargo json: %w", err)
+	}
+	return tests, nil
+}
+
+func (p *CargoTestJSON) toTest(e cargoEvent) Test {
+	t := newCargoTest(e.Name, p.relPath)
+	if e.ExecTime != nil {
+		t.Duration = time.Duration(*e.ExecTime * float64(time.Second))
+	}
+	output := strings.TrimSpace(e.Stdout)
+	switch e.Event {
+	case "ok":
+		t.Passed = true
+		t.Stdout = output
+	case "ignored":
+		t.Skipped = true
+		t.Message = e.Message
+	case "failed", "timeout":
+		t.Failed = true
+		t.TimedOut = e.Event == "timeout"
+		t.Message = output
+		if t.Message == "" {
+			t.Message = e.Message
+		}
+		t.FailureDetail = parseCargoPanic(t.Message, p.relPath)
+	}
+	return t
+}
+
+// newCargoTest splits a libtest name into the Test shape. Unit and
+// integration tests are module paths ("net::tests::parses") whose leading
+// segments become Suite. Doc-tests are "src/lib.rs - add (line 12)" and
+// carry their own file and line.
+func newCargoTest(name string, rel func(string) string) Test {
+	t := Test{Framework: Cargo}
+	if m := cargoDocTestRe.FindStringSubmatch(name); m != nil {
+		t.File = rel(m[1])
+		t.Suite = []string{cargoDocTestSuite}
+		t.Name = m[2]
+		t.Line, _ = strconv.Atoi(m[3])
+		return t
+	}
+	parts := strings.Split(name, "::")
+	t.Name = parts[len(parts)-1]
+	if len(parts) > 1 {
+		t.Suite = parts[:len(parts)-1]
+	}
+	return t
+}
+
+// CargoTestPath rebuilds the libtest name ("net::tests::parses") that
+// newCargoTest split into Suite and Name, for use as a test filter.
+// Doc-tests have no module path and return their bare name.
+func CargoTestPath(t Test) string {
+	if len(t.Suite) == 1 && t.Suite[0] == cargoDocTestSuite {
+		return t.Name
+	}
+	return strings.Join(append(append([]string{}, t.Suite...), t.Name), "::")
+}
+
+const cargoDocTestSuite = "doc-tests"
+
+var (
+	cargoDocTestRe = regexp.MustCompile(`^(\S+\.rs) - (.+?) \(line (\d+)\)$`)
+	// Rust ≥1.73: "thread 'x' panicked at src/lib.rs:12:9:\n<message>"
+	cargoPanicRe = regexp.MustCompile(`(?m)^thread '[^']*' panicked at (\S+?):(\d+):\d+:$`)
+	// Rust <1.73: "thread 'x' panicked at '<message>', src/lib.rs:12:9"
+	cargoLegacyPanicRe = regexp.MustCompile(`(?s)thread '[^']*' panicked at '(.*)', (\S+?):(\d+):\d+`)
+	cargoAssertRe      = regexp.MustCompile("assertion (?:`left (==|!=) right` failed|failed: `\\(left (==|!=) right\\)`)")
+	cargoLeftRe        = regexp.MustCompile(`(?m)^\s*left: (.*)$`)
+	cargoRightRe       = regexp.MustCompile(`(?m)^\s*right: (.*)$`)
+)
+
+// parseCargoPanic builds a FailureDetail from the output of a failed Rust
+// test. assert_eq!/assert_ne! panics become FailureKindGomega with left as
+// Actual and right as Expected so the UI renders them side-by-side; any
+// other panic is FailureKindPanic with the backtrace (when RUST_BACKTRACE
+// was set) as Stack. Falls back to ParseFailureDetail when no panic header
+// is present.
+func parseCargoPanic(out string, rel func(string) string) *FailureDetail {
+	var message, file, line string
+	if m := cargoPanicRe.FindStringSubmatchIndex(out); m != nil {
+		file, line = out[m[2]:m[3]], out[m[4]:m[5]]
+		message = out[m[1]:]
+	} else if m := cargoLegacyPanicRe.FindStringSubmatch(out); m != nil {
+		message, file, line = m[1], m[2], m[3]
+	} else {
+		return ParseFailureDetail(out)
+	}
+
+	stack := ""
+	if i := strings.Index(message, "stack backtrace:"); i >= 0 {
+		stack = strings.TrimSpace(message[i+len("stack backtrace:"):])
+		message = message[:i]
+	}
+	if i := strings.Index(message, "note: run with `RUST_BACKTRACE"); i >= 0 {
+		message = message[:i]
+	}
+	message = strings.TrimSpace(message)
+	location := rel(filepath.FromSlash(file)) + ":" + line
+
+	if m := cargoAssertRe.FindStringSubmatch(message); m != nil {
+		op := m[1] + m[2]
+		matcher := "to equal"
+		if op == "!=" {
+			matcher = "not to equal"
+		}
+		var left, right string
+		if lm := cargoLeftRe.FindStringSubmatch(message); lm != nil {
+			left = trimCargoValue(lm[1])
+		}
+		if rm := cargoRightRe.FindStringSubmatch(message); rm != nil {
+			right = trimCargoValue(rm[1])
+		}
+		return &FailureDetail{
+			Kind:     FailureKindGomega,
+			Summary:  buildGomegaSummary(left, matcher, right),
+			Matcher:  matcher,
+			Actual:   left,
+			Expected: right,
+			Location: location,
+			Stack:    stack,
+		}
+	}
+
+	headline := strings.SplitN(message, "\n", 2)[0]
+	return &FailureDetail{
+		Kind:     FailureKindPanic,
+		Summary:  oneLine("panic: "+headline, summaryMaxLen),
+		Actual:   message,
+		Location: location,
+		Stack:    stack,
+	}
+}
+
+// trimCargoValue strips the backtick quoting and trailing comma the
+// pre-1.73 assert_eq! format wraps around each value.
+func trimCargoValue(v string) string {
+	v = strings.TrimSpace(v)
+	v = strings.TrimSuffix(v, ",")
+	if len(v) >= 2 && strings.HasPrefix(v, "`") && strings.HasSuffix(v, "`") {
+		v = v[1 : len(v)-1]
+	}
+	return v
+}
+
+func (p *CargoTestJSON) relPath(filePath string) string {
+	return rootRelPath(p.workDir, p.rootDir, filePath)
+}
@@ -0,0 +1,133 @@
+package parsers
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCargoTestJSON_Parse(t *testing.T) {
+	data, err := os.ReadFile("testdata/cargo-libtest.json")
+	if err != nil {
+		t.Fatalf("read fixture: %v", err)
+	}
+	tests, err := NewCargoTestJSON("/repo", "/repo").Parse(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if len(tests) != 5 {
+		t.Fatalf("expected 5 tests, got %d: %+v", len(tests), tests)
+	}
+	byName := map[string]Test{}
+	for _, tst := range tests {
+		byName[tst.Name] = tst
+	}
+
+	adds := byName["adds"]
+	if !adds.Passed || adds.Duration != time.Millisecond || strings.Join(adds.Suite, "::") != "math::tests" {
+		t.Errorf("adds = %+v", adds)
+	}
+	if adds.Framework != Cargo {
+		t.Errorf("framework = %q", adds.Framework)
+	}
+
+	sub := byName["subtracts"]
+	if !sub.Failed {
+		t.Fatalf("expected subtracts to fail: %+v", sub)
+	}
+	d := sub.FailureDetail
+	if d == nil || d.Kind != FailureKindGomega {
+		t.Fatalf("FailureDetail = %+v, want gomega kind", d)
+	}
+	if d.Actual != "1" || d.Expected != "2" || d.Matcher != "to equal" {
+		t.Errorf("actual/matcher/expected = %q/%q/%q", d.Actual, d.Matcher, d.Expected)
+	}
+	if d.Location != "crates/calc/src/math.rs:21" {
+		t.Errorf("location = %q", d.Location)
+	}
+
+	if slow := byName["slow"]; !slow.Skipped || slow.Message != "needs network" {
+		t.Errorf("ignored test = %+v", slow)
+	}
+
+	empty := byName["rejects_empty"]
+	if !empty.Failed || empty.Duration != 61500*time.Millisecond {
+		t.Errorf("timeout warning should be replaced by the final event: %+v", empty)
+	}
+	if ed := empty.FailureDetail; ed == nil || ed.Kind != FailureKindPanic || ed.Summary != "panic: empty input" || !strings.Contains(ed.Stack, "rust_begin_unwind") {
+		t.Errorf("panic detail = %+v", ed)
+	}
+
+	doc := byName["add"]
+	if doc.File != "crates/calc/src/lib.rs" || doc.Line != 5 || !doc.Passed {
+		t.Errorf("doc-test = %+v", doc)
+	}
+	if got := CargoTestPath(doc); got != "add" {
+		t.Errorf("CargoTestPath(doc) = %q", got)
+	}
+	if got := CargoTestPath(sub); got != "math::tests::subtracts" {
+		t.Errorf("CargoTestPath = %q", got)
+	}
+}
+
+func TestCargoTestJSON_ParseStream(t *testing.T) {
+	data, err := os.ReadFile("testdata/cargo-libtest.json")
+	if err != nil {
+		t.Fatalf("read fixture: %v", err)
+	}
+	var out bytes.Buffer
+	passed, failed, err := NewCargoTestJSON("/repo", "").ParseStream(bytes.NewReader(data), &out, nil)
+	if err != nil {
+		t.Fatalf("ParseStream: %v", err)
+	}
+	if passed != 2 || failed != 2 {
+		t.Errorf("passed/failed = %d/%d, want 2/2", passed, failed)
+	}
+	if out.Len() != len(data) {
+		t.Errorf("stdout copy = %d bytes, want %d", out.Len(), len(data))
+	}
+}
+
+func TestParseCargoPanic_Legacy(t *testing.T) {
+	out := "thread 'tests::eq' panicked at 'assertion failed: `(left == right)`\n  left: `\"a\"`,\n right: `\"b\"`', src/lib.rs:9:5\n"
+	d := parseCargoPanic(out, func(s string) string { return s })
+	if d == nil || d.Kind != FailureKindGomega {
+		t.Fatalf("detail = %+v", d)
+	}
+	if d.Actual != `"a"` || d.Expected != `"b"` || d.Location != "src/lib.rs:9" {
+		t.Errorf("detail = %+v", d)
+	}
+}
+
+func TestCargoNextestJUnit_Parse(t *testing.T) {
+	xml := `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites name="nextest-run" tests="2" failures="1" errors="0">
+  <testsuite name="calc" tests="2" failures="1">
+    <testcase name="math::tests::adds" classname="calc" time="0.004"/>
+    <testcase name="math::tests::subtracts" classname="calc" time="0.006">
+      <failure type="test failure">thread 'math::tests::subtracts' panicked at crates/calc/src/math.rs:21:9</failure>
+      <system-err>thread 'math::tests::subtracts' panicked at crates/calc/src/math.rs:21:9:
+assertion ` + "`left != right`" + ` failed
+  left: 2
+ right: 2
+</system-err>
+    </testcase>
+  </testsuite>
+</testsuites>`
+	tests, err := NewCargoNextestJUnit("/repo", "").Parse(strings.NewReader(xml))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if len(tests) != 2 {
+		t.Fatalf("expected 2 tests, got %d", len(tests))
+	}
+	if !tests[0].Passed || tests[0].Name != "adds" {
+		t.Errorf("first = %+v", tests[0])
+	}
+	d := tests[1].FailureDetail
+	if !tests[1].Failed || d == nil || d.Matcher != "not to equal" || d.Actual != "2" {
+		t.Errorf("second = %+v detail=%+v", tests[1], d)
+	}
+}
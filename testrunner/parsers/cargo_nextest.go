@@ -0,0 +1,80 @@
+package parsers
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/flanksource/clicky/task"
+)
+
+// CargoNextestJUnit parses the JUnit XML report cargo-nextest writes when a
+// profile sets `[profile.<name>.junit] path = ...`. nextest names each
+// <testsuite> after the binary id ("mycrate", "mycrate::integration") and
+// each <testcase> after the libtest name, so results take the same shape
+// as CargoTestJSON's.
+type CargoNextestJUnit struct {
+	workDir string
+	rootDir string
+}
+
+// NewCargoNextestJUnit returns a nextest JUnit parser. rootDir is the cargo
+// workspace root the run was started from; empty means workDir.
+func NewCargoNextestJUnit(workDir, rootDir string) *CargoNextestJUnit {
+	if rootDir == "" {
+		rootDir = workDir
+	}
+	return &CargoNextestJUnit{workDir: workDir, rootDir: rootDir}
+}
+
+func (p *CargoNextestJUnit) Name() string {
+	return "cargo nextest junit"
+}
+
+func (p *CargoNextestJUnit) ParseStream(output io.Reader, stdout io.Writer, t *task.Task) (int, int, error) {
+	return 0, 0, nil
+}
+
+func (p *CargoNextestJUnit) Parse(output io.Reader) ([]Test, error) {
+	suites, err := decodeJUnit(output)
+	if err != nil {
+		return nil, fmt.Errorf("parse nextest junit: %w", err)
+	}
+	rel := func(f string) string { return rootRelPath(p.workDir, p.rootDir, f) }
+
+	var tests []Test
+	for _, suite := range suites {
+		for _, c := range suite.Cases {
+			t := newCargoTest(c.Name, rel)
+			t.Duration = parseJUnitSeconds(c.Time)
+			t.Stdout = strings.TrimSpace(c.SystemOut)
+			t.Stderr = strings.TrimSpace(c.SystemErr)
+			switch {
+			case c.failed():
+				t.Failed = true
+				// nextest keeps the panic on the test's stderr and only a
+				// one-line summary in the <failure> body.
+				t.Message = strings.TrimSpace(strings.Join([]string{c.failureText(), t.Stderr}, "\n"))
+				t.FailureDetail = parseCargoPanic(nextestPanicOutput(c.failureText(), t.Stderr, t.Stdout), rel)
+			case c.Skipped != nil:
+				t.Skipped = true
+				t.Message = strings.TrimSpace(c.Skipped.Message)
+			default:
+				t.Passed = true
+			}
+			tests = append(tests, t)
+		}
+	}
+	return tests, nil
+}
+
+// nextestPanicOutput picks the stream carrying the panic message, falling
+// back to the <failure> body when no stream has one.
+func nextestPanicOutput(failure string, streams ...string) string {
+	for _, s := range streams {
+		if strings.Contains(s, "' panicked at ") {
+			return s
+		}
+	}
+	return failure
+}
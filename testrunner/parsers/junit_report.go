@@ -0,0 +1,109 @@
+package parsers
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/flanksource/clicky/task"
+)
+
+// JUnitXML parses a plain JUnit XML report for frameworks declared in
+// .gavel.yaml (bats, maven surefire, dotnet test, ...). Unlike the pytest
+// and nextest parsers it assumes nothing about the producing tool: each
+// <testcase> becomes one Test whose Suite is its classname (or the
+// enclosing <testsuite> name when classname is empty).
+type JUnitXML struct {
+	framework Framework
+	workDir   string
+	// rootDir is the directory the command ran in; relative file
+	// attributes in the report are resolved against it.
+	rootDir string
+}
+
+// NewJUnitXML returns a parser that stamps every Test with framework. An
+// empty rootDir means the report was produced in workDir.
+func NewJUnitXML(framework Framework, workDir, rootDir string) *JUnitXML {
+	if rootDir == "" {
+		rootDir = workDir
+	}
+	return &JUnitXML{framework: framework, workDir: workDir, rootDir: rootDir}
+}
+
+func (p *JUnitXML) Name() string {
+	return "junit xml"
+}
+
+func (p *JUnitXML) ParseStream(output io.Reader, stdout io.Writer, t *task.Task) (int, int, error) {
+	return 0, 0, nil
+}
+
+func (p *JUnitXML) Parse(output io.Reader) ([]Test, error) {
+	suites, err := decodeJUnit(output)
+	if err != nil {
+		return nil, fmt.Errorf("parse %s junit: %w", p.framework, err)
+	}
+
+	var tests []Test
+	for _, suite := range suites {
+		for _, c := range suite.Cases {
+			tests = append(tests, p.toTest(suite, c))
+		}
+	}
+	return tests, nil
+}
+
+func (p *JUnitXML) toTest(suite junitSuite, c junitCase) Test {
+	file := c.File
+	if file == "" {
+		file = suite.File
+	}
+	t := Test{
+		Name:      c.Name,
+		File:      rootRelPath(p.workDir, p.rootDir, filepath.FromSlash(file)),
+		Duration:  parseJUnitSeconds(c.Time),
+		Framework: p.framework,
+		Stdout:    strings.TrimSpace(c.SystemOut),
+		Stderr:    strings.TrimSpace(c.SystemErr),
+	}
+	if name := strings.TrimSpace(c.ClassName); name != "" {
+		t.Suite = []string{name}
+	} else if name := strings.TrimSpace(suite.Name); name != "" {
+		t.Suite = []string{name}
+	}
+	if line, err := strconv.Atoi(c.Line); err == nil {
+		t.Line = line
+	}
+
+	switch {
+	case c.failed():
+		t.Failed = true
+		t.Message = c.failureText()
+		t.FailureDetail = ParseFailureDetail(t.Message)
+	case c.Skipped != nil:
+		t.Skipped = true
+		t.Message = strings.TrimSpace(c.Skipped.Message)
+	default:
+		t.Passed = true
+	}
+	return t
+}
+
+// rootRelPath resolves a report path (relative to the directory the tool
+// ran in) to a workDir-relative path. Paths outside workDir are returned
+// unchanged.
+func rootRelPath(workDir, rootDir, filePath string) string {
+	if filePath == "" || workDir == "" {
+		return filePath
+	}
+	abs := filePath
+	if !filepath.IsAbs(abs) {
+		abs = filepath.Join(rootDir, filePath)
+	}
+	if rel, err := filepath.Rel(workDir, abs); err == nil && !strings.HasPrefix(rel, "..") {
+		return filepath.ToSlash(rel)
+	}
+	return filePath
+}
@@ -0,0 +1,76 @@
+package parsers
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestJUnitXML_Parse(t *testing.T) {
+	data, err := os.ReadFile("testdata/surefire-junit.xml")
+	if err != nil {
+		t.Fatalf("read fixture: %v", err)
+	}
+	tests, err := NewJUnitXML("surefire", "/repo", "/repo").Parse(strings.NewReader(string(data)))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if len(tests) != 4 {
+		t.Fatalf("expected 4 tests, got %d: %+v", len(tests), tests)
+	}
+
+	byName := map[string]Test{}
+	for _, tst := range tests {
+		if tst.Framework != "surefire" {
+			t.Errorf("%s: framework = %q, want surefire", tst.Name, tst.Framework)
+		}
+		byName[tst.Name] = tst
+	}
+
+	pass := byName["addsNumbers"]
+	if !pass.Passed || pass.Duration != 4*time.Millisecond {
+		t.Errorf("addsNumbers = %+v", pass)
+	}
+	if len(pass.Suite) != 1 || pass.Suite[0] != "com.example.CalculatorTest" {
+		t.Errorf("suite = %v, want classname", pass.Suite)
+	}
+
+	errored := byName["dividesByZero"]
+	if !errored.Failed || !strings.Contains(errored.Message, "/ by zero") {
+		t.Errorf("<error> should fail the test with its body: %+v", errored)
+	}
+	if errored.Stdout != "dividing 1 by 0" {
+		t.Errorf("stdout = %q", errored.Stdout)
+	}
+	if errored.FailureDetail == nil {
+		t.Error("expected a FailureDetail for a failed test")
+	}
+
+	failed := byName["subtracts"]
+	if !failed.Failed || failed.Message != "expected: <3> but was: <4>" {
+		t.Errorf("empty <failure> body should fall back to message: %+v", failed)
+	}
+
+	skipped := byName["pending"]
+	if !skipped.Skipped || skipped.Message != "not implemented" {
+		t.Errorf("pending = %+v", skipped)
+	}
+	if len(skipped.Suite) != 1 || skipped.Suite[0] != "com.example.CalculatorTest" {
+		t.Errorf("case without classname should use the suite name, got %v", skipped.Suite)
+	}
+}
+
+func TestRootRelPath(t *testing.T) {
+	cases := []struct{ root, file, want string }{
+		{"/repo/java", "src/Foo.java", "java/src/Foo.java"},
+		{"/repo", "/repo/test/a.bats", "test/a.bats"},
+		{"/repo", "/elsewhere/a.bats", "/elsewhere/a.bats"},
+		{"/repo", "", ""},
+	}
+	for _, c := range cases {
+		if got := rootRelPath("/repo", c.root, c.file); got != c.want {
+			t.Errorf("rootRelPath(%q, %q) = %q, want %q", c.root, c.file, got, c.want)
+		}
+	}
+}
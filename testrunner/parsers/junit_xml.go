@@ -0,0 +1,113 @@
+package parsers
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// junitReport is the generic JUnit XML schema shared by every tool that
+// emits it (pytest --junitxml, cargo nextest, maven surefire, ...). Tools
+// disagree on whether the root is <testsuites> or a bare <testsuite>, so
+// decodeJUnit normalises both into a flat list of suites.
+type junitReport struct {
+	XMLName xml.Name     `xml:"testsuites"`
+	Suites  []junitSuite `xml:"testsuite"`
+}
+
+type junitSuite struct {
+	Name      string       `xml:"name,attr"`
+	Time      string       `xml:"time,attr"`
+	File      string       `xml:"file,attr"`
+	Suites    []junitSuite `xml:"testsuite"`
+	Cases     []junitCase  `xml:"testcase"`
+	SystemOut string       `xml:"system-out"`
+	SystemErr string       `xml:"system-err"`
+}
+
+type junitCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	File      string        `xml:"file,attr"`
+	Line      string        `xml:"line,attr"`
+	Time      string        `xml:"time,attr"`
+	Failures  []junitResult `xml:"failure"`
+	Errors    []junitResult `xml:"error"`
+	Skipped   *junitResult  `xml:"skipped"`
+	SystemOut string        `xml:"system-out"`
+	SystemErr string        `xml:"system-err"`
+}
+
+// junitResult is the body of a <failure>, <error> or <skipped> element.
+type junitResult struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// failed reports whether the case carries a <failure> or <error>. JUnit
+// draws a line between assertion failures and unexpected errors, but gavel
+// only has one failed state.
+func (c junitCase) failed() bool {
+	return len(c.Failures) > 0 || len(c.Errors) > 0
+}
+
+// failureText joins every <failure>/<error> body into one message, falling
+// back to the message attribute when a tool leaves the body empty.
+func (c junitCase) failureText() string {
+	var parts []string
+	for _, r := range append(append([]junitResult{}, c.Failures...), c.Errors...) {
+		text := strings.TrimSpace(r.Text)
+		if text == "" {
+			text = strings.TrimSpace(r.Message)
+		}
+		if text != "" {
+			parts = append(parts, text)
+		}
+	}
+	return strings.Join(parts, "\n\n")
+}
+
+// decodeJUnit reads a JUnit XML document whose root is either <testsuites>
+// or <testsuite> and returns the suites flattened depth-first.
+func decodeJUnit(r io.Reader) ([]junitSuite, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("read junit report: %w", err)
+	}
+	var report junitReport
+	if err := xml.Unmarshal(data, &report); err != nil {
+		var suite junitSuite
+		if err2 := xml.Unmarshal(data, &suite); err2 != nil {
+			return nil, fmt.Errorf("parse junit xml: %w", err)
+		}
+		report.Suites = []junitSuite{suite}
+	}
+	var out []junitSuite
+	var walk func([]junitSuite)
+	walk = func(suites []junitSuite) {
+		for _, s := range suites {
+			out = append(out, s)
+			walk(s.Suites)
+		}
+	}
+	walk(report.Suites)
+	return out, nil
+}
+
+// parseJUnitSeconds converts a JUnit time attribute ("0.012", "1,234.5")
+// into a Duration. Unparseable values read as zero.
+func parseJUnitSeconds(s string) time.Duration {
+	s = strings.ReplaceAll(strings.TrimSpace(s), ",", "")
+	if s == "" {
+		return 0
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0
+	}
+	return time.Duration(f * float64(time.Second))
+}
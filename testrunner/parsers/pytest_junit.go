@@ -0,0 +1,366 @@
+package parsers
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/flanksource/clicky/task"
+)
+
+// PytestJUnit parses the JUnit XML report pytest writes with
+// `--junitxml=<path> -o junit_family=xunit1`. The xunit1 family is required
+// because it is the only one that emits file/line attributes on each
+// <testcase>.
+type PytestJUnit struct {
+	workDir string
+	// rootDir is the pytest rootdir the report's file attributes are
+	// relative to. It differs from workDir when a pytest project lives in a
+	// subdirectory of the repo gavel was started in.
+	rootDir string
+}
+
+// NewPytestJUnit returns a parser that resolves report paths against
+// rootDir and normalises them relative to workDir. An empty rootDir means
+// the report was produced with workDir as the pytest rootdir.
+func NewPytestJUnit(workDir, rootDir string) *PytestJUnit {
+	if rootDir == "" {
+		rootDir = workDir
+	}
+	return &PytestJUnit{workDir: workDir, rootDir: rootDir}
+}
+
+func (p *PytestJUnit) Name() string {
+	return "pytest junit"
+}
+
+func (p *PytestJUnit) ParseStream(output io.Reader, stdout io.Writer, t *task.Task) (int, int, error) {
+	return 0, 0, nil
+}
+
+func (p *PytestJUnit) Parse(output io.Reader) ([]Test, error) {
+	suites, err := decodeJUnit(output)
+	if err != nil {
+		return nil, fmt.Errorf("parse pytest junit: %w", err)
+	}
+
+	var tests []Test
+	// Parametrized cases ("test_add[1-2]") are grouped under a synthetic
+	// parent named after the function. parents maps the grouping key to the
+	// parent's index in tests so cases keep their report order.
+	parents := map[string]int{}
+	for _, suite := range suites {
+		for _, c := range suite.Cases {
+			t := p.toTest(c)
+			base, params := splitPytestParams(c.Name)
+			if params == "" {
+				tests = append(tests, t)
+				continue
+			}
+			key := t.File + "::" + strings.Join(t.Suite, "::") + "::" + base
+			idx, ok := parents[key]
+			if !ok {
+				tests = append(tests, Test{
+					Name:      base,
+					Suite:     t.Suite,
+					File:      t.File,
+					Line:      t.Line,
+					Framework: Pytest,
+				})
+				idx = len(tests) - 1
+				parents[key] = idx
+			}
+			tests[idx].Children = append(tests[idx].Children, t)
+		}
+	}
+	for _, idx := range parents {
+		aggregatePytestParent(&tests[idx])
+	}
+	return tests, nil
+}
+
+func (p *PytestJUnit) toTest(c junitCase) Test {
+	file := p.relPath(c.File)
+	if file == "" {
+		file = pytestModuleFile(c.ClassName)
+	}
+	t := Test{
+		Name:      c.Name,
+		Suite:     pytestClassChain(c.ClassName, file),
+		File:      file,
+		Duration:  parseJUnitSeconds(c.Time),
+		Framework: Pytest,
+		Stdout:    stripPytestCaptureHeaders(c.SystemOut),
+		Stderr:    stripPytestCaptureHeaders(c.SystemErr),
+	}
+	// pytest writes 0-based line numbers (the `def` line minus one).
+	if line, err := strconv.Atoi(c.Line); err == nil {
+		t.Line = line + 1
+	}
+
+	switch {
+	case c.failed():
+		t.Failed = true
+		t.Message = c.failureText()
+		t.FailureDetail = parsePytestFailure(t.Message)
+		if t.FailureDetail != nil && t.FailureDetail.Location != "" {
+			if file, line := splitPytestLocation(t.FailureDetail.Location); file != "" {
+				t.FailureDetail.Location = fmt.Sprintf("%s:%d", p.relPath(file), line)
+			}
+		}
+	case c.Skipped != nil:
+		t.Skipped = true
+		t.Message = strings.TrimSpace(c.Skipped.Message)
+	default:
+		t.Passed = true
+	}
+	return t
+}
+
+// aggregatePytestParent derives a parametrized parent's status and
+// duration from its cases: failed if any case failed, skipped only when
+// every case was skipped, passed otherwise.
+func aggregatePytestParent(t *Test) {
+	allSkipped := true
+	for _, c := range t.Children {
+		t.Duration += c.Duration
+		if c.Failed {
+			t.Failed = true
+		}
+		if !c.Skipped {
+			allSkipped = false
+		}
+	}
+	switch {
+	case t.Failed:
+	case allSkipped:
+		t.Skipped = true
+	default:
+		t.Passed = true
+	}
+}
+
+// splitPytestParams splits "test_add[1-2]" into ("test_add", "1-2"). Names
+// without a parameter id return an empty params string.
+func splitPytestParams(name string) (base, params string) {
+	i := strings.Index(name, "[")
+	if i <= 0 || !strings.HasSuffix(name, "]") {
+		return name, ""
+	}
+	return name[:i], name[i+1 : len(name)-1]
+}
+
+// PytestBaseName strips a parametrize id from a pytest test name so it can
+// be used as a `-k` expression ("test_add[1-2]" → "test_add").
+func PytestBaseName(name string) string {
+	base, _ := splitPytestParams(name)
+	return base
+}
+
+// pytestClassChain returns the test-class nesting encoded in a JUnit
+// classname. pytest builds classname from the module's dotted path plus any
+// enclosing classes ("tests.test_math.TestAdd.TestNested"); the module
+// prefix is dropped because BuildTestTree already groups by file.
+func pytestClassChain(className, file string) []string {
+	if className == "" {
+		return nil
+	}
+	if module := pytestModulePath(file); module != "" {
+		if className == module {
+			return nil
+		}
+		if rest, ok := strings.CutPrefix(className, module+"."); ok {
+			return strings.Split(rest, ".")
+		}
+	}
+	// No usable file attribute: treat the trailing Capitalised segments as
+	// classes, which matches pytest's Test* class naming convention.
+	parts := strings.Split(className, ".")
+	i := len(parts)
+	for i > 0 && parts[i-1] != "" && parts[i-1][0] >= 'A' && parts[i-1][0] <= 'Z' {
+		i--
+	}
+	if i == len(parts) {
+		return nil
+	}
+	return parts[i:]
+}
+
+// pytestModulePath turns "tests/test_math.py" into "tests.test_math".
+func pytestModulePath(file string) string {
+	if file == "" {
+		return ""
+	}
+	file = strings.TrimSuffix(filepath.ToSlash(file), ".py")
+	return strings.ReplaceAll(file, "/", ".")
+}
+
+// pytestModuleFile is the inverse of pytestModulePath for reports without a
+// file attribute: the leading lowercase segments of a classname are taken
+// as the module path.
+func pytestModuleFile(className string) string {
+	var mod []string
+	for _, part := range strings.Split(className, ".") {
+		if part == "" || (part[0] >= 'A' && part[0] <= 'Z') {
+			break
+		}
+		mod = append(mod, part)
+	}
+	if len(mod) == 0 {
+		return ""
+	}
+	return strings.Join(mod, "/") + ".py"
+}
+
+// pytestCaptureHeaderRe matches the centred banner pytest writes above each
+// captured stream when junit_logging is enabled, e.g.
+// "------------------ Captured Out ------------------".
+var pytestCaptureHeaderRe = regexp.MustCompile(`^-+ Captured (Out|Err|Log) -+$`)
+
+func stripPytestCaptureHeaders(s string) string {
+	if s == "" {
+		return ""
+	}
+	lines := strings.Split(s, "\n")
+	kept := lines[:0]
+	for _, line := range lines {
+		if pytestCaptureHeaderRe.MatchString(strings.TrimSpace(line)) {
+			continue
+		}
+		kept = append(kept, line)
+	}
+	return strings.TrimSpace(strings.Join(kept, "\n"))
+}
+
+var (
+	// pytestLocationRe matches the trailer pytest prints under each
+	// traceback entry: "tests/test_math.py:14: AssertionError".
+	pytestLocationRe = regexp.MustCompile(`^(\S+\.py):(\d+): (\S.*)$`)
+	// pytestAssertRe matches the rewritten assertion pytest prints on the
+	// first "E" line: "assert 4 == 5", "assert 'a' in 'xyz'".
+	pytestAssertRe = regexp.MustCompile(`^assert (.+?) (==|!=|<=|>=|<|>|not in|in|is not|is) (.+)$`)
+	// pytestExceptionRe matches an "E   ValueError: boom" style line.
+	pytestExceptionRe = regexp.MustCompile(`^([A-Za-z_][\w.]*(?:Error|Exception|Exit|Interrupt|Warning)): ?(.*)$`)
+)
+
+var pytestMatchers = map[string]string{
+	"==":     "to equal",
+	"!=":     "not to equal",
+	"<":      "to be <",
+	"<=":     "to be <=",
+	">":      "to be >",
+	">=":     "to be >=",
+	"in":     "to be in",
+	"not in": "not to be in",
+	"is":     "to be",
+	"is not": "not to be",
+}
+
+// parsePytestFailure builds a FailureDetail from a pytest longrepr. The
+// lines pytest prefixes with "E" carry the explanation; the last
+// "file.py:NN: Type" trailer is the innermost frame.
+//
+// A rewritten comparison ("assert 4 == 5") is reported as
+// FailureKindGomega so the UI shows Actual/Expected side-by-side; an
+// uncaught exception becomes FailureKindPanic with the traceback as Stack.
+func parsePytestFailure(msg string) *FailureDetail {
+	var explanation []string
+	location := ""
+	for _, line := range strings.Split(msg, "\n") {
+		if rest, ok := strings.CutPrefix(line, "E "); ok || line == "E" {
+			explanation = append(explanation, strings.TrimSpace(rest))
+			continue
+		}
+		if m := pytestLocationRe.FindStringSubmatch(strings.TrimSpace(line)); m != nil {
+			location = m[1] + ":" + m[2]
+		}
+	}
+	if len(explanation) == 0 {
+		d := ParseFailureDetail(msg)
+		if d != nil && d.Location == "" {
+			d.Location = location
+		}
+		return d
+	}
+
+	for _, line := range explanation {
+		line = strings.TrimPrefix(line, "AssertionError: ")
+		m := pytestAssertRe.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		matcher := pytestMatchers[m[2]]
+		return &FailureDetail{
+			Kind:     FailureKindGomega,
+			Summary:  buildGomegaSummary(m[1], matcher, m[3]),
+			Matcher:  matcher,
+			Actual:   m[1],
+			Expected: m[3],
+			Location: location,
+		}
+	}
+
+	headline := explanation[0]
+	if pytestExceptionRe.MatchString(headline) {
+		return &FailureDetail{
+			Kind:     FailureKindPanic,
+			Summary:  oneLine(headline, summaryMaxLen),
+			Actual:   headline,
+			Location: location,
+			Stack:    strings.TrimSpace(msg),
+		}
+	}
+	return &FailureDetail{
+		Kind:     FailureKindRaw,
+		Summary:  oneLine(strings.Join(explanation, "\n"), summaryMaxLen),
+		Location: location,
+	}
+}
+
+// splitPytestLocation splits "path.py:NN" into its parts.
+func splitPytestLocation(loc string) (string, int) {
+	i := strings.LastIndex(loc, ":")
+	if i <= 0 {
+		return "", 0
+	}
+	line, err := strconv.Atoi(loc[i+1:])
+	if err != nil {
+		return "", 0
+	}
+	return loc[:i], line
+}
+
+// relPath resolves a report path against the pytest rootdir and returns it
+// relative to workDir. Report paths are rootdir-relative, but when gavel's
+// guess at the rootdir is wrong the workDir-relative reading is tried too.
+func (p *PytestJUnit) relPath(filePath string) string {
+	if filePath == "" || p.workDir == "" {
+		return filePath
+	}
+	candidates := []string{filePath}
+	if !filepath.IsAbs(filePath) {
+		candidates = []string{filepath.Join(p.rootDir, filePath), filepath.Join(p.workDir, filePath)}
+	}
+	for _, abs := range candidates {
+		if _, err := os.Stat(abs); err != nil {
+			continue
+		}
+		if rel, err := filepath.Rel(p.workDir, abs); err == nil {
+			return filepath.ToSlash(rel)
+		}
+	}
+	if filepath.IsAbs(filePath) {
+		if rel, err := filepath.Rel(p.workDir, filePath); err == nil {
+			return filepath.ToSlash(rel)
+		}
+		return filePath
+	}
+	if rel, err := filepath.Rel(p.workDir, filepath.Join(p.rootDir, filePath)); err == nil {
+		return filepath.ToSlash(rel)
+	}
+	return filePath
+}
@@ -0,0 +1,133 @@
+package parsers
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPytestJUnit_Parse(t *testing.T) {
+	data, err := os.ReadFile("testdata/pytest-junit.xml")
+	if err != nil {
+		t.Fatalf("read fixture: %v", err)
+	}
+	tests, err := NewPytestJUnit("/repo", "/repo").Parse(strings.NewReader(string(data)))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if len(tests) != 5 {
+		t.Fatalf("expected 5 top-level tests, got %d: %+v", len(tests), tests)
+	}
+
+	byName := map[string]Test{}
+	for _, tst := range tests {
+		byName[tst.Name] = tst
+	}
+
+	t.Run("parametrized cases group under the function", func(t *testing.T) {
+		add, ok := byName["test_add"]
+		if !ok {
+			t.Fatal("missing parametrized parent 'test_add'")
+		}
+		if len(add.Children) != 2 {
+			t.Fatalf("expected 2 children, got %d", len(add.Children))
+		}
+		if !add.Failed || add.Passed {
+			t.Errorf("parent should be failed when any case fails: %+v", add)
+		}
+		if add.Duration != 3*time.Millisecond {
+			t.Errorf("parent duration = %v, want 3ms", add.Duration)
+		}
+		if add.File != "tests/test_math.py" || add.Line != 4 {
+			t.Errorf("parent location = %s:%d, want tests/test_math.py:4", add.File, add.Line)
+		}
+		if add.Children[0].Name != "test_add[1-2-3]" || !add.Children[0].Passed {
+			t.Errorf("first case = %+v", add.Children[0])
+		}
+	})
+
+	t.Run("assertion becomes expected/actual", func(t *testing.T) {
+		fail := byName["test_add"].Children[1]
+		if !fail.Failed {
+			t.Fatalf("expected failed case, got %+v", fail)
+		}
+		d := fail.FailureDetail
+		if d == nil || d.Kind != FailureKindGomega {
+			t.Fatalf("FailureDetail = %+v, want gomega kind", d)
+		}
+		if d.Actual != "4" || d.Expected != "5" || d.Matcher != "to equal" {
+			t.Errorf("actual/matcher/expected = %q/%q/%q", d.Actual, d.Matcher, d.Expected)
+		}
+		if d.Location != "tests/test_math.py:6" {
+			t.Errorf("location = %q", d.Location)
+		}
+		if fail.Stdout != "adding 2 + 2" {
+			t.Errorf("stdout = %q, want capture header stripped", fail.Stdout)
+		}
+	})
+
+	t.Run("class chain becomes suite", func(t *testing.T) {
+		upper := byName["test_upper"]
+		if strings.Join(upper.Suite, ">") != "TestStrings" {
+			t.Errorf("suite = %v, want [TestStrings]", upper.Suite)
+		}
+		if !upper.Passed {
+			t.Errorf("expected pass: %+v", upper)
+		}
+		if add := byName["test_add"]; len(add.Suite) != 0 {
+			t.Errorf("module-level test should have no suite, got %v", add.Suite)
+		}
+	})
+
+	t.Run("exception becomes panic with stack", func(t *testing.T) {
+		split := byName["test_split"]
+		d := split.FailureDetail
+		if d == nil || d.Kind != FailureKindPanic {
+			t.Fatalf("FailureDetail = %+v, want panic kind", d)
+		}
+		if d.Summary != "ValueError: boom" {
+			t.Errorf("summary = %q", d.Summary)
+		}
+		if d.Location != "app/parse.py:2" {
+			t.Errorf("location = %q, want innermost frame", d.Location)
+		}
+		if split.Stderr != "warning: empty input" {
+			t.Errorf("stderr = %q", split.Stderr)
+		}
+	})
+
+	t.Run("skipped and errored cases", func(t *testing.T) {
+		if later := byName["test_later"]; !later.Skipped || later.Message != "not ready" {
+			t.Errorf("skipped case = %+v", later)
+		}
+		if conn := byName["test_connect"]; !conn.Failed || !strings.Contains(conn.Message, "ConnectionError: refused") {
+			t.Errorf("errored case = %+v", conn)
+		}
+	})
+}
+
+func TestPytestJUnit_BareTestsuiteRoot(t *testing.T) {
+	xml := `<testsuite name="pytest" tests="1"><testcase classname="test_app" file="test_app.py" line="0" name="test_ok" time="0.5"/></testsuite>`
+	tests, err := NewPytestJUnit("/repo", "").Parse(strings.NewReader(xml))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if len(tests) != 1 || !tests[0].Passed || tests[0].Duration != 500*time.Millisecond {
+		t.Fatalf("unexpected tests: %+v", tests)
+	}
+}
+
+func TestPytestBaseName(t *testing.T) {
+	cases := map[string]string{
+		"test_add[1-2-3]":  "test_add",
+		"test_plain":       "test_plain",
+		"test_ids[a[0]-b]": "test_ids",
+		"[weird]":          "[weird]",
+	}
+	for in, want := range cases {
+		if got := PytestBaseName(in); got != want {
+			t.Errorf("PytestBaseName(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
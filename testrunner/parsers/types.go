@@ -23,6 +23,8 @@ const (
 	Jest       Framework = "jest"
 	Vitest     Framework = "vitest"
 	Playwright Framework = "playwright"
+	Pytest     Framework = "pytest"
+	Cargo      Framework = "cargo"
 )
 
 // String returns the string representation of the framework.
@@ -32,7 +34,7 @@ func (f Framework) String() string {
 
 // AllFrameworks lists every framework gavel knows how to run. Order is
 // stable so help text and error messages read the same on every invocation.
-var AllFrameworks = []Framework{GoTest, Ginkgo, Jest, Vitest, Playwright}
+var AllFrameworks = []Framework{GoTest, Ginkgo, Jest, Vitest, Playwright, Pytest, Cargo}
 
 // ParseFramework resolves a user-supplied name to a known Framework. It
 // accepts the canonical value ("go test", "ginkgo", ...) and the tolerant
@@ -50,6 +52,10 @@ func ParseFramework(name string) (Framework, error) {
 		return Vitest, nil
 	case "playwright":
 		return Playwright, nil
+	case "pytest", "py", "python":
+		return Pytest, nil
+	case "cargo", "cargo test", "cargo-test", "rust", "nextest":
+		return Cargo, nil
 	}
 	known := make([]string, len(AllFrameworks))
 	for i, f := range AllFrameworks {
@@ -90,6 +96,14 @@ type Test struct {
 	TimedOut bool   `json:"timed_out,omitempty"` // True when the test package subprocess was killed by the --test-timeout or --timeout supervisor
 	TaskID   string `json:"task_id,omitempty"`
 	CanStop  bool   `json:"can_stop,omitempty"`
+	// Flaky marks a test that failed and then passed on a --retries attempt.
+	// It is Passed; Attempts holds the failing tries.
+	Flaky bool `json:"flaky,omitempty"`
+	// Quarantined marks a test listed in .gavel.yaml test.quarantine. It
+	// still runs and reports its real outcome, but a quarantined failure is
+	// counted under TestSummary.Quarantined instead of Failed and never fails
+	// the build.
+	Quarantined bool `json:"quarantined,omitempty"`
 	// IsGinkgoBootstrap marks a Go test function whose body only invokes ginkgo's RunSpecs.
 	// These wrappers still carry pass/fail/duration for the whole suite when a Ginkgo
 	// JSON report file is unavailable, but are deduped against real specs from the
@@ -266,6 +280,12 @@ func (t Test) Pretty() api.Text {
 	if t.TimedOut {
 		s = s.Space().Append("[timed out]", "text-amber-600 font-bold")
 	}
+	if t.Flaky {
+		s = s.Space().Append(fmt.Sprintf("[flaky: passed on attempt %d]", len(t.Attempts)), "text-orange-500 font-bold")
+	}
+	if t.Quarantined {
+		s = s.Space().Append("[quarantined]", "text-muted font-bold")
+	}
 
 	// Add benchmark metrics if present
 	if t.Benchmark != nil {
@@ -370,6 +390,14 @@ func (t Test) RerunCommand() string {
 			return fmt.Sprintf(`npx playwright test %s`, t.File)
 		}
 		return fmt.Sprintf(`npx playwright test -g %q`, t.FullName())
+	case Pytest:
+		if t.File != "" {
+			nodeID := strings.Join(append(append([]string{t.File}, t.Suite...), t.Name), "::")
+			return fmt.Sprintf(`pytest %q`, nodeID)
+		}
+		return fmt.Sprintf(`pytest -k %q`, PytestBaseName(t.Name))
+	case Cargo:
+		return fmt.Sprintf(`cargo test %s -- --exact`, CargoTestPath(t))
 	default:
 		return ""
 	}
@@ -447,7 +475,9 @@ func (tr Test) Sum() TestSummary {
 		Duration: tr.Duration,
 		Total:    1,
 	}
-	if tr.Failed {
+	if tr.Failed && tr.Quarantined {
+		summary.Quarantined = 1
+	} else if tr.Failed {
 		summary.Failed = 1
 	} else if tr.Warned {
 		summary.Warned = 1
@@ -455,6 +485,9 @@ func (tr Test) Sum() TestSummary {
 		summary.Skipped = 1
 	} else if tr.Passed {
 		summary.Passed = 1
+		if tr.Flaky {
+			summary.Flaky = 1
+		}
 	} else if tr.Running {
 		summary.Running = 1
 	} else if tr.Pending {
@@ -472,6 +505,8 @@ func (tr Test) Sum() TestSummary {
 		summary.Skipped += childSummary.Skipped
 		summary.Pending += childSummary.Pending
 		summary.Running += childSummary.Running
+		summary.Flaky += childSummary.Flaky
+		summary.Quarantined += childSummary.Quarantined
 		summary.Duration += childSummary.Duration
 	}
 
@@ -599,6 +634,10 @@ type TestSummary struct {
 	Pending  int
 	Running  int
 	Duration time.Duration
+	// Flaky counts passed tests that needed a retry; they are also in Passed.
+	Flaky int
+	// Quarantined counts failed quarantined tests; they are not in Failed.
+	Quarantined int
 }
 
 func (s TestSummary) Pretty() api.Text {
@@ -613,6 +652,12 @@ func (s TestSummary) Pretty() api.Text {
 	if s.Warned > 0 {
 		t = t.Add(clicky.KeyValue(" warned", s.Warned, "text-amber-500")).Append(" ")
 	}
+	if s.Flaky > 0 {
+		t = t.Add(clicky.KeyValue(" flaky", s.Flaky, "text-orange-500")).Append(" ")
+	}
+	if s.Quarantined > 0 {
+		t = t.Add(clicky.KeyValue(" quarantined", s.Quarantined, "muted")).Append(" ")
+	}
 	if s.Skipped > 0 {
 		t = t.Add(clicky.KeyValue(" skipped", s.Skipped, "text-yellow-500")).Append(" ")
 	}
@@ -629,14 +674,16 @@ func (s TestSummary) Pretty() api.Text {
 
 func (tr TestSummary) Add(other TestSummary) TestSummary {
 	return TestSummary{
-		Total:    tr.Total + other.Total,
-		Passed:   tr.Passed + other.Passed,
-		Failed:   tr.Failed + other.Failed,
-		Warned:   tr.Warned + other.Warned,
-		Skipped:  tr.Skipped + other.Skipped,
-		Pending:  tr.Pending + other.Pending,
-		Running:  tr.Running + other.Running,
-		Duration: tr.Duration + other.Duration,
+		Total:       tr.Total + other.Total,
+		Passed:      tr.Passed + other.Passed,
+		Failed:      tr.Failed + other.Failed,
+		Warned:      tr.Warned + other.Warned,
+		Skipped:     tr.Skipped + other.Skipped,
+		Pending:     tr.Pending + other.Pending,
+		Running:     tr.Running + other.Running,
+		Flaky:       tr.Flaky + other.Flaky,
+		Quarantined: tr.Quarantined + other.Quarantined,
+		Duration:    tr.Duration + other.Duration,
 	}
 }
 
@@ -685,6 +732,8 @@ func (tr Test) Filter(filter TestFilter) Test {
 		Warned:            tr.Warned,
 		Skipped:           tr.Skipped,
 		Passed:            tr.Passed,
+		Flaky:             tr.Flaky,
+		Quarantined:       tr.Quarantined,
 		Stdout:            tr.Stdout,
 		Stderr:            tr.Stderr,
 		Framework:         tr.Framework,
@@ -47,7 +47,12 @@ func (o *TestOrchestrator) preBuildGoPackages(pkgs []string) error {
 	}
 
 	name := fmt.Sprintf("Pre-build (compiling %d Go test %s)", len(pkgs), plural(len(pkgs), "package", "packages"))
-	process := exec.NewExec("go", goPreBuildArgs(pkgs)...).WithCwd(o.WorkDir).WithProcessGroup()
+	args := goPreBuildArgs(pkgs)
+	if o.CoverageEnabled() {
+		// Coverage runs compile instrumented binaries; warm those instead.
+		args = append([]string{"test", "-count=0", "-cover"}, pkgs...)
+	}
+	process := exec.NewExec("go", args...).WithCwd(o.WorkDir).WithProcessGroup()
 	if o.OutputTee != nil {
 		process = process.Stream(o.OutputTee, o.OutputTee)
 	}
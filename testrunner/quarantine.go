@@ -0,0 +1,108 @@
+package testrunner
+
+import (
+	"path"
+	"strings"
+
+	"github.com/flanksource/gavel/testrunner/parsers"
+)
+
+// quarantineList matches tests against the .gavel.yaml test.quarantine
+// patterns. An entry is a test name or "<./package/path>:<name>"; names are
+// path.Match globs compared against the test's name, its full
+// "suite > name" path and each of its suites.
+type quarantineList []quarantineEntry
+
+type quarantineEntry struct {
+	pkg  string
+	name string
+}
+
+func newQuarantineList(patterns []string) quarantineList {
+	var q quarantineList
+	for _, p := range patterns {
+		p = strings.TrimSpace(p)
+		if p == "" {
+			continue
+		}
+		entry := quarantineEntry{name: p}
+		if pkg, name, ok := strings.Cut(p, ":"); ok && strings.HasPrefix(pkg, ".") {
+			entry = quarantineEntry{pkg: cleanPackagePath(pkg), name: name}
+		}
+		q = append(q, entry)
+	}
+	return q
+}
+
+func cleanPackagePath(p string) string {
+	p = strings.TrimSuffix(strings.TrimPrefix(p, "./"), "/")
+	if p == "" {
+		return "."
+	}
+	return p
+}
+
+func (q quarantineList) matches(t parsers.Test) bool {
+	for _, e := range q {
+		if e.pkg != "" && e.pkg != cleanPackagePath(t.PackagePath) {
+			continue
+		}
+		candidates := append([]string{t.Name, t.FullName()}, t.Suite...)
+		for _, c := range candidates {
+			if c == e.name {
+				return true
+			}
+			if ok, _ := path.Match(e.name, c); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// apply marks quarantined tests (and everything under a quarantined parent)
+// in results, then quarantines parents whose only failures are quarantined
+// descendants so they do not fail the package either.
+func (q quarantineList) apply(results parsers.TestSuiteResults) {
+	if len(q) == 0 {
+		return
+	}
+	for ri := range results {
+		q.mark(results[ri].Tests, false)
+		q.markSubtests(results[ri].Tests)
+		settleParents(results[ri].Tests, func(parent *parsers.Test, descendants []*parsers.Test) {
+			quarantined := false
+			for _, d := range descendants {
+				if d.Failed && !d.Quarantined {
+					return
+				}
+				quarantined = quarantined || (d.Failed && d.Quarantined)
+			}
+			parent.Quarantined = quarantined
+		})
+	}
+}
+
+func (q quarantineList) mark(tests parsers.Tests, inherited bool) {
+	for i := range tests {
+		if inherited || q.matches(tests[i]) {
+			tests[i].Quarantined = true
+		}
+		q.mark(tests[i].Children, tests[i].Quarantined)
+	}
+}
+
+// markSubtests extends a quarantined Go test to its subtests, which the Go
+// parser reports as flat siblings named "<parent>/<subtest>".
+func (q quarantineList) markSubtests(tests parsers.Tests) {
+	for i := range tests {
+		if !tests[i].Quarantined {
+			continue
+		}
+		for j := range tests {
+			if strings.HasPrefix(tests[j].Name, tests[i].Name+"/") {
+				tests[j].Quarantined = true
+			}
+		}
+	}
+}
@@ -5,8 +5,10 @@ import (
 
 	"github.com/flanksource/clicky"
 	"github.com/flanksource/clicky/api"
+	"github.com/flanksource/commons/logger"
 	"github.com/flanksource/gavel/testrunner/parsers"
 	"github.com/flanksource/gavel/testrunner/runners"
+	"github.com/flanksource/gavel/verify"
 	"github.com/samber/lo"
 )
 
@@ -40,10 +42,35 @@ func DefaultRegistry(workDir string) *Registry {
 	reg.Register(runners.NewJest(workDir))
 	reg.Register(runners.NewVitest(workDir))
 	reg.Register(runners.NewPlaywright(workDir))
+	reg.Register(runners.NewPytest(workDir))
+	reg.Register(runners.NewCargo(workDir))
 
 	return reg
 }
 
+// RegisterCustomFrameworks adds a JUnit runner for each test.frameworks entry
+// from .gavel.yaml. Entries missing a name, command or report, or reusing a
+// built-in framework name, are skipped with a warning rather than failing
+// the whole run.
+func (r *Registry) RegisterCustomFrameworks(frameworks []verify.TestFrameworkConfig) {
+	for _, fw := range frameworks {
+		if fw.Name == "" || fw.Command == "" || fw.Report == "" {
+			logger.Warnf("test.frameworks: %q needs name, command and report; skipping", fw.Name)
+			continue
+		}
+		if _, err := parsers.ParseFramework(fw.Name); err == nil {
+			logger.Warnf("test.frameworks: %q is a built-in framework; skipping", fw.Name)
+			continue
+		}
+		r.Register(runners.NewJUnit(r.workDir, runners.JUnitSpec{
+			Name:    Framework(fw.Name),
+			Detect:  fw.Detect,
+			Command: fw.Command,
+			Report:  fw.Report,
+		}))
+	}
+}
+
 // Register adds a test runner to the registry.
 func (r *Registry) Register(runner runners.Runner) {
 	if runner == nil {
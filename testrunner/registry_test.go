@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/flanksource/gavel/testrunner/runners"
+	"github.com/flanksource/gavel/verify"
 )
 
 func TestRegistryDefaultFactory(t *testing.T) {
@@ -34,6 +35,25 @@ func TestRegistryDefaultFactory(t *testing.T) {
 	}
 }
 
+func TestRegistryCustomFrameworks(t *testing.T) {
+	registry := DefaultRegistry(t.TempDir())
+	registry.RegisterCustomFrameworks([]verify.TestFrameworkConfig{
+		{Name: "bats", Detect: "**/*.bats", Command: "bats test", Report: "report.xml"},
+		{Name: "incomplete", Command: "make test"},
+		{Name: "pytest", Command: "pytest", Report: "junit.xml"},
+	})
+
+	if _, ok := registry.Get("bats"); !ok {
+		t.Error("expected bats runner to be registered")
+	}
+	if _, ok := registry.Get("incomplete"); ok {
+		t.Error("framework without a report should be skipped")
+	}
+	if r, _ := registry.Get("pytest"); r == nil || r.Parser().Name() != "pytest junit" {
+		t.Error("custom framework must not replace a built-in runner")
+	}
+}
+
 func TestRegistryDetectAll(t *testing.T) {
 	tmpDir := t.TempDir()
 
@@ -0,0 +1,188 @@
+package testrunner
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/flanksource/clicky/task"
+	commonsCtx "github.com/flanksource/commons/context"
+	"github.com/flanksource/commons/logger"
+	"github.com/flanksource/gavel/testrunner/parsers"
+	"github.com/flanksource/gavel/testrunner/runners"
+)
+
+// runKindRetry tags the TestAttempts produced by --retries.
+const runKindRetry = "retry"
+
+// retryFailedTests re-runs the failed tests of one package up to o.Retries
+// times, folding each attempt into result. A test that passes on a retry
+// becomes Passed + Flaky; one that keeps failing stays Failed with every
+// try recorded in Attempts. Timed-out packages are not retried — another
+// full timeout rarely tells the user anything new.
+func (o *TestOrchestrator) retryFailedTests(
+	ctx commonsCtx.Context,
+	pkgPath string,
+	framework Framework,
+	runner runners.Runner,
+	t *task.Task,
+	extraArgs []string,
+	result packageResult,
+) packageResult {
+	if result.timedOut {
+		return result
+	}
+	for attempt := 1; attempt <= o.Retries; attempt++ {
+		names := retryableTestNames(result.testResults)
+		if len(names) == 0 {
+			break
+		}
+		if ctx.Err() != nil {
+			break
+		}
+		logger.Infof("[%s] %s: retry %d/%d for %d failed tests", framework, pkgPath, attempt, o.Retries, len(names))
+		args := append(append([]string{}, extraArgs...), testSelectorArgs(framework, names)...)
+		retry, err := o.runPackageTest(ctx, pkgPath, framework, runner, t, args, runKindRetry)
+		if err != nil {
+			logger.Warnf("[%s] %s: retry %d failed: %v", framework, pkgPath, attempt, err)
+			break
+		}
+		mergeRetryResults(result.testResults, retry.testResults)
+		if retry.timedOut {
+			break
+		}
+	}
+	setPackageTaskStatus(t, framework, pkgPath, result.testResults)
+	return result
+}
+
+// retryableTestNames returns the names of the failed tests a retry should
+// select. Quarantined failures never fail the build and are not retried;
+// synthetic results (build / execution failures) carry no framework and
+// cannot be selected by name.
+func retryableTestNames(results parsers.TestSuiteResults) []string {
+	var names []string
+	var walk func(tests parsers.Tests)
+	walk = func(tests parsers.Tests) {
+		for _, t := range tests {
+			if t.Failed && !t.Quarantined && !t.TimedOut && t.Framework != "" && len(t.Children) == 0 {
+				names = append(names, t.Name)
+			}
+			walk(t.Children)
+		}
+	}
+	for _, tr := range results {
+		walk(tr.Tests)
+	}
+	return names
+}
+
+// mergeRetryResults folds a retry run into the original results. Every
+// originally failed test that the retry reran takes the retry's outcome and
+// gains its attempts; tests the retry did not report are left untouched.
+func mergeRetryResults(results, retry parsers.TestSuiteResults) {
+	byKey := map[string]*parsers.Test{}
+	for ri := range retry {
+		indexTests(retry[ri].Tests, "", byKey)
+	}
+	for ri := range results {
+		applyRetry(results[ri].Tests, "", byKey)
+		settleFlakyParents(results[ri].Tests)
+	}
+}
+
+func testKey(prefix string, t parsers.Test) string {
+	return prefix + "\x00" + strings.Join(t.Suite, "\x00") + "\x00" + t.Name
+}
+
+func indexTests(tests parsers.Tests, prefix string, byKey map[string]*parsers.Test) {
+	for i := range tests {
+		key := testKey(prefix, tests[i])
+		byKey[key] = &tests[i]
+		indexTests(tests[i].Children, key, byKey)
+	}
+}
+
+func applyRetry(tests parsers.Tests, prefix string, byKey map[string]*parsers.Test) {
+	for i := range tests {
+		t := &tests[i]
+		key := testKey(prefix, *t)
+		applyRetry(t.Children, key, byKey)
+		r, ok := byKey[key]
+		if !ok || !t.Failed || t.Quarantined || r.Pending || r.IsFolder() {
+			continue
+		}
+		// ginkgo --focus reports the specs it did not select as skipped.
+		if r.Skipped && !r.Failed {
+			continue
+		}
+		for _, a := range r.Attempts {
+			a.Sequence = len(t.Attempts) + 1
+			t.Attempts = append(t.Attempts, a)
+		}
+		t.Failed = r.Failed
+		t.Passed = r.Passed
+		t.TimedOut = r.TimedOut
+		t.Duration = r.Duration
+		t.Message = r.Message
+		t.Stdout = r.Stdout
+		t.Stderr = r.Stderr
+		t.FailureDetail = r.FailureDetail
+		t.Flaky = r.Passed && !r.Failed
+	}
+}
+
+// settleFlakyParents clears the failure of a parent whose failing
+// descendants all turned out flaky. Parents are either tree parents
+// (Children) or, in the flat lists the Go parser produces, the test whose
+// name prefixes "<parent>/<subtest>".
+func settleFlakyParents(tests parsers.Tests) {
+	settleParents(tests, func(parent *parsers.Test, descendants []*parsers.Test) {
+		flaky := false
+		for _, d := range descendants {
+			if d.Failed && !d.Quarantined {
+				return
+			}
+			flaky = flaky || d.Flaky
+		}
+		if flaky {
+			parent.Failed = false
+			parent.Passed = true
+			parent.Flaky = true
+		}
+	})
+}
+
+// settleParents calls fn bottom-up for every failed, unquarantined test that
+// has descendants, so a parent sees its descendants' final status.
+func settleParents(tests parsers.Tests, fn func(parent *parsers.Test, descendants []*parsers.Test)) {
+	for i := range tests {
+		settleParents(tests[i].Children, fn)
+	}
+	// Deepest names first so TestA/b is settled before TestA.
+	order := make([]int, len(tests))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return strings.Count(tests[order[a]].Name, "/") > strings.Count(tests[order[b]].Name, "/")
+	})
+	for _, i := range order {
+		parent := &tests[i]
+		if !parent.Failed || parent.Quarantined {
+			continue
+		}
+		var descendants []*parsers.Test
+		for j := range parent.Children {
+			descendants = append(descendants, &parent.Children[j])
+		}
+		for j := range tests {
+			if j != i && strings.Join(tests[j].Suite, "\x00") == strings.Join(parent.Suite, "\x00") &&
+				strings.HasPrefix(tests[j].Name, parent.Name+"/") {
+				descendants = append(descendants, &tests[j])
+			}
+		}
+		if len(descendants) > 0 {
+			fn(parent, descendants)
+		}
+	}
+}
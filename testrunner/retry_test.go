@@ -0,0 +1,197 @@
+package testrunner
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/flanksource/gavel/testrunner/parsers"
+)
+
+func attempt(passed bool) []parsers.TestAttempt {
+	return []parsers.TestAttempt{{Sequence: 1, Passed: passed, Failed: !passed}}
+}
+
+func TestMergeRetryResults(t *testing.T) {
+	results := parsers.TestSuiteResults{{Tests: parsers.Tests{
+		{Name: "TestFoo", Failed: true, Message: "first", Attempts: attempt(false)},
+		{Name: "TestFoo/sub", Failed: true, Attempts: attempt(false)},
+		{Name: "TestBar", Failed: true, Attempts: attempt(false)},
+		{Name: "TestOK", Passed: true, Attempts: attempt(true)},
+	}}}
+	retry := parsers.TestSuiteResults{{Tests: parsers.Tests{
+		{Name: "TestFoo", Passed: true, Attempts: attempt(true)},
+		{Name: "TestFoo/sub", Passed: true, Attempts: attempt(true)},
+		{Name: "TestBar", Failed: true, Message: "again", Attempts: attempt(false)},
+	}}}
+
+	mergeRetryResults(results, retry)
+	tests := results[0].Tests
+
+	for _, i := range []int{0, 1} {
+		if !tests[i].Passed || tests[i].Failed || !tests[i].Flaky {
+			t.Errorf("%s should be passed+flaky, got %+v", tests[i].Name, tests[i])
+		}
+	}
+	if tests[0].Message != "" {
+		t.Errorf("TestFoo should carry the passing attempt's (empty) message, got %q", tests[0].Message)
+	}
+	if len(tests[0].Attempts) != 2 || tests[0].Attempts[1].Sequence != 2 || !tests[0].Attempts[1].Passed {
+		t.Errorf("TestFoo attempts = %+v", tests[0].Attempts)
+	}
+	if !tests[2].Failed || tests[2].Flaky || tests[2].Message != "again" || len(tests[2].Attempts) != 2 {
+		t.Errorf("TestBar should stay failed with both attempts, got %+v", tests[2])
+	}
+	if len(tests[3].Attempts) != 1 {
+		t.Errorf("TestOK was not retried, got %d attempts", len(tests[3].Attempts))
+	}
+
+	sum := parsers.Tests(tests).Sum()
+	if sum.Failed != 1 || sum.Flaky != 2 || sum.Passed != 3 {
+		t.Errorf("summary = %+v", sum)
+	}
+}
+
+func TestMergeRetryResults_ParentSettlesWhenChildrenFlaky(t *testing.T) {
+	results := parsers.TestSuiteResults{{Tests: parsers.Tests{{
+		Name:   "Suite",
+		Failed: true,
+		Children: parsers.Tests{
+			{Name: "spec a", Suite: []string{"Suite"}, Failed: true},
+			{Name: "spec b", Suite: []string{"Suite"}, Passed: true},
+		},
+	}}}}
+	retry := parsers.TestSuiteResults{{Tests: parsers.Tests{{
+		Name: "Suite",
+		Children: parsers.Tests{
+			{Name: "spec a", Suite: []string{"Suite"}, Passed: true},
+			{Name: "spec b", Suite: []string{"Suite"}, Skipped: true},
+		},
+	}}}}
+
+	mergeRetryResults(results, retry)
+	suite := results[0].Tests[0]
+	if suite.Failed || !suite.Flaky {
+		t.Errorf("suite should settle to flaky, got %+v", suite)
+	}
+	if !suite.Children[1].Passed || suite.Children[1].Skipped {
+		t.Errorf("a spec ginkgo skipped on the retry must keep its result, got %+v", suite.Children[1])
+	}
+}
+
+func TestQuarantineApply(t *testing.T) {
+	results := parsers.TestSuiteResults{{Tests: parsers.Tests{
+		{Name: "TestFoo", PackagePath: "./pkg", Failed: true},
+		{Name: "TestFoo/flaky_case", PackagePath: "./pkg", Failed: true},
+		{Name: "TestBar", PackagePath: "./pkg", Failed: true},
+		{Name: "TestNetworkDial", PackagePath: "./pkg", Passed: true},
+	}}}
+
+	newQuarantineList([]string{"TestFoo/flaky_case", "./other:TestBar", "TestNetwork*"}).apply(results)
+	tests := results[0].Tests
+
+	if !tests[1].Quarantined {
+		t.Error("TestFoo/flaky_case should be quarantined")
+	}
+	if !tests[0].Quarantined {
+		t.Error("TestFoo only fails through its quarantined subtest and should be quarantined too")
+	}
+	if tests[2].Quarantined {
+		t.Error("./other:TestBar must not match a test in ./pkg")
+	}
+	if !tests[3].Quarantined {
+		t.Error("TestNetwork* should match TestNetworkDial")
+	}
+
+	sum := parsers.Tests(tests).Sum()
+	if sum.Failed != 1 || sum.Quarantined != 2 {
+		t.Errorf("summary = %+v, want 1 failed and 2 quarantined", sum)
+	}
+	if got := retryableTestNames(results); !reflect.DeepEqual(got, []string{"TestBar"}) {
+		t.Errorf("retryable = %v, want only TestBar", got)
+	}
+}
+
+func TestQuarantineApply_ParentCoversSubtests(t *testing.T) {
+	results := parsers.TestSuiteResults{{Tests: parsers.Tests{
+		{Name: "TestFoo", Failed: true},
+		{Name: "TestFoo/a", Failed: true},
+		{Name: "TestFoobar", Failed: true},
+	}}}
+	newQuarantineList([]string{"TestFoo"}).apply(results)
+	tests := results[0].Tests
+	if !tests[0].Quarantined || !tests[1].Quarantined {
+		t.Errorf("TestFoo and its subtest should be quarantined: %+v", tests)
+	}
+	if tests[2].Quarantined {
+		t.Error("TestFoobar is not a subtest of TestFoo")
+	}
+}
+
+func TestTestSelectorArgs(t *testing.T) {
+	cases := []struct {
+		fw    Framework
+		names []string
+		want  []string
+	}{
+		{parsers.GoTest, []string{"TestFoo/sub case", "TestFoo", "TestBar"}, []string{"-run", "^(TestFoo|TestBar)$"}},
+		{parsers.Ginkgo, []string{"works", "fails"}, []string{"--focus", "works|fails"}},
+		{parsers.Cargo, []string{"a::b", "a::b"}, []string{"a::b"}},
+		{parsers.Jest, []string{"adds"}, nil},
+	}
+	for _, tc := range cases {
+		if got := testSelectorArgs(tc.fw, tc.names); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("testSelectorArgs(%s, %v) = %v, want %v", tc.fw, tc.names, got, tc.want)
+		}
+	}
+}
+
+func TestRunRetriesFlakyGoTest(t *testing.T) {
+	repo := t.TempDir()
+	writeGoTestPackage(t, repo, "pkg1", "example.com/repo")
+	// Fails on the first run and passes once the marker file exists.
+	flaky := `package sample
+
+import (
+	"os"
+	"testing"
+)
+
+func TestFlaky(t *testing.T) {
+	if _, err := os.Stat("ran-once"); err != nil {
+		_ = os.WriteFile("ran-once", nil, 0o644)
+		t.Fatal("first attempt fails")
+	}
+}
+`
+	if err := os.WriteFile(filepath.Join(repo, "pkg1", "flaky_test.go"), []byte(flaky), 0o644); err != nil {
+		t.Fatalf("write flaky test: %v", err)
+	}
+
+	var summary parsers.TestSummary
+	result, err := Run(RunOptions{
+		WorkDir:       repo,
+		StartingPaths: []string{filepath.Join(repo, "pkg1")},
+		Retries:       2,
+		SummaryOut:    &summary,
+	})
+	if err != nil {
+		t.Fatalf("Run returned error: %v", err)
+	}
+
+	leaves := collectLeavesMatching(result.([]parsers.Test), func(t parsers.Test) bool { return t.Name == "TestFlaky" })
+	if len(leaves) != 1 {
+		t.Fatalf("expected one TestFlaky leaf, got %d", len(leaves))
+	}
+	got := leaves[0]
+	if !got.Passed || !got.Flaky || got.Failed {
+		t.Errorf("TestFlaky should be passed+flaky, got passed=%v flaky=%v failed=%v", got.Passed, got.Flaky, got.Failed)
+	}
+	if len(got.Attempts) != 2 || got.Attempts[1].RunKind != runKindRetry {
+		t.Errorf("attempts = %+v, want an initial failure and one retry", got.Attempts)
+	}
+	if summary.Failed != 0 || summary.Flaky != 1 {
+		t.Errorf("summary = %+v, want no failures and 1 flaky", summary)
+	}
+}
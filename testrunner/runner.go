@@ -27,6 +27,8 @@ import (
 	"github.com/flanksource/commons/logger"
 	"github.com/flanksource/gavel/baseline"
 	"github.com/flanksource/gavel/fixtures"
+	"github.com/flanksource/gavel/internal/runcache"
+	"github.com/flanksource/gavel/testrunner/coverage"
 	"github.com/flanksource/gavel/testrunner/parsers"
 	"github.com/flanksource/gavel/testrunner/runners"
 	"github.com/flanksource/gavel/utils"
@@ -104,6 +106,13 @@ type TestOrchestrator struct {
 	registry *Registry
 	streamer *TestStreamer
 	selector *selectorContext
+	coverage coverageCollector
+	// quarantine holds the .gavel.yaml test.quarantine patterns.
+	quarantine quarantineList
+
+	// cacheOptions configures the --cache store from .gavel.yaml and the
+	// GAVEL_CACHE_* environment.
+	cacheOptions runcache.Options
 }
 
 // RunOptions configures test execution behavior.
@@ -137,16 +146,29 @@ type RunOptions struct {
 	Cache         bool                  `json:"cache,omitempty" flag:"cache"`                                 // Skip packages whose content fingerprint matches the last passing run
 	Changed       bool                  `json:"changed,omitempty" flag:"changed"`                             // Only run packages affected by staged/unstaged/untracked changes and the diff against origin/main
 	Since         string                `json:"since,omitempty" flag:"since"`                                 // Only run packages affected by the diff since <ref> (merge-base(HEAD,ref)..HEAD) plus the working tree
+	ChangedLines  bool                  `json:"changed_lines,omitempty" flag:"changed-lines"`                 // With --lint, only report violations on lines added or modified in the --changed/--since diff (the uncommitted changes when neither is set)
+	LinesContext  int                   `json:"changed_lines_context,omitempty" flag:"changed-lines-context"` // With --changed-lines, also keep violations within N lines of a change
 	Bench         string                `json:"bench,omitempty" flag:"bench"`                                 // Run Go benchmarks matching this regex ("." or "true" runs all). Auto-enabled for packages containing only Benchmark* funcs.
+	Coverage      bool                  `json:"coverage,omitempty" flag:"coverage"`                           // Collect line coverage from go test, ginkgo, jest and vitest and attach it to the results
+	DiffCoverage  int                   `json:"min_diff_coverage,omitempty" flag:"min-diff-coverage"`         // Minimum coverage (percent) of changed lines; the run fails below it. Implies --coverage.
 	Fixtures      bool                  `json:"fixtures,omitempty" flag:"fixtures"`                           // Discover and run fixture files. Off by default; can also be enabled via .gavel.yaml fixtures.enabled
 	FixtureFiles  []string              `json:"fixture_files,omitempty" flag:"fixture-files"`                 // Globs for fixture discovery. Overrides .gavel.yaml fixtures.files. Default: **/*.fixture.md
 	Frameworks    []string              `json:"frameworks,omitempty" flag:"framework"`                        // Restrict execution to these frameworks (e.g. jest, vitest, playwright, go, ginkgo). Empty = run every detected framework. Unknown names hard-fail.
 	Baseline      string                `json:"baseline,omitempty" flag:"baseline"`                           // Path to previous results JSON; only report NEW failures not in baseline
 	Failed        string                `json:"failed,omitempty" flag:"failed"`                               // Path to previous results JSON; re-run only failed tests
+	Retries       int                   `json:"retries,omitempty" flag:"retries"`                             // Re-run each failed test up to N more times; a test that then passes is reported flaky instead of failed
+	Shard         string                `json:"shard,omitempty" flag:"shard"`                                 // Run only shard <index>/<total> of the discovered packages (e.g. 2/5), balanced by --shard-timings
+	ShardTimings  string                `json:"shard_timings,omitempty" flag:"shard-timings"`                 // gavel JSON result of an earlier run (e.g. the gavel merge output) whose per-package durations balance --shard; every shard must get the same file
+	Watch         bool                  `json:"watch,omitempty" flag:"watch"`                                 // After the run, keep watching the working tree and rerun the packages affected by each save until interrupted
 	Updates       chan<- []parsers.Test `json:"-"`                                                            // Channel for streaming test result updates to UI
 	OutputTee     io.Writer             `json:"-"`                                                            // Optional writer that receives a copy of raw process stdout/stderr
-	RunKind       string                `json:"run_kind,omitempty"`                                           // "initial" (default) or "rerun" — tagged onto each TestAttempt produced
+	RunKind       string                `json:"run_kind,omitempty"`                                           // "initial" (default) or "rerun" — tagged onto each TestAttempt produced; --retries attempts are tagged "retry"
 	SummaryOut    *parsers.TestSummary  `json:"-"`                                                            // If non-nil, the runner writes the aggregate pass/fail/skip/total/duration counts here before returning, so CLI callers can print an end-of-run summary even when the run errors mid-way and returns a partial tree.
+	CoverageOut   *coverage.Profile     `json:"-"`                                                            // If non-nil and coverage is enabled, the runner merges the collected line coverage here, keyed by paths relative to WorkDir.
+
+	// watch is set by Watch on each iteration: the saved files replace the
+	// git diff as the change set.
+	watch *watchIteration
 }
 
 func (opts RunOptions) Pretty() api.Text {
@@ -191,6 +213,15 @@ func (opts RunOptions) Pretty() api.Text {
 	if opts.Since != "" {
 		text = text.Space().Append("Since: ", "text-muted").Append(opts.Since, "text-blue-500")
 	}
+	if opts.ChangedLines {
+		text = text.Space().Append("ChangedLines: ", "text-muted").Append(icons.Check, "text-green-500")
+	}
+	if opts.CoverageEnabled() {
+		text = text.Space().Append("Coverage: ", "text-muted").Append(icons.Check, "text-green-500")
+	}
+	if opts.DiffCoverage > 0 {
+		text = text.Space().Append("MinDiffCoverage: ", "text-muted").Append(fmt.Sprintf("%d%%", opts.DiffCoverage), "text-blue-500")
+	}
 	if opts.Fixtures {
 		text = text.Space().Append("Fixtures: ", "text-muted").Append(icons.Check, "text-green-500")
 	}
@@ -212,13 +243,36 @@ func (opts RunOptions) Pretty() api.Text {
 	if opts.Concurrency > 0 {
 		text = text.Space().Append("Concurrency: ", "text-muted").Append(fmt.Sprintf("%d", opts.Concurrency), "text-blue-500")
 	}
+	if opts.Retries > 0 {
+		text = text.Space().Append("Retries: ", "text-muted").Append(fmt.Sprintf("%d", opts.Retries), "text-blue-500")
+	}
+	if opts.Shard != "" {
+		text = text.Space().Append("Shard: ", "text-muted").Append(opts.Shard, "text-blue-500")
+	}
+	if opts.Watch {
+		text = text.Space().Append("Watch: ", "text-muted").Append(icons.Check, "text-green-500")
+	}
 	return text
 }
 
 // hasChangeSelector reports whether any flag in opts narrows execution to a
 // subset of packages via the change graph.
 func (opts RunOptions) hasChangeSelector() bool {
-	return opts.Changed || opts.Since != ""
+	return opts.Changed || opts.Since != "" || opts.watch != nil
+}
+
+// runKind is the TestAttempt.RunKind stamped on a package's first run.
+func (opts RunOptions) runKind() string {
+	if opts.RunKind == "" {
+		return "initial"
+	}
+	return opts.RunKind
+}
+
+// CoverageEnabled reports whether package runs should collect coverage. A
+// diff-coverage threshold is meaningless without it.
+func (opts RunOptions) CoverageEnabled() bool {
+	return opts.Coverage || opts.DiffCoverage > 0
 }
 
 func (r RunOptions) Help() string {
@@ -396,6 +450,14 @@ func Run(opts RunOptions) (any, error) {
 	if opts.WorkDir == "" {
 		opts.WorkDir, _ = os.Getwd()
 	}
+	if _, err := parseShard(opts.Shard); err != nil {
+		return nil, err
+	}
+	if opts.Cache && opts.DiffCoverage > 0 {
+		// Cached packages are not run, so their changed lines would have no
+		// coverage and read as untested
+		return nil, fmt.Errorf("--cache cannot be combined with --min-diff-coverage")
+	}
 
 	// Split starting paths by execution root so each group runs with the
 	// correct WorkDir. Nested Go modules get their own groups.
@@ -431,6 +493,10 @@ func runMultiRoot(base RunOptions, groups []testGroup) (any, error) {
 		opts := base
 		opts.WorkDir = g.workDir
 		opts.StartingPaths = g.paths
+		var groupCoverage coverage.Profile
+		if base.CoverageOut != nil {
+			opts.CoverageOut = &groupCoverage
+		}
 
 		var result any
 		var err error
@@ -461,6 +527,12 @@ func runMultiRoot(base RunOptions, groups []testGroup) (any, error) {
 		if err != nil {
 			return nil, err
 		}
+		if base.CoverageOut != nil {
+			absBase, _ := filepath.Abs(baseWorkDir)
+			absRoot, _ := filepath.Abs(g.workDir)
+			rel, _ := filepath.Rel(absBase, absRoot)
+			base.CoverageOut.Merge(groupCoverage.Rebase(filepath.ToSlash(rel)))
+		}
 		if streamedRoot != nil {
 			streamedTree = append(streamedTree, *streamedRoot)
 			sendTestUpdate(base.Updates, append([]parsers.Test(nil), streamedTree...))
@@ -585,10 +657,23 @@ func runSingleRootWithUpdateOwnership(opts RunOptions, closeUpdates bool) (any,
 		defer streamer.Done()
 	}
 
+	registry := DefaultRegistry(opts.WorkDir)
+	var quarantine quarantineList
+	var cacheOptions runcache.Options
+	if cfg, err := verify.LoadGavelConfig(opts.WorkDir); err != nil {
+		logger.Warnf("failed to load .gavel.yaml test config: %v", err)
+	} else {
+		registry.RegisterCustomFrameworks(cfg.Test.Frameworks)
+		quarantine = newQuarantineList(cfg.Test.Quarantine)
+		cacheOptions = runcache.Options{URL: cfg.Test.Cache.URL, Mode: cfg.Test.Cache.Mode}
+	}
+
 	t := &TestOrchestrator{
-		RunOptions: opts,
-		registry:   DefaultRegistry(opts.WorkDir),
-		streamer:   streamer,
+		RunOptions:   opts,
+		registry:     registry,
+		streamer:     streamer,
+		quarantine:   quarantine,
+		cacheOptions: cacheOptions.WithEnv(),
 	}
 	results, err := t.Run()
 	// Populate SummaryOut from whatever completed before the error, so the
@@ -596,6 +681,9 @@ func runSingleRootWithUpdateOwnership(opts RunOptions, closeUpdates bool) (any,
 	if opts.SummaryOut != nil {
 		*opts.SummaryOut = opts.SummaryOut.Add(results.All().Sum())
 	}
+	if opts.CoverageOut != nil {
+		opts.CoverageOut.Merge(t.coverage.profile)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -621,7 +709,7 @@ func runSingleRootWithUpdateOwnership(opts RunOptions, closeUpdates bool) (any,
 		}
 		if fixtureTree != nil {
 			for _, child := range fixtureTree.Children {
-				tree = append(tree, fixtureNodeToTests(child)...)
+				tree = append(tree, FixtureNodeToTests(child)...)
 			}
 		}
 	}
@@ -735,7 +823,13 @@ func filterFrameworks(detected []Framework, requested []string) ([]Framework, er
 	for _, name := range requested {
 		fw, err := parsers.ParseFramework(name)
 		if err != nil {
-			return nil, err
+			// Frameworks declared in .gavel.yaml have no alias table; they
+			// match by exact name once detected.
+			custom := Framework(strings.TrimSpace(name))
+			if !detectedSet[custom] {
+				return nil, err
+			}
+			fw = custom
 		}
 		if seen[fw] {
 			continue
@@ -750,9 +844,10 @@ func filterFrameworks(detected []Framework, requested []string) ([]Framework, er
 }
 
 // applyFailedFilter loads a previous Snapshot and narrows packagesByFramework
-// to only packages that had failures. It also injects framework-specific
-// --run/--focus args to target specific failed tests.
-func applyFailedFilter(packagesByFramework map[Framework][]string, extraArgs []string, failedPath string) (map[Framework][]string, []string, error) {
+// to only packages that had failures. It also returns framework-specific
+// -run/--focus/-k args that target the failed tests; these are keyed by
+// framework so one runner's selector never leaks into another's command.
+func applyFailedFilter(packagesByFramework map[Framework][]string, failedPath string) (map[Framework][]string, map[Framework][]string, error) {
 	snapshot, err := baseline.LoadSnapshot(failedPath)
 	if err != nil {
 		return nil, nil, fmt.Errorf("--failed: %w", err)
@@ -763,7 +858,7 @@ func applyFailedFilter(packagesByFramework map[Framework][]string, extraArgs []s
 	}
 
 	filtered := make(map[Framework][]string)
-	var goTestNames, ginkgoTestNames []string
+	namesByFramework := make(map[Framework][]string)
 
 	for fw, pkgs := range packagesByFramework {
 		failedForFW, ok := failedPkgs[parsers.Framework(fw)]
@@ -780,26 +875,47 @@ func applyFailedFilter(packagesByFramework map[Framework][]string, extraArgs []s
 			}
 		}
 		for _, names := range failedForFW {
-			switch parsers.Framework(fw) {
-			case parsers.GoTest:
-				goTestNames = append(goTestNames, names...)
-			case parsers.Ginkgo:
-				ginkgoTestNames = append(ginkgoTestNames, names...)
-			}
+			namesByFramework[fw] = append(namesByFramework[fw], names...)
 		}
 	}
 
-	if len(goTestNames) > 0 {
-		pattern := "^(" + strings.Join(escapeRegexNames(goTestNames), "|") + ")$"
-		extraArgs = append(extraArgs, "-run", pattern)
-	}
-	if len(ginkgoTestNames) > 0 {
-		pattern := strings.Join(ginkgoTestNames, "|")
-		extraArgs = append(extraArgs, "--focus", pattern)
+	argsByFramework := make(map[Framework][]string)
+	for fw, names := range namesByFramework {
+		if args := testSelectorArgs(fw, names); len(args) > 0 {
+			argsByFramework[fw] = args
+		}
 	}
 
 	logger.Infof("--failed: narrowed to %d frameworks from %s", len(filtered), failedPath)
-	return filtered, extraArgs, nil
+	return filtered, argsByFramework, nil
+}
+
+// testSelectorArgs returns the framework flags that restrict a run to the
+// named tests, or nil when the framework has no per-test selector and the
+// whole package must run. Go subtests are selected through their top-level
+// test: `go test -run` splits its pattern on "/", so a subtest path inside
+// an alternation is not a valid selector.
+func testSelectorArgs(fw Framework, names []string) []string {
+	if len(names) == 0 {
+		return nil
+	}
+	switch parsers.Framework(fw) {
+	case parsers.GoTest:
+		top := lo.Uniq(lo.Map(names, func(name string, _ int) string {
+			root, _, _ := strings.Cut(name, "/")
+			return root
+		}))
+		return []string{"-run", "^(" + strings.Join(escapeRegexNames(top), "|") + ")$"}
+	case parsers.Ginkgo:
+		return []string{"--focus", strings.Join(names, "|")}
+	case parsers.Pytest:
+		base := lo.Uniq(lo.Map(names, func(name string, _ int) string { return parsers.PytestBaseName(name) }))
+		return []string{"-k", strings.Join(base, " or ")}
+	case parsers.Cargo:
+		// libtest takes several substring filters and runs tests matching any.
+		return lo.Uniq(names)
+	}
+	return nil
 }
 
 func escapeRegexNames(names []string) []string {
@@ -879,18 +995,23 @@ func (o *TestOrchestrator) detectAndRun(frameworks []Framework, startingPaths []
 
 	// Narrow via change graph and/or run cache. Both selectors share a
 	// single lazily-loaded graph/hasher instance via selectorContext.
-	if o.hasChangeSelector() || o.Cache {
+	if graph := o.watchGraph(); graph != nil {
+		o.selector = newSelectorContextWithGraph(o.WorkDir, graph, o.cacheOptions)
+	} else if o.hasChangeSelector() || o.Cache {
 		var err error
-		o.selector, err = newSelectorContext(o.WorkDir)
+		o.selector, err = newSelectorContext(o.WorkDir, o.cacheOptions)
 		if err != nil {
 			return nil, fmt.Errorf("initialize selector: %w", err)
 		}
 	}
 
 	if o.hasChangeSelector() && o.selector != nil {
-		diff := diffOptionsFromRunOptions(o.RunOptions)
+		changed, err := o.changedFiles()
+		if err != nil {
+			return nil, err
+		}
 		for fw, pkgs := range packagesByFramework {
-			kept, err := o.selector.filterByChangeGraph(pkgs, diff)
+			kept, err := o.selector.filterByChangeGraph(fw, pkgs, changed)
 			if err != nil {
 				return nil, fmt.Errorf("change-graph filter for %s: %w", fw, err)
 			}
@@ -901,6 +1022,10 @@ func (o *TestOrchestrator) detectAndRun(frameworks []Framework, startingPaths []
 		}
 	}
 
+	if shard, _ := parseShard(o.Shard); shard.enabled() {
+		o.applyShard(shard, packagesByFramework)
+	}
+
 	cachedByFramework := map[Framework][]cacheHit{}
 	if o.Cache && o.selector != nil {
 		for fw, pkgs := range packagesByFramework {
@@ -917,9 +1042,10 @@ func (o *TestOrchestrator) detectAndRun(frameworks []Framework, startingPaths []
 	}
 
 	// Narrow to previously-failed packages/tests when --failed is set.
+	var failedArgs map[Framework][]string
 	if o.Failed != "" {
 		var err error
-		packagesByFramework, extraArgs, err = applyFailedFilter(packagesByFramework, extraArgs, o.Failed)
+		packagesByFramework, failedArgs, err = applyFailedFilter(packagesByFramework, o.Failed)
 		if err != nil {
 			return nil, err
 		}
@@ -927,7 +1053,7 @@ func (o *TestOrchestrator) detectAndRun(frameworks []Framework, startingPaths []
 
 	// If dry-run mode, display what would be executed and return early
 	if o.DryRun {
-		return o.displayDryRun(packagesByFramework, extraArgs), nil
+		return o.displayDryRun(packagesByFramework, extraArgs, failedArgs), nil
 	}
 
 	// Compile all Go test binaries up front so the per-package timeout below
@@ -968,9 +1094,9 @@ func (o *TestOrchestrator) detectAndRun(frameworks []Framework, startingPaths []
 			continue
 		}
 
-		fwExtraArgs := extraArgs
+		fwExtraArgs := append(append([]string{}, extraArgs...), failedArgs[fw]...)
 		if fw == parsers.Ginkgo && o.Nodes != 0 {
-			fwExtraArgs = append([]string{fmt.Sprintf("--nodes=%d", o.Nodes)}, extraArgs...)
+			fwExtraArgs = append([]string{fmt.Sprintf("--nodes=%d", o.Nodes)}, fwExtraArgs...)
 		}
 
 		for _, pkgPath := range packages {
@@ -986,7 +1112,10 @@ func (o *TestOrchestrator) detectAndRun(frameworks []Framework, startingPaths []
 
 			taskName := fmt.Sprintf("%s %s", fw, pkgPath)
 			group.Add(taskName, func(ctx commonsCtx.Context, t *task.Task) (packageResult, error) {
-				result, err := o.runPackageTest(ctx, pkgPath, fw, runner, t, pkgExtraArgs)
+				result, err := o.runPackageTest(ctx, pkgPath, fw, runner, t, pkgExtraArgs, o.runKind())
+				if err == nil && o.Retries > 0 {
+					result = o.retryFailedTests(ctx, pkgPath, fw, runner, t, pkgExtraArgs, result)
+				}
 				if o.streamer != nil {
 					o.streamer.CompletePackage(pkgPath, fw, result.testResults)
 				}
@@ -1241,6 +1370,7 @@ func (o *TestOrchestrator) runPackageTest(
 	runner runners.Runner, // runners.Runner interface
 	t *task.Task,
 	extraArgs []string,
+	runKind string,
 ) (packageResult, error) {
 	// Prepend subprocess-timeout flags so the test binary fires its own
 	// timeout (goroutine dump / progress report) before gavel SIGKILLs it.
@@ -1249,6 +1379,17 @@ func (o *TestOrchestrator) runPackageTest(
 		extraArgs = append(subprocessTimeoutArgs(framework, subTimeout), extraArgs...)
 	}
 
+	// Coverage flags are prepended for the same reason.
+	var covTarget *coverageTarget
+	if o.CoverageEnabled() {
+		covArgs, target, err := o.coverageArgs(framework, pkgPath)
+		if err != nil {
+			logger.Warnf("coverage disabled for %s: %v", pkgPath, err)
+		}
+		extraArgs = append(covArgs, extraArgs...)
+		covTarget = target
+	}
+
 	// Build the test command (without executing)
 	testRun, err := runner.BuildCommand(pkgPath, extraArgs...)
 	if err != nil {
@@ -1283,6 +1424,13 @@ func (o *TestOrchestrator) runPackageTest(
 		ginkgoStream = parsers.NewGinkgoStreamWriter(pkgPath, o.streamer)
 		testRun.Process.Stream(ginkgoStream, io.Discard)
 	}
+	// cargo reports over stdout in libtest JSON; feed it through the parser's
+	// ParseStream so the task label tracks pass/fail counts while the crate
+	// is still running. Parse() on the captured stdout stays authoritative.
+	var stopStream func()
+	if framework == parsers.Cargo && testRun.ReportPath == "" {
+		stopStream = streamParse(testRun.Process, testRun.Parser, t)
+	}
 	// Execute the test process in the orchestrator
 	process := testRun.Process.WithTask(t)
 	// Keep the RUNNING-state label short; it may be rendered if the
@@ -1303,8 +1451,13 @@ func (o *TestOrchestrator) runPackageTest(
 	if ginkgoStream != nil {
 		ginkgoStream.Flush()
 	}
+	if stopStream != nil {
+		stopStream()
+	}
 	peakMetrics := *peakPtr
 
+	o.collectCoverage(covTarget)
+
 	// Always attempt to parse test results first, even if there was an execution error
 	// This handles cases like `go test -json` which outputs valid JSON even when tests fail
 	testResults, parseErr := o.parseTestResults(testRun, result, pkgPath)
@@ -1338,10 +1491,6 @@ func (o *TestOrchestrator) runPackageTest(
 			ExitCode:  result.ExitCode,
 			Tests:     parsers.Tests{fallback},
 		}}
-		runKind := o.RunKind
-		if runKind == "" {
-			runKind = "initial"
-		}
 		stampAttempts(testResults, runKind, runStart, runStart.Add(runDuration), process.Pid(), result.ExitCode, peakMetrics)
 
 		// Fold the parse/execution failure reason into the compact task
@@ -1370,20 +1519,35 @@ func (o *TestOrchestrator) runPackageTest(
 		markTimedOutTests(testResults, runDuration, result.Stdout, result.Stderr, framework)
 	}
 
-	runKind := o.RunKind
-	if runKind == "" {
-		runKind = "initial"
-	}
+	o.quarantine.apply(testResults)
 	stampAttempts(testResults, runKind, runStart, runStart.Add(runDuration), process.Pid(), result.ExitCode, peakMetrics)
 
-	// Update task status based on results. Build a compact, no-ANSI label
-	// that fits in the one-line-per-task CI budget. On failure the label
-	// carries the first failing leaf's stderr/stdout/message snippet so
-	// the line is self-explanatory in the GitHub step log.
-	sum := testResults.All().Sum()
+	sum := setPackageTaskStatus(t, framework, pkgPath, testResults)
+	// Persist the passing outcome to the run cache so the next invocation
+	// with --cache can skip it. No-op when --cache is off (selector is nil).
+	// A retry only reran the failed subset, so it never describes the whole
+	// package.
+	if sum.Failed == 0 && sum.Skipped == 0 && o.selector != nil && runKind != runKindRetry {
+		o.selector.recordSuccess(framework, pkgPath, testResults, runDuration)
+	}
+
+	return packageResult{
+		packagePath: pkgPath,
+		framework:   framework,
+		testResults: testResults,
+		timedOut:    timedOut,
+	}, nil
+}
+
+// setPackageTaskStatus updates a package's task from its results. The label
+// is compact and ANSI-free so it fits the one-line-per-task CI budget; on
+// failure it carries the first failing leaf's stderr/stdout/message snippet
+// so the line is self-explanatory in the GitHub step log.
+func setPackageTaskStatus(t *task.Task, framework Framework, pkgPath string, results parsers.TestSuiteResults) parsers.TestSummary {
+	sum := results.All().Sum()
 	var firstFail *parsers.Test
 	if sum.Failed > 0 {
-		firstFail = firstFailingLeaf(testResults)
+		firstFail = firstFailingLeaf(results)
 	}
 	t.SetName(formatPackageLabel(framework, pkgPath, sum, firstFail))
 	t.SetDescription("")
@@ -1394,20 +1558,8 @@ func (o *TestOrchestrator) runPackageTest(
 		t.Warning()
 	} else {
 		t.Success()
-		// Persist the passing outcome to the run cache so the next
-		// invocation with --cache can skip it. No-op when --cache is off
-		// (selector is nil).
-		if o.selector != nil {
-			o.selector.recordSuccess(framework, pkgPath, testResults, runDuration)
-		}
 	}
-
-	return packageResult{
-		packagePath: pkgPath,
-		framework:   framework,
-		testResults: testResults,
-		timedOut:    timedOut,
-	}, nil
+	return sum
 }
 
 // stampAttempts appends one TestAttempt per leaf Test capturing the command,
@@ -1713,8 +1865,8 @@ func (o *TestOrchestrator) parseTestResults(testRun *runners.TestRun, result *ex
 	}
 
 	// Parsers own file-path normalization — see parsers.GinkgoJSON.relToWorkDir,
-	// parsers.JestJSON.relPath, parsers.PlaywrightJSON.relPath, and the
-	// location map in parsers.GoTestJSON. Orchestrator just stamps package
+	// parsers.JestJSON.relPath, parsers.PlaywrightJSON.relPath,
+	// parsers.PytestJUnit.relPath, and the location map in parsers.GoTestJSON. Orchestrator just stamps package
 	// context on top; no second-pass normalization here.
 
 	return parsers.TestSuiteResults{{
@@ -1727,44 +1879,82 @@ func (o *TestOrchestrator) parseTestResults(testRun *runners.TestRun, result *ex
 	}}, nil
 }
 
-// parseReportFile reads and parses a runner's JSON report file using the
-// TestRun's own Parser. Used by Ginkgo, Jest, Vitest, and Playwright runners
-// that write results to a file rather than stdout.
+// streamParse tees a process's stdout into parser.ParseStream on a
+// background goroutine. The returned func closes the pipe and waits for the
+// parser to finish; it must be called once the process has exited. Anything
+// the parser leaves unread is drained so a parser that bails early never
+// blocks the process.
+func streamParse(process *exec.Process, parser parsers.ResultParser, t *task.Task) func() {
+	pr, pw := io.Pipe()
+	process.Stream(pw, io.Discard)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		if _, _, err := parser.ParseStream(pr, io.Discard, t); err != nil {
+			logger.V(2).Infof("%s stream: %v", parser.Name(), err)
+		}
+		_, _ = io.Copy(io.Discard, pr)
+	}()
+	return func() {
+		_ = pw.Close()
+		<-done
+	}
+}
+
+// parseReportFile reads and parses a runner's report file using the
+// TestRun's own Parser. Used by Ginkgo, Jest, Vitest, Playwright, pytest and
+// .gavel.yaml JUnit runners that write results to a file rather than stdout.
+// A glob ReportPath (surefire's TEST-*.xml) parses every matching file.
 func (o *TestOrchestrator) parseReportFile(testRun *runners.TestRun) (parsers.Tests, error) {
 	reportPath := testRun.ReportPath
 	if o.WorkDir != "" && !filepath.IsAbs(reportPath) {
 		reportPath = filepath.Join(o.WorkDir, reportPath)
 	}
 	execName := fmt.Sprintf("%s Execution", testRun.Framework)
-	if _, err := os.Stat(reportPath); err != nil {
-		absPath, _ := filepath.Abs(reportPath)
-		return parsers.Tests{{
-			Name:    execName,
-			Failed:  true,
-			Message: fmt.Sprintf("'%s' not found", absPath),
-		}}, nil
+	reportPaths := []string{reportPath}
+	if runners.IsReportGlob(reportPath) {
+		reportPaths, _ = doublestar.FilepathGlob(reportPath)
 	}
-
-	reportFile, err := os.Open(reportPath)
-	if err != nil {
+	if len(reportPaths) == 0 {
 		return parsers.Tests{{
 			Name:    execName,
 			Failed:  true,
-			Message: fmt.Sprintf("Failed to open %s report: %v", testRun.Framework, err),
+			Message: fmt.Sprintf("no report matching '%s'", reportPath),
 		}}, nil
 	}
-	defer reportFile.Close()
 
-	tests, err := testRun.Parser.Parse(reportFile)
-	if err != nil {
-		return nil, fmt.Errorf("failed to parse %s report: %w", testRun.Framework, err)
+	var tests parsers.Tests
+	for _, path := range reportPaths {
+		if _, err := os.Stat(path); err != nil {
+			absPath, _ := filepath.Abs(path)
+			return parsers.Tests{{
+				Name:    execName,
+				Failed:  true,
+				Message: fmt.Sprintf("'%s' not found", absPath),
+			}}, nil
+		}
+
+		reportFile, err := os.Open(path)
+		if err != nil {
+			return parsers.Tests{{
+				Name:    execName,
+				Failed:  true,
+				Message: fmt.Sprintf("Failed to open %s report: %v", testRun.Framework, err),
+			}}, nil
+		}
+		parsed, err := testRun.Parser.Parse(reportFile)
+		reportFile.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s report: %w", testRun.Framework, err)
+		}
+		tests = append(tests, parsed...)
 	}
 
 	return tests, nil
 }
 
 // displayDryRun shows what tests would be executed without running them.
-func (o *TestOrchestrator) displayDryRun(packagesByFramework map[Framework][]string, extraArgs []string) parsers.TestSuiteResults {
+func (o *TestOrchestrator) displayDryRun(packagesByFramework map[Framework][]string, extraArgs []string, failedArgs map[Framework][]string) parsers.TestSuiteResults {
 	logger.Infof("🔍 Dry-run mode: showing what would be executed\n")
 
 	var totalPackages int
@@ -1782,7 +1972,7 @@ func (o *TestOrchestrator) displayDryRun(packagesByFramework map[Framework][]str
 		}
 
 		for _, pkg := range packages {
-			pkgExtraArgs := extraArgs
+			pkgExtraArgs := append(append([]string{}, extraArgs...), failedArgs[framework]...)
 			if framework == parsers.GoTest {
 				pkgExtraArgs = o.augmentBenchArgs(runner, pkg, pkgExtraArgs)
 			}
@@ -1859,6 +2049,13 @@ func resolveFixtureGlobs(opts RunOptions) []string {
 	if !opts.Fixtures && !cfg.Fixtures.Enabled {
 		return nil
 	}
+	if shard, _ := parseShard(opts.Shard); shard.enabled() && shard.index != 1 {
+		logger.V(1).Infof("fixtures run on shard 1 only, skipping on shard %s", shard)
+		return nil
+	}
+	if opts.watch != nil {
+		return nil
+	}
 	if len(opts.FixtureFiles) > 0 {
 		return opts.FixtureFiles
 	}
@@ -1935,7 +2132,7 @@ func runDiscoveredFixtures(workDir string, startingPaths []string, globs []strin
 			tree := runner.Tree()
 			var fixtureTests []parsers.Test
 			for _, child := range tree.Children {
-				fixtureTests = append(fixtureTests, fixtureNodeToTests(child)...)
+				fixtureTests = append(fixtureTests, FixtureNodeToTests(child)...)
 			}
 			streamer.UpdateFixtures(fixtureTests)
 		})
@@ -1946,7 +2143,7 @@ func runDiscoveredFixtures(workDir string, startingPaths []string, globs []strin
 	if streamer != nil && result != nil {
 		var fixtureTests []parsers.Test
 		for _, child := range result.Children {
-			fixtureTests = append(fixtureTests, fixtureNodeToTests(child)...)
+			fixtureTests = append(fixtureTests, FixtureNodeToTests(child)...)
 		}
 		streamer.UpdateFixtures(fixtureTests)
 	}
@@ -1954,7 +2151,9 @@ func runDiscoveredFixtures(workDir string, startingPaths []string, globs []strin
 	return result, err
 }
 
-func fixtureNodeToTests(node *fixtures.FixtureNode) []parsers.Test {
+// FixtureNodeToTests converts a fixture result tree into tests. File and
+// section nodes become parents; other grouping nodes are flattened away.
+func FixtureNodeToTests(node *fixtures.FixtureNode) []parsers.Test {
 	if node.Results != nil {
 		r := node.Results
 		t := parsers.Test{
@@ -1965,6 +2164,7 @@ func fixtureNodeToTests(node *fixtures.FixtureNode) []parsers.Test {
 			Stderr:    r.Stderr,
 			Failed:    r.Status == task.StatusFAIL || r.Status == task.StatusFailed || r.Status == task.StatusERR,
 			Passed:    r.Status == task.StatusPASS || r.Status == task.StatusSuccess,
+			Skipped:   r.Status == task.StatusSKIP,
 			Message:   r.Error,
 			Context: parsers.FixtureContext{
 				Command:       r.Command,
@@ -1981,7 +2181,7 @@ func fixtureNodeToTests(node *fixtures.FixtureNode) []parsers.Test {
 
 	var children parsers.Tests
 	for _, child := range node.Children {
-		children = append(children, fixtureNodeToTests(child)...)
+		children = append(children, FixtureNodeToTests(child)...)
 	}
 
 	if node.Type == fixtures.FileNode || node.Type == fixtures.SectionNode {
@@ -903,11 +903,27 @@ func TestFixtureNodeToTests(t *testing.T) {
 				Message:   "timeout",
 			}},
 		},
+		{
+			name: "SKIP status maps to skipped",
+			node: &fixtures.FixtureNode{
+				Name: "skip test",
+				Type: fixtures.TestNode,
+				Results: &fixtures.FixtureResult{
+					Name:   "skip test",
+					Status: task.StatusSKIP,
+				},
+			},
+			expected: []parsers.Test{{
+				Name:      "skip test",
+				Framework: "fixture",
+				Skipped:   true,
+			}},
+		},
 	}
 
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
-			got := fixtureNodeToTests(tc.node)
+			got := FixtureNodeToTests(tc.node)
 			if len(got) != len(tc.expected) {
 				t.Fatalf("expected %d tests, got %d: %+v", len(tc.expected), len(got), got)
 			}
@@ -924,6 +940,9 @@ func TestFixtureNodeToTests(t *testing.T) {
 				if got[i].Passed != exp.Passed {
 					t.Errorf("test[%d].Passed = %v, want %v", i, got[i].Passed, exp.Passed)
 				}
+				if got[i].Skipped != exp.Skipped {
+					t.Errorf("test[%d].Skipped = %v, want %v", i, got[i].Skipped, exp.Skipped)
+				}
 				if got[i].Message != exp.Message {
 					t.Errorf("test[%d].Message = %q, want %q", i, got[i].Message, exp.Message)
 				}
@@ -0,0 +1,258 @@
+package runners
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	osexec "os/exec"
+	"path/filepath"
+
+	"github.com/flanksource/clicky/exec"
+	"github.com/flanksource/commons/logger"
+	"github.com/flanksource/gavel/testrunner/parsers"
+	"github.com/flanksource/gavel/utils"
+	"github.com/hairyhenderson/toml"
+)
+
+// Cargo runs Rust tests. By default each crate is one package, run with
+// `cargo test -p <crate>` and parsed from libtest's JSON event stream on
+// stdout. When the workspace configures a nextest JUnit report and
+// cargo-nextest is installed, the whole workspace is one package run with
+// `cargo nextest run` and parsed from that report instead.
+type Cargo struct {
+	workDir string
+	parser  parsers.ResultParser
+}
+
+func NewCargo(workDir string) *Cargo {
+	return &Cargo{
+		workDir: workDir,
+		parser:  parsers.NewCargoTestJSON(workDir, workDir),
+	}
+}
+
+func (r *Cargo) Name() parsers.Framework      { return parsers.Cargo }
+func (r *Cargo) Parser() parsers.ResultParser { return r.parser }
+
+// cargoSkipDirs names build outputs and vendored sources that contain
+// Cargo.toml files which are not part of the project.
+var cargoSkipDirs = map[string]bool{
+	"target":       true,
+	"vendor":       true,
+	"node_modules": true,
+}
+
+// nextestProfile is the nextest profile gavel runs. Its junit.path setting
+// decides whether nextest mode is available.
+const nextestProfile = "default"
+
+var errCargoManifestFound = errors.New("cargo manifest found")
+
+// cargoManifest is the subset of Cargo.toml gavel reads.
+type cargoManifest struct {
+	Package *struct {
+		Name string `toml:"name"`
+	} `toml:"package"`
+	Workspace *struct{} `toml:"workspace"`
+}
+
+// readCargoManifest parses <dir>/Cargo.toml. Missing or malformed manifests
+// return (nil, false); malformed ones are logged at V(2).
+func readCargoManifest(dir string) (*cargoManifest, bool) {
+	path := filepath.Join(dir, "Cargo.toml")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, false
+	}
+	var m cargoManifest
+	if err := toml.Unmarshal(data, &m); err != nil {
+		logger.V(2).Infof("cargo detect: parse %s: %v", path, err)
+		return nil, false
+	}
+	return &m, true
+}
+
+// nextestConfig is the subset of .config/nextest.toml gavel reads.
+type nextestConfig struct {
+	Profile map[string]struct {
+		JUnit struct {
+			Path string `toml:"path"`
+		} `toml:"junit"`
+	} `toml:"profile"`
+}
+
+// nextestReportPath returns the absolute JUnit report path nextest writes
+// for root, or "" when nextest mode is unavailable: no junit path in the
+// default profile, or cargo-nextest not on PATH.
+func nextestReportPath(root string) string {
+	data, err := os.ReadFile(filepath.Join(root, ".config", "nextest.toml"))
+	if err != nil {
+		return ""
+	}
+	var cfg nextestConfig
+	if err := toml.Unmarshal(data, &cfg); err != nil {
+		logger.V(2).Infof("cargo detect: parse nextest.toml: %v", err)
+		return ""
+	}
+	path := cfg.Profile[nextestProfile].JUnit.Path
+	if path == "" {
+		return ""
+	}
+	if _, err := osexec.LookPath("cargo-nextest"); err != nil {
+		logger.V(2).Infof("cargo detect: nextest junit configured but cargo-nextest not on PATH, using cargo test")
+		return ""
+	}
+	targetDir := os.Getenv("CARGO_TARGET_DIR")
+	if targetDir == "" {
+		targetDir = filepath.Join(root, "target")
+	}
+	return filepath.Join(targetDir, "nextest", nextestProfile, path)
+}
+
+// findCargoRoot walks up from dir to the outermost Cargo.toml that declares
+// a [workspace], bounded by the git root. A crate outside any workspace is
+// its own root.
+func findCargoRoot(dir string) string {
+	dir, _ = filepath.Abs(dir)
+	gitRoot := utils.FindGitRoot(dir)
+	root := dir
+	for cur := dir; ; {
+		if m, ok := readCargoManifest(cur); ok && m.Workspace != nil {
+			root = cur
+		}
+		parent := filepath.Dir(cur)
+		if parent == cur || (gitRoot != "" && cur == gitRoot) {
+			return root
+		}
+		cur = parent
+	}
+}
+
+func (r *Cargo) Detect(workDir string) (bool, error) {
+	if _, ok := readCargoManifest(workDir); ok {
+		return true, nil
+	}
+	err := r.walkManifests(workDir, func(string, *cargoManifest) error {
+		return errCargoManifestFound
+	})
+	if errors.Is(err, errCargoManifestFound) {
+		return true, nil
+	}
+	return false, err
+}
+
+// DiscoverPackages returns every crate (a Cargo.toml with a [package]
+// table) under workDir. In nextest mode it returns the workspace roots
+// instead, since one nextest invocation covers the whole workspace.
+func (r *Cargo) DiscoverPackages(workDir string, recursive bool) ([]string, error) {
+	var crates []string
+	if !recursive {
+		if m, ok := readCargoManifest(workDir); ok && m.Package != nil {
+			crates = []string{workDir}
+		}
+	} else {
+		err := r.walkManifests(workDir, func(dir string, m *cargoManifest) error {
+			if m.Package != nil {
+				crates = append(crates, dir)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var packages []string
+	seen := map[string]bool{}
+	for _, dir := range crates {
+		if root := findCargoRoot(dir); nextestReportPath(root) != "" {
+			dir = root
+		}
+		rel := r.getRelativePath(dir)
+		if !seen[rel] {
+			seen[rel] = true
+			packages = append(packages, rel)
+		}
+	}
+	return packages, nil
+}
+
+func (r *Cargo) walkManifests(root string, fn func(dir string, m *cargoManifest) error) error {
+	return utils.WalkGitIgnoredBounded(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path != root && cargoSkipDirs[d.Name()] {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if d.Name() != "Cargo.toml" {
+			return nil
+		}
+		dir := filepath.Dir(path)
+		m, ok := readCargoManifest(dir)
+		if !ok {
+			return nil
+		}
+		return fn(dir, m)
+	})
+}
+
+func (r *Cargo) BuildCommand(packagePath string, extraArgs ...string) (*TestRun, error) {
+	pkgDir, _ := filepath.Abs(filepath.Join(r.workDir, packagePath))
+	root := findCargoRoot(pkgDir)
+
+	// extraArgs always go to the test harness (libtest or nextest's libtest
+	// emulation), so filters like `--exact name` work in both modes.
+	if reportPath := nextestReportPath(root); reportPath != "" && root == pkgDir {
+		// nextest overwrites the report in place; drop the previous one so a
+		// build failure can't be mistaken for the last run's results.
+		if err := os.Remove(reportPath); err != nil && !os.IsNotExist(err) {
+			return nil, fmt.Errorf("remove stale nextest report: %w", err)
+		}
+		args := []string{"nextest", "run", "--workspace", "--no-fail-fast", "--profile", nextestProfile}
+		if len(extraArgs) > 0 {
+			args = append(append(args, "--"), extraArgs...)
+		}
+		process := exec.NewExec("cargo", args...).WithCwd(root).WithProcessGroup()
+		process.SucceedOnNonZero = true
+		return &TestRun{
+			Framework:  parsers.Cargo,
+			Package:    Package(packagePath),
+			Parser:     parsers.NewCargoNextestJUnit(r.workDir, root),
+			Process:    process,
+			ReportPath: reportPath,
+		}, nil
+	}
+
+	m, ok := readCargoManifest(pkgDir)
+	if !ok || m.Package == nil {
+		return nil, fmt.Errorf("no crate manifest in %s", packagePath)
+	}
+	// libtest only accepts -Z on nightly; RUSTC_BOOTSTRAP lets stable test
+	// binaries emit the JSON format too.
+	args := []string{"test", "-p", m.Package.Name, "--no-fail-fast", "--",
+		"-Z", "unstable-options", "--format", "json", "--report-time"}
+	args = append(args, extraArgs...)
+	process := exec.NewExec("cargo", args...).WithCwd(root).WithProcessGroup().WithEnv(map[string]string{
+		"RUSTC_BOOTSTRAP": "1",
+	})
+	process.SucceedOnNonZero = true
+
+	return &TestRun{
+		Framework: parsers.Cargo,
+		Package:   Package(packagePath),
+		Parser:    parsers.NewCargoTestJSON(r.workDir, root),
+		Process:   process,
+	}, nil
+}
+
+func (r *Cargo) getRelativePath(dir string) string {
+	if relPath, err := filepath.Rel(r.workDir, dir); err == nil {
+		return "./" + filepath.ToSlash(relPath)
+	}
+	return dir
+}
@@ -0,0 +1,73 @@
+package runners
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/flanksource/gavel/testrunner/parsers"
+)
+
+func writeCargoFile(t *testing.T, dir, name, content string) {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCargoDiscoverWorkspace(t *testing.T) {
+	tmp := t.TempDir()
+	writeCargoFile(t, tmp, "Cargo.toml", "[workspace]\nmembers = [\"crates/*\"]\n")
+	writeCargoFile(t, tmp, "crates/calc/Cargo.toml", "[package]\nname = \"calc\"\nversion = \"0.1.0\"\n")
+	writeCargoFile(t, tmp, "crates/cli/Cargo.toml", "[package]\nname = \"calc-cli\"\nversion = \"0.1.0\"\n")
+	writeCargoFile(t, tmp, "target/package/calc-0.1.0/Cargo.toml", "[package]\nname = \"calc\"\n")
+
+	r := NewCargo(tmp)
+	if ok, err := r.Detect(tmp); err != nil || !ok {
+		t.Fatalf("Detect = %v, %v", ok, err)
+	}
+	got, err := r.DiscoverPackages(tmp, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Strings(got)
+	want := []string{"./crates/calc", "./crates/cli"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Fatalf("packages = %v, want %v (workspace root and target/ excluded)", got, want)
+	}
+
+	run, err := r.BuildCommand("./crates/cli", "--exact", "parses")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if run.Framework != parsers.Cargo || run.ReportPath != "" {
+		t.Errorf("run = %+v", run)
+	}
+	if abs, _ := filepath.Abs(tmp); run.Process.Cwd != abs {
+		t.Errorf("cwd = %q, want workspace root %q", run.Process.Cwd, abs)
+	}
+	args := strings.Join(run.Process.Args, " ")
+	if !strings.HasPrefix(args, "test -p calc-cli --no-fail-fast -- -Z unstable-options --format json") {
+		t.Errorf("args = %q", args)
+	}
+	if !strings.HasSuffix(args, "--exact parses") {
+		t.Errorf("extra args should reach the harness: %q", args)
+	}
+	if run.Process.Env["RUSTC_BOOTSTRAP"] != "1" {
+		t.Errorf("RUSTC_BOOTSTRAP not set: %v", run.Process.Env)
+	}
+}
+
+func TestCargoDetectNoManifest(t *testing.T) {
+	tmp := t.TempDir()
+	writeCargoFile(t, tmp, "main.go", "package main\n")
+	if ok, _ := NewCargo(tmp).Detect(tmp); ok {
+		t.Fatal("expected detect=false without Cargo.toml")
+	}
+}
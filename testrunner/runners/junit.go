@@ -0,0 +1,151 @@
+package runners
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/bmatcuk/doublestar/v4"
+	"github.com/flanksource/clicky/exec"
+	"github.com/flanksource/gavel/testrunner/parsers"
+	"github.com/flanksource/gavel/utils"
+)
+
+// JUnitSpec describes a test framework declared under test.frameworks in
+// .gavel.yaml. See verify.TestFrameworkConfig for the field semantics.
+type JUnitSpec struct {
+	Name    parsers.Framework
+	Detect  string
+	Command string
+	Report  string
+}
+
+// JUnit runs a framework gavel has no native support for: a shell command
+// that writes a JUnit XML report. The directory under test is the single
+// package; the command cannot be split into smaller units generically, so
+// recursive discovery is not applied.
+type JUnit struct {
+	workDir string
+	spec    JUnitSpec
+	parser  parsers.ResultParser
+}
+
+func NewJUnit(workDir string, spec JUnitSpec) *JUnit {
+	return &JUnit{
+		workDir: workDir,
+		spec:    spec,
+		parser:  parsers.NewJUnitXML(spec.Name, workDir, workDir),
+	}
+}
+
+func (r *JUnit) Name() parsers.Framework      { return r.spec.Name }
+func (r *JUnit) Parser() parsers.ResultParser { return r.parser }
+
+var errJUnitDetectMatched = errors.New("junit detect glob matched")
+
+// Detect reports whether any file under workDir matches the spec's detect
+// glob. An empty glob always matches.
+func (r *JUnit) Detect(workDir string) (bool, error) {
+	if r.spec.Detect == "" {
+		return true, nil
+	}
+	if !doublestar.ValidatePattern(r.spec.Detect) {
+		return false, fmt.Errorf("%s: invalid detect glob %q", r.spec.Name, r.spec.Detect)
+	}
+	err := utils.WalkGitIgnored(workDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(workDir, path)
+		if err != nil {
+			return nil
+		}
+		if ok, _ := doublestar.Match(r.spec.Detect, filepath.ToSlash(rel)); ok {
+			return errJUnitDetectMatched
+		}
+		return nil
+	})
+	if errors.Is(err, errJUnitDetectMatched) {
+		return true, nil
+	}
+	return false, err
+}
+
+func (r *JUnit) DiscoverPackages(workDir string, recursive bool) ([]string, error) {
+	found, err := r.Detect(workDir)
+	if err != nil || !found {
+		return nil, err
+	}
+	return []string{r.getRelativePath(workDir)}, nil
+}
+
+func (r *JUnit) BuildCommand(packagePath string, extraArgs ...string) (*TestRun, error) {
+	dir := filepath.Join(r.workDir, packagePath)
+	reportPath := r.spec.Report
+	if !filepath.IsAbs(reportPath) {
+		reportPath = filepath.Join(dir, reportPath)
+	}
+	// Reports left over from the previous run would otherwise be read back
+	// as this run's results when the command fails before writing its own.
+	if err := removeReports(reportPath); err != nil {
+		return nil, fmt.Errorf("remove stale %s report: %w", r.spec.Name, err)
+	}
+
+	// extraArgs are appended as positional parameters so they reach the
+	// command without being re-parsed by the shell.
+	script := r.spec.Command
+	args := []string{"-c", script}
+	if len(extraArgs) > 0 {
+		args = append([]string{"-c", script + ` "$@"`, "sh"}, extraArgs...)
+	}
+	process := exec.NewExec("sh", args...).WithCwd(dir).WithProcessGroup()
+	process.SucceedOnNonZero = true
+
+	return &TestRun{
+		Framework:  r.spec.Name,
+		Package:    Package(packagePath),
+		Parser:     parsers.NewJUnitXML(r.spec.Name, r.workDir, dir),
+		Process:    process,
+		ReportPath: reportPath,
+	}, nil
+}
+
+// removeReports deletes the report at path, or every file matching it when
+// path is a glob.
+func removeReports(path string) error {
+	if !IsReportGlob(path) {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+	matches, err := doublestar.FilepathGlob(path)
+	if err != nil {
+		return err
+	}
+	for _, m := range matches {
+		if err := os.Remove(m); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// IsReportGlob reports whether a TestRun.ReportPath is a glob covering
+// several report files (e.g. surefire's TEST-*.xml) rather than one file.
+func IsReportGlob(path string) bool {
+	return strings.ContainsAny(path, "*?[{")
+}
+
+func (r *JUnit) getRelativePath(dir string) string {
+	if relPath, err := filepath.Rel(r.workDir, dir); err == nil {
+		return "./" + filepath.ToSlash(relPath)
+	}
+	return dir
+}
@@ -0,0 +1,80 @@
+package runners
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestJUnitDetect(t *testing.T) {
+	tmp := t.TempDir()
+	writeCargoFile(t, tmp, "test/math.bats", "@test \"adds\" { true; }\n")
+
+	cases := []struct {
+		glob string
+		want bool
+	}{
+		{"**/*.bats", true},
+		{"{pom.xml,test/*.bats}", true},
+		{"pom.xml", false},
+		{"", true},
+	}
+	for _, c := range cases {
+		r := NewJUnit(tmp, JUnitSpec{Name: "bats", Detect: c.glob, Command: "bats test", Report: "report.xml"})
+		got, err := r.Detect(tmp)
+		if err != nil {
+			t.Fatalf("Detect(%q): %v", c.glob, err)
+		}
+		if got != c.want {
+			t.Errorf("Detect(%q) = %v, want %v", c.glob, got, c.want)
+		}
+	}
+
+	pkgs, err := NewJUnit(tmp, JUnitSpec{Name: "bats", Detect: "**/*.bats"}).DiscoverPackages(tmp, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pkgs) != 1 || pkgs[0] != "./." {
+		t.Errorf("packages = %v, want the workdir as one package", pkgs)
+	}
+}
+
+func TestJUnitBuildCommand(t *testing.T) {
+	tmp := t.TempDir()
+	writeCargoFile(t, tmp, "target/surefire-reports/TEST-Old.xml", "<testsuite/>")
+	r := NewJUnit(tmp, JUnitSpec{
+		Name:    "surefire",
+		Command: "mvn -q test",
+		Report:  "target/surefire-reports/TEST-*.xml",
+	})
+
+	run, err := r.BuildCommand("./.", "-Dtest=CalculatorTest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if run.Framework != "surefire" {
+		t.Errorf("framework = %q", run.Framework)
+	}
+	if want := filepath.Join(tmp, "target/surefire-reports/TEST-*.xml"); run.ReportPath != want {
+		t.Errorf("report = %q, want %q", run.ReportPath, want)
+	}
+	if _, err := os.Stat(filepath.Join(tmp, "target/surefire-reports/TEST-Old.xml")); !os.IsNotExist(err) {
+		t.Error("stale reports matching the glob should be removed")
+	}
+	if run.Process.Cmd != "sh" {
+		t.Errorf("cmd = %q, want sh", run.Process.Cmd)
+	}
+	args := strings.Join(run.Process.Args, " ")
+	if args != `-c mvn -q test "$@" sh -Dtest=CalculatorTest` {
+		t.Errorf("args = %q", args)
+	}
+
+	run, err = r.BuildCommand("./.")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if args := strings.Join(run.Process.Args, " "); args != "-c mvn -q test" {
+		t.Errorf("args without extras = %q", args)
+	}
+}
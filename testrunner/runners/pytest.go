@@ -0,0 +1,287 @@
+package runners
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/flanksource/clicky/exec"
+	"github.com/flanksource/gavel/testrunner/parsers"
+	"github.com/flanksource/gavel/utils"
+)
+
+// Pytest runs Python tests through pytest's JUnit XML reporter.
+type Pytest struct {
+	workDir string
+	parser  parsers.ResultParser
+}
+
+func NewPytest(workDir string) *Pytest {
+	return &Pytest{
+		workDir: workDir,
+		parser:  parsers.NewPytestJUnit(workDir, workDir),
+	}
+}
+
+func (r *Pytest) Name() parsers.Framework      { return parsers.Pytest }
+func (r *Pytest) Parser() parsers.ResultParser { return r.parser }
+
+// pythonSkipDirs names virtualenvs, caches and build outputs that never hold
+// a project's own tests but often contain thousands of third-party
+// test_*.py files.
+var pythonSkipDirs = map[string]bool{
+	".venv":         true,
+	"venv":          true,
+	"env":           true,
+	"__pycache__":   true,
+	".tox":          true,
+	".nox":          true,
+	".mypy_cache":   true,
+	".pytest_cache": true,
+	".ruff_cache":   true,
+	".eggs":         true,
+	"site-packages": true,
+	"node_modules":  true,
+	"build":         true,
+	"dist":          true,
+}
+
+// errPytestProjectFound is a sentinel used by Detect to stop walking on the
+// first pytest project.
+var errPytestProjectFound = errors.New("pytest project found")
+
+// isPytestTestFile reports whether name matches pytest's default
+// python_files pattern (test_*.py or *_test.py).
+func isPytestTestFile(name string) bool {
+	if !strings.HasSuffix(name, ".py") {
+		return false
+	}
+	return strings.HasPrefix(name, "test_") || strings.HasSuffix(name, "_test.py")
+}
+
+// hasPytestConfig reports whether dir holds a file that configures pytest:
+// a root config (see hasPytestRootConfig) or a conftest.py.
+func hasPytestConfig(dir string) bool {
+	return hasPytestRootConfig(dir) || fileExists(filepath.Join(dir, "conftest.py"))
+}
+
+// hasPytestRootConfig reports whether dir holds pytest.ini or a pytest
+// section in pyproject.toml, setup.cfg or tox.ini — the files pytest uses
+// to pick its rootdir.
+func hasPytestRootConfig(dir string) bool {
+	return fileExists(filepath.Join(dir, "pytest.ini")) ||
+		fileContains(filepath.Join(dir, "pyproject.toml"), "[tool.pytest") ||
+		fileContains(filepath.Join(dir, "setup.cfg"), "[tool:pytest]") ||
+		fileContains(filepath.Join(dir, "tox.ini"), "[pytest]")
+}
+
+// findPytestProject walks up from dir (inclusive) to stop (inclusive)
+// looking for the directory pytest would pick as rootdir: the first one
+// with a pytest ini section, else the first with a pyproject.toml or
+// setup.py. Returns "" when dir is not inside a pytest project.
+func findPytestProject(dir, stop string) string {
+	dir, _ = filepath.Abs(dir)
+	stop, _ = filepath.Abs(stop)
+	fallback := ""
+	configured := false
+	for cur := dir; ; {
+		if hasPytestRootConfig(cur) {
+			return cur
+		}
+		if fileExists(filepath.Join(cur, "conftest.py")) {
+			configured = true
+		}
+		if fallback == "" && (fileExists(filepath.Join(cur, "pyproject.toml")) || fileExists(filepath.Join(cur, "setup.py"))) {
+			fallback = cur
+		}
+		parent := filepath.Dir(cur)
+		if cur == stop || parent == cur || !utils.IsWithin(parent, stop) {
+			break
+		}
+		cur = parent
+	}
+	if !configured && fallback == "" {
+		return ""
+	}
+	if fallback == "" {
+		return stop
+	}
+	return fallback
+}
+
+func (r *Pytest) Detect(workDir string) (bool, error) {
+	if hasPytestConfig(workDir) {
+		return true, nil
+	}
+	err := utils.WalkGitIgnoredBounded(workDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		if path != workDir && pythonSkipDirs[d.Name()] {
+			return fs.SkipDir
+		}
+		if hasPytestConfig(path) {
+			return errPytestProjectFound
+		}
+		return nil
+	})
+	if errors.Is(err, errPytestProjectFound) {
+		return true, nil
+	}
+	return false, err
+}
+
+// DiscoverPackages returns every directory holding pytest test files that
+// sits inside a pytest project. Each directory is one gavel package, so a
+// project with tests/unit and tests/integration runs them as two packages.
+func (r *Pytest) DiscoverPackages(workDir string, recursive bool) ([]string, error) {
+	stop := r.workDir
+	if !utils.IsWithin(workDir, stop) {
+		stop = workDir
+	}
+	if !recursive {
+		if hasPytestFiles(workDir) && findPytestProject(workDir, stop) != "" {
+			return []string{r.getRelativePath(workDir)}, nil
+		}
+		return nil, nil
+	}
+
+	var packages []string
+	seen := map[string]bool{}
+	err := utils.WalkGitIgnoredBounded(workDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path != workDir && pythonSkipDirs[d.Name()] {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if !isPytestTestFile(d.Name()) {
+			return nil
+		}
+		dir := filepath.Dir(path)
+		if seen[dir] {
+			return nil
+		}
+		seen[dir] = true
+		if findPytestProject(dir, stop) != "" {
+			packages = append(packages, r.getRelativePath(dir))
+		}
+		return nil
+	})
+	return packages, err
+}
+
+func (r *Pytest) BuildCommand(packagePath string, extraArgs ...string) (*TestRun, error) {
+	pkgDir := filepath.Join(r.workDir, packagePath)
+	root := findPytestProject(pkgDir, r.workDir)
+	if root == "" {
+		root = r.workDir
+	}
+	reportPath := filepath.Join(r.workDir, ".pytest", fmt.Sprintf("pytest-report-%s-%d.xml", sanitizePkgPath(packagePath), time.Now().UnixNano()))
+	if err := os.MkdirAll(filepath.Dir(reportPath), 0o755); err != nil {
+		return nil, fmt.Errorf("create pytest report dir: %w", err)
+	}
+
+	target, err := filepath.Rel(root, pkgDir)
+	if err != nil {
+		target = pkgDir
+	}
+
+	cmd, pre := detectPythonRunner(root, r.workDir)
+	args := append([]string{}, pre...)
+	args = append(args, filepath.ToSlash(target),
+		"--junitxml="+reportPath,
+		"-o", "junit_family=xunit1",
+		"-o", "junit_logging=out-err",
+	)
+	args = append(args, extraArgs...)
+
+	process := exec.NewExec(cmd, args...).WithCwd(root).WithProcessGroup()
+	process.SucceedOnNonZero = true
+
+	return &TestRun{
+		Framework:  parsers.Pytest,
+		Package:    Package(packagePath),
+		Parser:     parsers.NewPytestJUnit(r.workDir, root),
+		Process:    process,
+		ReportPath: reportPath,
+	}, nil
+}
+
+// detectPythonRunner returns the command + prefix args that run pytest
+// inside the project's environment. It walks up from dir to stop looking
+// for a lockfile or virtualenv and falls back to pytest on PATH.
+//   - uv.lock          → ("uv", ["run", "pytest"])
+//   - poetry.lock      → ("poetry", ["run", "pytest"])
+//   - Pipfile.lock     → ("pipenv", ["run", "pytest"])
+//   - .venv/, venv/    → ("<venv>/bin/python", ["-m", "pytest"])
+//   - none             → ("pytest", nil)
+func detectPythonRunner(dir, stop string) (string, []string) {
+	cur, _ := filepath.Abs(dir)
+	stop, _ = filepath.Abs(stop)
+	for {
+		if fileExists(filepath.Join(cur, "uv.lock")) {
+			return "uv", []string{"run", "pytest"}
+		}
+		if fileExists(filepath.Join(cur, "poetry.lock")) {
+			return "poetry", []string{"run", "pytest"}
+		}
+		if fileExists(filepath.Join(cur, "Pipfile.lock")) {
+			return "pipenv", []string{"run", "pytest"}
+		}
+		for _, venv := range []string{".venv", "venv"} {
+			python := filepath.Join(cur, venv, "bin", "python")
+			if fileExists(python) {
+				return python, []string{"-m", "pytest"}
+			}
+		}
+		parent := filepath.Dir(cur)
+		if cur == stop || parent == cur {
+			return "pytest", nil
+		}
+		cur = parent
+	}
+}
+
+func hasPytestFiles(dir string) bool {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return false
+	}
+	for _, e := range entries {
+		if !e.IsDir() && isPytestTestFile(e.Name()) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Pytest) getRelativePath(dir string) string {
+	if relPath, err := filepath.Rel(r.workDir, dir); err == nil {
+		return "./" + filepath.ToSlash(relPath)
+	}
+	return dir
+}
+
+func fileExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
+
+func fileContains(path, needle string) bool {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return false
+	}
+	return strings.Contains(string(data), needle)
+}
@@ -0,0 +1,106 @@
+package runners
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/flanksource/gavel/testrunner/parsers"
+)
+
+func writePyFile(t *testing.T, dir, name, content string) {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPytestDetect(t *testing.T) {
+	cases := []struct {
+		name  string
+		files map[string]string
+		want  bool
+	}{
+		{"pytest.ini", map[string]string{"pytest.ini": "[pytest]\n"}, true},
+		{"conftest.py", map[string]string{"tests/conftest.py": ""}, true},
+		{"pyproject section", map[string]string{"pyproject.toml": "[tool.pytest.ini_options]\nminversion = \"7.0\"\n"}, true},
+		{"pyproject without pytest", map[string]string{"pyproject.toml": "[project]\nname = \"x\"\n", "test_x.py": ""}, false},
+		{"config inside venv is ignored", map[string]string{".venv/lib/pkg/pytest.ini": "[pytest]\n"}, false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			tmp := t.TempDir()
+			for name, content := range tc.files {
+				writePyFile(t, tmp, name, content)
+			}
+			if got, _ := NewPytest(tmp).Detect(tmp); got != tc.want {
+				t.Fatalf("Detect = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestPytestDiscoverPackages(t *testing.T) {
+	tmp := t.TempDir()
+	writePyFile(t, tmp, "pyproject.toml", "[tool.pytest.ini_options]\n")
+	writePyFile(t, tmp, "tests/unit/test_a.py", "")
+	writePyFile(t, tmp, "tests/unit/test_b.py", "")
+	writePyFile(t, tmp, "tests/integration/db_test.py", "")
+	writePyFile(t, tmp, "src/app/main.py", "")
+	writePyFile(t, tmp, ".venv/lib/site-packages/dep/test_dep.py", "")
+
+	r := NewPytest(tmp)
+	got, err := r.DiscoverPackages(tmp, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Strings(got)
+	want := []string{"./tests/integration", "./tests/unit"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Fatalf("packages = %v, want %v", got, want)
+	}
+
+	got, err = r.DiscoverPackages(filepath.Join(tmp, "tests", "unit"), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0] != "./tests/unit" {
+		t.Fatalf("non-recursive packages = %v", got)
+	}
+}
+
+func TestPytestBuildCommand(t *testing.T) {
+	tmp := t.TempDir()
+	writePyFile(t, tmp, "services/api/pytest.ini", "[pytest]\n")
+	writePyFile(t, tmp, "services/api/uv.lock", "")
+	writePyFile(t, tmp, "services/api/tests/test_api.py", "")
+
+	run, err := NewPytest(tmp).BuildCommand("./services/api/tests", "-x")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if run.Framework != parsers.Pytest {
+		t.Errorf("framework = %q", run.Framework)
+	}
+	if run.Process.Cmd != "uv" {
+		t.Errorf("cmd = %q, want uv", run.Process.Cmd)
+	}
+	if run.Process.Cwd != filepath.Join(tmp, "services", "api") {
+		t.Errorf("cwd = %q, want the pytest rootdir", run.Process.Cwd)
+	}
+	args := strings.Join(run.Process.Args, " ")
+	for _, want := range []string{"run pytest tests ", "--junitxml=" + run.ReportPath, "junit_family=xunit1", "-x"} {
+		if !strings.Contains(args, want) {
+			t.Errorf("args %q missing %q", args, want)
+		}
+	}
+	if !strings.HasPrefix(run.ReportPath, filepath.Join(tmp, ".pytest")) {
+		t.Errorf("report path = %q", run.ReportPath)
+	}
+}
@@ -3,13 +3,16 @@ package testrunner
 import (
 	"fmt"
 	"os"
+	"path"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/flanksource/commons/logger"
 	"github.com/flanksource/gavel/internal/changegraph"
 	"github.com/flanksource/gavel/internal/runcache"
 	"github.com/flanksource/gavel/testrunner/parsers"
+	"github.com/flanksource/gavel/utils"
 )
 
 // selectorContext carries the per-invocation state for change-graph and
@@ -19,7 +22,10 @@ type selectorContext struct {
 	workDir string
 	graph   *changegraph.Graph
 	hasher  *runcache.Hasher
-	store   *runcache.Store
+	store   runcache.Backend
+
+	// cacheOptions locates the local and (optional) shared remote store.
+	cacheOptions runcache.Options
 
 	// absDirByPkgPath memoizes the gavel-relative pkg path → absolute
 	// directory mapping, since gavel represents packages as "./pkg/foo"
@@ -28,29 +34,55 @@ type selectorContext struct {
 }
 
 // newSelectorContext initializes the graph and hasher. The cache store is
-// opened lazily on first cache hit/miss.
-func newSelectorContext(workDir string) (*selectorContext, error) {
-	graph, err := changegraph.Load(workDir)
+// opened lazily on first cache hit/miss. Outside a Go module the graph is
+// left nil: non-Go frameworks are selected by path and never consult it.
+func newSelectorContext(workDir string, cacheOptions runcache.Options) (*selectorContext, error) {
+	graph, err := loadPackageGraph(workDir)
 	if err != nil {
-		return nil, fmt.Errorf("load package graph: %w", err)
+		return nil, err
 	}
+	return newSelectorContextWithGraph(workDir, graph, cacheOptions), nil
+}
+
+// newSelectorContextWithGraph builds a selector around an already loaded
+// graph, which --watch keeps between iterations.
+func newSelectorContextWithGraph(workDir string, graph *changegraph.Graph, cacheOptions runcache.Options) *selectorContext {
 	return &selectorContext{
 		workDir:         workDir,
 		graph:           graph,
 		hasher:          runcache.NewHasher(graph, nil),
+		cacheOptions:    cacheOptions,
 		absDirByPkgPath: map[string]string{},
-	}, nil
+	}
 }
 
-// openStore lazily opens the on-disk run cache.
-func (s *selectorContext) openStore() (*runcache.Store, error) {
+// loadPackageGraph runs `go list` in workDir. Outside a Go module it returns
+// a nil graph and no error.
+func loadPackageGraph(workDir string) (*changegraph.Graph, error) {
+	if utils.FindNearestGoModRoot(workDir) == "" {
+		logger.V(2).Infof("change graph: no go.mod above %s, skipping package graph", workDir)
+		return nil, nil
+	}
+	graph, err := changegraph.Load(workDir)
+	if err != nil {
+		return nil, fmt.Errorf("load package graph: %w", err)
+	}
+	return graph, nil
+}
+
+// openStore lazily opens the run cache: the on-disk store, layered under the
+// shared remote when one is configured.
+func (s *selectorContext) openStore() (runcache.Backend, error) {
 	if s.store != nil {
 		return s.store, nil
 	}
-	store, err := runcache.Open("")
+	store, err := runcache.OpenBackend(s.cacheOptions)
 	if err != nil {
 		return nil, err
 	}
+	if s.cacheOptions.URL != "" {
+		logger.V(2).Infof("run-cache: remote %s (%s)", s.cacheOptions.URL, s.cacheOptions.Mode)
+	}
 	s.store = store
 	return store, nil
 }
@@ -59,6 +91,9 @@ func (s *selectorContext) openStore() (*runcache.Store, error) {
 // Go import path reported by `go list`. Returns "" if the package is not in
 // the graph.
 func (s *selectorContext) importPathOf(pkgPath string) string {
+	if s.graph == nil {
+		return ""
+	}
 	absDir, ok := s.absDirByPkgPath[pkgPath]
 	if !ok {
 		absDir = filepath.Join(s.workDir, filepath.FromSlash(pkgPath))
@@ -71,17 +106,17 @@ func (s *selectorContext) importPathOf(pkgPath string) string {
 	return ""
 }
 
-// filterByChangeGraph narrows pkgs to those affected by the given DiffOptions.
-// A nil or all-zero DiffOptions bypasses filtering and returns pkgs unchanged.
-func (s *selectorContext) filterByChangeGraph(pkgs []string, opts changegraph.DiffOptions) ([]string, error) {
-	fs, err := changegraph.ComputeFileSet(s.workDir, opts)
-	if err != nil {
-		return nil, fmt.Errorf("compute change set: %w", err)
-	}
+// filterByChangeGraph narrows pkgs to those affected by the changed files in
+// fs. Go frameworks resolve changes through the package import graph; every
+// other framework is narrowed by path (see filterByChangedPaths).
+func (s *selectorContext) filterByChangeGraph(framework parsers.Framework, pkgs []string, fs changegraph.FileSet) ([]string, error) {
 	if fs.Len() == 0 {
 		logger.V(3).Infof("change graph: no changes detected, nothing will run")
 		return nil, nil
 	}
+	if framework != parsers.GoTest && framework != parsers.Ginkgo {
+		return filterByChangedPaths(pkgs, fs), nil
+	}
 
 	affected := s.graph.AffectedPackages(fs)
 	if len(affected) == 0 {
@@ -108,6 +143,46 @@ func (s *selectorContext) filterByChangeGraph(pkgs []string, opts changegraph.Di
 	return kept, nil
 }
 
+// filterByChangedPaths keeps the packages that contain a changed file. A
+// changed file outside every package (shared source, config, fixtures)
+// could affect any of them, so it keeps all packages — unless it is Go
+// source or module metadata, which a non-Go test runner never reads.
+func filterByChangedPaths(pkgs []string, fs changegraph.FileSet) []string {
+	dirs := make([]string, len(pkgs))
+	for i, pkg := range pkgs {
+		dirs[i] = path.Clean(strings.TrimPrefix(filepath.ToSlash(pkg), "./"))
+	}
+	keep := make([]bool, len(pkgs))
+	for _, file := range fs.Sorted() {
+		owned := false
+		for i, dir := range dirs {
+			if dir == "." || file == dir || strings.HasPrefix(file, dir+"/") {
+				keep[i] = true
+				owned = true
+			}
+		}
+		if owned || isGoOnlyFile(file) {
+			continue
+		}
+		return pkgs
+	}
+	kept := make([]string, 0, len(pkgs))
+	for i, pkg := range pkgs {
+		if keep[i] {
+			kept = append(kept, pkg)
+		}
+	}
+	return kept
+}
+
+func isGoOnlyFile(file string) bool {
+	switch path.Base(file) {
+	case "go.mod", "go.sum", "go.work", "go.work.sum":
+		return true
+	}
+	return strings.HasSuffix(file, ".go")
+}
+
 // cacheHit represents a package that was served from the run cache and does
 // not need to be re-executed. The entry is a snapshot of the original run.
 type cacheHit struct {
@@ -0,0 +1,204 @@
+package testrunner
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/flanksource/commons/logger"
+	"github.com/flanksource/gavel/testrunner/parsers"
+)
+
+// shardSpec is a parsed --shard "<index>/<total>", 1-based.
+type shardSpec struct {
+	index int
+	total int
+}
+
+func (s shardSpec) enabled() bool { return s.total > 1 }
+
+func (s shardSpec) String() string { return fmt.Sprintf("%d/%d", s.index, s.total) }
+
+// parseShard parses --shard. An empty value disables sharding.
+func parseShard(value string) (shardSpec, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return shardSpec{index: 1, total: 1}, nil
+	}
+	i, n, ok := strings.Cut(value, "/")
+	index, err1 := strconv.Atoi(strings.TrimSpace(i))
+	total, err2 := strconv.Atoi(strings.TrimSpace(n))
+	if !ok || err1 != nil || err2 != nil || total < 1 || index < 1 || index > total {
+		return shardSpec{}, fmt.Errorf("invalid --shard %q: want <index>/<total> with 1 <= index <= total, e.g. 2/5", value)
+	}
+	return shardSpec{index: index, total: total}, nil
+}
+
+// shardPackage identifies one package of one framework, the unit that is
+// assigned to a shard.
+type shardPackage struct {
+	framework Framework
+	pkgPath   string
+}
+
+func (p shardPackage) key() string {
+	return string(p.framework) + "\x00" + cleanPackagePath(p.pkgPath)
+}
+
+// applyShard keeps the packages assigned to this shard. Every shard must
+// see the same package list and weights to compute the same plan, so
+// sharding runs after the (deterministic) change-graph filter but before the
+// machine-local run cache, and weights only come from --shard-timings, a
+// file every shard is handed.
+func (o *TestOrchestrator) applyShard(spec shardSpec, packagesByFramework map[Framework][]string) {
+	var all []shardPackage
+	for fw, pkgs := range packagesByFramework {
+		for _, pkg := range pkgs {
+			all = append(all, shardPackage{framework: fw, pkgPath: pkg})
+		}
+	}
+	timings, err := loadShardTimings(o.ShardTimings)
+	if err != nil {
+		logger.Warnf("shard %s: ignoring --shard-timings: %v", spec, err)
+	}
+	weights, source := shardWeights(all, timings)
+	plan := planShards(all, weights, spec.total)
+	mine := plan[spec.index-1]
+
+	kept := map[Framework][]string{}
+	var load time.Duration
+	for _, p := range mine {
+		kept[p.framework] = append(kept[p.framework], p.pkgPath)
+		load += weights[p.key()]
+	}
+	for fw := range packagesByFramework {
+		packagesByFramework[fw] = kept[fw]
+	}
+	logger.Infof("shard %s: %d of %d packages, estimated %s (weights: %s, plan %s)",
+		spec, len(mine), len(all), load.Round(time.Second), source, planDigest(plan))
+}
+
+// shardWeights estimates each package's run time from timings. Packages the
+// timings do not know get the mean of the known ones, so a new package does
+// not look free; without timings every package weighs the same and the
+// split is by package count.
+//
+// Nothing machine-local (run history, run cache) is consulted: two runners
+// with different local state would otherwise compute different plans and
+// silently skip or double-run packages.
+func shardWeights(pkgs []shardPackage, timings map[string]time.Duration) (map[string]time.Duration, string) {
+	weights := map[string]time.Duration{}
+	for _, p := range pkgs {
+		if w, ok := timings[p.key()]; ok && w > 0 {
+			weights[p.key()] = w
+		}
+	}
+	if len(weights) == 0 {
+		for _, p := range pkgs {
+			weights[p.key()] = time.Second
+		}
+		return weights, "package count"
+	}
+	var sum time.Duration
+	for _, w := range weights {
+		sum += w
+	}
+	mean := sum / time.Duration(len(weights))
+	for _, p := range pkgs {
+		if _, ok := weights[p.key()]; !ok {
+			weights[p.key()] = mean
+		}
+	}
+	return weights, "shard timings"
+}
+
+// loadShardTimings sums the leaf test durations of a gavel JSON result (a
+// snapshot or a plain test array, e.g. the gavel merge output of an earlier
+// run) per framework and package path. Package paths are relative to the
+// checkout, so the timings mean the same on every runner.
+func loadShardTimings(path string) (map[string]time.Duration, error) {
+	if path == "" {
+		return nil, nil
+	}
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var tests []parsers.Test
+	if strings.HasPrefix(strings.TrimSpace(string(raw)), "[") {
+		err = json.Unmarshal(raw, &tests)
+	} else {
+		var snap struct {
+			Tests []parsers.Test `json:"tests"`
+		}
+		err = json.Unmarshal(raw, &snap)
+		tests = snap.Tests
+	}
+	if err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	out := map[string]time.Duration{}
+	var walk func(tests []parsers.Test)
+	walk = func(tests []parsers.Test) {
+		for _, t := range tests {
+			if len(t.Children) > 0 {
+				walk(t.Children)
+				continue
+			}
+			if t.Framework == "" || t.Cached || filepath.IsAbs(t.PackagePath) {
+				continue
+			}
+			out[shardPackage{framework: t.Framework, pkgPath: t.PackagePath}.key()] += t.Duration
+		}
+	}
+	walk(tests)
+	return out, nil
+}
+
+// planShards bin-packs pkgs into total shards with the longest-first
+// greedy heuristic: heaviest package first, each onto the lightest shard.
+// Ties are broken by package key and shard index so every shard computes
+// the identical plan.
+func planShards(pkgs []shardPackage, weights map[string]time.Duration, total int) [][]shardPackage {
+	sorted := append([]shardPackage(nil), pkgs...)
+	sort.Slice(sorted, func(i, j int) bool {
+		wi, wj := weights[sorted[i].key()], weights[sorted[j].key()]
+		if wi != wj {
+			return wi > wj
+		}
+		return sorted[i].key() < sorted[j].key()
+	})
+	plan := make([][]shardPackage, total)
+	loads := make([]time.Duration, total)
+	for _, p := range sorted {
+		best := 0
+		for s := 1; s < total; s++ {
+			if loads[s] < loads[best] || (loads[s] == loads[best] && len(plan[s]) < len(plan[best])) {
+				best = s
+			}
+		}
+		plan[best] = append(plan[best], p)
+		loads[best] += weights[p.key()]
+	}
+	return plan
+}
+
+// planDigest is a short hash of the whole plan. Shards of one CI run log the
+// same digest; a mismatch means they saw different packages or weights.
+func planDigest(plan [][]shardPackage) string {
+	h := sha256.New()
+	for i, shard := range plan {
+		fmt.Fprintf(h, "shard %d\n", i)
+		for _, p := range shard {
+			fmt.Fprintf(h, "%s\n", p.key())
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil))[:8]
+}
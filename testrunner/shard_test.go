@@ -0,0 +1,128 @@
+package testrunner
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/flanksource/gavel/testrunner/parsers"
+)
+
+func TestParseShard(t *testing.T) {
+	for value, want := range map[string]shardSpec{
+		"":      {index: 1, total: 1},
+		"2/5":   {index: 2, total: 5},
+		" 1/1 ": {index: 1, total: 1},
+	} {
+		got, err := parseShard(value)
+		if err != nil || got != want {
+			t.Errorf("parseShard(%q) = %v, %v; want %v", value, got, err, want)
+		}
+	}
+	for _, bad := range []string{"3", "0/2", "3/2", "a/b", "1/0", "-1/2"} {
+		if _, err := parseShard(bad); err == nil {
+			t.Errorf("parseShard(%q) should fail", bad)
+		}
+	}
+}
+
+func TestPlanShardsBalancesByWeight(t *testing.T) {
+	pkgs := []shardPackage{
+		{parsers.GoTest, "./slow"},
+		{parsers.GoTest, "./a"},
+		{parsers.GoTest, "./b"},
+		{parsers.GoTest, "./c"},
+		{parsers.Ginkgo, "./d"},
+	}
+	weights := map[string]time.Duration{}
+	for _, p := range pkgs {
+		weights[p.key()] = time.Minute
+	}
+	weights[pkgs[0].key()] = 4 * time.Minute
+
+	plan := planShards(pkgs, weights, 2)
+	if len(plan[0]) != 1 || plan[0][0].pkgPath != "./slow" {
+		t.Errorf("the slow package should get a shard to itself, got %v", plan)
+	}
+	if len(plan[1]) != 4 {
+		t.Errorf("the other shard should take the remaining 4 packages, got %v", plan)
+	}
+
+	// Every shard must compute the same plan whatever order discovery
+	// returned the packages in.
+	reversed := make([]shardPackage, len(pkgs))
+	for i, p := range pkgs {
+		reversed[len(pkgs)-1-i] = p
+	}
+	if planDigest(planShards(reversed, weights, 2)) != planDigest(plan) {
+		t.Error("plan depends on input order")
+	}
+}
+
+func TestPlanShardsMoreShardsThanPackages(t *testing.T) {
+	pkgs := []shardPackage{{parsers.GoTest, "./a"}}
+	plan := planShards(pkgs, map[string]time.Duration{pkgs[0].key(): time.Second}, 3)
+	if len(plan) != 3 || len(plan[0]) != 1 || len(plan[1]) != 0 || len(plan[2]) != 0 {
+		t.Errorf("plan = %v", plan)
+	}
+}
+
+func TestApplyShardIgnoresMachineLocalHistory(t *testing.T) {
+	timings := filepath.Join(t.TempDir(), "timings.json")
+	writeJSON(t, timings, map[string]any{"tests": []parsers.Test{
+		{Name: "./slow", Children: parsers.Tests{
+			{Name: "TestSlow", Framework: parsers.GoTest, PackagePath: "./slow", Duration: 4 * time.Minute},
+		}},
+		{Name: "TestA", Framework: parsers.GoTest, PackagePath: "./a", Duration: time.Minute},
+		{Name: "TestB", Framework: parsers.GoTest, PackagePath: "b", Duration: time.Minute},
+		{Name: "TestCached", Framework: parsers.GoTest, PackagePath: "./c", Duration: time.Hour, Cached: true},
+	}})
+
+	// Two runners with contradicting local histories, as CI jobs that
+	// restored different .gavel directories would have.
+	plan := func(localSlow string) []string {
+		workDir := t.TempDir()
+		writeJSON(t, filepath.Join(workDir, ".gavel", "run-1.json"), map[string]any{"tests": []parsers.Test{
+			{Name: "TestLocal", Framework: parsers.GoTest, PackagePath: localSlow, Duration: time.Hour, Passed: true},
+		}})
+		o := &TestOrchestrator{RunOptions: RunOptions{WorkDir: workDir, ShardTimings: timings}}
+		pkgs := map[Framework][]string{parsers.GoTest: {"./a", "./b", "./c", "./slow"}}
+		o.applyShard(shardSpec{index: 1, total: 2}, pkgs)
+		return pkgs[parsers.GoTest]
+	}
+	first, second := plan("./a"), plan("./c")
+	if strings.Join(first, ",") != strings.Join(second, ",") {
+		t.Fatalf("plans differ across runners: %v vs %v", first, second)
+	}
+	if strings.Join(first, ",") != "./slow" {
+		t.Errorf("shard 1 = %v, want the slow package on its own", first)
+	}
+}
+
+func TestShardWeightsWithoutTimingsSplitsByCount(t *testing.T) {
+	pkgs := []shardPackage{{parsers.GoTest, "./a"}, {parsers.GoTest, "./b"}}
+	weights, source := shardWeights(pkgs, nil)
+	if source != "package count" || weights[pkgs[0].key()] != weights[pkgs[1].key()] {
+		t.Errorf("weights = %v (%s), want equal package-count weights", weights, source)
+	}
+	if _, err := loadShardTimings(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("a missing --shard-timings file should be reported")
+	}
+}
+
+func writeJSON(t *testing.T, path string, v any) {
+	t.Helper()
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,283 @@
+package testui
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/flanksource/clicky"
+	"github.com/flanksource/clicky/api"
+	"github.com/flanksource/clicky/api/icons"
+)
+
+// DiffKind classifies how a test or lint finding changed between two runs.
+type DiffKind string
+
+const (
+	DiffNewlyFailing DiffKind = "newly_failing"
+	DiffFixed        DiffKind = "fixed"
+	DiffStillFailing DiffKind = "still_failing"
+	DiffAdded        DiffKind = "added"
+	DiffRemoved      DiffKind = "removed"
+	DiffSlower       DiffKind = "slower"
+)
+
+// DiffKinds lists every kind in the order diffs are reported.
+var DiffKinds = []DiffKind{DiffNewlyFailing, DiffFixed, DiffStillFailing, DiffSlower, DiffAdded, DiffRemoved}
+
+func (k DiffKind) Label() string {
+	switch k {
+	case DiffNewlyFailing:
+		return "Newly failing"
+	case DiffFixed:
+		return "Fixed"
+	case DiffStillFailing:
+		return "Still failing"
+	case DiffAdded:
+		return "Added"
+	case DiffRemoved:
+		return "Removed"
+	case DiffSlower:
+		return "Slower"
+	}
+	return string(k)
+}
+
+// Order is the position of k in DiffKinds, used to sort diffs.
+func (k DiffKind) Order() int {
+	for i, kind := range DiffKinds {
+		if kind == k {
+			return i
+		}
+	}
+	return len(DiffKinds)
+}
+
+// DiffRun identifies one side of a comparison.
+type DiffRun struct {
+	Label   string    `json:"label,omitempty"`
+	SHA     string    `json:"sha,omitempty"`
+	Started time.Time `json:"started,omitempty"`
+}
+
+func (r DiffRun) String() string {
+	label := r.Label
+	if label == "" {
+		label = "run"
+	}
+	if r.SHA != "" {
+		sha := r.SHA
+		if len(sha) > 8 {
+			sha = sha[:8]
+		}
+		label += "@" + sha
+	}
+	return label
+}
+
+// TestDiff is one test whose outcome or duration changed between runs.
+// Statuses are empty on the side the test is missing from.
+type TestDiff struct {
+	Kind         DiffKind      `json:"kind"`
+	Framework    string        `json:"framework,omitempty"`
+	PackagePath  string        `json:"package_path,omitempty"`
+	Name         string        `json:"name"`
+	BaseStatus   string        `json:"base_status,omitempty"`
+	HeadStatus   string        `json:"head_status,omitempty"`
+	BaseDuration time.Duration `json:"base_duration,omitempty"`
+	HeadDuration time.Duration `json:"head_duration,omitempty"`
+	Message      string        `json:"message,omitempty"`
+}
+
+// LintDiff is one lint finding, keyed by linter, file and rule, that
+// appeared, disappeared or persisted between runs.
+type LintDiff struct {
+	Kind      DiffKind `json:"kind"`
+	Linter    string   `json:"linter"`
+	File      string   `json:"file,omitempty"`
+	Rule      string   `json:"rule,omitempty"`
+	BaseCount int      `json:"base_count,omitempty"`
+	HeadCount int      `json:"head_count,omitempty"`
+	Message   string   `json:"message,omitempty"`
+}
+
+// RunDiff is the comparison of a head run against a base run.
+type RunDiff struct {
+	Base  DiffRun    `json:"base"`
+	Head  DiffRun    `json:"head"`
+	Tests []TestDiff `json:"tests,omitempty"`
+	Lint  []LintDiff `json:"lint,omitempty"`
+	// Unchanged counts the tests present in both runs with the same outcome
+	// and no significant slowdown.
+	Unchanged int `json:"unchanged"`
+}
+
+// TestCount returns the number of tests classified as kind.
+func (d RunDiff) TestCount(kind DiffKind) int {
+	n := 0
+	for _, t := range d.Tests {
+		if t.Kind == kind {
+			n++
+		}
+	}
+	return n
+}
+
+// LintCount returns the number of lint findings classified as kind.
+func (d RunDiff) LintCount(kind DiffKind) int {
+	n := 0
+	for _, l := range d.Lint {
+		if l.Kind == kind {
+			n++
+		}
+	}
+	return n
+}
+
+// HasRegression reports whether head fails a test or has a lint finding
+// base did not.
+func (d RunDiff) HasRegression() bool {
+	return d.TestCount(DiffNewlyFailing) > 0 || d.LintCount(DiffNewlyFailing) > 0
+}
+
+func (t TestDiff) Pretty() api.Text {
+	s := clicky.Text("")
+	switch t.Kind {
+	case DiffNewlyFailing, DiffStillFailing:
+		s = s.Append(icons.Fail, "text-red-500")
+	case DiffFixed:
+		s = s.Append(icons.Pass, "text-green-600")
+	case DiffSlower:
+		s = s.Append("▲", "text-orange-500")
+	default:
+		s = s.Append("·", "text-muted")
+	}
+	s = s.Space().Append(t.Name, "bold")
+	if t.PackagePath != "" {
+		s = s.Space().Append(t.PackagePath, "text-muted")
+	}
+	switch t.Kind {
+	case DiffSlower:
+		s = s.Space().Append(fmt.Sprintf("%s → %s", formatDiffDuration(t.BaseDuration), formatDiffDuration(t.HeadDuration)), "text-muted")
+		if t.BaseDuration > 0 {
+			pct := float64(t.HeadDuration-t.BaseDuration) / float64(t.BaseDuration) * 100
+			s = s.Space().Append(fmt.Sprintf("%+.0f%%", pct), "text-orange-500 bold")
+		}
+	case DiffAdded, DiffRemoved:
+		status := t.HeadStatus
+		if t.Kind == DiffRemoved {
+			status = t.BaseStatus
+		}
+		s = s.Space().Append(fmt.Sprintf("(%s)", status), "text-muted")
+	}
+	if t.Message != "" {
+		s = s.Space().Append(t.Message, "text-muted")
+	}
+	return s
+}
+
+func (l LintDiff) Pretty() api.Text {
+	s := clicky.Text("")
+	switch l.Kind {
+	case DiffNewlyFailing, DiffStillFailing:
+		s = s.Append(icons.Fail, "text-red-500")
+	case DiffFixed:
+		s = s.Append(icons.Pass, "text-green-600")
+	}
+	s = s.Space().Append(l.Linter, "bold")
+	if l.Rule != "" {
+		s = s.Space().Append(l.Rule)
+	}
+	if l.File != "" {
+		s = s.Space().Append(l.File, "text-muted")
+	}
+	switch {
+	case l.Kind == DiffStillFailing && l.BaseCount != l.HeadCount:
+		s = s.Space().Append(fmt.Sprintf("(%d → %d)", l.BaseCount, l.HeadCount), "text-muted")
+	case l.Kind == DiffFixed && l.BaseCount > 1:
+		s = s.Space().Append(fmt.Sprintf("(×%d)", l.BaseCount), "text-muted")
+	case l.HeadCount > 1:
+		s = s.Space().Append(fmt.Sprintf("(×%d)", l.HeadCount), "text-muted")
+	}
+	if l.Message != "" {
+		s = s.Space().Append(l.Message, "text-muted")
+	}
+	return s
+}
+
+// Pretty renders the diff as sections per kind. Newly failing tests and
+// findings are always expanded; everything else collapses so a PR comment
+// leads with what needs attention.
+func (d RunDiff) Pretty() api.Text {
+	text := clicky.Text("Run diff", "bold").Space().
+		Append(fmt.Sprintf("%s → %s", d.Base, d.Head), "text-muted").NewLine()
+
+	if len(d.Tests) == 0 && len(d.Lint) == 0 {
+		return text.NewLine().Append(fmt.Sprintf("No changes (%d tests unchanged)", d.Unchanged), "text-muted")
+	}
+
+	for _, kind := range DiffKinds {
+		var rows []TestDiff
+		for _, t := range d.Tests {
+			if t.Kind == kind {
+				rows = append(rows, t)
+			}
+		}
+		if len(rows) == 0 {
+			continue
+		}
+		inner := clicky.Text("")
+		for _, t := range rows {
+			inner = inner.Add(t.Pretty()).NewLine()
+		}
+		text = text.NewLine().Add(diffSection(fmt.Sprintf("%s (%d)", kind.Label(), len(rows)), kind, inner))
+	}
+
+	lintLabels := map[DiffKind]string{
+		DiffNewlyFailing: "New lint violations",
+		DiffFixed:        "Fixed lint violations",
+		DiffStillFailing: "Remaining lint violations",
+	}
+	for _, kind := range []DiffKind{DiffNewlyFailing, DiffFixed, DiffStillFailing} {
+		var rows []LintDiff
+		for _, l := range d.Lint {
+			if l.Kind == kind {
+				rows = append(rows, l)
+			}
+		}
+		if len(rows) == 0 {
+			continue
+		}
+		inner := clicky.Text("")
+		for _, l := range rows {
+			inner = inner.Add(l.Pretty()).NewLine()
+		}
+		text = text.NewLine().Add(diffSection(fmt.Sprintf("%s (%d)", lintLabels[kind], len(rows)), kind, inner))
+	}
+
+	text = text.NewLine().Append(fmt.Sprintf("%d tests unchanged", d.Unchanged), "text-muted")
+	if d.HasRegression() {
+		text = text.Space().Append("[REGRESSION]", "text-red-500 bold")
+	}
+	return text
+}
+
+func diffSection(label string, kind DiffKind, content api.Text) api.Text {
+	switch kind {
+	case DiffNewlyFailing:
+		return clicky.Text(label, "bold text-red-500").NewLine().Add(content)
+	case DiffFixed:
+		return clicky.Text(label, "bold text-green-600").NewLine().Add(content)
+	}
+	return clicky.Text("").Add(api.Collapsed{Label: label, Content: content}).NewLine()
+}
+
+func formatDiffDuration(d time.Duration) string {
+	switch {
+	case d >= time.Second:
+		return fmt.Sprintf("%.2fs", d.Seconds())
+	case d >= time.Millisecond:
+		return fmt.Sprintf("%dms", d.Milliseconds())
+	default:
+		return d.String()
+	}
+}
@@ -0,0 +1,61 @@
+package testui
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/flanksource/gavel/linters/fixpreview"
+)
+
+type FixApplyRequest struct {
+	Hunks []string `json:"hunks"`
+}
+
+// SetFixPreview stores the fixes of a `gavel lint --fix --preview` run so
+// they can be reviewed and applied from the lint tab.
+func (s *Server) SetFixPreview(preview *fixpreview.Preview) {
+	s.mu.Lock()
+	s.fixes = preview
+	s.mu.Unlock()
+	s.notify()
+}
+
+func (s *Server) handleFixApply(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req FixApplyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid json: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if len(req.Hunks) == 0 {
+		http.Error(w, "hunks is required", http.StatusBadRequest)
+		return
+	}
+
+	s.mu.Lock()
+	if s.fixes == nil {
+		s.mu.Unlock()
+		http.Error(w, "no fix preview in this run", http.StatusNotFound)
+		return
+	}
+	if s.replayed {
+		s.mu.Unlock()
+		http.Error(w, "fixes from a saved snapshot cannot be applied", http.StatusConflict)
+		return
+	}
+	result, err := s.fixes.Apply(req.Hunks)
+	s.mu.Unlock()
+	s.notify()
+	if err != nil {
+		http.Error(w, fmt.Sprintf("apply fixes: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(result)
+}
@@ -0,0 +1,66 @@
+package testui_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/flanksource/gavel/linters/fixpreview"
+	testui "github.com/flanksource/gavel/testrunner/ui"
+)
+
+func TestFixApplyWritesAcceptedHunks(t *testing.T) {
+	srv, handler := newTestServer(t)
+
+	root := t.TempDir()
+	before := "a\nb\nc\nd\ne\nf\ng\nh\ni\nj\n"
+	if err := os.WriteFile(filepath.Join(root, "x.txt"), []byte(before), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	preview := &fixpreview.Preview{}
+	preview.Add(*fixpreview.Compute("eslint", root, "x.txt", before, "A\nb\nc\nd\ne\nf\ng\nh\ni\nJ\n"))
+	if len(preview.Files[0].Hunks) != 2 {
+		t.Fatalf("hunks = %d, want 2", len(preview.Files[0].Hunks))
+	}
+	srv.SetFixPreview(preview)
+
+	id := preview.Files[0].Hunks[1].ID
+	body, _ := json.Marshal(testui.FixApplyRequest{Hunks: []string{id}})
+	resp := doRequest(t, handler, http.MethodPost, "/api/lint/fixes/apply", bytes.NewReader(body))
+	if resp.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", resp.Code, resp.Body.String())
+	}
+	var result fixpreview.ApplyResult
+	if err := json.Unmarshal(resp.Body.Bytes(), &result); err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Applied) != 1 || result.Applied[0] != id {
+		t.Fatalf("applied = %v, want [%s]", result.Applied, id)
+	}
+
+	data, _ := os.ReadFile(filepath.Join(root, "x.txt"))
+	if string(data) != "a\nb\nc\nd\ne\nf\ng\nh\ni\nJ\n" {
+		t.Fatalf("only the accepted hunk should be applied, got %q", data)
+	}
+
+	resp = doRequest(t, handler, http.MethodGet, "/api/tests", nil)
+	var snap testui.Snapshot
+	if err := json.Unmarshal(resp.Body.Bytes(), &snap); err != nil {
+		t.Fatal(err)
+	}
+	if snap.FixPreview == nil || snap.FixPreview.Files[0].Hunks[1].Status != fixpreview.HunkApplied {
+		t.Fatalf("snapshot should report the applied hunk, got %+v", snap.FixPreview)
+	}
+}
+
+func TestFixApplyWithoutPreview(t *testing.T) {
+	_, handler := newTestServer(t)
+	body, _ := json.Marshal(testui.FixApplyRequest{Hunks: []string{"eslint:x.txt#1"}})
+	resp := doRequest(t, handler, http.MethodPost, "/api/lint/fixes/apply", bytes.NewReader(body))
+	if resp.Code != http.StatusNotFound {
+		t.Fatalf("status = %d, want 404", resp.Code)
+	}
+}
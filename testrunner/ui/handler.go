@@ -12,7 +12,9 @@ import (
 	"github.com/flanksource/clicky/api"
 	clickytask "github.com/flanksource/clicky/task"
 	"github.com/flanksource/gavel/linters"
+	"github.com/flanksource/gavel/linters/fixpreview"
 	"github.com/flanksource/gavel/testrunner/bench"
+	"github.com/flanksource/gavel/testrunner/coverage"
 	"github.com/flanksource/gavel/testrunner/parsers"
 	gopsutilProcess "github.com/shirou/gopsutil/v3/process"
 )
@@ -23,6 +25,10 @@ type Server struct {
 	lint     []*linters.LinterResult
 	lintRun  bool
 	benchCmp *bench.BenchComparison
+	benchHis *bench.BenchHistory
+	coverage *coverage.Report
+	fixes    *fixpreview.Preview
+	diff     *RunDiff
 	done     bool
 	// replayed marks a server hydrated from a static JSON snapshot
 	// (LoadSnapshot). Its results are fixed, so snapshot() must not consult
@@ -149,6 +155,10 @@ func (s *Server) LoadSnapshot(snapshot Snapshot) {
 	s.lint = snapshot.Lint
 	s.lintRun = snapshot.Status.LintRun
 	s.benchCmp = snapshot.Bench
+	s.benchHis = snapshot.BenchHistory
+	s.coverage = snapshot.Coverage
+	s.fixes = snapshot.FixPreview
+	s.diff = snapshot.Diff
 	s.metadata = cloneSnapshotMetadata(snapshot.Metadata)
 	s.git = cloneSnapshotGit(snapshot.Git)
 	s.embeddedDiagnostics = cloneDiagnosticsSnapshot(snapshot.Diagnostics)
@@ -188,6 +198,22 @@ func (s *Server) SetBenchComparison(cmp *bench.BenchComparison) {
 	s.notify()
 }
 
+// SetBenchHistory stores the per-benchmark trends so the Bench tab can plot them.
+func (s *Server) SetBenchHistory(history *bench.BenchHistory) {
+	s.mu.Lock()
+	s.benchHis = history
+	s.mu.Unlock()
+	s.notify()
+}
+
+// SetCoverage stores the run's coverage report so it appears in the next snapshot.
+func (s *Server) SetCoverage(report *coverage.Report) {
+	s.mu.Lock()
+	s.coverage = report
+	s.mu.Unlock()
+	s.notify()
+}
+
 // SetGitRoot records the git root used for resolving relative paths and
 // locating the .gavel.yaml written by the ignore endpoint.
 func (s *Server) SetGitRoot(root string) {
@@ -273,6 +299,7 @@ func (s *Server) Handler() http.Handler {
 	mux.HandleFunc("/api/rerun/stream", s.handleRerunStream)
 	mux.HandleFunc("/api/stop", s.handleStop)
 	mux.HandleFunc("/api/lint/ignore", s.handleLintIgnore)
+	mux.HandleFunc("/api/lint/fixes/apply", s.handleFixApply)
 	mux.HandleFunc("/api/tests/edit", s.handleTestEdit)
 	mux.HandleFunc("/api/benchmarks", s.handleBenchJSON)
 	return mux
@@ -404,10 +431,14 @@ func (s *Server) snapshot() Snapshot {
 			Stopped:              stopped,
 			StopMessage:          stopMessage,
 		},
-		Tests:       tests,
-		Lint:        s.lint,
-		Bench:       s.benchCmp,
-		Diagnostics: cloneDiagnosticsSnapshot(s.embeddedDiagnostics),
+		Tests:        tests,
+		Lint:         s.lint,
+		Bench:        s.benchCmp,
+		BenchHistory: s.benchHis,
+		Coverage:     s.coverage,
+		FixPreview:   s.fixes,
+		Diagnostics:  cloneDiagnosticsSnapshot(s.embeddedDiagnostics),
+		Diff:         s.diff,
 	}
 }
 
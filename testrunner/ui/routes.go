@@ -17,6 +17,7 @@ import (
 	"github.com/flanksource/gavel/linters"
 	"github.com/flanksource/gavel/models"
 	"github.com/flanksource/gavel/testrunner/bench"
+	"github.com/flanksource/gavel/testrunner/coverage"
 	"github.com/flanksource/gavel/testrunner/parsers"
 )
 
@@ -24,7 +25,9 @@ const (
 	viewTabTests       = "tests"
 	viewTabLint        = "lint"
 	viewTabBench       = "bench"
+	viewTabCoverage    = "coverage"
 	viewTabDiagnostics = "diagnostics"
+	viewTabCompare     = "compare"
 )
 
 type routeRequest struct {
@@ -55,6 +58,8 @@ type exportReport struct {
 	Tests    []*ViewNode `json:"tests,omitempty"`
 	Lint     []*ViewNode `json:"lint,omitempty"`
 	Bench    []*ViewNode `json:"bench,omitempty"`
+	Coverage []*ViewNode `json:"coverage,omitempty"`
+	Compare  []*ViewNode `json:"compare,omitempty"`
 	Done     bool        `json:"done"`
 
 	roots []*ViewNode `json:"-"`
@@ -227,7 +232,7 @@ func parseRouteRequest(r *http.Request) (routeRequest, bool) {
 	}
 
 	switch tabSeg {
-	case viewTabTests, viewTabLint, viewTabBench, viewTabDiagnostics:
+	case viewTabTests, viewTabLint, viewTabBench, viewTabCoverage, viewTabDiagnostics, viewTabCompare:
 		req.Tab = tabSeg
 	default:
 		return routeRequest{}, false
@@ -365,6 +370,14 @@ func (s *Server) buildExportReport(req routeRequest) (*exportReport, error) {
 		report.Bench = buildBenchViewNodes(snap.Bench)
 		annotateViewPaths(report.Bench, nil)
 		report.roots = report.Bench
+	case viewTabCoverage:
+		report.Coverage = buildCoverageViewNodes(snap.Coverage)
+		annotateViewPaths(report.Coverage, nil)
+		report.roots = report.Coverage
+	case viewTabCompare:
+		report.Compare = buildDiffViewNodes(snap.Diff)
+		annotateViewPaths(report.Compare, nil)
+		report.roots = report.Compare
 	default:
 		return nil, fmt.Errorf("unsupported tab: %s", req.Tab)
 	}
@@ -382,6 +395,10 @@ func (s *Server) buildExportReport(req routeRequest) (*exportReport, error) {
 			report.Lint = []*ViewNode{selected}
 		case viewTabBench:
 			report.Bench = []*ViewNode{selected}
+		case viewTabCoverage:
+			report.Coverage = []*ViewNode{selected}
+		case viewTabCompare:
+			report.Compare = []*ViewNode{selected}
 		}
 		report.roots = []*ViewNode{selected}
 	}
@@ -1071,6 +1088,155 @@ func buildBenchViewNodes(cmp *bench.BenchComparison) []*ViewNode {
 	return nodes
 }
 
+// buildCoverageViewNodes groups file coverage under its package. When the
+// report carries diff coverage it is listed first as its own root, with the
+// files whose changed lines are not fully covered beneath it.
+func buildCoverageViewNodes(report *coverage.Report) []*ViewNode {
+	if report == nil {
+		return nil
+	}
+	var nodes []*ViewNode
+	if d := report.Diff; d != nil {
+		status := "passed"
+		if d.Failed() {
+			status = "failed"
+		}
+		diff := &ViewNode{
+			Name:    "Changed lines",
+			Kind:    "coverage-diff",
+			Status:  status,
+			Message: formatCoverage(d.Summary),
+		}
+		if d.Threshold > 0 {
+			diff.Message += fmt.Sprintf(", threshold %d%%", d.Threshold)
+		}
+		for _, f := range d.Files {
+			if len(f.Uncovered) == 0 {
+				continue
+			}
+			lines := make([]string, len(f.Uncovered))
+			for i, l := range f.Uncovered {
+				lines[i] = fmt.Sprintf("%d", l)
+			}
+			diff.Children = append(diff.Children, &ViewNode{
+				Name:    f.File,
+				Kind:    "coverage-file",
+				Status:  "failed",
+				File:    f.File,
+				Line:    f.Uncovered[0],
+				Message: formatCoverage(f.Summary) + ", uncovered: " + strings.Join(lines, ", "),
+			})
+		}
+		nodes = append(nodes, diff)
+	}
+
+	byPkg := map[string]*ViewNode{}
+	for _, pkg := range report.Packages {
+		node := &ViewNode{
+			Name:    pkg.Package,
+			Kind:    "coverage-package",
+			Status:  "passed",
+			Package: pkg.Package,
+			Message: formatCoverage(pkg.Summary),
+		}
+		byPkg[pkg.Package] = node
+		nodes = append(nodes, node)
+	}
+	for _, f := range report.Files {
+		pkg := byPkg[f.Package]
+		if pkg == nil {
+			continue
+		}
+		pkg.Children = append(pkg.Children, &ViewNode{
+			Name:    filepath.Base(f.File),
+			Kind:    "coverage-file",
+			Status:  "passed",
+			Package: f.Package,
+			File:    f.File,
+			Message: formatCoverage(f.Summary),
+		})
+	}
+	return nodes
+}
+
+// buildDiffViewNodes groups a run diff by kind, tests first and lint
+// findings after, each group listing what changed beneath it.
+func buildDiffViewNodes(d *RunDiff) []*ViewNode {
+	if d == nil {
+		return nil
+	}
+	var nodes []*ViewNode
+	for _, kind := range DiffKinds {
+		group := &ViewNode{Name: kind.Label(), Kind: "diff-" + string(kind), Status: diffViewStatus(kind)}
+		for _, t := range d.Tests {
+			if t.Kind != kind {
+				continue
+			}
+			message := t.Message
+			switch kind {
+			case DiffSlower:
+				message = fmt.Sprintf("%s -> %s", formatDiffDuration(t.BaseDuration), formatDiffDuration(t.HeadDuration))
+			case DiffAdded:
+				message = t.HeadStatus
+			case DiffRemoved:
+				message = t.BaseStatus
+			}
+			group.Children = append(group.Children, &ViewNode{
+				Name:        t.Name,
+				Kind:        "diff-test",
+				Status:      group.Status,
+				Framework:   t.Framework,
+				PackagePath: t.PackagePath,
+				Duration:    t.HeadDuration,
+				Message:     message,
+			})
+		}
+		if len(group.Children) > 0 {
+			group.Message = fmt.Sprintf("%d tests", len(group.Children))
+			nodes = append(nodes, group)
+		}
+	}
+	for _, kind := range []DiffKind{DiffNewlyFailing, DiffFixed, DiffStillFailing} {
+		group := &ViewNode{Name: "Lint: " + strings.ToLower(kind.Label()), Kind: "diff-lint-" + string(kind), Status: diffViewStatus(kind)}
+		for _, l := range d.Lint {
+			if l.Kind != kind {
+				continue
+			}
+			name := l.Linter
+			if l.Rule != "" {
+				name += " " + l.Rule
+			}
+			group.Children = append(group.Children, &ViewNode{
+				Name:      name,
+				Kind:      "diff-lint",
+				Status:    group.Status,
+				Framework: l.Linter,
+				File:      l.File,
+				Message:   l.Message,
+			})
+		}
+		if len(group.Children) > 0 {
+			group.Message = fmt.Sprintf("%d violations", len(group.Children))
+			nodes = append(nodes, group)
+		}
+	}
+	return nodes
+}
+
+func diffViewStatus(kind DiffKind) string {
+	switch kind {
+	case DiffNewlyFailing, DiffStillFailing:
+		return "failed"
+	case DiffFixed:
+		return "passed"
+	}
+	return "skipped"
+}
+
+func formatCoverage(s coverage.Summary) string {
+	return fmt.Sprintf("%.1f%% (%d/%d lines)", s.Percent, s.Covered, s.Total)
+}
+
 func formatBenchNs(ns float64) string {
 	switch {
 	case ns >= 1e9:
@@ -4,6 +4,7 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/flanksource/gavel/linters"
 	"github.com/flanksource/gavel/models"
@@ -201,3 +202,41 @@ func containsName(values []string, want string) bool {
 	}
 	return false
 }
+
+func TestBuildDiffViewNodesGroupsByKind(t *testing.T) {
+	nodes := buildDiffViewNodes(&RunDiff{
+		Tests: []TestDiff{
+			{Kind: DiffNewlyFailing, Name: "TestBreaks", PackagePath: "./pkg", HeadStatus: "failed", Message: "want 1"},
+			{Kind: DiffSlower, Name: "TestSlow", BaseDuration: time.Second, HeadDuration: 3 * time.Second},
+			{Kind: DiffFixed, Name: "TestFixed"},
+		},
+		Lint: []LintDiff{{Kind: DiffNewlyFailing, Linter: "golangci-lint", Rule: "unused", File: "a.go"}},
+	})
+	if got := names(nodes); strings.Join(got, ",") != "Newly failing,Fixed,Slower,Lint: newly failing" {
+		t.Fatalf("groups = %v", got)
+	}
+	breaks := mustChildByName(t, nodes[0].Children, "TestBreaks")
+	if breaks.Status != "failed" || breaks.Message != "want 1" || breaks.PackagePath != "./pkg" {
+		t.Fatalf("newly failing node = %#v", breaks)
+	}
+	if nodes[1].Status != "passed" {
+		t.Fatalf("fixed status = %q, want passed", nodes[1].Status)
+	}
+	if slow := nodes[2].Children[0]; slow.Message != "1.00s -> 3.00s" {
+		t.Fatalf("slower message = %q", slow.Message)
+	}
+	lint := mustChildByName(t, nodes[3].Children, "golangci-lint unused")
+	if lint.File != "a.go" || lint.Status != "failed" {
+		t.Fatalf("lint node = %#v", lint)
+	}
+	if buildDiffViewNodes(nil) != nil {
+		t.Fatalf("nil diff should build no nodes")
+	}
+}
+
+func TestParseRouteRequestAcceptsCompareTab(t *testing.T) {
+	parsed, ok := parseRouteRequest(httptest.NewRequest("GET", "/compare", nil))
+	if !ok || parsed.Tab != viewTabCompare {
+		t.Fatalf("parsed = %#v, ok = %v", parsed, ok)
+	}
+}
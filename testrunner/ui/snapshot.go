@@ -5,7 +5,9 @@ import (
 
 	"github.com/flanksource/clicky/api"
 	"github.com/flanksource/gavel/linters"
+	"github.com/flanksource/gavel/linters/fixpreview"
 	"github.com/flanksource/gavel/testrunner/bench"
+	"github.com/flanksource/gavel/testrunner/coverage"
 	"github.com/flanksource/gavel/testrunner/parsers"
 )
 
@@ -40,13 +42,17 @@ type SnapshotStatus struct {
 }
 
 type Snapshot struct {
-	Metadata    *SnapshotMetadata       `json:"metadata,omitempty"`
-	Git         *SnapshotGit            `json:"git,omitempty"`
-	Status      SnapshotStatus          `json:"status"`
-	Tests       []parsers.Test          `json:"tests"`
-	Lint        []*linters.LinterResult `json:"lint,omitempty"`
-	Bench       *bench.BenchComparison  `json:"bench,omitempty"`
-	Diagnostics *DiagnosticsSnapshot    `json:"diagnostics,omitempty"`
+	Metadata     *SnapshotMetadata       `json:"metadata,omitempty"`
+	Git          *SnapshotGit            `json:"git,omitempty"`
+	Status       SnapshotStatus          `json:"status"`
+	Tests        []parsers.Test          `json:"tests"`
+	Lint         []*linters.LinterResult `json:"lint,omitempty"`
+	Bench        *bench.BenchComparison  `json:"bench,omitempty"`
+	BenchHistory *bench.BenchHistory     `json:"bench_history,omitempty"`
+	Coverage     *coverage.Report        `json:"coverage,omitempty"`
+	FixPreview   *fixpreview.Preview     `json:"fix_preview,omitempty"`
+	Diagnostics  *DiagnosticsSnapshot    `json:"diagnostics,omitempty"`
+	Diff         *RunDiff                `json:"diff,omitempty"`
 }
 
 // Pretty is the root label of the tree clicky renders for serialized formats
@@ -0,0 +1,126 @@
+package testui
+
+import (
+	"slices"
+	"sort"
+
+	"github.com/flanksource/gavel/testrunner/coverage"
+	"github.com/flanksource/gavel/testrunner/parsers"
+)
+
+// MergeSnapshots combines the snapshots of the shards of one `gavel test
+// --shard i/N` run into a single snapshot describing the whole run. Shards
+// run disjoint packages, so group nodes (framework, package, suite) with the
+// same identity are merged and their leaves concatenated; lint results, which
+// every shard may produce for the same linter, keep the first copy.
+func MergeSnapshots(shards []Snapshot) Snapshot {
+	var merged Snapshot
+	var covs []*coverage.Report
+	seenLint := map[string]bool{}
+	for _, s := range shards {
+		merged.Metadata = mergeSnapshotMetadata(merged.Metadata, s.Metadata)
+		if merged.Git == nil {
+			merged.Git = cloneSnapshotGit(s.Git)
+		}
+		merged.Status.LintRun = merged.Status.LintRun || s.Status.LintRun
+		merged.Tests = mergeShardTests(merged.Tests, s.Tests)
+		for _, r := range s.Lint {
+			if r == nil {
+				continue
+			}
+			key := r.Linter + "\x00" + r.WorkDir
+			if seenLint[key] {
+				continue
+			}
+			seenLint[key] = true
+			merged.Lint = append(merged.Lint, r)
+		}
+		if merged.Bench == nil {
+			merged.Bench = s.Bench
+		}
+		if merged.BenchHistory == nil {
+			merged.BenchHistory = s.BenchHistory
+		}
+		covs = append(covs, s.Coverage)
+	}
+	merged.Coverage = coverage.MergeReports(covs...)
+	if merged.Metadata != nil {
+		merged.Metadata.Kind = "merge"
+		delete(merged.Metadata.Args, "shard")
+	}
+	return merged
+}
+
+func mergeSnapshotMetadata(into, meta *SnapshotMetadata) *SnapshotMetadata {
+	if meta == nil {
+		return into
+	}
+	if into == nil {
+		return cloneSnapshotMetadata(meta)
+	}
+	if !meta.Started.IsZero() && (into.Started.IsZero() || meta.Started.Before(into.Started)) {
+		into.Started = meta.Started
+	}
+	if meta.Ended.After(into.Ended) {
+		into.Ended = meta.Ended
+	}
+	if meta.ExitCode != nil && (into.ExitCode == nil || *meta.ExitCode > *into.ExitCode) {
+		code := *meta.ExitCode
+		into.ExitCode = &code
+	}
+	into.TimedOut = into.TimedOut || meta.TimedOut
+	for _, fw := range meta.Frameworks {
+		if !slices.Contains(into.Frameworks, fw) {
+			into.Frameworks = append(into.Frameworks, fw)
+		}
+	}
+	sort.Strings(into.Frameworks)
+	return into
+}
+
+// mergeShardTests merges incoming into existing. Nodes with children that
+// share a shardMergeKey are merged recursively; everything else is appended.
+// Roll-up summaries and failure flags are recomputed from the merged children.
+func mergeShardTests(existing, incoming []parsers.Test) []parsers.Test {
+	if len(existing) == 0 {
+		return append([]parsers.Test(nil), incoming...)
+	}
+	merged := append([]parsers.Test(nil), existing...)
+	groups := map[string]int{}
+	for i, t := range merged {
+		if len(t.Children) > 0 {
+			groups[shardMergeKey(t)] = i
+		}
+	}
+	for _, in := range incoming {
+		idx, ok := groups[shardMergeKey(in)]
+		if !ok || len(in.Children) == 0 {
+			merged = append(merged, in)
+			if len(in.Children) > 0 {
+				groups[shardMergeKey(in)] = len(merged) - 1
+			}
+			continue
+		}
+		node := merged[idx]
+		node.Children = mergeShardTests(node.Children, in.Children)
+		node.Duration += in.Duration
+		node.Failed = node.Failed || in.Failed
+		node.Warned = !node.Failed && (node.Warned || in.Warned)
+		node.TimedOut = node.TimedOut || in.TimedOut
+		if node.Summary != nil || in.Summary != nil {
+			node.Summary = nil
+			sum := node.Sum()
+			node.Summary = &sum
+		}
+		merged[idx] = node
+	}
+	return merged
+}
+
+func shardMergeKey(t parsers.Test) string {
+	key := testMergeKey(t) + "\x00" + t.WorkDir
+	for _, s := range t.Suite {
+		key += "\x00" + s
+	}
+	return key
+}
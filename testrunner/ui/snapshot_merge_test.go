@@ -0,0 +1,72 @@
+package testui
+
+import (
+	"testing"
+	"time"
+
+	"github.com/flanksource/gavel/linters"
+	"github.com/flanksource/gavel/testrunner/parsers"
+)
+
+func TestMergeSnapshotsCombinesShards(t *testing.T) {
+	t0 := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	zero, one := 0, 1
+	goRoot := func(pkg string, leaf parsers.Test) parsers.Test {
+		return parsers.Test{
+			Name:      "go test",
+			Framework: parsers.GoTest,
+			Children: parsers.Tests{{
+				Name:        pkg,
+				Framework:   parsers.GoTest,
+				PackagePath: pkg,
+				Children:    parsers.Tests{leaf},
+			}},
+		}
+	}
+	shard1 := Snapshot{
+		Metadata: &SnapshotMetadata{Started: t0, Ended: t0.Add(time.Minute), ExitCode: &zero,
+			Frameworks: []string{"go test"}, Args: map[string]any{"shard": "1/2"}},
+		Git:   &SnapshotGit{SHA: "abc"},
+		Tests: []parsers.Test{goRoot("./a", parsers.Test{Name: "TestA", PackagePath: "./a", Framework: parsers.GoTest, Passed: true})},
+		Lint:  []*linters.LinterResult{{Linter: "golangci-lint", WorkDir: "/repo"}},
+	}
+	shard2 := Snapshot{
+		Metadata: &SnapshotMetadata{Started: t0.Add(time.Second), Ended: t0.Add(2 * time.Minute), ExitCode: &one,
+			Frameworks: []string{"ginkgo", "go test"}, Args: map[string]any{"shard": "2/2"}},
+		Git:   &SnapshotGit{SHA: "abc"},
+		Tests: []parsers.Test{goRoot("./b", parsers.Test{Name: "TestB", PackagePath: "./b", Framework: parsers.GoTest, Failed: true})},
+		Lint:  []*linters.LinterResult{{Linter: "golangci-lint", WorkDir: "/repo"}},
+	}
+
+	m := MergeSnapshots([]Snapshot{shard1, shard2})
+
+	if len(m.Tests) != 1 || len(m.Tests[0].Children) != 2 {
+		t.Fatalf("want one go test root with both packages, got %+v", m.Tests)
+	}
+	if !m.Tests[0].Failed {
+		t.Error("a failure on any shard should mark the merged root failed")
+	}
+	sum := parsers.Tests(m.Tests).Sum()
+	if sum.Passed != 1 || sum.Failed != 1 {
+		t.Errorf("summary = %+v", sum)
+	}
+	if len(m.Lint) != 1 {
+		t.Errorf("lint results from every shard should be deduplicated, got %d", len(m.Lint))
+	}
+	meta := m.Metadata
+	if !meta.Started.Equal(t0) || !meta.Ended.Equal(t0.Add(2*time.Minute)) {
+		t.Errorf("time range = %s..%s", meta.Started, meta.Ended)
+	}
+	if meta.ExitCode == nil || *meta.ExitCode != 1 || meta.Kind != "merge" {
+		t.Errorf("metadata = %+v", meta)
+	}
+	if _, ok := meta.Args["shard"]; ok {
+		t.Error("merged args should not carry a shard")
+	}
+	if len(meta.Frameworks) != 2 {
+		t.Errorf("frameworks = %v", meta.Frameworks)
+	}
+	if shard1.Metadata.ExitCode != &zero || *shard1.Metadata.ExitCode != 0 || shard1.Metadata.Args["shard"] != "1/2" {
+		t.Error("merge must not mutate its inputs")
+	}
+}
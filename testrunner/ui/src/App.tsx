@@ -1,5 +1,5 @@
 import { useState, useEffect, useRef, useMemo, useCallback, type MutableRefObject } from 'react';
-import type { Test, Snapshot, SnapshotStatus, LinterResult, BenchComparison, DiagnosticsSnapshot, ProcessNode, ProcessDetails, RunMeta, TestEditAction, TestEditScope } from './types';
+import type { Test, Snapshot, SnapshotStatus, LinterResult, BenchComparison, BenchHistory, CoverageReport, FixPreview, DiagnosticsSnapshot, RunDiff, ProcessNode, ProcessDetails, RunMeta, TestEditAction, TestEditScope } from './types';
 import { Summary } from './components/Summary';
 import { TestNode } from './components/TestNode';
 import { DetailPanel, type IgnoreRequest } from './components/DetailPanel';
@@ -9,6 +9,9 @@ import { FilterBar, type Filters } from './components/FilterBar';
 import { LintFilterBar, type LintGrouping, type LintFilters } from './components/LintFilterBar';
 import { LintView } from './components/LintView';
 import { BenchView } from './components/BenchView';
+import { CoverageView } from './components/CoverageView';
+import { CompareView } from './components/CompareView';
+import { FixPreviewView } from './components/FixPreviewView';
 import { RerunDialog } from './components/RerunDialog';
 import { SplitPane } from './components/SplitPane';
 import { copyCurrentViewForAgent } from './export';
@@ -44,6 +47,10 @@ function applySnapshot(
   setLint: (l: LinterResult[] | undefined) => void,
   setLintRun: (r: boolean) => void,
   setBench: (b: BenchComparison | undefined) => void,
+  setBenchHistory: (h: BenchHistory | undefined) => void,
+  setCoverage: (c: CoverageReport | undefined) => void,
+  setDiff: (d: RunDiff | undefined) => void,
+  setFixPreview: (p: FixPreview | undefined) => void,
   setDiagnosticsAvailable: (v: boolean) => void,
   setDiagnostics: (d: DiagnosticsSnapshot | undefined) => void,
   setSnapshotStatus: (s: SnapshotStatus) => void,
@@ -71,6 +78,10 @@ function applySnapshot(
   setLint(snap.lint);
   setLintRun(!!status.lint_run);
   setBench(snap.bench);
+  setBenchHistory(snap.bench_history);
+  setCoverage(snap.coverage);
+  setDiff(snap.diff);
+  setFixPreview(snap.fix_preview);
   setDiagnosticsAvailable(!!status.diagnostics_available);
   if (snap.diagnostics) setDiagnostics(snap.diagnostics);
   setSnapshotStatus(status);
@@ -134,6 +145,10 @@ export function App() {
   const [lint, setLint] = useState<LinterResult[] | undefined>(undefined);
   const [lintRun, setLintRun] = useState(false);
   const [bench, setBench] = useState<BenchComparison | undefined>(undefined);
+  const [benchHistory, setBenchHistory] = useState<BenchHistory | undefined>(undefined);
+  const [coverage, setCoverage] = useState<CoverageReport | undefined>(undefined);
+  const [diff, setDiff] = useState<RunDiff | undefined>(undefined);
+  const [fixPreview, setFixPreview] = useState<FixPreview | undefined>(undefined);
   const [diagnosticsAvailable, setDiagnosticsAvailable] = useState(false);
   const [diagnostics, setDiagnostics] = useState<DiagnosticsSnapshot | undefined>(undefined);
   const [runMeta, setRunMeta] = useState<RunMeta | undefined>(undefined);
@@ -149,6 +164,7 @@ export function App() {
   const [rerunBusy, setRerunBusy] = useState(false);
   const [rerunDialogOpen, setRerunDialogOpen] = useState(false);
   const [ignoreBusy, setIgnoreBusy] = useState(false);
+  const [fixBusy, setFixBusy] = useState(false);
   const [testEditBusy, setTestEditBusy] = useState(false);
   const [stackBusyPID, setStackBusyPID] = useState<number | null>(null);
   const [copyState, setCopyState] = useState<'idle' | 'copying' | 'copied' | 'error'>('idle');
@@ -184,7 +200,7 @@ export function App() {
     const res = await fetch(apiUrl('/api/tests'));
     if (!res.ok) throw new Error(`Snapshot request failed (${res.status})`);
     const snap: Snapshot = await res.json();
-    applySnapshot(snap, startTime, endTime, doneRef, setTests, setLint, setLintRun, setBench, setDiagnosticsAvailable, setDiagnostics, setSnapshotStatus, setRunMeta, setDone, setStatus);
+    applySnapshot(snap, startTime, endTime, doneRef, setTests, setLint, setLintRun, setBench, setBenchHistory, setCoverage, setDiff, setFixPreview, setDiagnosticsAvailable, setDiagnostics, setSnapshotStatus, setRunMeta, setDone, setStatus);
   }, []);
 
   useEffect(() => {
@@ -214,7 +230,7 @@ export function App() {
       fetch(apiUrl('/api/tests'))
         .then(r => r.json())
         .then((snap: Snapshot) => {
-          applySnapshot(snap, startTime, endTime, doneRef, setTests, setLint, setLintRun, setBench, setDiagnosticsAvailable, setDiagnostics, setSnapshotStatus, setRunMeta, setDone, setStatus);
+          applySnapshot(snap, startTime, endTime, doneRef, setTests, setLint, setLintRun, setBench, setBenchHistory, setCoverage, setDiff, setFixPreview, setDiagnosticsAvailable, setDiagnostics, setSnapshotStatus, setRunMeta, setDone, setStatus);
         })
         .catch(() => {});
     }
@@ -223,7 +239,7 @@ export function App() {
 
     es.addEventListener('message', (e: MessageEvent) => {
       const snap: Snapshot = JSON.parse(e.data);
-      applySnapshot(snap, startTime, endTime, doneRef, setTests, setLint, setLintRun, setBench, setDiagnosticsAvailable, setDiagnostics, setSnapshotStatus, setRunMeta, setDone, setStatus);
+      applySnapshot(snap, startTime, endTime, doneRef, setTests, setLint, setLintRun, setBench, setBenchHistory, setCoverage, setDiff, setFixPreview, setDiagnosticsAvailable, setDiagnostics, setSnapshotStatus, setRunMeta, setDone, setStatus);
       if (!snap.status?.running) es.close();
     });
 
@@ -616,6 +632,30 @@ export function App() {
     }
   }, [ignoreBusy, routeState, commitRoute]);
 
+  const onApplyFixes = useCallback(async (hunks: string[]) => {
+    if (fixBusy) return;
+    setFixBusy(true);
+    try {
+      const res = await fetch(apiUrl('/api/lint/fixes/apply'), {
+        method: 'POST',
+        headers: { 'Content-Type': 'application/json' },
+        body: JSON.stringify({ hunks }),
+      });
+      if (!res.ok) {
+        const text = await res.text();
+        setStatus(`Apply failed: ${text.trim()}`);
+      } else {
+        const result = await res.json();
+        const conflicts = Object.keys(result.conflicts || {}).length;
+        setStatus(`Applied ${(result.applied || []).length} fix hunks${conflicts ? `, ${conflicts} conflicting` : ''}`);
+      }
+    } catch (e: any) {
+      setStatus(`Apply error: ${e?.message || e}`);
+    } finally {
+      setFixBusy(false);
+    }
+  }, [fixBusy]);
+
   const onTestEdit = useCallback(async (t: Test, action: TestEditAction, scope: TestEditScope) => {
     if (testEditBusy || !snapshotStatus.test_edit_supported) return;
     const target = scope === 'file' ? (t.file || 'file') : (t.name || 'test');
@@ -686,20 +726,31 @@ export function App() {
   }, [stackBusyPID]);
 
   const showLintTab = lintRun;
-  const showBenchTab = !!bench;
+  const showBenchTab = !!bench || !!benchHistory?.trends?.length;
+  const showCoverageTab = !!coverage;
   const showDiagnosticsTab = diagnosticsAvailable;
-  const showTabs = showLintTab || showBenchTab || showDiagnosticsTab;
+  const showCompareTab = !!diff;
+  const showTabs = showLintTab || showBenchTab || showCoverageTab || showDiagnosticsTab || showCompareTab;
   const benchRegressions = bench?.deltas?.filter(d => d.significant && d.delta_pct > bench.threshold).length || 0;
+  const newlyFailing = (diff?.tests || []).filter(t => t.kind === 'newly_failing').length
+    + (diff?.lint || []).filter(l => l.kind === 'newly_failing').length;
+  const diffCoverageFailed = !!coverage?.diff?.threshold && coverage.diff.total > 0 && coverage.diff.percent < coverage.diff.threshold;
   const hasContent = activeTab === 'tests'
     ? displayedTests.length > 0
     : activeTab === 'lint'
       ? lintTree.length > 0
       : activeTab === 'diagnostics'
         ? processCount > 0
-        : !!bench;
+        : activeTab === 'coverage'
+          ? !!coverage
+          : activeTab === 'compare'
+            ? !!diff
+            : showBenchTab;
   const canExportCurrentView = (activeTab === 'tests' && displayedTests.length > 0)
     || (activeTab === 'lint' && lintRun)
-    || (activeTab === 'bench' && !!bench);
+    || (activeTab === 'bench' && !!bench)
+    || (activeTab === 'coverage' && !!coverage)
+    || (activeTab === 'compare' && !!diff);
   const canGlobalStop = snapshotStatus.running && !!snapshotStatus.stop_supported;
   const wholeResultRouteState = useMemo<RouteState>(
     () => ({ ...routeState, selectedPath: '' }),
@@ -764,10 +815,14 @@ export function App() {
               {activeTab === 'lint'
                 ? 'Lint Results'
                 : activeTab === 'bench'
-                  ? 'Benchmark Comparison'
-                  : activeTab === 'diagnostics'
-                    ? 'Diagnostics'
-                    : 'Test Results'}
+                  ? (bench ? 'Benchmark Comparison' : 'Benchmark History')
+                  : activeTab === 'coverage'
+                    ? 'Coverage'
+                    : activeTab === 'diagnostics'
+                      ? 'Diagnostics'
+                      : activeTab === 'compare'
+                        ? 'Run Comparison'
+                        : 'Test Results'}
             </h1>
             {hasContent && (
               <div className="flex gap-1">
@@ -852,10 +907,30 @@ export function App() {
                 onClick={() => onTabChange('bench')}
                 icon="codicon:graph"
                 label="Bench"
-                count={benchRegressions > 0 ? benchRegressions : (bench?.deltas?.length || 0)}
+                count={benchRegressions > 0 ? benchRegressions : (bench?.deltas?.length || benchHistory?.trends?.length || 0)}
                 countColor={benchRegressions > 0 ? 'bg-red-500' : 'bg-gray-400'}
               />
             )}
+            {showCoverageTab && (
+              <TabButton
+                active={activeTab === 'coverage'}
+                onClick={() => onTabChange('coverage')}
+                icon="codicon:shield"
+                label="Coverage"
+                count={Math.round(coverage?.diff?.percent ?? coverage?.summary.percent ?? 0)}
+                countColor={diffCoverageFailed ? 'bg-red-500' : 'bg-green-500'}
+              />
+            )}
+            {showCompareTab && (
+              <TabButton
+                active={activeTab === 'compare'}
+                onClick={() => onTabChange('compare')}
+                icon="codicon:git-compare"
+                label="Compare"
+                count={newlyFailing > 0 ? newlyFailing : (diff?.tests?.length || 0) + (diff?.lint?.length || 0)}
+                countColor={newlyFailing > 0 ? 'bg-red-500' : 'bg-gray-400'}
+              />
+            )}
             {showDiagnosticsTab && (
               <TabButton
                 active={activeTab === 'diagnostics'}
@@ -910,15 +985,20 @@ export function App() {
               </>
             )}
             {activeTab === 'lint' && (
-              <LintView
-                lint={lint}
-                tree={lintTree}
-                expandAll={expandAll}
-                selected={selected}
-                onSelect={onSelect}
-              />
+              <>
+                <FixPreviewView preview={fixPreview} busy={fixBusy} onApply={onApplyFixes} />
+                <LintView
+                  lint={lint}
+                  tree={lintTree}
+                  expandAll={expandAll}
+                  selected={selected}
+                  onSelect={onSelect}
+                />
+              </>
             )}
-            {activeTab === 'bench' && <BenchView bench={bench} />}
+            {activeTab === 'bench' && <BenchView bench={bench} history={benchHistory} />}
+            {activeTab === 'coverage' && <CoverageView coverage={coverage} />}
+            {activeTab === 'compare' && <CompareView diff={diff} />}
             {activeTab === 'diagnostics' && (
               <DiagnosticsView
                 root={diagnostics?.root}
@@ -0,0 +1,46 @@
+import type { BenchTrend } from '../types';
+
+interface Props {
+  trend: BenchTrend;
+  width?: number;
+  height?: number;
+}
+
+// BenchSparkline plots a benchmark's ns/op across recorded commits, oldest on
+// the left. Change points are marked at the first commit after the shift:
+// red for regressions, green for improvements.
+export function BenchSparkline({ trend, width = 120, height = 24 }: Props) {
+  const values = trend.points.map(p => p.mean);
+  if (values.length === 0) return <span className="text-gray-300">—</span>;
+
+  const lo = Math.min(...values);
+  const hi = Math.max(...values);
+  const pad = 2;
+  const x = (i: number) => values.length === 1 ? width / 2 : pad + (i * (width - 2 * pad)) / (values.length - 1);
+  const y = (v: number) => hi === lo ? height / 2 : height - pad - ((v - lo) * (height - 2 * pad)) / (hi - lo);
+  const path = values.map((v, i) => `${x(i).toFixed(1)},${y(v).toFixed(1)}`).join(' ');
+
+  const title = trend.points
+    .map(p => `${p.commit.slice(0, 8)}${p.uncommitted ? '*' : ''} ${p.mean.toFixed(0)}ns/op`)
+    .join('\n');
+
+  return (
+    <svg width={width} height={height} className="inline-block align-middle">
+      <title>{title}</title>
+      <polyline points={path} fill="none" stroke="#6b7280" strokeWidth={1.25} />
+      {(trend.change_points || []).map(cp => (
+        <circle
+          key={cp.index}
+          cx={x(cp.index)}
+          cy={y(values[cp.index])}
+          r={2.5}
+          fill={cp.regression ? '#ef4444' : '#16a34a'}
+        >
+          <title>
+            {`${cp.regression ? 'regressed' : 'improved'} ${cp.delta_pct >= 0 ? '+' : ''}${cp.delta_pct.toFixed(2)}% at ${cp.commit.slice(0, 8)}${cp.subject ? ` ${cp.subject}` : ''}`}
+          </title>
+        </circle>
+      ))}
+    </svg>
+  );
+}
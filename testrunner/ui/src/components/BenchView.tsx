@@ -1,9 +1,11 @@
 import { useMemo, useState } from 'react';
-import type { BenchComparison, BenchDelta } from '../types';
+import type { BenchComparison, BenchDelta, BenchHistory, BenchTrend } from '../types';
 import { BenchDeltaBar } from './BenchDeltaBar';
+import { BenchSparkline } from './BenchSparkline';
 
 interface Props {
   bench: BenchComparison | undefined;
+  history?: BenchHistory;
 }
 
 type SortKey = 'name' | 'delta' | 'base' | 'head' | 'p';
@@ -22,10 +24,13 @@ function categorize(d: BenchDelta, threshold: number): 'regression' | 'improveme
   return 'neutral';
 }
 
-export function BenchView({ bench }: Props) {
+export function BenchView({ bench, history }: Props) {
   const [sortKey, setSortKey] = useState<SortKey>('delta');
   const [sortDesc, setSortDesc] = useState(true);
 
+  if (!bench && history?.trends?.length) {
+    return <BenchHistoryTable history={history} />;
+  }
   if (!bench) {
     return (
       <div className="p-8 text-center text-gray-400 text-sm">
@@ -64,6 +69,13 @@ export function BenchView({ bench }: Props) {
     return { regressions, improvements, neutral, only };
   }, [bench]);
 
+  const trends = useMemo(() => {
+    const byName = new Map<string, BenchTrend>();
+    for (const t of history?.trends || []) byName.set(t.name, t);
+    return byName;
+  }, [history]);
+  const showTrend = trends.size > 0;
+
   const onSort = (key: SortKey) => {
     if (sortKey === key) setSortDesc(!sortDesc);
     else { setSortKey(key); setSortDesc(true); }
@@ -115,10 +127,19 @@ export function BenchView({ bench }: Props) {
             <SortHeader onClick={() => onSort('head')} icon={sortIcon('head')} align="right">Head</SortHeader>
             <SortHeader onClick={() => onSort('delta')} icon={sortIcon('delta')}>Δ</SortHeader>
             <SortHeader onClick={() => onSort('p')} icon={sortIcon('p')} align="right">p-value</SortHeader>
+            {showTrend && <th className="py-2 px-2 font-semibold">Trend</th>}
           </tr>
         </thead>
         <tbody>
-          {sorted.map(d => <BenchRow key={d.name} delta={d} threshold={bench.threshold} />)}
+          {sorted.map(d => (
+            <BenchRow
+              key={d.name}
+              delta={d}
+              threshold={bench.threshold}
+              trend={trends.get(d.name)}
+              showTrend={showTrend}
+            />
+          ))}
         </tbody>
       </table>
     </div>
@@ -141,7 +162,12 @@ function SortHeader({ children, onClick, icon, align }: {
   );
 }
 
-function BenchRow({ delta, threshold }: { delta: BenchDelta; threshold: number }) {
+function BenchRow({ delta, threshold, trend, showTrend }: {
+  delta: BenchDelta;
+  threshold: number;
+  trend?: BenchTrend;
+  showTrend: boolean;
+}) {
   const cat = categorize(delta, threshold);
   const rowBg = cat === 'regression' ? 'bg-red-50' : cat === 'improvement' ? 'bg-green-50' : '';
 
@@ -149,7 +175,7 @@ function BenchRow({ delta, threshold }: { delta: BenchDelta; threshold: number }
     return (
       <tr className="border-b border-gray-100 text-gray-500">
         <td className="py-1.5 px-2 font-mono text-xs">{delta.name}</td>
-        <td className="py-1.5 px-2 text-right text-xs italic" colSpan={4}>
+        <td className="py-1.5 px-2 text-right text-xs italic" colSpan={showTrend ? 5 : 4}>
           only in {delta.only_in}
         </td>
       </tr>
@@ -183,6 +209,72 @@ function BenchRow({ delta, threshold }: { delta: BenchDelta; threshold: number }
           <span className="ml-1 text-gray-400">n={delta.samples}</span>
         )}
       </td>
+      {showTrend && (
+        <td className="py-1.5 px-2">
+          {trend ? <BenchSparkline trend={trend} /> : <span className="text-gray-300">—</span>}
+        </td>
+      )}
     </tr>
   );
 }
+
+// BenchHistoryTable lists every benchmark recorded by `gavel bench run` with
+// its ns/op trend, latest values, and the most recent change point.
+function BenchHistoryTable({ history }: { history: BenchHistory }) {
+  const regressed = (t: BenchTrend) => !!t.change_points?.[t.change_points.length - 1]?.regression;
+  const trends = [...history.trends].sort((a, b) =>
+    Number(regressed(b)) - Number(regressed(a)) || a.name.localeCompare(b.name));
+  const regressions = trends.filter(regressed).length;
+
+  return (
+    <div className="p-4">
+      <div className="mb-3 flex items-center gap-4 text-sm">
+        <div className="text-gray-600">{history.commits} commit{history.commits === 1 ? '' : 's'} recorded</div>
+        <div className="text-gray-500 text-xs">threshold ±{history.threshold.toFixed(1)}%</div>
+        {regressions > 0 && (
+          <span className="px-2 py-0.5 bg-red-100 text-red-700 text-xs rounded font-semibold">
+            {regressions} regression{regressions > 1 ? 's' : ''}
+          </span>
+        )}
+      </div>
+      <table className="w-full text-sm">
+        <thead>
+          <tr className="border-b border-gray-200 text-left text-xs text-gray-500 uppercase">
+            <th className="py-2 px-2 font-semibold">Benchmark</th>
+            <th className="py-2 px-2 font-semibold">Trend</th>
+            <th className="py-2 px-2 font-semibold text-right">ns/op</th>
+            <th className="py-2 px-2 font-semibold text-right">B/op</th>
+            <th className="py-2 px-2 font-semibold text-right">allocs/op</th>
+            <th className="py-2 px-2 font-semibold">Last change</th>
+          </tr>
+        </thead>
+        <tbody>
+          {trends.map(t => {
+            const last = t.points[t.points.length - 1];
+            const cp = t.change_points?.[t.change_points.length - 1];
+            return (
+              <tr key={`${t.package}:${t.name}`} className={`border-b border-gray-100 hover:bg-gray-50 ${regressed(t) ? 'bg-red-50' : ''}`}>
+                <td className="py-1.5 px-2 font-mono text-xs truncate max-w-[24rem]" title={t.package ? `${t.package}.${t.name}` : t.name}>
+                  {t.name}
+                </td>
+                <td className="py-1.5 px-2"><BenchSparkline trend={t} /></td>
+                <td className="py-1.5 px-2 text-right tabular-nums text-xs">{last ? formatNs(last.mean) : ''}</td>
+                <td className="py-1.5 px-2 text-right tabular-nums text-xs">{last?.bytes_per_op ?? ''}</td>
+                <td className="py-1.5 px-2 text-right tabular-nums text-xs">{last?.allocs_per_op ?? ''}</td>
+                <td className="py-1.5 px-2 text-xs">
+                  {cp ? (
+                    <span className={cp.regression ? 'text-red-600 font-semibold' : 'text-green-600'} title={cp.subject}>
+                      {cp.delta_pct >= 0 ? '+' : ''}{cp.delta_pct.toFixed(2)}% at <span className="font-mono">{cp.commit.slice(0, 8)}</span>
+                    </span>
+                  ) : (
+                    <span className="text-gray-300">—</span>
+                  )}
+                </td>
+              </tr>
+            );
+          })}
+        </tbody>
+      </table>
+    </div>
+  );
+}
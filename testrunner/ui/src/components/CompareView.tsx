@@ -0,0 +1,127 @@
+import { useState } from 'react';
+import type { DiffKind, DiffRun, LintDiff, RunDiff, TestDiff } from '../types';
+import { formatDuration } from '../utils';
+
+interface Props {
+  diff: RunDiff | undefined;
+}
+
+const KIND_ORDER: DiffKind[] = ['newly_failing', 'fixed', 'still_failing', 'slower', 'added', 'removed'];
+
+const KIND_STYLE: Record<DiffKind, { label: string; lintLabel: string; icon: string; color: string; open: boolean }> = {
+  newly_failing: { label: 'Newly failing', lintLabel: 'New lint violations', icon: 'codicon:error', color: 'text-red-600', open: true },
+  fixed: { label: 'Fixed', lintLabel: 'Fixed lint violations', icon: 'codicon:pass', color: 'text-green-600', open: true },
+  still_failing: { label: 'Still failing', lintLabel: 'Remaining lint violations', icon: 'codicon:circle-slash', color: 'text-red-400', open: false },
+  slower: { label: 'Slower', lintLabel: '', icon: 'codicon:watch', color: 'text-orange-500', open: true },
+  added: { label: 'Added', lintLabel: '', icon: 'codicon:add', color: 'text-gray-600', open: false },
+  removed: { label: 'Removed', lintLabel: '', icon: 'codicon:remove', color: 'text-gray-500', open: false },
+};
+
+function runLabel(run: DiffRun): string {
+  const label = run.label || 'run';
+  return run.sha ? `${label}@${run.sha.slice(0, 8)}` : label;
+}
+
+function testDetail(t: TestDiff): string {
+  switch (t.kind) {
+    case 'slower': {
+      const base = t.base_duration || 0;
+      const head = t.head_duration || 0;
+      const pct = base > 0 ? ` (+${Math.round(((head - base) / base) * 100)}%)` : '';
+      return `${formatDuration(base)} → ${formatDuration(head)}${pct}`;
+    }
+    case 'added':
+      return t.head_status || '';
+    case 'removed':
+      return t.base_status || '';
+  }
+  return t.message || '';
+}
+
+function lintCount(l: LintDiff): string {
+  if (l.kind === 'still_failing' && l.base_count !== l.head_count) return `${l.base_count} → ${l.head_count}`;
+  const n = l.kind === 'fixed' ? l.base_count : l.head_count;
+  return n && n > 1 ? `×${n}` : '';
+}
+
+function Section({ kind, label, count, children }: { kind: DiffKind; label: string; count: number; children: any }) {
+  const style = KIND_STYLE[kind];
+  const [open, setOpen] = useState(style.open);
+  return (
+    <div className="mb-3">
+      <button className="flex items-center gap-1.5 text-sm font-semibold" onClick={() => setOpen(!open)}>
+        <iconify-icon icon={open ? 'codicon:chevron-down' : 'codicon:chevron-right'} className="text-gray-400" />
+        <iconify-icon icon={style.icon} className={style.color} />
+        <span className={style.color}>{label}</span>
+        <span className="text-xs text-gray-400 tabular-nums">({count})</span>
+      </button>
+      {open && (
+        <table className="mt-1 w-full text-sm">
+          <tbody>{children}</tbody>
+        </table>
+      )}
+    </div>
+  );
+}
+
+export function CompareView({ diff }: Props) {
+  if (!diff) {
+    return (
+      <div className="p-8 text-center text-gray-400 text-sm">
+        No comparison loaded. Run <code className="px-1 bg-gray-200 rounded">gavel ui serve --compare base.json head.json</code>.
+      </div>
+    );
+  }
+
+  const tests = diff.tests || [];
+  const lint = diff.lint || [];
+
+  return (
+    <div className="p-4">
+      <div className="mb-3 flex items-center gap-2 text-sm text-gray-600">
+        <span className="font-mono">{runLabel(diff.base)}</span>
+        <iconify-icon icon="codicon:arrow-right" className="text-gray-400" />
+        <span className="font-mono">{runLabel(diff.head)}</span>
+        <span className="ml-4 text-xs text-gray-400">{diff.unchanged} tests unchanged</span>
+      </div>
+
+      {tests.length === 0 && lint.length === 0 && (
+        <div className="p-8 text-center text-gray-400 text-sm">No changes between the two runs</div>
+      )}
+
+      {KIND_ORDER.map(kind => {
+        const rows = tests.filter(t => t.kind === kind);
+        if (rows.length === 0) return null;
+        return (
+          <Section key={kind} kind={kind} label={KIND_STYLE[kind].label} count={rows.length}>
+            {rows.map(t => (
+              <tr key={`${t.framework}|${t.package_path}|${t.name}`} className="border-b border-gray-100">
+                <td className="py-1 px-2 font-mono text-xs" title={t.name}>{t.name}</td>
+                <td className="py-1 px-2 font-mono text-xs text-gray-400 truncate max-w-[16rem]" title={t.package_path}>{t.package_path}</td>
+                <td className="py-1 px-2 text-xs text-gray-600 truncate max-w-[32rem]" title={t.message}>{testDetail(t)}</td>
+              </tr>
+            ))}
+          </Section>
+        );
+      })}
+
+      {(['newly_failing', 'fixed', 'still_failing'] as DiffKind[]).map(kind => {
+        const rows = lint.filter(l => l.kind === kind);
+        if (rows.length === 0) return null;
+        return (
+          <Section key={`lint-${kind}`} kind={kind} label={KIND_STYLE[kind].lintLabel} count={rows.length}>
+            {rows.map(l => (
+              <tr key={`${l.linter}|${l.file}|${l.rule}`} className="border-b border-gray-100">
+                <td className="py-1 px-2 text-xs font-semibold">{l.linter}</td>
+                <td className="py-1 px-2 font-mono text-xs">{l.rule}</td>
+                <td className="py-1 px-2 font-mono text-xs text-gray-500 truncate max-w-[20rem]" title={l.file}>{l.file}</td>
+                <td className="py-1 px-2 text-xs text-gray-400 tabular-nums">{lintCount(l)}</td>
+                <td className="py-1 px-2 text-xs text-gray-600 truncate max-w-[28rem]" title={l.message}>{l.message}</td>
+              </tr>
+            ))}
+          </Section>
+        );
+      })}
+    </div>
+  );
+}
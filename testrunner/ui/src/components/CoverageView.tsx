@@ -0,0 +1,127 @@
+import { useMemo, useState } from 'react';
+import type { CoverageReport, CoverageSummary, FileCoverage } from '../types';
+
+interface Props {
+  coverage: CoverageReport | undefined;
+}
+
+function percentColor(s: CoverageSummary): string {
+  if (s.total === 0) return 'text-gray-400';
+  if (s.percent < 50) return 'text-red-600';
+  if (s.percent < 80) return 'text-orange-500';
+  return 'text-green-600';
+}
+
+function barColor(s: CoverageSummary): string {
+  if (s.percent < 50) return 'bg-red-400';
+  if (s.percent < 80) return 'bg-orange-400';
+  return 'bg-green-500';
+}
+
+function CoverageBar({ summary }: { summary: CoverageSummary }) {
+  return (
+    <div className="flex items-center gap-2">
+      <div className="h-1.5 w-32 rounded bg-gray-200 overflow-hidden">
+        <div className={`h-full ${barColor(summary)}`} style={{ width: `${Math.min(100, summary.percent)}%` }} />
+      </div>
+      <span className={`tabular-nums text-xs font-semibold ${percentColor(summary)}`}>{summary.percent.toFixed(1)}%</span>
+      <span className="tabular-nums text-xs text-gray-400">{summary.covered}/{summary.total}</span>
+    </div>
+  );
+}
+
+export function CoverageView({ coverage }: Props) {
+  const [expanded, setExpanded] = useState<Record<string, boolean>>({});
+
+  const filesByPackage = useMemo(() => {
+    const out: Record<string, FileCoverage[]> = {};
+    for (const f of coverage?.files || []) {
+      (out[f.package] ||= []).push(f);
+    }
+    return out;
+  }, [coverage]);
+
+  if (!coverage) {
+    return (
+      <div className="p-8 text-center text-gray-400 text-sm">
+        No coverage collected. Run <code className="px-1 bg-gray-200 rounded">gavel test --coverage --ui</code>.
+      </div>
+    );
+  }
+
+  const diff = coverage.diff;
+  const diffFailed = !!diff && !!diff.threshold && diff.total > 0 && diff.percent < diff.threshold;
+  const uncoveredFiles = (diff?.files || []).filter(f => (f.uncovered || []).length > 0);
+
+  return (
+    <div className="p-4">
+      <div className="mb-3 flex items-center gap-6 text-sm">
+        <div className="flex items-center gap-2">
+          <span className="text-gray-600">Total</span>
+          <CoverageBar summary={coverage.summary} />
+        </div>
+        {diff && (
+          <div className="flex items-center gap-2">
+            <span className="text-gray-600">Changed lines</span>
+            <CoverageBar summary={diff} />
+            {!!diff.threshold && (
+              <span className={`px-2 py-0.5 text-xs rounded font-semibold ${diffFailed ? 'bg-red-100 text-red-700' : 'bg-green-100 text-green-700'}`}>
+                {diffFailed ? 'below' : 'meets'} {diff.threshold}%
+              </span>
+            )}
+          </div>
+        )}
+      </div>
+
+      {uncoveredFiles.length > 0 && (
+        <div className="mb-4">
+          <h3 className="mb-1 text-xs font-semibold uppercase text-gray-500">Uncovered changes</h3>
+          <table className="w-full text-sm">
+            <tbody>
+              {uncoveredFiles.map(f => (
+                <tr key={f.file} className="border-b border-gray-100">
+                  <td className="py-1.5 px-2 font-mono text-xs truncate max-w-[24rem]" title={f.file}>{f.file}</td>
+                  <td className="py-1.5 px-2 w-64"><CoverageBar summary={f} /></td>
+                  <td className="py-1.5 px-2 text-xs text-gray-500 font-mono">{(f.uncovered || []).join(', ')}</td>
+                </tr>
+              ))}
+            </tbody>
+          </table>
+        </div>
+      )}
+
+      <table className="w-full text-sm">
+        <thead>
+          <tr className="border-b border-gray-200 text-left text-xs text-gray-500 uppercase">
+            <th className="py-2 px-2 font-semibold">Package</th>
+            <th className="py-2 px-2 font-semibold">Coverage</th>
+          </tr>
+        </thead>
+        <tbody>
+          {(coverage.packages || []).map(pkg => {
+            const open = !!expanded[pkg.package];
+            return [
+              <tr
+                key={pkg.package}
+                className="border-b border-gray-100 hover:bg-gray-50 cursor-pointer"
+                onClick={() => setExpanded({ ...expanded, [pkg.package]: !open })}
+              >
+                <td className="py-1.5 px-2 font-mono text-xs">
+                  <iconify-icon icon={open ? 'codicon:chevron-down' : 'codicon:chevron-right'} className="mr-1 text-gray-400" />
+                  {pkg.package}
+                </td>
+                <td className="py-1.5 px-2 w-64"><CoverageBar summary={pkg} /></td>
+              </tr>,
+              ...(open ? (filesByPackage[pkg.package] || []).map(f => (
+                <tr key={f.file} className="border-b border-gray-50">
+                  <td className="py-1 pl-8 pr-2 font-mono text-xs text-gray-600">{f.file.split('/').pop()}</td>
+                  <td className="py-1 px-2 w-64"><CoverageBar summary={f} /></td>
+                </tr>
+              )) : []),
+            ];
+          })}
+        </tbody>
+      </table>
+    </div>
+  );
+}
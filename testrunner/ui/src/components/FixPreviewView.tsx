@@ -0,0 +1,94 @@
+import { useState } from 'react';
+import type { FixHunk, FixPreview } from '../types';
+
+interface Props {
+  preview: FixPreview | undefined;
+  busy: boolean;
+  onApply: (ids: string[]) => void;
+}
+
+function hunkRange(start: number, count: number): string {
+  if (count === 0) return `${start - 1},0`;
+  return count === 1 ? `${start}` : `${start},${count}`;
+}
+
+function lineClass(line: string): string {
+  if (line.startsWith('+')) return 'bg-green-50 text-green-800';
+  if (line.startsWith('-')) return 'bg-red-50 text-red-800';
+  return 'text-gray-600';
+}
+
+function HunkStatus({ hunk }: { hunk: FixHunk }) {
+  if (hunk.status === 'applied') {
+    return <span className="text-xs text-green-700">applied</span>;
+  }
+  if (hunk.status === 'conflict') {
+    return <span className="text-xs text-red-600" title={hunk.error}>conflict: {hunk.error}</span>;
+  }
+  return null;
+}
+
+// FixPreviewView lists the hunks of `gavel lint --fix --preview` grouped by
+// linter and file. Checked hunks are sent to /api/lint/fixes/apply; the next
+// snapshot marks each one applied or conflicting.
+export function FixPreviewView({ preview, busy, onApply }: Props) {
+  const [checked, setChecked] = useState<Record<string, boolean>>({});
+  const files = preview?.files || [];
+  if (files.length === 0) return null;
+
+  const pending = files.flatMap(f => f.hunks.filter(h => h.status !== 'applied'));
+  const selected = pending.filter(h => checked[h.id]).map(h => h.id);
+  const toggle = (id: string) => setChecked(prev => ({ ...prev, [id]: !prev[id] }));
+  const setAll = (value: boolean) =>
+    setChecked(Object.fromEntries(pending.map(h => [h.id, value])));
+
+  return (
+    <div className="border-b border-gray-200 bg-white">
+      <div className="flex items-center gap-2 px-3 py-2 border-b border-gray-100">
+        <iconify-icon icon="codicon:diff" className="text-blue-500" />
+        <span className="text-sm font-semibold">Proposed fixes</span>
+        <span className="text-xs text-gray-400">{pending.length} pending</span>
+        <div className="ml-auto flex items-center gap-2">
+          <button className="text-xs text-blue-600 hover:underline" onClick={() => setAll(true)}>Select all</button>
+          <button className="text-xs text-blue-600 hover:underline" onClick={() => setAll(false)}>None</button>
+          <button
+            className="text-xs px-2 py-1 rounded bg-blue-600 text-white hover:bg-blue-700 disabled:opacity-50 disabled:cursor-not-allowed"
+            disabled={busy || selected.length === 0}
+            onClick={() => onApply(selected)}
+            title="Write the selected hunks to the working tree"
+          >
+            Apply {selected.length || ''} selected
+          </button>
+        </div>
+      </div>
+      <div className="max-h-[50vh] overflow-auto">
+        {files.map(f => (
+          <div key={`${f.linter}:${f.file}`} className="px-3 py-2">
+            <div className="text-xs font-mono text-gray-700 mb-1">
+              <span className="text-gray-400">{f.linter}</span> {f.file}
+            </div>
+            {f.hunks.map(h => (
+              <div key={h.id} className="mb-2 rounded border border-gray-200 overflow-hidden">
+                <label className="flex items-center gap-2 px-2 py-1 bg-gray-50 text-xs font-mono cursor-pointer">
+                  <input
+                    type="checkbox"
+                    disabled={h.status === 'applied'}
+                    checked={h.status === 'applied' || !!checked[h.id]}
+                    onChange={() => toggle(h.id)}
+                  />
+                  <span className="text-blue-700">@@ -{hunkRange(h.old_start, h.old_lines)} +{hunkRange(h.new_start, h.new_lines)} @@</span>
+                  <span className="ml-auto"><HunkStatus hunk={h} /></span>
+                </label>
+                <pre className="text-xs font-mono leading-5 m-0">
+                  {h.lines.map((line, i) => (
+                    <div key={i} className={`px-2 whitespace-pre ${lineClass(line)}`}>{line.replace(/\n$/, '')}</div>
+                  ))}
+                </pre>
+              </div>
+            ))}
+          </div>
+        ))}
+      </div>
+    </div>
+  );
+}
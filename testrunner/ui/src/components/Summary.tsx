@@ -11,7 +11,7 @@ interface Props {
 }
 
 export function Summary({ tests, startTime, endTime, done, runMeta }: Props) {
-  const totals = { total: 0, passed: 0, failed: 0, skipped: 0, pending: 0, running: 0, timedout: 0 };
+  const totals = { total: 0, passed: 0, failed: 0, skipped: 0, pending: 0, running: 0, timedout: 0, flaky: 0, quarantined: 0 };
   for (const t of tests) {
     const s = sumNonTaskTests(t);
     totals.total += s.total;
@@ -21,6 +21,8 @@ export function Summary({ tests, startTime, endTime, done, runMeta }: Props) {
     totals.pending += s.pending;
     totals.running += s.running;
     totals.timedout += s.timedout;
+    totals.flaky += s.flaky;
+    totals.quarantined += s.quarantined;
   }
   const now = done && endTime ? endTime : Date.now();
   const elapsed = startTime ? ((now - startTime) / 1000).toFixed(1) + 's' : '';
@@ -72,6 +74,8 @@ export function Summary({ tests, startTime, endTime, done, runMeta }: Props) {
         {totals.passed > 0 && <><Sep /><span className="text-green-600">{totals.passed} passed</span></>}
         {totals.failed > 0 && <><Sep /><span className="text-red-600">{totals.failed} failed</span></>}
         {totals.timedout > 0 && <><Sep /><span className="text-amber-600">{totals.timedout} timed out</span></>}
+        {totals.flaky > 0 && <><Sep /><span className="text-orange-500" title="Passed on a --retries attempt">{totals.flaky} flaky</span></>}
+        {totals.quarantined > 0 && <><Sep /><span className="text-gray-500" title="Failed, but quarantined in .gavel.yaml">{totals.quarantined} quarantined</span></>}
         {totals.skipped > 0 && <><Sep /><span className="text-yellow-600">{totals.skipped} skipped</span></>}
         {totals.running > 0 && <><Sep /><span className="text-blue-500">{totals.running} running</span></>}
         {totals.pending > 0 && <><Sep /><span className="text-gray-400">{totals.pending} queued</span></>}
@@ -94,6 +98,7 @@ export function Summary({ tests, startTime, endTime, done, runMeta }: Props) {
               { count: totals.passed, color: 'bg-green-500', label: 'passed' },
               { count: totals.skipped, color: 'bg-yellow-400', label: 'skipped' },
               { count: totals.failed, color: 'bg-red-500', label: 'failed' },
+              { count: totals.quarantined, color: 'bg-gray-400', label: 'quarantined' },
               { count: totals.timedout, color: 'bg-amber-500', label: 'timed out' },
               { count: totals.running, color: 'bg-blue-400', label: 'running' },
               { count: totals.pending, color: 'bg-gray-300', label: 'queued' },
@@ -83,6 +83,7 @@ export function TestAttempts({ test }: Props) {
               <iconify-icon icon={attemptIcon(att)} className={attemptColor(att)} />
               <span>Attempt {att.sequence}</span>
               {att.run_kind === 'rerun' && <span className="opacity-60">rerun</span>}
+              {att.run_kind === 'retry' && <span className="opacity-60">retry</span>}
             </button>
           ))}
         </div>
@@ -103,6 +103,18 @@ export function TestNode({ test: t, depth, expandAll, selected, onSelect, onReru
           </span>
         )}
 
+        {t.flaky && (
+          <span className="text-xs text-orange-700 bg-orange-50 border border-orange-200 rounded px-1.5 py-0.5 shrink-0" title={`Passed on attempt ${(t.attempts || []).length}`}>
+            flaky
+          </span>
+        )}
+
+        {t.quarantined && (
+          <span className="text-xs text-gray-600 bg-gray-100 border border-gray-200 rounded px-1.5 py-0.5 shrink-0" title="Quarantined in .gavel.yaml: runs, but never fails the build">
+            quarantined
+          </span>
+        )}
+
         {(() => {
           const dur = t.duration || (hasChildren ? totalDuration(t) : 0);
           return dur > 0 ? <span className="text-xs text-gray-400 shrink-0">{formatDuration(dur)}</span> : null;
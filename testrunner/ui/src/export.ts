@@ -17,6 +17,8 @@ export function downloadCurrentView(state: RouteState, format: Exclude<ExportFor
 function promptHeader(state: RouteState): string {
   if (state.tab === 'lint') return 'Use this gavel lint report as context for your analysis or code changes.';
   if (state.tab === 'bench') return 'Use this gavel benchmark report as context for your analysis or code changes.';
+  if (state.tab === 'coverage') return 'Use this gavel coverage report as context for your analysis or code changes.';
+  if (state.tab === 'compare') return 'Use this gavel run comparison as context for your analysis or code changes.';
   return 'Use this gavel test report as context for your analysis or code changes.';
 }
 
@@ -21,7 +21,7 @@ function isDefaultStatusFilter(state: FilterState<string>): boolean {
   return want.every((token, i) => token === got[i]);
 }
 
-export type TabKey = 'tests' | 'lint' | 'bench' | 'diagnostics';
+export type TabKey = 'tests' | 'lint' | 'bench' | 'coverage' | 'diagnostics' | 'compare';
 
 export interface RouteState {
   tab: TabKey;
@@ -48,7 +48,7 @@ export function parseRoute(location: Location): RouteState {
   let tab: TabKey = 'tests';
   let selectedPath = '';
 
-  if (segments[0] === 'tests' || segments[0] === 'lint' || segments[0] === 'bench' || segments[0] === 'diagnostics') {
+  if (segments[0] === 'tests' || segments[0] === 'lint' || segments[0] === 'bench' || segments[0] === 'coverage' || segments[0] === 'diagnostics' || segments[0] === 'compare') {
     tab = segments[0];
     selectedPath = segments.slice(1).join('/');
   }
@@ -23,6 +23,12 @@ export interface Test {
   // an active spinner and pending with a static hollow icon.
   running?: boolean;
   timed_out?: boolean;
+  // flaky marks a test that failed and then passed on a --retries attempt;
+  // it is also passed.
+  flaky?: boolean;
+  // quarantined marks a test listed in .gavel.yaml test.quarantine. A
+  // quarantined failure is reported but never fails the run.
+  quarantined?: boolean;
   task_id?: string;
   can_stop?: boolean;
   stdout?: string;
@@ -114,6 +120,8 @@ export interface TestSummary {
   Pending: number;
   Running?: number;
   Duration: number;
+  Flaky?: number;
+  Quarantined?: number;
 }
 
 export interface Snapshot {
@@ -123,7 +131,11 @@ export interface Snapshot {
   tests: Test[];
   lint?: LinterResult[];
   bench?: BenchComparison;
+  bench_history?: BenchHistory;
+  coverage?: CoverageReport;
+  fix_preview?: FixPreview;
   diagnostics?: DiagnosticsSnapshot;
+  diff?: RunDiff;
 }
 
 export interface RunMeta {
@@ -221,6 +233,112 @@ export interface BenchComparison {
   has_regression: boolean;
 }
 
+export interface BenchTrendPoint {
+  commit: string;
+  uncommitted?: string;
+  subject?: string;
+  time: string;
+  mean: number;
+  stddev?: number;
+  samples: number;
+  bytes_per_op?: number;
+  allocs_per_op?: number;
+}
+
+export interface BenchChangePoint {
+  index: number;
+  commit: string;
+  subject?: string;
+  before_mean: number;
+  after_mean: number;
+  delta_pct: number;
+  p_value: number;
+  regression?: boolean;
+}
+
+export interface BenchTrend {
+  name: string;
+  package?: string;
+  points: BenchTrendPoint[];
+  change_points?: BenchChangePoint[];
+}
+
+export interface BenchHistory {
+  threshold: number;
+  commits: number;
+  trends: BenchTrend[];
+}
+
+export interface CoverageSummary {
+  covered: number;
+  total: number;
+  percent: number;
+}
+
+export interface FileCoverage extends CoverageSummary {
+  file: string;
+  package: string;
+}
+
+export interface PackageCoverage extends CoverageSummary {
+  package: string;
+}
+
+export interface DiffFileCoverage extends CoverageSummary {
+  file: string;
+  uncovered?: number[];
+}
+
+export interface DiffCoverage extends CoverageSummary {
+  threshold?: number;
+  files?: DiffFileCoverage[];
+}
+
+export interface CoverageReport {
+  summary: CoverageSummary;
+  packages?: PackageCoverage[];
+  files?: FileCoverage[];
+  diff?: DiffCoverage;
+}
+
+export type DiffKind = 'newly_failing' | 'fixed' | 'still_failing' | 'added' | 'removed' | 'slower';
+
+export interface DiffRun {
+  label?: string;
+  sha?: string;
+  started?: string;
+}
+
+export interface TestDiff {
+  kind: DiffKind;
+  framework?: string;
+  package_path?: string;
+  name: string;
+  base_status?: string;
+  head_status?: string;
+  base_duration?: number;
+  head_duration?: number;
+  message?: string;
+}
+
+export interface LintDiff {
+  kind: DiffKind;
+  linter: string;
+  file?: string;
+  rule?: string;
+  base_count?: number;
+  head_count?: number;
+  message?: string;
+}
+
+export interface RunDiff {
+  base: DiffRun;
+  head: DiffRun;
+  tests?: TestDiff[];
+  lint?: LintDiff[];
+  unchanged: number;
+}
+
 export interface GoTestContext {
   parent_test?: string;
   import_path?: string;
@@ -299,3 +417,25 @@ export interface TestEditResponse {
   removed?: number;
   message?: string;
 }
+
+export interface FixHunk {
+  id: string;
+  old_start: number;
+  old_lines: number;
+  new_start: number;
+  new_lines: number;
+  lines: string[];
+  status?: 'applied' | 'conflict';
+  error?: string;
+}
+
+export interface FixFileDiff {
+  linter: string;
+  work_dir: string;
+  file: string;
+  hunks: FixHunk[];
+}
+
+export interface FixPreview {
+  files: FixFileDiff[];
+}
@@ -946,6 +946,7 @@ export function statusIcon(t: Test): string {
   if (t.timed_out) return 'ion:hourglass-outline';
   if (t.running) return 'svg-spinners:ring-resize';
   if (t.pending) return 'codicon:circle-large-outline';
+  if (t.failed && t.quarantined) return 'codicon:eye-closed';
   if (t.failed) return 'codicon:error';
   if (t.warned) return 'codicon:warning';
   if (t.skipped) return 'codicon:circle-slash';
@@ -989,6 +990,7 @@ export function statusColor(t: Test): string {
   if (t.timed_out) return 'text-amber-600';
   if (t.running) return 'text-blue-500';
   if (t.pending) return 'text-gray-400';
+  if (t.failed && t.quarantined) return 'text-gray-500';
   if (t.failed) return 'text-red-600';
   if (t.warned) return 'text-amber-600';
   if (t.skipped) return 'text-yellow-600';
@@ -1127,7 +1129,8 @@ function compactWithUnit(value: number, unit: string): string {
 
 // StatusCounts is the tallied verdict breakdown a Test subtree rolls up to.
 // warned is amber and orthogonal to a real failure — a warned-only leaf counts
-// in total + warned, never in failed.
+// in total + warned, never in failed. flaky is a subset of passed; a
+// quarantined failure counts in quarantined instead of failed.
 export type StatusCounts = {
   total: number;
   passed: number;
@@ -1137,9 +1140,11 @@ export type StatusCounts = {
   pending: number;
   running: number;
   timedout: number;
+  flaky: number;
+  quarantined: number;
 };
 
-const emptyCounts = (): StatusCounts => ({ total: 0, passed: 0, failed: 0, warned: 0, skipped: 0, pending: 0, running: 0, timedout: 0 });
+const emptyCounts = (): StatusCounts => ({ total: 0, passed: 0, failed: 0, warned: 0, skipped: 0, pending: 0, running: 0, timedout: 0, flaky: 0, quarantined: 0 });
 
 const addCounts = (r: StatusCounts, s: StatusCounts) => {
   r.total += s.total;
@@ -1150,6 +1155,8 @@ const addCounts = (r: StatusCounts, s: StatusCounts) => {
   r.pending += s.pending;
   r.running += s.running;
   r.timedout += s.timedout;
+  r.flaky += s.flaky;
+  r.quarantined += s.quarantined;
 };
 
 const countsFromSummary = (summary: TestSummary): StatusCounts => ({
@@ -1161,20 +1168,25 @@ const countsFromSummary = (summary: TestSummary): StatusCounts => ({
   pending: summary.Pending || 0,
   running: summary.Running || 0,
   timedout: 0,
+  flaky: summary.Flaky || 0,
+  quarantined: summary.Quarantined || 0,
 });
 
 const countsFromLeaf = (t: Test): StatusCounts => {
   const isTimedOut = !!t.timed_out;
+  const isQuarantined = !isTimedOut && !!t.failed && !!t.quarantined;
   const counted = isTimedOut || t.passed || t.failed || t.warned || t.skipped || t.pending || t.running;
   return {
     total: counted ? 1 : 0,
     passed: !isTimedOut && t.passed ? 1 : 0,
-    failed: !isTimedOut && t.failed ? 1 : 0,
+    failed: !isTimedOut && !isQuarantined && t.failed ? 1 : 0,
     warned: !isTimedOut && t.warned ? 1 : 0,
     skipped: !isTimedOut && t.skipped ? 1 : 0,
     pending: !isTimedOut && t.pending ? 1 : 0,
     running: !isTimedOut && t.running ? 1 : 0,
     timedout: isTimedOut ? 1 : 0,
+    flaky: !isTimedOut && t.passed && t.flaky ? 1 : 0,
+    quarantined: isQuarantined ? 1 : 0,
   };
 };
 
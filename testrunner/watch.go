@@ -0,0 +1,131 @@
+package testrunner
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/flanksource/commons/logger"
+	"github.com/flanksource/gavel/internal/changegraph"
+	"github.com/flanksource/gavel/internal/watch"
+)
+
+// watchIteration is the per-iteration state Watch threads into Run.
+type watchIteration struct {
+	root  string
+	files changegraph.FileSet
+	graph *changegraph.Graph
+}
+
+// graphFor returns the warm graph when it was loaded for workDir. A nested
+// module runs with its own WorkDir and gets nil, i.e. a fresh `go list`.
+func (w *watchIteration) graphFor(workDir string) *changegraph.Graph {
+	abs, _ := filepath.Abs(workDir)
+	if w.graph == nil || w.graph.WorkDir != abs {
+		return nil
+	}
+	return w.graph
+}
+
+// changedFiles is the change set the change-graph filter narrows packages
+// by: the saved files in watch mode, otherwise the git diff selected by
+// --changed / --since.
+func (o *TestOrchestrator) changedFiles() (changegraph.FileSet, error) {
+	if o.watch != nil {
+		return rebaseFileSet(o.watch.files, o.watch.root, o.WorkDir), nil
+	}
+	fs, err := changegraph.ComputeFileSet(o.WorkDir, diffOptionsFromRunOptions(o.RunOptions))
+	if err != nil {
+		return nil, fmt.Errorf("compute change set: %w", err)
+	}
+	return fs, nil
+}
+
+// WatchFunc runs one watch iteration. files are the saved paths, relative to
+// WorkDir; opts is narrowed to the packages they affect and is meant to be
+// passed to Run. Run closes Updates, so the output sinks (Updates,
+// SummaryOut, CoverageOut) are cleared and must be attached per iteration.
+type WatchFunc func(files []string, opts RunOptions) error
+
+// Watch watches opts.WorkDir and calls fn with each debounced batch of saved
+// files until ctx is done. The `go list` package graph is loaded once and
+// kept between iterations; it is reloaded only when go.mod or go.work
+// change, so an iteration costs only the affected packages' test runs.
+func Watch(ctx context.Context, opts RunOptions, fn WatchFunc) error {
+	if opts.WorkDir == "" {
+		opts.WorkDir, _ = os.Getwd()
+	}
+	w, err := watch.New(watch.Options{Root: opts.WorkDir})
+	if err != nil {
+		return fmt.Errorf("watch %s: %w", opts.WorkDir, err)
+	}
+	defer w.Close()
+
+	graph, err := loadPackageGraph(opts.WorkDir)
+	if err != nil {
+		return err
+	}
+	logger.Infof("Watching %s for changes (Ctrl+C to stop)", opts.WorkDir)
+
+	return w.Run(ctx, func(files changegraph.FileSet) {
+		if touchesModuleFiles(files) {
+			logger.Infof("watch: module files changed, reloading package graph")
+			reloaded, err := loadPackageGraph(opts.WorkDir)
+			if err != nil {
+				logger.Warnf("watch: %v", err)
+				return
+			}
+			graph = reloaded
+		}
+		iteration := opts
+		iteration.Context = ctx
+		iteration.Updates = nil
+		iteration.SummaryOut = nil
+		iteration.CoverageOut = nil
+		iteration.watch = &watchIteration{root: opts.WorkDir, files: files, graph: graph}
+		if err := fn(files.Sorted(), iteration); err != nil && ctx.Err() == nil {
+			logger.Warnf("watch: %v", err)
+		}
+	})
+}
+
+func touchesModuleFiles(files changegraph.FileSet) bool {
+	for f := range files {
+		switch path.Base(f) {
+		case "go.mod", "go.work":
+			return true
+		}
+	}
+	return false
+}
+
+// rebaseFileSet re-expresses files, relative to root, relative to workDir,
+// dropping the ones outside it.
+func rebaseFileSet(files changegraph.FileSet, root, workDir string) changegraph.FileSet {
+	absRoot, _ := filepath.Abs(root)
+	absWork, _ := filepath.Abs(workDir)
+	if absRoot == absWork {
+		return files
+	}
+	out := changegraph.NewFileSet()
+	for f := range files {
+		rel, err := filepath.Rel(absWork, filepath.Join(absRoot, filepath.FromSlash(f)))
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			continue
+		}
+		out.Add(rel)
+	}
+	return out
+}
+
+// watchGraph is the warm package graph for this orchestrator's WorkDir, or
+// nil outside watch mode.
+func (o *TestOrchestrator) watchGraph() *changegraph.Graph {
+	if o.watch == nil {
+		return nil
+	}
+	return o.watch.graphFor(o.WorkDir)
+}
@@ -0,0 +1,44 @@
+package testrunner
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/flanksource/gavel/internal/changegraph"
+)
+
+func TestRebaseFileSet(t *testing.T) {
+	root := t.TempDir()
+	files := changegraph.NewFileSet()
+	files.Add("go.mod")
+	files.Add("tools/gen/main.go")
+	files.Add("tools/gen/sub/x.go")
+
+	same := rebaseFileSet(files, root, root)
+	if !reflect.DeepEqual(same.Sorted(), files.Sorted()) {
+		t.Errorf("same root should be a no-op, got %v", same.Sorted())
+	}
+
+	nested := rebaseFileSet(files, root, filepath.Join(root, "tools", "gen"))
+	if want := []string{"main.go", "sub/x.go"}; !reflect.DeepEqual(nested.Sorted(), want) {
+		t.Errorf("nested module change set = %v, want %v", nested.Sorted(), want)
+	}
+}
+
+func TestTouchesModuleFiles(t *testing.T) {
+	for files, want := range map[string]bool{
+		"pkg/a.go":        false,
+		"go.sum":          false,
+		"go.mod":          true,
+		"tools/go.mod":    true,
+		"go.work":         true,
+		"docs/go.mod.txt": false,
+	} {
+		fs := changegraph.NewFileSet()
+		fs.Add(files)
+		if got := touchesModuleFiles(fs); got != want {
+			t.Errorf("touchesModuleFiles(%q) = %v, want %v", files, got, want)
+		}
+	}
+}
@@ -0,0 +1,13 @@
+package utils
+
+import "strings"
+
+// FirstLine returns the first non-blank line of s, trimmed.
+func FirstLine(s string) string {
+	for _, line := range strings.Split(s, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			return line
+		}
+	}
+	return ""
+}
@@ -0,0 +1,24 @@
+package utils
+
+import "testing"
+
+func TestFirstLine(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected string
+	}{
+		{"", ""},
+		{"single", "single"},
+		{"subject\n\nbody", "subject"},
+		{"\n  \n  indented  \nnext", "indented"},
+		{"\r\nwindows\r\n", "windows"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			if got := FirstLine(tt.value); got != tt.expected {
+				t.Errorf("FirstLine(%q) = %q, want %q", tt.value, got, tt.expected)
+			}
+		})
+	}
+}
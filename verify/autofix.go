@@ -219,6 +219,12 @@ func buildFixPrompt(r *VerifyResult, verifyOpts RunOptions, loop *FixLoopResult,
 			continue
 		}
 		fmt.Fprintf(&b, "### Check: %s (FAILED)\n", id)
+		if c, ok := r.CheckByID(id); ok {
+			fmt.Fprintf(&b, "%s\n", c.Description)
+			if c.Prompt != "" {
+				fmt.Fprintf(&b, "%s\n", c.Prompt)
+			}
+		}
 		for _, e := range cr.Evidence {
 			if e.Line > 0 {
 				fmt.Fprintf(&b, "- %s:%d — %s\n", e.File, e.Line, e.Message)
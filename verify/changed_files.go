@@ -0,0 +1,110 @@
+package verify
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/flanksource/repomap"
+)
+
+// ChangedFiles lists the files a review scope covers, relative to the git
+// root, so path-scoped checks can be matched against them. It runs the same
+// git (or gh) command the prompt tells the reviewer to use, with
+// --name-only.
+func ChangedFiles(scope ReviewScope, repoPath string) ([]string, error) {
+	root := repomap.FindGitRoot(repoPath)
+	if root == "" {
+		return nil, fmt.Errorf("%s is not in a git repository", repoPath)
+	}
+
+	switch scope.Type {
+	case "files":
+		return scopeFiles(scope.Files, repoPath, root)
+	case "range":
+		return listFiles(root, "git", "diff", "--name-only", scope.CommitRange)
+	case "commit":
+		return listFiles(root, "git", "show", "--name-only", "--format=", scope.Commit)
+	case "branch":
+		return listFiles(root, "git", "diff", "--name-only", scope.Branch+"...HEAD")
+	case "pr":
+		return listFiles(root, "gh", "pr", "diff", strconv.Itoa(scope.PRNumber), "--name-only")
+	case "date-range":
+		return listFiles(root, "git", "log", "--name-only", "--format=",
+			"--after="+scope.Since, "--before="+scope.Until)
+	default:
+		tracked, err := listFiles(root, "git", "diff", "--name-only", "HEAD")
+		if err != nil {
+			return nil, err
+		}
+		untracked, err := listFiles(root, "git", "ls-files", "--others", "--exclude-standard")
+		if err != nil {
+			return nil, err
+		}
+		return dedupStrings(append(tracked, untracked...)), nil
+	}
+}
+
+func listFiles(dir, name string, args ...string) ([]string, error) {
+	cmd := exec.Command(name, args...)
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("%s %s: %w", name, strings.Join(args, " "), err)
+	}
+	var files []string
+	for _, line := range strings.Split(string(out), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			files = append(files, line)
+		}
+	}
+	return dedupStrings(files), nil
+}
+
+// scopeFiles expands file and directory arguments (relative to repoPath)
+// into files relative to root. Glob arguments are kept as given.
+func scopeFiles(args []string, repoPath, root string) ([]string, error) {
+	var files []string
+	for _, arg := range args {
+		abs := arg
+		if !filepath.IsAbs(abs) {
+			abs = filepath.Join(repoPath, arg)
+		}
+		info, err := os.Stat(abs)
+		if err != nil || !info.IsDir() {
+			files = append(files, relToRoot(root, abs))
+			continue
+		}
+		err = filepath.WalkDir(abs, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return nil
+			}
+			if d.IsDir() {
+				if d.Name() == ".git" {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			files = append(files, relToRoot(root, path))
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return dedupStrings(files), nil
+}
+
+func relToRoot(root, path string) string {
+	absRoot, _ := filepath.Abs(root)
+	absPath, _ := filepath.Abs(path)
+	rel, err := filepath.Rel(absRoot, absPath)
+	if err != nil {
+		return filepath.ToSlash(path)
+	}
+	return filepath.ToSlash(rel)
+}
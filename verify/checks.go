@@ -1,9 +1,39 @@
 package verify
 
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/bmatcuk/doublestar/v4"
+)
+
 type Check struct {
 	ID          string
 	Category    string
 	Description string
+	// Paths and Prompt are only set on custom checks; see CustomCheck.
+	Paths  []string
+	Prompt string
+}
+
+// CustomCategory is the category of custom checks that do not name one.
+const CustomCategory = "custom"
+
+// Applies reports whether the check should be sent for a review touching
+// files (repo-root relative). Checks without Paths always apply.
+func (c Check) Applies(files []string) bool {
+	if len(c.Paths) == 0 {
+		return true
+	}
+	for _, f := range files {
+		f = filepath.ToSlash(f)
+		for _, pattern := range c.Paths {
+			if ok, _ := doublestar.Match(strings.TrimPrefix(pattern, "./"), f); ok {
+				return true
+			}
+		}
+	}
+	return false
 }
 
 var AllChecks = []Check{
@@ -66,16 +96,71 @@ func EnabledChecks(cfg ChecksConfig) []Check {
 		disabledCats[cat] = true
 	}
 
+	custom := make(map[string]Check, len(cfg.Custom))
+	var added []Check
+	for _, cc := range cfg.Custom {
+		if cc.ID == "" {
+			continue
+		}
+		c := Check{ID: cc.ID, Category: cc.Category, Description: cc.Description, Paths: cc.Paths, Prompt: cc.Prompt}
+		if c.Category == "" {
+			c.Category = CustomCategory
+		}
+		if _, seen := custom[c.ID]; !seen {
+			added = append(added, c)
+		}
+		custom[c.ID] = c
+	}
+
 	var out []Check
+	keep := func(c Check) {
+		if !disabled[c.ID] && !disabledCats[c.Category] {
+			out = append(out, c)
+		}
+	}
 	for _, c := range AllChecks {
-		if disabled[c.ID] || disabledCats[c.Category] {
-			continue
+		if cc, ok := custom[c.ID]; ok {
+			c = cc
+			delete(custom, c.ID)
+		}
+		keep(c)
+	}
+	for _, c := range added {
+		if cc, ok := custom[c.ID]; ok {
+			keep(cc)
+		}
+	}
+	return out
+}
+
+// ScopeChecks drops the path-scoped checks that match none of files.
+func ScopeChecks(checks []Check, files []string) []Check {
+	var out []Check
+	for _, c := range checks {
+		if c.Applies(files) {
+			out = append(out, c)
 		}
-		out = append(out, c)
 	}
 	return out
 }
 
+// CategoryOrder lists AllCategories followed by any other category used by
+// checks, in order of first use.
+func CategoryOrder(checks []Check) []string {
+	order := append([]string{}, AllCategories...)
+	seen := make(map[string]bool, len(order))
+	for _, cat := range order {
+		seen[cat] = true
+	}
+	for _, c := range checks {
+		if !seen[c.Category] {
+			seen[c.Category] = true
+			order = append(order, c.Category)
+		}
+	}
+	return order
+}
+
 func ChecksByCategory(checks []Check) map[string][]Check {
 	out := make(map[string][]Check)
 	for _, c := range checks {
@@ -15,8 +15,25 @@ import (
 )
 
 type ChecksConfig struct {
-	Disabled           []string `yaml:"disabled" json:"disabled"`
-	DisabledCategories []string `yaml:"disabledCategories" json:"disabledCategories"`
+	Disabled           []string      `yaml:"disabled" json:"disabled"`
+	DisabledCategories []string      `yaml:"disabledCategories" json:"disabledCategories"`
+	Custom             []CustomCheck `yaml:"custom,omitempty" json:"custom,omitempty"`
+}
+
+// CustomCheck is a project-defined review rule. It is sent to the reviewer,
+// scored, reported and auto-fixed exactly like a built-in check, and an entry
+// whose ID matches a built-in check replaces it.
+//
+//   - Category groups it in the prompt and report; empty means "custom".
+//   - Paths are doublestar globs relative to the repository root. When set,
+//     the check is only sent if the reviewed changes touch a matching file.
+//   - Prompt is extra guidance for the reviewer, shown under the check.
+type CustomCheck struct {
+	ID          string   `yaml:"id" json:"id"`
+	Category    string   `yaml:"category,omitempty" json:"category,omitempty"`
+	Description string   `yaml:"description" json:"description"`
+	Paths       []string `yaml:"paths,omitempty" json:"paths,omitempty"`
+	Prompt      string   `yaml:"prompt,omitempty" json:"prompt,omitempty"`
 }
 
 type VerifyConfig struct {
@@ -64,6 +81,49 @@ func (r LintIgnoreRule) MatchesViolation(v models.Violation) bool {
 type LintConfig struct {
 	Ignore  []LintIgnoreRule            `yaml:"ignore,omitempty" json:"ignore,omitempty"`
 	Linters map[string]LintLinterConfig `yaml:"linters,omitempty" json:"linters,omitempty"`
+	Custom  []CustomLinter              `yaml:"custom,omitempty" json:"custom,omitempty"`
+}
+
+// CustomLinter declares an external checker that gavel runs alongside the
+// built-in linters. Args may contain a literal "{files}" element, which is
+// replaced by the files matching Includes/Excludes; without it no file
+// arguments are passed. FixArgs, when set, replace Args under --fix. A
+// relative Command containing a path separator is resolved against the git
+// root. Markers fan the linter out per project root like the built-ins.
+type CustomLinter struct {
+	Name     string             `yaml:"name" json:"name"`
+	Command  string             `yaml:"command" json:"command"`
+	Args     []string           `yaml:"args,omitempty" json:"args,omitempty"`
+	FixArgs  []string           `yaml:"fixArgs,omitempty" json:"fixArgs,omitempty"`
+	Includes []string           `yaml:"includes,omitempty" json:"includes,omitempty"`
+	Excludes []string           `yaml:"excludes,omitempty" json:"excludes,omitempty"`
+	Markers  []string           `yaml:"markers,omitempty" json:"markers,omitempty"`
+	Timeout  string             `yaml:"timeout,omitempty" json:"timeout,omitempty"`
+	Output   CustomLinterOutput `yaml:"output" json:"output"`
+}
+
+// CustomLinterOutput maps a custom linter's output onto violations. Exactly
+// one of Regex and JSON must be set. Regex is matched against each line of
+// stdout and stderr and uses the named groups file, line, col, severity,
+// rule and message. Severity is used when the output carries none.
+type CustomLinterOutput struct {
+	Regex    string            `yaml:"regex,omitempty" json:"regex,omitempty"`
+	JSON     *CustomLinterJSON `yaml:"json,omitempty" json:"json,omitempty"`
+	Severity string            `yaml:"severity,omitempty" json:"severity,omitempty"`
+}
+
+// CustomLinterJSON maps fields of a JSON report onto violations using dotted
+// paths ("location.start.line", "runs.0.results"). Items selects the array
+// of findings; empty means the document itself is the array, or that stdout
+// holds one JSON object per line.
+type CustomLinterJSON struct {
+	Items    string `yaml:"items,omitempty" json:"items,omitempty"`
+	File     string `yaml:"file,omitempty" json:"file,omitempty"`
+	Line     string `yaml:"line,omitempty" json:"line,omitempty"`
+	Column   string `yaml:"column,omitempty" json:"column,omitempty"`
+	Severity string `yaml:"severity,omitempty" json:"severity,omitempty"`
+	Rule     string `yaml:"rule,omitempty" json:"rule,omitempty"`
+	Message  string `yaml:"message,omitempty" json:"message,omitempty"`
 }
 
 type LintLinterConfig struct {
@@ -191,6 +251,46 @@ type FixturesConfig struct {
 	Files   []string `yaml:"files,omitempty" json:"files,omitempty"`
 }
 
+// TestConfig configures `gavel test`.
+type TestConfig struct {
+	Frameworks []TestFrameworkConfig `yaml:"frameworks,omitempty" json:"frameworks,omitempty"`
+	// Quarantine lists known-flaky tests that still run but never fail the
+	// build. Each entry is a test name ("TestFoo", "TestFoo/sub", a ginkgo
+	// spec or suite text) or a "<package path>:<name>" pair to scope it to
+	// one package; "*" globs are allowed. A quarantined suite or parent test
+	// covers everything under it.
+	Quarantine []string `yaml:"quarantine,omitempty" json:"quarantine,omitempty"`
+	// Cache points `gavel test --cache` at a shared run cache.
+	Cache RunCacheConfig `yaml:"cache,omitempty" json:"cache,omitempty"`
+}
+
+// RunCacheConfig configures the shared remote behind `gavel test --cache`,
+// typically a `gavel cache serve` instance. Mode is "read" (default) or
+// "readwrite"; CI usually sets GAVEL_CACHE_MODE=readwrite so it populates
+// the cache laptops only read. The bearer token is taken from
+// GAVEL_CACHE_TOKEN so it never lands in the repo, and GAVEL_CACHE_URL /
+// GAVEL_CACHE_MODE override the fields below.
+type RunCacheConfig struct {
+	URL  string `yaml:"url,omitempty" json:"url,omitempty"`
+	Mode string `yaml:"mode,omitempty" json:"mode,omitempty"`
+}
+
+// TestFrameworkConfig declares a test framework gavel has no native runner
+// for (bats, maven surefire, dotnet test, ...). gavel runs Command through
+// `sh -c` in the directory under test and reads results back from the JUnit
+// XML file(s) at Report.
+//
+//   - Name is the framework name shown in results and accepted by --framework.
+//   - Detect is a doublestar glob ("**/*.bats", "{pom.xml,*/pom.xml}")
+//     matched against files under the directory; empty means always run.
+//   - Report is a path or doublestar glob relative to that directory.
+type TestFrameworkConfig struct {
+	Name    string `yaml:"name" json:"name"`
+	Detect  string `yaml:"detect,omitempty" json:"detect,omitempty"`
+	Command string `yaml:"command" json:"command"`
+	Report  string `yaml:"report" json:"report"`
+}
+
 // ResolvedFiles returns the configured globs, falling back to the default when none are set.
 func (f FixturesConfig) ResolvedFiles() []string {
 	if len(f.Files) > 0 {
@@ -204,6 +304,7 @@ type GavelConfig struct {
 	Lint     LintConfig     `yaml:"lint,omitempty" json:"lint,omitempty"`
 	Commit   CommitConfig   `yaml:"commit,omitempty" json:"commit,omitempty"`
 	Fixtures FixturesConfig `yaml:"fixtures,omitempty" json:"fixtures,omitempty"`
+	Test     TestConfig     `yaml:"test,omitempty" json:"test,omitempty"`
 	SSH      SSHConfig      `yaml:"ssh,omitempty" json:"ssh,omitempty"`
 	Pre      []HookStep     `yaml:"pre,omitempty" json:"pre,omitempty"`
 	Post     []HookStep     `yaml:"post,omitempty" json:"post,omitempty"`
@@ -382,6 +483,7 @@ func mergeGavelConfig(base, override GavelConfig) GavelConfig {
 	base.Lint = MergeLintConfig(base.Lint, override.Lint)
 	base.Commit = MergeCommitConfig(base.Commit, override.Commit)
 	base.Fixtures = MergeFixturesConfig(base.Fixtures, override.Fixtures)
+	base.Test = MergeTestConfig(base.Test, override.Test)
 	base.SSH = MergeSSHConfig(base.SSH, override.SSH)
 	base.Pre = append(base.Pre, override.Pre...)
 	base.Post = append(base.Post, override.Post...)
@@ -458,9 +560,34 @@ func MergeVerifyConfig(base, override VerifyConfig) VerifyConfig {
 	if len(override.Checks.DisabledCategories) > 0 {
 		base.Checks.DisabledCategories = append(base.Checks.DisabledCategories, override.Checks.DisabledCategories...)
 	}
+	base.Checks.Custom = mergeByName(base.Checks.Custom, override.Checks.Custom, func(c CustomCheck) string { return c.ID })
 	return base
 }
 
+// mergeByName merges override onto base keyed by name: an override entry
+// replaces the base entry with the same name in place and new names are
+// appended, so a repo config can redefine an entry from the home config.
+func mergeByName[T any](base, override []T, name func(T) string) []T {
+	if len(override) == 0 {
+		return base
+	}
+	merged := append([]T{}, base...)
+	for _, o := range override {
+		replaced := false
+		for i := range merged {
+			if name(merged[i]) == name(o) {
+				merged[i] = o
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			merged = append(merged, o)
+		}
+	}
+	return merged
+}
+
 func MergeLintConfig(base, override LintConfig) LintConfig {
 	if len(override.Ignore) > 0 {
 		base.Ignore = append(base.Ignore, override.Ignore...)
@@ -477,6 +604,7 @@ func MergeLintConfig(base, override LintConfig) LintConfig {
 			base.Linters[name] = merged
 		}
 	}
+	base.Custom = mergeByName(base.Custom, override.Custom, func(l CustomLinter) string { return l.Name })
 	return base
 }
 
@@ -523,6 +651,22 @@ func dedupStrings(in []string) []string {
 	return out
 }
 
+// MergeTestConfig merges override onto base. Frameworks are keyed by name: an
+// override entry replaces a base entry with the same name in place, and new
+// names are appended, so a repo config can redefine a home-level framework.
+// Quarantine entries are unioned.
+func MergeTestConfig(base, override TestConfig) TestConfig {
+	base.Quarantine = dedupStrings(append(append([]string{}, base.Quarantine...), override.Quarantine...))
+	if override.Cache.URL != "" {
+		base.Cache.URL = override.Cache.URL
+	}
+	if override.Cache.Mode != "" {
+		base.Cache.Mode = override.Cache.Mode
+	}
+	base.Frameworks = mergeByName(base.Frameworks, override.Frameworks, func(fw TestFrameworkConfig) string { return fw.Name })
+	return base
+}
+
 // MergeFixturesConfig merges override onto base. Enabled is true if either side
 // sets it; Files from the override replace base so a repo-level config can
 // override a home-level default without accumulating globs.
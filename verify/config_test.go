@@ -216,6 +216,68 @@ func TestMergeFixturesConfig(t *testing.T) {
 	})
 }
 
+func TestLoadGavelConfig_WithTestFrameworks(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.Mkdir(filepath.Join(dir, ".git"), 0o755))
+
+	cfgData := []byte(`test:
+  frameworks:
+    - name: bats
+      detect: "**/*.bats"
+      command: bats --report-formatter junit --output build test
+      report: build/report.xml
+`)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".gavel.yaml"), cfgData, 0o644))
+
+	cfg, err := LoadGavelConfig(dir)
+	require.NoError(t, err)
+	require.Len(t, cfg.Test.Frameworks, 1)
+	assert.Equal(t, TestFrameworkConfig{
+		Name:    "bats",
+		Detect:  "**/*.bats",
+		Command: "bats --report-formatter junit --output build test",
+		Report:  "build/report.xml",
+	}, cfg.Test.Frameworks[0])
+}
+
+func TestMergeTestConfig(t *testing.T) {
+	base := TestConfig{Frameworks: []TestFrameworkConfig{
+		{Name: "bats", Command: "bats test"},
+		{Name: "surefire", Command: "mvn test"},
+	}}
+	override := TestConfig{Frameworks: []TestFrameworkConfig{
+		{Name: "surefire", Command: "./mvnw test"},
+		{Name: "dotnet", Command: "dotnet test"},
+	}}
+
+	merged := MergeTestConfig(base, override)
+	assert.Equal(t, []TestFrameworkConfig{
+		{Name: "bats", Command: "bats test"},
+		{Name: "surefire", Command: "./mvnw test"},
+		{Name: "dotnet", Command: "dotnet test"},
+	}, merged.Frameworks)
+	assert.Len(t, base.Frameworks, 2, "base must not be mutated")
+	assert.Equal(t, "mvn test", base.Frameworks[1].Command)
+
+	assert.Equal(t, base, MergeTestConfig(base, TestConfig{}))
+}
+
+func TestMergeTestConfig_Quarantine(t *testing.T) {
+	merged := MergeTestConfig(
+		TestConfig{Quarantine: []string{"TestFlaky", "./pkg:TestSlow"}},
+		TestConfig{Quarantine: []string{"TestFlaky", "TestNetwork*"}},
+	)
+	assert.Equal(t, []string{"TestFlaky", "./pkg:TestSlow", "TestNetwork*"}, merged.Quarantine)
+}
+
+func TestMergeTestConfig_Cache(t *testing.T) {
+	merged := MergeTestConfig(
+		TestConfig{Cache: RunCacheConfig{URL: "https://cache.example.com", Mode: "read"}},
+		TestConfig{Cache: RunCacheConfig{Mode: "readwrite"}},
+	)
+	assert.Equal(t, RunCacheConfig{URL: "https://cache.example.com", Mode: "readwrite"}, merged.Cache)
+}
+
 func TestMergeLintConfig(t *testing.T) {
 	enabledFalse := false
 	enabledTrue := true
@@ -257,6 +319,55 @@ func TestLoadGavelConfig_WithLintLinterEnablement(t *testing.T) {
 	assert.True(t, cfg.Lint.IsLinterEnabled("jscpd", false))
 }
 
+func TestMergeLintConfig_CustomLinters(t *testing.T) {
+	base := LintConfig{Custom: []CustomLinter{
+		{Name: "shellcheck", Command: "shellcheck"},
+		{Name: "hadolint", Command: "hadolint"},
+	}}
+	override := LintConfig{Custom: []CustomLinter{
+		{Name: "shellcheck", Command: "/opt/bin/shellcheck"},
+		{Name: "todo-grep", Command: "./hack/todo.sh"},
+	}}
+	merged := MergeLintConfig(base, override)
+	require.Len(t, merged.Custom, 3)
+	assert.Equal(t, "/opt/bin/shellcheck", merged.Custom[0].Command)
+	assert.Equal(t, "hadolint", merged.Custom[1].Name)
+	assert.Equal(t, "todo-grep", merged.Custom[2].Name)
+	assert.Len(t, base.Custom, 2, "base slice must not be mutated")
+	assert.Equal(t, "shellcheck", base.Custom[0].Command)
+}
+
+func TestLoadGavelConfig_WithCustomLinter(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.Mkdir(filepath.Join(dir, ".git"), 0o755))
+
+	cfgData := []byte(`lint:
+  custom:
+    - name: hadolint
+      command: hadolint
+      args: [--format, json, "{files}"]
+      includes: ["**/Dockerfile"]
+      timeout: 30s
+      output:
+        json:
+          file: file
+          line: line
+          rule: code
+          severity: level
+          message: message
+`)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".gavel.yaml"), cfgData, 0o644))
+
+	cfg, err := LoadGavelConfig(dir)
+	require.NoError(t, err)
+	require.Len(t, cfg.Lint.Custom, 1)
+	l := cfg.Lint.Custom[0]
+	assert.Equal(t, []string{"--format", "json", "{files}"}, l.Args)
+	assert.Equal(t, "30s", l.Timeout)
+	require.NotNil(t, l.Output.JSON)
+	assert.Equal(t, "code", l.Output.JSON.Rule)
+}
+
 func TestLoadGavelConfig_WithPushHooksAndSSH(t *testing.T) {
 	dir := t.TempDir()
 	require.NoError(t, os.Mkdir(filepath.Join(dir, ".git"), 0o755))
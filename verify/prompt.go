@@ -9,15 +9,14 @@ import (
 //go:embed verify-prompt.md
 var verifyPromptTemplate string
 
-func renderPrompt(scope ReviewScope, cfg VerifyConfig) (string, error) {
-	checks := EnabledChecks(cfg.Checks)
+func renderPrompt(scope ReviewScope, cfg VerifyConfig, checks []Check) (string, error) {
 	byCategory := ChecksByCategory(checks)
 
 	data := map[string]any{
 		"scope":        scope,
 		"extra_prompt": cfg.Prompt,
 		"categories":   byCategory,
-		"catOrder":     AllCategories,
+		"catOrder":     CategoryOrder(checks),
 		"ratings":      RatingDimensions,
 	}
 	return gomplate.RunTemplate(data, gomplate.Template{
@@ -100,7 +100,11 @@ func checkIDs(checks []Check) []string {
 }
 
 func SchemaFile(cfg ChecksConfig) (string, error) {
-	schema, err := BuildSchema(EnabledChecks(cfg))
+	return writeSchemaFile(EnabledChecks(cfg))
+}
+
+func writeSchemaFile(checks []Check) (string, error) {
+	schema, err := BuildSchema(checks)
 	if err != nil {
 		return "", err
 	}
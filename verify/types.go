@@ -2,6 +2,7 @@ package verify
 
 import (
 	"fmt"
+	"slices"
 
 	"github.com/flanksource/clicky"
 	"github.com/flanksource/clicky/api"
@@ -35,6 +36,23 @@ type VerifyResult struct {
 	Ratings      map[string]RatingResult `json:"ratings" yaml:"ratings"`
 	Completeness CompletenessResult      `json:"completeness" yaml:"completeness"`
 	Score        int                     `json:"score" yaml:"score"`
+
+	// checks are the checks the review was asked to evaluate, including
+	// custom ones; nil for results decoded from JSON.
+	checks []Check
+}
+
+// CheckByID looks id up among the checks this review evaluated, falling
+// back to the built-in checks.
+func (r VerifyResult) CheckByID(id string) (Check, bool) {
+	for _, list := range [][]Check{r.checks, AllChecks} {
+		for _, c := range list {
+			if c.ID == id {
+				return c, true
+			}
+		}
+	}
+	return Check{}, false
 }
 
 func ratingColor(score int) string {
@@ -80,15 +98,18 @@ func (r VerifyResult) prettyChecks() api.Text {
 
 	byCategory := make(map[string][]string)
 	for id := range r.Checks {
-		for _, c := range AllChecks {
-			if c.ID == id {
-				byCategory[c.Category] = append(byCategory[c.Category], id)
-				break
-			}
+		cat := CustomCategory
+		if c, ok := r.CheckByID(id); ok {
+			cat = c.Category
 		}
+		byCategory[cat] = append(byCategory[cat], id)
 	}
 
-	for _, cat := range AllCategories {
+	order := CategoryOrder(r.checks)
+	if len(byCategory[CustomCategory]) > 0 && !slices.Contains(order, CustomCategory) {
+		order = append(order, CustomCategory)
+	}
+	for _, cat := range order {
 		ids := byCategory[cat]
 		if len(ids) == 0 {
 			continue
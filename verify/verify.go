@@ -24,12 +24,22 @@ func RunVerify(opts RunOptions) (*VerifyResult, error) {
 
 	logger.Infof("Verifying %s using %s", scope, model)
 
-	prompt, err := renderPrompt(scope, opts.Config)
+	checks := EnabledChecks(opts.Config.Checks)
+	if hasPathScopedChecks(checks) {
+		files, err := ChangedFiles(scope, opts.RepoPath)
+		if err != nil {
+			logger.Warnf("Could not list changed files, sending all path-scoped checks: %v", err)
+		} else {
+			checks = ScopeChecks(checks, files)
+		}
+	}
+
+	prompt, err := renderPrompt(scope, opts.Config, checks)
 	if err != nil {
 		return nil, fmt.Errorf("failed to render prompt: %w", err)
 	}
 
-	schemaFile, err := SchemaFile(opts.Config.Checks)
+	schemaFile, err := writeSchemaFile(checks)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create schema file: %w", err)
 	}
@@ -48,9 +58,19 @@ func RunVerify(opts RunOptions) (*VerifyResult, error) {
 	}
 
 	result.Score = ComputeOverallScore(result)
+	result.checks = checks
 	return &result, nil
 }
 
+func hasPathScopedChecks(checks []Check) bool {
+	for _, c := range checks {
+		if len(c.Paths) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
 func ComputeOverallScore(r VerifyResult) int {
 	var total, passed int
 	for _, cr := range r.Checks {
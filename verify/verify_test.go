@@ -112,6 +112,88 @@ func TestEnabledChecks(t *testing.T) {
 	})
 }
 
+func TestEnabledChecksCustom(t *testing.T) {
+	cfg := ChecksConfig{Custom: []CustomCheck{
+		{ID: "handler-authz", Category: "security", Description: "Every new HTTP handler has an authz check", Paths: []string{"api/**/*.go"}},
+		{ID: "migrations-reversible", Description: "DB migrations are reversible", Prompt: "Look for a matching down migration."},
+		{ID: "tests-added", Category: "testing", Description: "Tests use testify"},
+	}}
+
+	checks := EnabledChecks(cfg)
+	assert.Len(t, checks, len(AllChecks)+2)
+
+	byID := map[string]Check{}
+	for _, c := range checks {
+		byID[c.ID] = c
+	}
+	assert.Equal(t, "Tests use testify", byID["tests-added"].Description, "a custom check replaces the built-in with the same ID")
+	assert.Equal(t, CustomCategory, byID["migrations-reversible"].Category)
+	assert.Equal(t, "migrations-reversible", checks[len(checks)-1].ID)
+
+	cfg.DisabledCategories = []string{"security"}
+	cfg.Disabled = []string{"migrations-reversible"}
+	for _, c := range EnabledChecks(cfg) {
+		assert.NotEqual(t, "handler-authz", c.ID)
+		assert.NotEqual(t, "migrations-reversible", c.ID)
+	}
+}
+
+func TestScopeChecks(t *testing.T) {
+	checks := []Check{
+		{ID: "always"},
+		{ID: "api", Paths: []string{"api/**/*.go"}},
+		{ID: "migrations", Paths: []string{"./db/migrations/*.sql"}},
+	}
+	ids := func(cs []Check) []string {
+		var out []string
+		for _, c := range cs {
+			out = append(out, c.ID)
+		}
+		return out
+	}
+	assert.Equal(t, []string{"always", "api"}, ids(ScopeChecks(checks, []string{"api/v1/users.go", "README.md"})))
+	assert.Equal(t, []string{"always", "migrations"}, ids(ScopeChecks(checks, []string{"db/migrations/002_add.sql"})))
+	assert.Equal(t, []string{"always"}, ids(ScopeChecks(checks, nil)))
+}
+
+func TestRenderPromptCustomChecks(t *testing.T) {
+	checks := EnabledChecks(ChecksConfig{
+		DisabledCategories: AllCategories,
+		Custom: []CustomCheck{
+			{ID: "migrations-reversible", Category: "database", Description: "DB migrations are reversible", Prompt: "Look for a matching down migration."},
+		},
+	})
+	prompt, err := renderPrompt(ReviewScope{Type: "diff"}, VerifyConfig{}, checks)
+	assert.NoError(t, err)
+	assert.Contains(t, prompt, "### database")
+	assert.Contains(t, prompt, "**migrations-reversible**: DB migrations are reversible")
+	assert.Contains(t, prompt, "Look for a matching down migration.")
+	assert.NotContains(t, prompt, "tests-added")
+}
+
+func TestChangedFilesForFileScope(t *testing.T) {
+	root := t.TempDir()
+	assert.NoError(t, os.Mkdir(filepath.Join(root, ".git"), 0o755))
+	assert.NoError(t, os.MkdirAll(filepath.Join(root, "api", "v1"), 0o755))
+	assert.NoError(t, os.WriteFile(filepath.Join(root, "api", "v1", "users.go"), nil, 0o644))
+	assert.NoError(t, os.WriteFile(filepath.Join(root, "main.go"), nil, 0o644))
+
+	files, err := ChangedFiles(ReviewScope{Type: "files", Files: []string{"api", "main.go"}}, root)
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{"api/v1/users.go", "main.go"}, files)
+}
+
+func TestCheckByIDUsesReviewedChecks(t *testing.T) {
+	r := VerifyResult{checks: []Check{{ID: "handler-authz", Category: "security"}}}
+	c, ok := r.CheckByID("handler-authz")
+	assert.True(t, ok)
+	assert.Equal(t, "security", c.Category)
+
+	c, ok = VerifyResult{}.CheckByID("tests-added")
+	assert.True(t, ok)
+	assert.Equal(t, "testing", c.Category)
+}
+
 func TestChecksByCategory(t *testing.T) {
 	byCategory := ChecksByCategory(AllChecks)
 	for _, cat := range AllCategories {
@@ -142,6 +224,18 @@ func TestMergeVerifyConfig(t *testing.T) {
 		}
 	})
 
+	t.Run("custom checks merge by ID", func(t *testing.T) {
+		home := MergeVerifyConfig(base, VerifyConfig{Checks: ChecksConfig{Custom: []CustomCheck{
+			{ID: "a", Description: "home a"}, {ID: "b", Description: "home b"},
+		}}})
+		got := MergeVerifyConfig(home, VerifyConfig{Checks: ChecksConfig{Custom: []CustomCheck{
+			{ID: "b", Description: "repo b"}, {ID: "c", Description: "repo c"},
+		}}})
+		assert.Equal(t, []CustomCheck{
+			{ID: "a", Description: "home a"}, {ID: "b", Description: "repo b"}, {ID: "c", Description: "repo c"},
+		}, got.Checks.Custom)
+	})
+
 	t.Run("empty override changes nothing", func(t *testing.T) {
 		got := MergeVerifyConfig(base, VerifyConfig{})
 		if got.Model != "claude" {